@@ -16,4 +16,7 @@ type Config struct {
 	KubeconfigStream []byte
 	// LeaderElection is optional.
 	LeaderElection componentbaseconfig.LeaderElectionConfiguration
+	// StatusBindAddress is the address the read-only VirtualCluster status
+	// HTTP server binds to.
+	StatusBindAddress string
 }
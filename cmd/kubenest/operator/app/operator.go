@@ -7,6 +7,7 @@ import (
 
 	"github.com/spf13/cobra"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	clientset "k8s.io/client-go/kubernetes"
 	restclient "k8s.io/client-go/rest"
@@ -16,6 +17,7 @@ import (
 	"k8s.io/klog/v2"
 	controllerruntime "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/kosmos.io/kosmos/cmd/kubenest/operator/app/config"
 	"github.com/kosmos.io/kosmos/cmd/kubenest/operator/app/options"
@@ -27,6 +29,8 @@ import (
 	glnodecontroller "github.com/kosmos.io/kosmos/pkg/kubenest/controller/global.node.controller"
 	kosmos "github.com/kosmos.io/kosmos/pkg/kubenest/controller/kosmos"
 	vcnodecontroller "github.com/kosmos.io/kosmos/pkg/kubenest/controller/virtualcluster.node.controller"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/util"
+	kubenestwebhook "github.com/kosmos.io/kosmos/pkg/kubenest/webhook"
 	"github.com/kosmos.io/kosmos/pkg/scheme"
 	"github.com/kosmos.io/kosmos/pkg/sharedcli/klogflag"
 )
@@ -117,6 +121,7 @@ func SetupConfig(opts *options.Options) (*config.Config, error) {
 	c.Client = client
 	c.LeaderElection = opts.LeaderElection
 	c.KubeNestOptions = koc
+	c.StatusBindAddress = opts.StatusBindAddress
 
 	return c, nil
 }
@@ -210,6 +215,29 @@ func startEndPointsControllers(mgr manager.Manager) error {
 	return nil
 }
 
+// newSyncedHostPortManager builds a HostPortManager from the configured host
+// port pool and immediately syncs it against existing VirtualClusters, so a
+// controller restart doesn't forget ports already claimed by clusters still
+// running.
+func newSyncedHostPortManager(ctx context.Context, hostKubeClient kubernetes.Interface, kosmosClient versioned.Interface) (*util.HostPortManager, error) {
+	hostPool, err := controller.GetHostPortPoolFromConfigMap(hostKubeClient, constants.KosmosNs, constants.HostPortsCMName, constants.HostPortsCMDataName)
+	if err != nil {
+		return nil, fmt.Errorf("get host port pool error: %v", err)
+	}
+
+	portManager := util.NewHostPortManager(hostPool.PortsPool)
+
+	vcList, err := kosmosClient.KosmosV1alpha1().VirtualClusters(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list virtual clusters error: %v", err)
+	}
+	if err := portManager.SyncFromClusters(ctx, vcList.Items); err != nil {
+		return nil, fmt.Errorf("sync host port manager from virtual clusters error: %v", err)
+	}
+
+	return portManager, nil
+}
+
 func run(ctx context.Context, config *config.Config) error {
 	newscheme := scheme.NewSchema()
 	err := apiextensionsv1.AddToScheme(newscheme)
@@ -238,6 +266,11 @@ func run(ctx context.Context, config *config.Config) error {
 		return fmt.Errorf("could not create clientset: %v", err)
 	}
 
+	portManager, err := newSyncedHostPortManager(ctx, hostKubeClient, kosmosClient)
+	if err != nil {
+		return fmt.Errorf("failed to build host port manager: %v", err)
+	}
+
 	VirtualClusterInitController := controller.VirtualClusterInitController{
 		Client:          mgr.GetClient(),
 		Config:          mgr.GetConfig(),
@@ -245,11 +278,30 @@ func run(ctx context.Context, config *config.Config) error {
 		RootClientSet:   hostKubeClient,
 		KosmosClient:    kosmosClient,
 		KubeNestOptions: &config.KubeNestOptions,
+		PortManager:     portManager,
 	}
 	if err = VirtualClusterInitController.SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("error starting %s: %v", constants.InitControllerName, err)
 	}
 
+	statusServer := &controller.VirtualClusterStatusServer{
+		Client:      mgr.GetClient(),
+		BindAddress: config.StatusBindAddress,
+	}
+	if err = mgr.Add(statusServer); err != nil {
+		return fmt.Errorf("error starting virtualcluster status server: %v", err)
+	}
+
+	// For this handler to actually be reached by the API server, the
+	// ValidatingWebhookConfiguration, Service and serving cert in
+	// deploy/virtual-cluster-operator.yml must also be applied - the cert
+	// placeholders there are not filled in by this repo yet.
+	virtualClusterValidator := &kubenestwebhook.VirtualClusterValidator{Client: mgr.GetClient()}
+	mgr.GetWebhookServer().Register(
+		"/validate-kosmos-io-v1alpha1-virtualcluster",
+		admission.WithCustomValidator(&v1alpha1.VirtualCluster{}, virtualClusterValidator),
+	)
+
 	GlobalNodeController := glnodecontroller.GlobalNodeController{
 		Client:        mgr.GetClient(),
 		RootClientSet: hostKubeClient,
@@ -261,6 +313,84 @@ func run(ctx context.Context, config *config.Config) error {
 		return fmt.Errorf("error starting %s: %v", constants.GlobalNodeControllerName, err)
 	}
 
+	hostPortReconciler := controller.HostPortReconciler{
+		Client:        mgr.GetClient(),
+		RootClientSet: hostKubeClient,
+	}
+	if err = hostPortReconciler.SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("error starting host port reconciler: %v", err)
+	}
+
+	orphanNamespaceReconciler := controller.OrphanNamespaceReconciler{
+		Client:         mgr.GetClient(),
+		RootClientSet:  hostKubeClient,
+		CleanupEnabled: config.KubeNestOptions.KubeInKubeConfig.OrphanNamespaceCleanup,
+	}
+	if err = orphanNamespaceReconciler.SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("error starting orphan namespace reconciler: %v", err)
+	}
+
+	clusterInfoReconciler := controller.ClusterInfoReconciler{
+		RootClientSet: hostKubeClient,
+		ServerURL:     config.RestConfig.Host,
+		CACert:        config.RestConfig.CAData,
+	}
+	if err = clusterInfoReconciler.SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("error starting cluster-info reconciler: %v", err)
+	}
+
+	adminKubeconfigController := controller.AdminKubeconfigController{
+		Client: mgr.GetClient(),
+	}
+	if err = adminKubeconfigController.SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("error starting %s: %v", constants.AdminKubeconfigControllerName, err)
+	}
+
+	bootstrapTokenGCController := controller.BootstrapTokenGCController{
+		Client:        mgr.GetClient(),
+		RootClientSet: hostKubeClient,
+	}
+	if err = bootstrapTokenGCController.SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("error starting %s: %v", constants.BootstrapTokenGCControllerName, err)
+	}
+
+	etcdHealthController := controller.EtcdHealthController{
+		Client:        mgr.GetClient(),
+		RootClientSet: hostKubeClient,
+	}
+	if err = etcdHealthController.SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("error starting %s: %v", constants.EtcdHealthControllerName, err)
+	}
+
+	nodeAutoscaleController := controller.NodeAutoscaleController{
+		Client: mgr.GetClient(),
+	}
+	if err = nodeAutoscaleController.SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("error starting %s: %v", constants.NodeAutoscaleControllerName, err)
+	}
+
+	virtualClusterTTLController := controller.VirtualClusterTTLController{
+		Client: mgr.GetClient(),
+	}
+	if err = virtualClusterTTLController.SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("error starting %s: %v", constants.VirtualClusterTTLControllerName, err)
+	}
+
+	certRenewalController := controller.CertRenewalController{
+		Client:        mgr.GetClient(),
+		RootClientSet: hostKubeClient,
+	}
+	if err = certRenewalController.SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("error starting %s: %v", constants.CertRenewalControllerName, err)
+	}
+
+	orphanedNodeController := controller.OrphanedNodeController{
+		Client: mgr.GetClient(),
+	}
+	if err = orphanedNodeController.SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("error starting %s: %v", constants.OrphanedNodeControllerName, err)
+	}
+
 	if err := startEndPointsControllers(mgr); err != nil {
 		return err
 	}
@@ -15,6 +15,7 @@ type Options struct {
 	DeprecatedOptions          v1alpha1.KubeNestConfiguration
 	AllowNodeOwnbyMulticluster bool
 	KosmosJoinController       bool
+	StatusBindAddress          string
 
 	// ConfigFile is the location of the kubenest's configuration file.
 	ConfigFile string
@@ -70,4 +71,5 @@ func (o *Options) AddFlags(flags *pflag.FlagSet) {
 	flags.StringVar(&o.DeprecatedOptions.KubeInKubeConfig.ETCDStorageClass, "etcd-storage-class", "openebs-hostpath", "Used to set the etcd storage class.")
 	flags.StringVar(&o.DeprecatedOptions.KubeInKubeConfig.ETCDUnitSize, "etcd-unit-size", "1Gi", "Used to set the etcd unit size, each node is allocated storage of etcd-unit-size.")
 	flags.StringVar(&o.ConfigFile, "config", "", "The path to the configuration file.")
+	flags.StringVar(&o.StatusBindAddress, "status-bind-address", ":8090", "The address the read-only VirtualCluster status HTTP server binds to.")
 }
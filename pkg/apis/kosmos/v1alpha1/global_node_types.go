@@ -37,6 +37,21 @@ type GlobalNodeSpec struct {
 
 	// +optional
 	Labels labels.Set `json:"labels,omitempty"`
+
+	// Taints is synced from the corresponding node on the host cluster and
+	// can be used by a VirtualCluster's PromotePolicy to exclude this node
+	// from assignment unless tolerated.
+	// +optional
+	Taints []corev1.Taint `json:"taints,omitempty"`
+
+	// KubeletExtraArgs are additional kubelet flags (e.g. "max-pods",
+	// "system-reserved") applied when this node is joined to a
+	// VirtualCluster's control plane, for capacity management that differs
+	// from the host cluster's own kubelet configuration. Keys are flag names
+	// without the leading "--"; only flags recognized by
+	// util.ValidateKubeletExtraArgs are accepted.
+	// +optional
+	KubeletExtraArgs map[string]string `json:"kubeletExtraArgs,omitempty"`
 }
 
 type NodeState string
@@ -51,6 +66,14 @@ type GlobalNodeStatus struct {
 	// +optional
 	VirtualCluster string `json:"virtualCluster,omitempty"`
 
+	// ClaimedAt records when VirtualCluster was last set, so the
+	// orphaned-node reclaim sweep can apply a grace period before reclaiming
+	// a node whose VirtualCluster has disappeared, instead of racing an
+	// in-flight create that hasn't finished setting up its VirtualCluster
+	// object yet.
+	// +optional
+	ClaimedAt *metav1.Time `json:"claimedAt,omitempty"`
+
 	// Conditions is an array of current observed node conditions.
 	// More info: https://kubernetes.io/docs/concepts/nodes/node/#condition
 	// +optional
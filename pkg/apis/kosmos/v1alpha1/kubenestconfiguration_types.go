@@ -18,6 +18,32 @@ const (
 	NodePort    APIServerServiceType = "nodePort"
 )
 
+// KubeconfigSinkType selects where a VirtualCluster's generated admin
+// kubeconfig is persisted.
+type KubeconfigSinkType string
+
+const (
+	// SecretKubeconfigSink writes the kubeconfig to a Kubernetes Secret. This
+	// is the default, preserving the pre-existing behavior.
+	SecretKubeconfigSink KubeconfigSinkType = "Secret"
+	// NoneKubeconfigSink skips persisting the kubeconfig to any in-cluster
+	// storage, for deployments where an external KubeconfigSink implementation
+	// is registered to handle it instead.
+	NoneKubeconfigSink KubeconfigSinkType = "None"
+)
+
+// AuditBackendType selects where the apiserver sends audit events.
+type AuditBackendType string
+
+const (
+	// AuditLogBackend writes audit events to a log file, rotated according
+	// to AuditConfig.Log.
+	AuditLogBackend AuditBackendType = "Log"
+	// AuditWebhookBackend sends audit events to a webhook endpoint described
+	// by AuditConfig.Webhook.
+	AuditWebhookBackend AuditBackendType = "Webhook"
+)
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // KubeNestConfiguration defines the configuration for KubeNest
@@ -50,14 +76,164 @@ type KubeInKubeConfig struct {
 	AnpMode string `yaml:"anpMode" json:"anpMode,omitempty"`
 	// +optional
 	AdmissionPlugins bool `yaml:"admissionPlugins" json:"admissionPlugins,omitempty"`
+	// Profiling enables the apiserver's profiling endpoint (/debug/pprof).
+	// It should only be turned on for a debugging session and off again afterwards.
+	// +optional
+	Profiling bool `yaml:"profiling" json:"profiling,omitempty"`
 	// +optional
 	APIServerReplicas int `yaml:"apiServerReplicas" json:"apiServerReplicas,omitempty"`
+	// MaxConcurrentReconciles caps how many VirtualClusters the init
+	// controller reconciles at once. Left unset (or <= 0), it defaults to 5.
+	// Raise it for large fleets; the per-resource locks documented on
+	// VirtualClusterInitController still serialize contended operations
+	// (GlobalNode assignment, host port/VIP allocation) regardless of this
+	// value.
+	// +optional
+	MaxConcurrentReconciles int `yaml:"maxConcurrentReconciles" json:"maxConcurrentReconciles,omitempty"`
 	// +optional
 	ClusterCIDR string `yaml:"clusterCIDR" json:"clusterCIDR,omitempty"`
+	// ClusterDNS is the explicit IP address assigned to the CoreDNS service.
+	// When set, it must fall within the virtual cluster's service CIDR and is
+	// used both for the CoreDNS service's clusterIP and the kubelet's --cluster-dns
+	// on node join, keeping the two in sync.
+	// +optional
+	ClusterDNS string `yaml:"clusterDNS" json:"clusterDNS,omitempty"`
+	// ShutdownDelayDuration is the time the apiserver, once SIGTERM'd, keeps
+	// reporting not-ready while still serving requests, giving load balancers
+	// time to notice and stop sending new traffic before it shuts down.
+	// It is passed to the apiserver's --shutdown-delay-duration flag and must
+	// be a non-negative duration, e.g. "30s".
+	// +optional
+	ShutdownDelayDuration string `yaml:"shutdownDelayDuration" json:"shutdownDelayDuration,omitempty"`
+	// ShutdownGracePeriod is the total time the apiserver is given to finish
+	// in-flight requests after receiving SIGTERM before it terminates.
+	// It is passed to the apiserver's --shutdown-grace-period flag and must
+	// be a non-negative duration, e.g. "30s".
+	// +optional
+	ShutdownGracePeriod string `yaml:"shutdownGracePeriod" json:"shutdownGracePeriod,omitempty"`
+	// AdminKubeconfigTokenTTL, when set, makes the admin kubeconfig use a
+	// short-lived token minted via TokenRequest instead of a long-lived
+	// client certificate. The controller refreshes the token before it
+	// expires. Must be a positive duration, e.g. "1h".
+	// +optional
+	AdminKubeconfigTokenTTL string `yaml:"adminKubeconfigTokenTTL" json:"adminKubeconfigTokenTTL,omitempty"`
+	// StorageMediaType is the media type used to store resources in etcd.
+	// It is passed to the apiserver's --storage-media-type flag and must be
+	// one of "application/json" or "application/vnd.kubernetes.protobuf".
+	// +optional
+	StorageMediaType string `yaml:"storageMediaType" json:"storageMediaType,omitempty"`
+	// EtcdSelfHeal, when enabled, allows the etcd-health controller to
+	// automatically recover a lost etcd member it detects during health
+	// probing: the member is removed from the cluster and its pod is
+	// restarted so it can rejoin in its place. When disabled, lost members
+	// are only surfaced on VirtualClusterStatus for an operator to act on.
+	// +optional
+	EtcdSelfHeal bool `yaml:"etcdSelfHeal" json:"etcdSelfHeal,omitempty"`
+	// ConnectionLimitPerClient, when set, configures a FlowSchema and
+	// PriorityLevelConfiguration in the virtual apiserver limiting how many
+	// concurrent requests a single client identity may have in flight, to
+	// protect the control plane from a single abusive client.
+	// +optional
+	ConnectionLimitPerClient *ConnectionLimitConfig `yaml:"connectionLimitPerClient" json:"connectionLimitPerClient,omitempty"`
 	// +optional
 	ETCDStorageClass string `yaml:"etcdStorageClass" json:"etcdStorageClass,omitempty"`
 	// +optional
 	ETCDUnitSize string `yaml:"etcdUnitSize" json:"etcdUnitSize,omitempty"`
+	// EtcdVersion pins the etcd image tag independently of the bundled
+	// control-plane version, for operators who need to run a newer/older
+	// etcd than the default. Must be listed as compatible with the
+	// apiserver version in util.EtcdCompatibilityMatrix; defaults to the
+	// bundled control-plane version when unset.
+	// +optional
+	EtcdVersion string `yaml:"etcdVersion" json:"etcdVersion,omitempty"`
+	// KubernetesVersion pins the apiserver, controller-manager and scheduler
+	// image tag for the virtual cluster's control plane. Must be one of
+	// util.SupportedKubernetesVersions, since manifests and etcd-compatibility
+	// data are only validated against those; defaults to the bundled
+	// control-plane version when unset.
+	// +optional
+	KubernetesVersion string `yaml:"kubernetesVersion" json:"kubernetesVersion,omitempty"`
+	// EtcdSnapshotCount sets etcd's --snapshot-count, the number of applied
+	// Raft entries etcd keeps between snapshots. Must be a positive integer
+	// when set; defaults to etcd's own built-in default otherwise.
+	// +optional
+	EtcdSnapshotCount int64 `yaml:"etcdSnapshotCount" json:"etcdSnapshotCount,omitempty"`
+	// EtcdAutoCompactionRetentionHours sets etcd's --auto-compaction-retention
+	// in hours, how far back etcd keeps old key revisions before compacting
+	// them away. Must be a positive integer when set; defaults to etcd's own
+	// built-in default otherwise.
+	// +optional
+	EtcdAutoCompactionRetentionHours int64 `yaml:"etcdAutoCompactionRetentionHours" json:"etcdAutoCompactionRetentionHours,omitempty"`
+	// DefaultStorageClass, when set, makes the virtual cluster reconcile a
+	// default StorageClass at creation time, so tenants deploying stateful
+	// workloads have one to bind PVCs against without configuring storage
+	// themselves. Left unset, no StorageClass is created.
+	// +optional
+	DefaultStorageClass *DefaultStorageClassConfig `yaml:"defaultStorageClass" json:"defaultStorageClass,omitempty"`
+	// ContentType, when set, overrides the content type used for request
+	// bodies and content negotiation (ContentType/AcceptContentTypes on the
+	// underlying rest.Config) by clients built for this virtual cluster, e.g.
+	// "application/vnd.kubernetes.protobuf" to reduce apiserver load for
+	// internal clients. Left unset, client-go's default (JSON) is used.
+	// +optional
+	ContentType string `yaml:"contentType" json:"contentType,omitempty"`
+	// KubeconfigSink selects where the generated admin kubeconfig is
+	// persisted once the control plane is ready. Defaults to
+	// SecretKubeconfigSink, preserving today's behavior of storing it in a
+	// Kubernetes Secret. Set to NoneKubeconfigSink when an external
+	// KubeconfigSink (e.g. a Vault or cloud secret manager integration) is
+	// wired in to take over persistence instead.
+	// +optional
+	KubeconfigSink KubeconfigSinkType `yaml:"kubeconfigSink" json:"kubeconfigSink,omitempty"`
+	// Audit configures where the apiserver sends audit events, in addition to
+	// whatever audit policy is already applied. Leaving it unset keeps audit
+	// logging off, preserving today's behavior.
+	// +optional
+	Audit *AuditConfig `yaml:"audit" json:"audit,omitempty"`
+	// ReconcileFailureThreshold is how long a VirtualCluster can stay stuck
+	// in the Pending phase before the init controller raises its Degraded
+	// status condition, for alerting on clusters failing to reconcile.
+	// Must be a positive duration, e.g. "15m". Defaults to
+	// constants.VirtualClusterDegradedThreshold when unset.
+	// +optional
+	ReconcileFailureThreshold string `yaml:"reconcileFailureThreshold" json:"reconcileFailureThreshold,omitempty"`
+	// AuthenticationWebhook, when set, wires the apiserver's
+	// --authentication-token-webhook-config-file flag to the kubeconfig held
+	// by the referenced Secret, delegating bearer-token authentication to an
+	// external webhook.
+	// +optional
+	AuthenticationWebhook *WebhookKubeconfigConfig `yaml:"authenticationWebhook" json:"authenticationWebhook,omitempty"`
+	// AuthorizationWebhook, when set, wires the apiserver's
+	// --authorization-webhook-config-file flag the same way, delegating
+	// authorization decisions to an external webhook.
+	// +optional
+	AuthorizationWebhook *WebhookKubeconfigConfig `yaml:"authorizationWebhook" json:"authorizationWebhook,omitempty"`
+	// OrphanNamespaceCleanup, when enabled, lets the periodic orphaned
+	// control-plane namespace sweep actually delete namespaces it finds with
+	// no owning VirtualCluster (e.g. left behind when a VirtualCluster's
+	// finalizer was force-removed before its namespace was cleaned up).
+	// When disabled, the sweep only logs what it would delete.
+	// +optional
+	OrphanNamespaceCleanup bool `yaml:"orphanNamespaceCleanup" json:"orphanNamespaceCleanup,omitempty"`
+	// NodeMonitorPeriod is passed to the virtual controller-manager's
+	// --node-monitor-period flag, how often it polls node health. Must be a
+	// positive duration, e.g. "5s". Defaults to the controller-manager's own
+	// built-in default when unset.
+	// +optional
+	NodeMonitorPeriod string `yaml:"nodeMonitorPeriod" json:"nodeMonitorPeriod,omitempty"`
+	// NodeMonitorGracePeriod is passed to the virtual controller-manager's
+	// --node-monitor-grace-period flag, how long a node can go unreported
+	// before it is marked NotReady. Tenants with flaky networks may want to
+	// raise this to avoid flapping. Must be a positive duration, e.g. "40s".
+	// Defaults to the controller-manager's own built-in default when unset.
+	// +optional
+	NodeMonitorGracePeriod string `yaml:"nodeMonitorGracePeriod" json:"nodeMonitorGracePeriod,omitempty"`
+	// PodEvictionTimeout is passed to the virtual controller-manager's
+	// --pod-eviction-timeout flag, how long a node can stay NotReady before
+	// its pods are evicted. Must be a positive duration, e.g. "5m". Defaults
+	// to the controller-manager's own built-in default when unset.
+	// +optional
+	PodEvictionTimeout string `yaml:"podEvictionTimeout" json:"podEvictionTimeout,omitempty"`
 
 	//// Etcd contains the configuration for the etcd statefulset.
 	//Etcd EtcdCluster `yaml:"etcd" json:"etcd,omitempty"`
@@ -91,6 +267,162 @@ type KubeInKubeConfig struct {
 	UseTenantDNS bool `yaml:"useTenantDNS" json:"useTenantDNS,omitempty"`
 	// +optional
 	ExternalPort int32 `json:"externalPort,omitempty"`
+
+	// CORSAllowedOrigins is passed to the apiserver's --cors-allowed-origins
+	// flag as a comma-separated list, letting a web dashboard served from one
+	// of these origins talk to the virtual apiserver directly from a browser.
+	// Each entry must be a valid regular expression, matched against the
+	// request's Origin header, e.g. "https://dashboard\\.example\\.com".
+	// +optional
+	CORSAllowedOrigins []string `yaml:"corsAllowedOrigins" json:"corsAllowedOrigins,omitempty"`
+
+	// RequireNodeReady, when enabled, makes node assignment check the
+	// candidate's underlying host Node object is Ready (via the host
+	// cluster client) before claiming it, filtering out NotReady nodes from
+	// the candidate set instead of assigning a control-plane pod to a node
+	// it can't schedule onto. Left disabled, a GlobalNode in NodeFreeState
+	// is treated as authoritative regardless of its Node's reported
+	// readiness, preserving today's behavior.
+	// +optional
+	RequireNodeReady bool `yaml:"requireNodeReady" json:"requireNodeReady,omitempty"`
+
+	// PodSecurityAdmission, when set, configures the apiserver's built-in
+	// PodSecurity admission plugin with cluster-wide default enforce/audit/warn
+	// levels, via --admission-control-config-file. Left unset, the apiserver
+	// uses its own built-in PodSecurity defaults (the "privileged" level).
+	// +optional
+	PodSecurityAdmission *PodSecurityAdmissionConfig `yaml:"podSecurityAdmission" json:"podSecurityAdmission,omitempty"`
+}
+
+// ConnectionLimitConfig bounds how many concurrent requests a single client
+// identity may have in flight against the virtual apiserver.
+type ConnectionLimitConfig struct {
+	// AssuredConcurrencyShares is the share of the apiserver's total
+	// concurrency budget reserved for all clients matched by this limit,
+	// see PriorityLevelConfiguration.Spec.Limited.NominalConcurrencyShares.
+	// +kubebuilder:default=5
+	// +optional
+	AssuredConcurrencyShares int32 `yaml:"assuredConcurrencyShares" json:"assuredConcurrencyShares,omitempty"`
+	// QueueLength is the maximum number of requests a single client may have
+	// queued before further requests from it are rejected.
+	// +kubebuilder:default=50
+	// +optional
+	QueueLength int32 `yaml:"queueLength" json:"queueLength,omitempty"`
+}
+
+// AuditConfig configures the virtual apiserver's audit backend. Exactly one
+// of Log or Webhook must be set, matching Backend.
+type AuditConfig struct {
+	// Backend selects which of Log or Webhook carries audit events.
+	// +kubebuilder:validation:Enum=Log;Webhook
+	Backend AuditBackendType `yaml:"backend" json:"backend,omitempty"`
+	// Log configures the log-file audit backend. Required when Backend is
+	// AuditLogBackend, and must be unset otherwise.
+	// +optional
+	Log *AuditLogConfig `yaml:"log" json:"log,omitempty"`
+	// Webhook configures the webhook audit backend. Required when Backend is
+	// AuditWebhookBackend, and must be unset otherwise.
+	// +optional
+	Webhook *AuditWebhookConfig `yaml:"webhook" json:"webhook,omitempty"`
+}
+
+// AuditLogConfig configures the apiserver's --audit-log-* flags.
+type AuditLogConfig struct {
+	// Path is the host path audit events are logged to.
+	// +kubebuilder:default="/var/log/kubernetes/audit/audit.log"
+	// +optional
+	Path string `yaml:"path" json:"path,omitempty"`
+	// MaxAge is the maximum number of days to retain old audit log files,
+	// passed to --audit-log-maxage.
+	// +optional
+	MaxAge int32 `yaml:"maxAge" json:"maxAge,omitempty"`
+	// MaxBackup is the maximum number of old audit log files to retain,
+	// passed to --audit-log-maxbackup.
+	// +optional
+	MaxBackup int32 `yaml:"maxBackup" json:"maxBackup,omitempty"`
+	// MaxSize is the maximum size in megabytes of an audit log file before
+	// it gets rotated, passed to --audit-log-maxsize.
+	// +optional
+	MaxSize int32 `yaml:"maxSize" json:"maxSize,omitempty"`
+}
+
+// AuditWebhookConfig configures the apiserver's --audit-webhook-config-file
+// flag, sending audit events to an externally-managed webhook.
+type AuditWebhookConfig struct {
+	// ConfigMapName references a ConfigMap, in the virtual cluster's control
+	// plane namespace, holding the webhook kubeconfig file under the data
+	// key "webhook-config.yaml".
+	ConfigMapName string `yaml:"configMapName" json:"configMapName,omitempty"`
+	// InitialBackoff is passed to --audit-webhook-initial-backoff, bounding
+	// how long the apiserver waits before retrying a failed webhook request.
+	// +optional
+	InitialBackoff string `yaml:"initialBackoff" json:"initialBackoff,omitempty"`
+}
+
+// WebhookKubeconfigConfig names a Secret holding a kubeconfig the apiserver
+// uses to contact an external webhook server, including that webhook's CA
+// bundle as the kubeconfig's cluster.certificate-authority-data.
+type WebhookKubeconfigConfig struct {
+	// SecretName is the Secret, in the virtual cluster's control plane
+	// namespace, whose "kubeconfig" data key holds the webhook kubeconfig.
+	// +required
+	SecretName string `yaml:"secretName" json:"secretName,omitempty"`
+}
+
+// PodSecurityAdmissionConfig sets the apiserver's cluster-wide default Pod
+// Security Standards levels, mirroring the fields of Kubernetes' own
+// PodSecurityConfiguration admission plugin config.
+type PodSecurityAdmissionConfig struct {
+	// Enforce is the default enforce level for namespaces with no
+	// pod-security.kubernetes.io/enforce label of their own. Must be one of
+	// "privileged", "baseline", "restricted". Defaults to "privileged" when
+	// unset, matching the apiserver's own built-in default.
+	// +kubebuilder:validation:Enum=privileged;baseline;restricted
+	// +optional
+	Enforce string `yaml:"enforce" json:"enforce,omitempty"`
+	// EnforceVersion pins the Pod Security Standards version the Enforce level
+	// is evaluated against, e.g. "v1.28". Defaults to "latest" when unset.
+	// +optional
+	EnforceVersion string `yaml:"enforceVersion" json:"enforceVersion,omitempty"`
+	// Audit is the default audit level, same validation and default as
+	// Enforce.
+	// +kubebuilder:validation:Enum=privileged;baseline;restricted
+	// +optional
+	Audit string `yaml:"audit" json:"audit,omitempty"`
+	// AuditVersion pins the Pod Security Standards version the Audit level is
+	// evaluated against. Defaults to "latest" when unset.
+	// +optional
+	AuditVersion string `yaml:"auditVersion" json:"auditVersion,omitempty"`
+	// Warn is the default warn level, same validation and default as Enforce.
+	// +kubebuilder:validation:Enum=privileged;baseline;restricted
+	// +optional
+	Warn string `yaml:"warn" json:"warn,omitempty"`
+	// WarnVersion pins the Pod Security Standards version the Warn level is
+	// evaluated against. Defaults to "latest" when unset.
+	// +optional
+	WarnVersion string `yaml:"warnVersion" json:"warnVersion,omitempty"`
+}
+
+// DefaultStorageClassConfig configures the default StorageClass a
+// VirtualCluster reconciles into its tenant cluster, typically mapping onto
+// a provisioner already available in the host cluster.
+type DefaultStorageClassConfig struct {
+	// Name is the StorageClass's name in the tenant cluster. Defaults to
+	// "default" when unset.
+	// +optional
+	Name string `yaml:"name" json:"name,omitempty"`
+	// Provisioner is the StorageClass's provisioner, e.g. a CSI driver name
+	// reachable from the tenant cluster's nodes.
+	// +required
+	Provisioner string `yaml:"provisioner" json:"provisioner,omitempty"`
+	// Parameters are passed through to the StorageClass verbatim, e.g. to
+	// map onto a specific host-cluster storage pool or type.
+	// +optional
+	Parameters map[string]string `yaml:"parameters" json:"parameters,omitempty"`
+	// ReclaimPolicy is the StorageClass's reclaim policy. Defaults to
+	// "Delete" when unset.
+	// +optional
+	ReclaimPolicy string `yaml:"reclaimPolicy" json:"reclaimPolicy,omitempty"`
 }
 
 // TenantEntrypoint contains the configuration for the tenant entrypoint.
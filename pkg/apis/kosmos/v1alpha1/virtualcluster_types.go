@@ -23,6 +23,43 @@ const (
 	// Updating means that some changes are happening
 	Updating Phase = "Updating"
 	Pending  Phase = "Pending"
+	// DryRunComplete means a DryRun VirtualCluster's node selection has been
+	// computed and published to Status.PlannedNodes without claiming any
+	// GlobalNode or deploying a control plane.
+	DryRunComplete Phase = "DryRunComplete"
+	// WorkersScaledDown means every PromotePolicy's NodeCount has been
+	// scaled to zero: all worker GlobalNodes have been released, but the
+	// control plane itself keeps running so the VirtualCluster can be
+	// scaled back up without recreating it.
+	WorkersScaledDown Phase = "WorkersScaledDown"
+)
+
+// DegradedConditionType is the Status.Conditions type set once a
+// VirtualCluster has been stuck failing to reconcile beyond
+// KubeInKubeConfig.ReconcileFailureThreshold, so it can be alerted on
+// independently of the reason string in Status.Reason.
+const DegradedConditionType = "Degraded"
+
+// Status.Conditions types tracking the individual stages a VirtualCluster
+// goes through on its way to Completed, so multiple concurrent problems
+// (e.g. a node shortage alongside a failed component) each keep their own
+// history instead of overwriting a single Status.Reason string.
+const (
+	// NodesAssignedConditionType reports whether assignWorkNodes was able to
+	// satisfy every PromotePolicy with matching, free GlobalNodes.
+	NodesAssignedConditionType = "NodesAssigned"
+	// ControlPlaneReadyConditionType reports whether the virtual control
+	// plane's components executed successfully.
+	ControlPlaneReadyConditionType = "ControlPlaneReady"
+	// AllPodsRunningConditionType reports whether every workload Pod in the
+	// virtual cluster reached Running within the configured timeout.
+	AllPodsRunningConditionType = "AllPodsRunning"
+	// NodeOwnershipConflictConditionType reports whether any node this
+	// VirtualCluster claims is also still claimed by another VirtualCluster,
+	// e.g. after a bug or manual edit left a GlobalNode's
+	// Status.VirtualCluster and another cluster's
+	// Spec.PromoteResources.NodeInfos disagreeing about who owns it.
+	NodeOwnershipConflictConditionType = "NodeOwnershipConflict"
 )
 
 // +genclient
@@ -56,9 +93,24 @@ type VirtualClusterSpec struct {
 	// +optional
 	ExternalIps []string `json:"externalIps,omitempty"`
 
+	// ExternalHostName is the externally-reachable hostname of the virtual
+	// kubernetes's control plane, used when it differs from ExternalIP (e.g.
+	// behind NAT). It is added to the apiserver certificate's SANs and used
+	// as the server endpoint in the external admin kubeconfig, while
+	// ExternalIP/ExternalIps keep serving the internal endpoints.
+	// +optional
+	ExternalHostName string `json:"externalHostName,omitempty"`
+
 	// KubeInKubeConfig is the external config of virtual cluster
 	// +optional
 	KubeInKubeConfig *KubeInKubeConfig `json:"kubeInKubeConfig,omitempty"`
+
+	// ControlPlaneConfig overrides the replica count of this VirtualCluster's
+	// control plane components. Any field left unset (zero) keeps today's
+	// default for that component.
+	// +optional
+	ControlPlaneConfig *ControlPlaneConfig `json:"controlPlaneConfig,omitempty"`
+
 	// PromotePolicies definites the policies for promote to the kubernetes's control plane
 	// +required
 	PromotePolicies []PromotePolicy `json:"promotePolicies,omitempty"`
@@ -76,6 +128,230 @@ type VirtualClusterSpec struct {
 	// datasource for plugin yaml
 	// +optional
 	PluginOptions []PluginOptions `json:"pluginOptions,omitempty"`
+
+	// DryRun, when set on a newly-created VirtualCluster, makes the init
+	// controller compute node selection and publish it to
+	// Status.PlannedNodes without claiming any GlobalNode or deploying a
+	// control plane. The VirtualCluster settles in the DryRunComplete phase
+	// instead of proceeding to Initialized.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// TTL is the maximum lifetime of this VirtualCluster, as a
+	// time.ParseDuration string (e.g. "24h"), measured from its
+	// CreationTimestamp. Once elapsed, the ttl controller deletes the
+	// VirtualCluster; a warning event is emitted as the deadline nears.
+	// Re-reading Spec.TTL on every reconcile means extending it on an
+	// existing VirtualCluster pushes the deadline out, even if the cluster
+	// is already past its original TTL. Leave unset to disable auto-expiry.
+	// +optional
+	TTL string `json:"ttl,omitempty"`
+
+	// Paused stops the init controller from making any further changes to
+	// this VirtualCluster - useful during maintenance, e.g. hand-editing the
+	// control plane. The kosmos.io/paused: "true" annotation has the same
+	// effect and takes precedence if both are set. Deletion is never paused:
+	// a VirtualCluster being deleted is reconciled as usual regardless of
+	// this field.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// Priority ranks this VirtualCluster against others sharing a GlobalNode
+	// pool for preemption purposes: a cluster may only preempt nodes from
+	// clusters with a strictly lower Priority. Unset clusters default to
+	// priority 0, same as most other unset clusters.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// EnablePreemption opts this VirtualCluster into reclaiming InUse nodes
+	// from lower-Priority VirtualClusters sharing the same GlobalNode pool
+	// when assignWorkNodes otherwise doesn't have enough free nodes to
+	// satisfy a PromotePolicy. Preempted nodes are released from the victim
+	// cluster (triggering its own scale-down) before being claimed here.
+	// Defaults to false: preemption never happens unless explicitly enabled.
+	// +optional
+	EnablePreemption bool `json:"enablePreemption,omitempty"`
+
+	// ReadinessTimeoutSeconds overrides how long the AllNodeReady phase waits
+	// for every Deployment/StatefulSet/DaemonSet in the tenant cluster to
+	// become fully available before giving up. Large clusters with
+	// slow-starting operators may need more than the default, while small
+	// ones may want to fail fast instead of waiting the full default.
+	// Defaults to constants.WaitAllPodsRunningTimeoutSeconds when unset. Must
+	// be positive.
+	// +optional
+	ReadinessTimeoutSeconds *int32 `json:"readinessTimeoutSeconds,omitempty"`
+
+	// SkipReadinessCheck transitions AllNodeReady straight to Completed (or
+	// WorkersScaledDown) without polling workloads in the tenant cluster for
+	// readiness, for operators who manage their own workload readiness and
+	// don't want the controller blocking on ensureAllPodsRunning. Defaults
+	// to false, preserving today's wait-for-pods behavior.
+	// +optional
+	SkipReadinessCheck bool `json:"skipReadinessCheck,omitempty"`
+
+	// ImageRepository overrides the default container registry used for this
+	// VirtualCluster's control-plane component images (apiserver, etcd,
+	// scheduler, kube-controller-manager), for air-gapped environments that
+	// mirror images to a private registry. Defaults to the registry
+	// configured by the kosmos operator's IMAGE_REPOSITIRY environment
+	// variable when unset.
+	// +optional
+	ImageRepository string `json:"imageRepository,omitempty"`
+
+	// ComponentImageOverrides lets individual control-plane components use a
+	// fully-qualified image reference instead of ImageRepository, for
+	// components that need a different registry, repository, or tag than
+	// the rest of the control plane. Keys are component names ("apiserver",
+	// "etcd", "KubeControllerManager", "VirtualClusterScheduler"); values
+	// are used verbatim, so they must already include a registry and tag.
+	// +optional
+	ComponentImageOverrides map[string]string `json:"componentImageOverrides,omitempty"`
+
+	// ResourceLabels are merged onto every Kubernetes object provisioned for
+	// this VirtualCluster's control plane (Deployments, Secrets, ...), for
+	// org-standard labels like cost-center or team that external tooling
+	// keys off of. A label kosmos already sets on an object (e.g.
+	// virtualCluster-app) always wins, so teardown selection by kosmos's own
+	// labels keeps working.
+	// +optional
+	ResourceLabels map[string]string `json:"resourceLabels,omitempty"`
+
+	// ResourceAnnotations are merged onto every Kubernetes object
+	// provisioned for this VirtualCluster's control plane, the same way
+	// ResourceLabels are. An annotation kosmos already sets always wins.
+	// +optional
+	ResourceAnnotations map[string]string `json:"resourceAnnotations,omitempty"`
+
+	// Etcd overrides this VirtualCluster's etcd persistent storage settings.
+	// +optional
+	Etcd *EtcdConfig `json:"etcd,omitempty"`
+
+	// AdditionalKubeconfigs provisions extra kubeconfigs for this
+	// VirtualCluster, alongside the cluster-admin kubeconfig every
+	// VirtualCluster already gets. Each entry is signed with its own client
+	// certificate, scoped to its ClusterRole via a ClusterRoleBinding
+	// created in the tenant cluster, so a consumer only needs the one
+	// kubeconfig matching the access it actually needs instead of the
+	// cluster-admin one. Note that, like the cluster-admin kubeconfig's
+	// client certificate, these are not currently renewed by
+	// CertRenewalController; rotating the admin cert's CA should be
+	// followed by recreating any VirtualCluster with AdditionalKubeconfigs
+	// set so their certificates get reissued against the new CA too.
+	// +optional
+	AdditionalKubeconfigs []AdditionalKubeconfig `json:"additionalKubeconfigs,omitempty"`
+
+	// ExternalControlPlane, when set, tells createVirtualCluster that an
+	// external operator already runs this VirtualCluster's control plane:
+	// every executor control-plane provisioning task is skipped, and
+	// createVirtualCluster instead validates connectivity to the existing
+	// apiserver before proceeding straight to node assignment, exactly as if
+	// a kubenest-managed control plane had just come up. The phase machine
+	// is unaffected.
+	// +optional
+	ExternalControlPlane *ExternalControlPlane `json:"externalControlPlane,omitempty"`
+}
+
+// ExternalControlPlane references a pre-provisioned (bring-your-own)
+// Kubernetes control plane for createVirtualCluster to register against
+// instead of deploying one itself.
+type ExternalControlPlane struct {
+	// APIServerEndpoint is the externally-reachable address
+	// (e.g. "https://host:6443") of the existing control plane's apiserver.
+	// +required
+	APIServerEndpoint string `json:"apiServerEndpoint"`
+
+	// KubeconfigSecretRef names a Secret, in the VirtualCluster's own
+	// namespace, holding an admin kubeconfig for the existing control plane
+	// under the same constants.KubeConfig data key kubenest writes for a
+	// control plane it provisions itself.
+	// +required
+	KubeconfigSecretRef string `json:"kubeconfigSecretRef"`
+}
+
+// AdditionalKubeconfig describes one extra scoped kubeconfig to provision
+// for a VirtualCluster, beyond the default cluster-admin kubeconfig.
+type AdditionalKubeconfig struct {
+	// Name identifies this kubeconfig. It is used to derive both its client
+	// certificate's CommonName and its secret name
+	// ("<virtualcluster-name>-<name>-config").
+	Name string `json:"name"`
+
+	// ClusterRole is the name of a ClusterRole already present in the
+	// tenant cluster that this kubeconfig's identity is bound to via a
+	// ClusterRoleBinding.
+	ClusterRole string `json:"clusterRole"`
+}
+
+// EtcdConfig overrides the PVC-backed storage used by a VirtualCluster's
+// etcd members, beyond the cluster-wide defaults in
+// KubeNestConfiguration.KubeInKubeConfig.
+type EtcdConfig struct {
+	// StorageClassName overrides the StorageClass of etcd's PVC for this
+	// VirtualCluster. Falls back to the kosmos operator's configured
+	// ETCDStorageClass default when unset.
+	// +optional
+	StorageClassName string `json:"storageClassName,omitempty"`
+
+	// StorageSize overrides the requested size of etcd's PVC for this
+	// VirtualCluster, as a resource.Quantity string (e.g. "10Gi"). Falls
+	// back to KubeInKubeConfig.ETCDUnitSize, then the kosmos operator's
+	// configured default, when unset.
+	// +optional
+	StorageSize string `json:"storageSize,omitempty"`
+
+	// RetainDataOnDelete keeps etcd's PVCs around after this VirtualCluster
+	// is deleted, instead of the default behavior of deleting them along
+	// with the rest of the control plane. Useful for recovering data or
+	// recreating the cluster from the same etcd state.
+	// +optional
+	RetainDataOnDelete bool `json:"retainDataOnDelete,omitempty"`
+}
+
+// ControlPlaneConfig overrides the default replica counts used when
+// provisioning a VirtualCluster's control plane components.
+type ControlPlaneConfig struct {
+	// APIServerReplicas overrides KubeInKubeConfig.APIServerReplicas for this
+	// VirtualCluster.
+	// +optional
+	APIServerReplicas int `json:"apiServerReplicas,omitempty"`
+
+	// ControllerManagerReplicas overrides the default kube-controller-manager
+	// replica count for this VirtualCluster.
+	// +optional
+	ControllerManagerReplicas int `json:"controllerManagerReplicas,omitempty"`
+
+	// EtcdReplicas overrides the default etcd replica count for this
+	// VirtualCluster. Must be an odd number >= 1, since etcd requires a
+	// majority quorum to make progress.
+	// +optional
+	EtcdReplicas int `json:"etcdReplicas,omitempty"`
+
+	// APIServerExtraArgs are additional flags merged into the apiserver
+	// container's command, for feature gates, audit configuration, admission
+	// plugins and the like that aren't otherwise exposed as their own
+	// KubeNestConfiguration field. Precedence, highest first: flags kosmos
+	// itself sets to provision the control plane (e.g. --etcd-servers,
+	// --secure-port) always win and silently ignore a conflicting entry
+	// here; everything else in APIServerExtraArgs is appended as given. A
+	// flag in util.DisallowedAPIServerExtraArgs is rejected outright rather
+	// than silently dropped, since overriding it wouldn't just be
+	// ineffective, it would break provisioning. Keys must not include the
+	// leading "--".
+	// +optional
+	APIServerExtraArgs map[string]string `json:"apiServerExtraArgs,omitempty"`
+
+	// ControllerManagerExtraArgs are additional flags merged into the
+	// kube-controller-manager container's command, following the same
+	// precedence rules as APIServerExtraArgs.
+	// +optional
+	ControllerManagerExtraArgs map[string]string `json:"controllerManagerExtraArgs,omitempty"`
+
+	// SchedulerExtraArgs are additional flags merged into the
+	// virtualcluster-scheduler container's command, following the same
+	// precedence rules as APIServerExtraArgs.
+	// +optional
+	SchedulerExtraArgs map[string]string `json:"schedulerExtraArgs,omitempty"`
 }
 
 // PluginSet specifies enabled and disabled plugins .
@@ -112,6 +388,87 @@ type PromotePolicy struct {
 	// NodeCount is the number of nodes to promote to the kubernetes's control plane
 	// +required
 	NodeCount int32 `json:"nodeCount"`
+
+	// Tolerations allows a node carrying a matching taint to still be
+	// eligible for promotion. A candidate GlobalNode carrying a taint that
+	// is not tolerated here is excluded from assignment even if it matches
+	// LabelSelector.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// AutoScale, when set, lets the node-autoscale controller adjust
+	// NodeCount on its own, between AutoScale.MinNodeCount and
+	// AutoScale.MaxNodeCount, in response to pending pods in the tenant
+	// cluster. When unset, NodeCount is a fixed target the way it always
+	// was.
+	// +optional
+	AutoScale *PromotePolicyAutoScale `json:"autoScale,omitempty"`
+
+	// DrainGracePeriodSeconds bounds how long a node being released on
+	// scale-down is given to cordon and gracefully evict its pods in the
+	// tenant cluster before the release proceeds anyway. Defaults to the
+	// same wait used by node unjoin when unset. A node that doesn't finish
+	// draining within this period is still released -- NodeController force
+	// removes it regardless -- but the failure is recorded in
+	// VirtualClusterStatus.Reason instead of being silently dropped.
+	// +optional
+	DrainGracePeriodSeconds *int32 `json:"drainGracePeriodSeconds,omitempty"`
+
+	// SelectionOrder controls the order free candidate GlobalNodes are
+	// considered in when this PromotePolicy's NodeCount grows, so which
+	// nodes get claimed is deterministic and reproducible across reconciles
+	// instead of depending on GlobalNode list order, which API pagination
+	// can reshuffle. Defaults to NameAsc when unset.
+	// +optional
+	SelectionOrder NodeSelectionOrder `json:"selectionOrder,omitempty"`
+
+	// RandomSeed seeds the shuffle SelectionOrder: Random uses, so the same
+	// seed and candidate set always produce the same order. Ignored for
+	// every other SelectionOrder. Defaults to 0 when unset.
+	// +optional
+	RandomSeed int64 `json:"randomSeed,omitempty"`
+}
+
+// NodeSelectionOrder is the order a PromotePolicy's free candidate
+// GlobalNodes are sorted into before claiming the first NodeCount of them.
+type NodeSelectionOrder string
+
+const (
+	// NodeSelectionOrderNameAsc selects nodes in ascending name order. This
+	// is the default when SelectionOrder is unset, since it's deterministic
+	// without depending on any other GlobalNode field.
+	NodeSelectionOrderNameAsc NodeSelectionOrder = "NameAsc"
+	// NodeSelectionOrderOldestFirst selects nodes oldest
+	// CreationTimestamp-first.
+	NodeSelectionOrderOldestFirst NodeSelectionOrder = "OldestFirst"
+	// NodeSelectionOrderNewestFirst selects nodes newest
+	// CreationTimestamp-first.
+	NodeSelectionOrderNewestFirst NodeSelectionOrder = "NewestFirst"
+	// NodeSelectionOrderRandom shuffles nodes using RandomSeed, so the order
+	// is stable for a fixed RandomSeed and candidate set but otherwise
+	// unpredictable.
+	NodeSelectionOrderRandom NodeSelectionOrder = "Random"
+)
+
+// PromotePolicyAutoScale bounds and tunes the node-autoscale controller's
+// adjustments to a single PromotePolicy's NodeCount.
+type PromotePolicyAutoScale struct {
+	// MinNodeCount is the floor the node-autoscale controller will not scale
+	// this policy's NodeCount below.
+	// +required
+	MinNodeCount int32 `json:"minNodeCount"`
+
+	// MaxNodeCount is the ceiling the node-autoscale controller will not
+	// scale this policy's NodeCount above.
+	// +required
+	MaxNodeCount int32 `json:"maxNodeCount"`
+
+	// PendingPodsWindow is how long pods must stay unschedulable due to
+	// insufficient nodes before the controller scales up by one node. Must
+	// be a positive duration, e.g. "5m". Defaults to
+	// constants.NodeAutoscaleDefaultPendingPodsWindow when unset.
+	// +optional
+	PendingPodsWindow string `json:"pendingPodsWindow,omitempty"`
 }
 
 type PromoteResources struct {
@@ -128,6 +485,41 @@ type NodeInfo struct {
 	//NodeName defines node name
 	//+optional
 	NodeName string `json:"nodeName,omitempty"`
+
+	// PromotePolicy records the LabelSelector of the PromotePolicy that
+	// claimed this node, so it keeps counting toward that policy even if the
+	// GlobalNode is later relabeled while in use. Only free nodes are
+	// matched against a policy's live LabelSelector; already-assigned nodes
+	// are matched back to their policy by this field instead. NodeInfos
+	// persisted before this field existed leave it nil, and fall back to
+	// being matched by live labels.
+	// +optional
+	PromotePolicy *metav1.LabelSelector `json:"promotePolicy,omitempty"`
+}
+
+// PromotePolicyStatus reports the outcome of assigning work nodes to a
+// single PromotePolicy, so a VirtualCluster with several PromotePolicies can
+// show which one is blocking node assignment instead of only exposing an
+// aggregate Phase/Reason for the whole cluster.
+type PromotePolicyStatus struct {
+	// Selector is PromotePolicy.LabelSelector formatted for display, e.g.
+	// "topology.kubernetes.io/zone=a".
+	// +optional
+	Selector string `json:"selector,omitempty"`
+
+	// Desired is PromotePolicy.NodeCount at the time this status was
+	// computed.
+	Desired int32 `json:"desired"`
+
+	// Assigned is how many nodes are currently assigned to this policy.
+	Assigned int32 `json:"assigned"`
+
+	// Ready is true once Assigned meets or exceeds Desired.
+	Ready bool `json:"ready"`
+
+	// Shortage is max(Desired-Assigned, 0), the number of additional nodes
+	// this policy still needs.
+	Shortage int32 `json:"shortage"`
 }
 
 type VirtualClusterStatus struct {
@@ -144,6 +536,92 @@ type VirtualClusterStatus struct {
 	PortMap map[string]int32 `json:"portMap,omitempty"`
 	// +optional
 	VipMap map[string]string `json:"vipMap,omitempty"`
+
+	// CreationStartTime is the time the VirtualCluster first entered the
+	// Preparing phase. It is reset whenever the VirtualCluster is recreated.
+	// +optional
+	CreationStartTime *metav1.Time `json:"creationStartTime,omitempty"`
+
+	// CreationDuration is the elapsed time between CreationStartTime and the
+	// VirtualCluster reaching the Completed phase, used for SLO tracking.
+	// +optional
+	CreationDuration string `json:"creationDuration,omitempty"`
+
+	// AdminKubeconfigTokenExpirationTimestamp is when the short-lived token
+	// currently embedded in the admin kubeconfig expires. Only set when
+	// KubeInKubeConfig.AdminKubeconfigTokenTTL is configured.
+	// +optional
+	AdminKubeconfigTokenExpirationTimestamp *metav1.Time `json:"adminKubeconfigTokenExpirationTimestamp,omitempty"`
+
+	// CertExpiry is the earliest NotAfter across this VirtualCluster's
+	// control-plane certificates, kept up to date by the cert-renewal
+	// controller.
+	// +optional
+	CertExpiry *metav1.Time `json:"certExpiry,omitempty"`
+
+	// EtcdHealthy reports whether every member of this VirtualCluster's etcd
+	// cluster answered its last health probe. Only set once the cluster has
+	// reached the Completed phase.
+	// +optional
+	EtcdHealthy *bool `json:"etcdHealthy,omitempty"`
+
+	// EtcdUnhealthyMembers lists the etcd members that failed their last
+	// health probe, if any.
+	// +optional
+	EtcdUnhealthyMembers []string `json:"etcdUnhealthyMembers,omitempty"`
+
+	// KubernetesVersion is the Kubernetes version resolved for this
+	// VirtualCluster's control plane (see util.ResolveKubernetesVersion),
+	// set before control-plane components are applied.
+	// +optional
+	KubernetesVersion string `json:"kubernetesVersion,omitempty"`
+
+	// PlannedNodes is the set of GlobalNodes that would be assigned to this
+	// VirtualCluster's PromotePolicies. Only populated when Spec.DryRun is
+	// true; no GlobalNode listed here is actually claimed.
+	// +optional
+	PlannedNodes []NodeInfo `json:"plannedNodes,omitempty"`
+
+	// FailureSince is when the VirtualCluster first entered the current run
+	// of the Pending phase. It is cleared as soon as the cluster reconciles
+	// successfully, and used to time how long a failure has persisted before
+	// raising the Degraded condition.
+	// +optional
+	FailureSince *metav1.Time `json:"failureSince,omitempty"`
+
+	// FailureCount is the number of consecutive createVirtualCluster/pod-
+	// readiness failures since the cluster last reconciled successfully. It
+	// drives the exponential reconcile backoff computed by
+	// reconcileBackoff, and is reset to 0 on the next successful reconcile.
+	// +optional
+	FailureCount int32 `json:"failureCount,omitempty"`
+
+	// LastFailureTime is when the most recent consecutive failure counted in
+	// FailureCount was recorded.
+	// +optional
+	LastFailureTime *metav1.Time `json:"lastFailureTime,omitempty"`
+
+	// Conditions is the set of observed status conditions for this
+	// VirtualCluster, e.g. DegradedConditionType.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+
+	// PromotePolicyStatuses reports the node-assignment outcome for each
+	// entry in Spec.PromotePolicies, in the same order, so a cluster with
+	// multiple policies shows exactly which one is short of nodes.
+	// +optional
+	PromotePolicyStatuses []PromotePolicyStatus `json:"promotePolicyStatuses,omitempty"`
+
+	// ObservedGeneration is the Generation most recently acted on by
+	// VirtualClusterInitController's Completed/WorkersScaledDown
+	// checkPromotePoliciesChanged evaluation. A Completed VirtualCluster
+	// whose ObservedGeneration already matches Generation has no spec change
+	// to react to, so the controller can skip re-listing GlobalNodes on a
+	// reconcile triggered only by a status write.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -84,6 +84,64 @@ func (in *Arp) DeepCopy() *Arp {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditConfig) DeepCopyInto(out *AuditConfig) {
+	*out = *in
+	if in.Log != nil {
+		in, out := &in.Log, &out.Log
+		*out = new(AuditLogConfig)
+		**out = **in
+	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(AuditWebhookConfig)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditConfig.
+func (in *AuditConfig) DeepCopy() *AuditConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditLogConfig) DeepCopyInto(out *AuditLogConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditLogConfig.
+func (in *AuditLogConfig) DeepCopy() *AuditLogConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditLogConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditWebhookConfig) DeepCopyInto(out *AuditWebhookConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditWebhookConfig.
+func (in *AuditWebhookConfig) DeepCopy() *AuditWebhookConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditWebhookConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Chart) DeepCopyInto(out *Chart) {
 	*out = *in
@@ -569,6 +627,75 @@ func (in *ClusterTreeStatus) DeepCopy() *ClusterTreeStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdditionalKubeconfig) DeepCopyInto(out *AdditionalKubeconfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdditionalKubeconfig.
+func (in *AdditionalKubeconfig) DeepCopy() *AdditionalKubeconfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AdditionalKubeconfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneConfig) DeepCopyInto(out *ControlPlaneConfig) {
+	*out = *in
+	if in.APIServerExtraArgs != nil {
+		in, out := &in.APIServerExtraArgs, &out.APIServerExtraArgs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ControllerManagerExtraArgs != nil {
+		in, out := &in.ControllerManagerExtraArgs, &out.ControllerManagerExtraArgs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SchedulerExtraArgs != nil {
+		in, out := &in.SchedulerExtraArgs, &out.SchedulerExtraArgs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneConfig.
+func (in *ControlPlaneConfig) DeepCopy() *ControlPlaneConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionLimitConfig) DeepCopyInto(out *ConnectionLimitConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionLimitConfig.
+func (in *ConnectionLimitConfig) DeepCopy() *ConnectionLimitConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionLimitConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Converters) DeepCopyInto(out *Converters) {
 	*out = *in
@@ -737,6 +864,29 @@ func (in *DaemonSetStatus) DeepCopy() *DaemonSetStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DefaultStorageClassConfig) DeepCopyInto(out *DefaultStorageClassConfig) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DefaultStorageClassConfig.
+func (in *DefaultStorageClassConfig) DeepCopy() *DefaultStorageClassConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DefaultStorageClassConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Device) DeepCopyInto(out *Device) {
 	*out = *in
@@ -859,6 +1009,38 @@ func (in *EtcdCluster) DeepCopy() *EtcdCluster {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdConfig) DeepCopyInto(out *EtcdConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdConfig.
+func (in *EtcdConfig) DeepCopy() *EtcdConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExternalControlPlane) DeepCopyInto(out *ExternalControlPlane) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalControlPlane.
+func (in *ExternalControlPlane) DeepCopy() *ExternalControlPlane {
+	if in == nil {
+		return nil
+	}
+	out := new(ExternalControlPlane)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Fdb) DeepCopyInto(out *Fdb) {
 	*out = *in
@@ -946,6 +1128,20 @@ func (in *GlobalNodeSpec) DeepCopyInto(out *GlobalNodeSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.Taints != nil {
+		in, out := &in.Taints, &out.Taints
+		*out = make([]v1.Taint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.KubeletExtraArgs != nil {
+		in, out := &in.KubeletExtraArgs, &out.KubeletExtraArgs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -962,6 +1158,10 @@ func (in *GlobalNodeSpec) DeepCopy() *GlobalNodeSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GlobalNodeStatus) DeepCopyInto(out *GlobalNodeStatus) {
 	*out = *in
+	if in.ClaimedAt != nil {
+		in, out := &in.ClaimedAt, &out.ClaimedAt
+		*out = (*in).DeepCopy()
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.NodeCondition, len(*in))
@@ -1056,7 +1256,42 @@ func (in *KosmosKubeConfig) DeepCopy() *KosmosKubeConfig {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubeInKubeConfig) DeepCopyInto(out *KubeInKubeConfig) {
 	*out = *in
+	if in.ConnectionLimitPerClient != nil {
+		in, out := &in.ConnectionLimitPerClient, &out.ConnectionLimitPerClient
+		*out = new(ConnectionLimitConfig)
+		**out = **in
+	}
+	if in.DefaultStorageClass != nil {
+		in, out := &in.DefaultStorageClass, &out.DefaultStorageClass
+		*out = new(DefaultStorageClassConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Audit != nil {
+		in, out := &in.Audit, &out.Audit
+		*out = new(AuditConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AuthenticationWebhook != nil {
+		in, out := &in.AuthenticationWebhook, &out.AuthenticationWebhook
+		*out = new(WebhookKubeconfigConfig)
+		**out = **in
+	}
+	if in.AuthorizationWebhook != nil {
+		in, out := &in.AuthorizationWebhook, &out.AuthorizationWebhook
+		*out = new(WebhookKubeconfigConfig)
+		**out = **in
+	}
 	in.TenantEntrypoint.DeepCopyInto(&out.TenantEntrypoint)
+	if in.CORSAllowedOrigins != nil {
+		in, out := &in.CORSAllowedOrigins, &out.CORSAllowedOrigins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodSecurityAdmission != nil {
+		in, out := &in.PodSecurityAdmission, &out.PodSecurityAdmission
+		*out = new(PodSecurityAdmissionConfig)
+		**out = **in
+	}
 	return
 }
 
@@ -1289,6 +1524,11 @@ func (in *NodeConfigStatus) DeepCopy() *NodeConfigStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeInfo) DeepCopyInto(out *NodeInfo) {
 	*out = *in
+	if in.PromotePolicy != nil {
+		in, out := &in.PromotePolicy, &out.PromotePolicy
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1525,6 +1765,22 @@ func (in *PodConvertPolicySpec) DeepCopy() *PodConvertPolicySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodSecurityAdmissionConfig) DeepCopyInto(out *PodSecurityAdmissionConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSecurityAdmissionConfig.
+func (in *PodSecurityAdmissionConfig) DeepCopy() *PodSecurityAdmissionConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PodSecurityAdmissionConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PolicyTerm) DeepCopyInto(out *PolicyTerm) {
 	*out = *in
@@ -1550,6 +1806,23 @@ func (in *PromotePolicy) DeepCopyInto(out *PromotePolicy) {
 		*out = new(metav1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]v1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AutoScale != nil {
+		in, out := &in.AutoScale, &out.AutoScale
+		*out = new(PromotePolicyAutoScale)
+		**out = **in
+	}
+	if in.DrainGracePeriodSeconds != nil {
+		in, out := &in.DrainGracePeriodSeconds, &out.DrainGracePeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
@@ -1563,13 +1836,47 @@ func (in *PromotePolicy) DeepCopy() *PromotePolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotePolicyAutoScale) DeepCopyInto(out *PromotePolicyAutoScale) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotePolicyAutoScale.
+func (in *PromotePolicyAutoScale) DeepCopy() *PromotePolicyAutoScale {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotePolicyAutoScale)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromotePolicyStatus) DeepCopyInto(out *PromotePolicyStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromotePolicyStatus.
+func (in *PromotePolicyStatus) DeepCopy() *PromotePolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PromotePolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PromoteResources) DeepCopyInto(out *PromoteResources) {
 	*out = *in
 	if in.NodeInfos != nil {
 		in, out := &in.NodeInfos, &out.NodeInfos
 		*out = make([]NodeInfo, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Resources != nil {
 		in, out := &in.Resources, &out.Resources
@@ -1970,6 +2277,11 @@ func (in *VirtualClusterSpec) DeepCopyInto(out *VirtualClusterSpec) {
 		*out = new(KubeInKubeConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ControlPlaneConfig != nil {
+		in, out := &in.ControlPlaneConfig, &out.ControlPlaneConfig
+		*out = new(ControlPlaneConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.PromotePolicies != nil {
 		in, out := &in.PromotePolicies, &out.PromotePolicies
 		*out = make([]PromotePolicy, len(*in))
@@ -1984,6 +2296,47 @@ func (in *VirtualClusterSpec) DeepCopyInto(out *VirtualClusterSpec) {
 		*out = make([]PluginOptions, len(*in))
 		copy(*out, *in)
 	}
+	if in.ReadinessTimeoutSeconds != nil {
+		in, out := &in.ReadinessTimeoutSeconds, &out.ReadinessTimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ComponentImageOverrides != nil {
+		in, out := &in.ComponentImageOverrides, &out.ComponentImageOverrides
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ResourceLabels != nil {
+		in, out := &in.ResourceLabels, &out.ResourceLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ResourceAnnotations != nil {
+		in, out := &in.ResourceAnnotations, &out.ResourceAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Etcd != nil {
+		in, out := &in.Etcd, &out.Etcd
+		*out = new(EtcdConfig)
+		**out = **in
+	}
+	if in.AdditionalKubeconfigs != nil {
+		in, out := &in.AdditionalKubeconfigs, &out.AdditionalKubeconfigs
+		*out = make([]AdditionalKubeconfig, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExternalControlPlane != nil {
+		in, out := &in.ExternalControlPlane, &out.ExternalControlPlane
+		*out = new(ExternalControlPlane)
+		**out = **in
+	}
 	return
 }
 
@@ -2018,6 +2371,55 @@ func (in *VirtualClusterStatus) DeepCopyInto(out *VirtualClusterStatus) {
 			(*out)[key] = val
 		}
 	}
+	if in.CreationStartTime != nil {
+		in, out := &in.CreationStartTime, &out.CreationStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.AdminKubeconfigTokenExpirationTimestamp != nil {
+		in, out := &in.AdminKubeconfigTokenExpirationTimestamp, &out.AdminKubeconfigTokenExpirationTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.CertExpiry != nil {
+		in, out := &in.CertExpiry, &out.CertExpiry
+		*out = (*in).DeepCopy()
+	}
+	if in.EtcdHealthy != nil {
+		in, out := &in.EtcdHealthy, &out.EtcdHealthy
+		*out = new(bool)
+		**out = **in
+	}
+	if in.EtcdUnhealthyMembers != nil {
+		in, out := &in.EtcdUnhealthyMembers, &out.EtcdUnhealthyMembers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PlannedNodes != nil {
+		in, out := &in.PlannedNodes, &out.PlannedNodes
+		*out = make([]NodeInfo, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.FailureSince != nil {
+		in, out := &in.FailureSince, &out.FailureSince
+		*out = (*in).DeepCopy()
+	}
+	if in.LastFailureTime != nil {
+		in, out := &in.LastFailureTime, &out.LastFailureTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PromotePolicyStatuses != nil {
+		in, out := &in.PromotePolicyStatuses, &out.PromotePolicyStatuses
+		*out = make([]PromotePolicyStatus, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -2047,6 +2449,22 @@ func (in *VxlanCIDRs) DeepCopy() *VxlanCIDRs {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookKubeconfigConfig) DeepCopyInto(out *WebhookKubeconfigConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookKubeconfigConfig.
+func (in *WebhookKubeconfigConfig) DeepCopy() *WebhookKubeconfigConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookKubeconfigConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Yaml) DeepCopyInto(out *Yaml) {
 	*out = *in
@@ -22,6 +22,7 @@ import (
 	"github.com/kosmos.io/kosmos/pkg/kosmosctl/rsmigrate"
 	"github.com/kosmos.io/kosmos/pkg/kosmosctl/uninstall"
 	"github.com/kosmos.io/kosmos/pkg/kosmosctl/unjoin"
+	"github.com/kosmos.io/kosmos/pkg/kosmosctl/virtualcluster"
 )
 
 // DefaultConfigFlags It composes the set of values necessary for obtaining a REST client config with default values set.
@@ -90,6 +91,12 @@ func NewKosmosCtlCommand() *cobra.Command {
 				image.NewCmdImage(),
 			},
 		},
+		{
+			Message: "Virtual Cluster Commands:",
+			Commands: []*cobra.Command{
+				virtualcluster.NewCmdVirtualCluster(f, ioStreams),
+			},
+		},
 	}
 	groups.Add(cmds)
 
@@ -0,0 +1,144 @@
+package virtualcluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	ctlutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kosmos.io/kosmos/pkg/generated/clientset/versioned"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/controller"
+	"github.com/kosmos.io/kosmos/pkg/scheme"
+)
+
+var RebalanceExample = templates.Examples(i18n.T(`
+		# Preview which free nodes a rebalance would claim, e.g:
+		kosmosctl virtualcluster rebalance kosmos-system/vc-demo
+
+		# Apply the proposed rebalance, e.g:
+		kosmosctl virtualcluster rebalance kosmos-system/vc-demo --confirm
+`))
+
+// CommandRebalanceOptions holds the options for 'virtualcluster rebalance' sub command.
+type CommandRebalanceOptions struct {
+	Namespace string
+	Name      string
+	Confirm   bool
+
+	Streams genericclioptions.IOStreams
+
+	Client       client.Client
+	KosmosClient versioned.Interface
+}
+
+// NewCmdRebalance re-runs the assignment delta computation for an existing
+// VirtualCluster and, with --confirm, claims the nodes it proposes. It never
+// proposes releasing a node already claimed, so it is safe to run against a
+// VirtualCluster with running workloads.
+func NewCmdRebalance(f ctlutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	o := &CommandRebalanceOptions{Streams: streams}
+	cmd := &cobra.Command{
+		Use:                   "rebalance <namespace>/<name>",
+		Short:                 i18n.T("Claim additional free nodes for a VirtualCluster without disrupting nodes it already holds. "),
+		Long:                  "",
+		Example:               RebalanceExample,
+		SilenceUsage:          true,
+		DisableFlagsInUseLine: true,
+		Args:                  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctlutil.CheckErr(o.Complete(f, args))
+			ctlutil.CheckErr(o.Validate())
+			ctlutil.CheckErr(o.Run())
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&o.Confirm, "confirm", false, "Apply the proposed rebalance instead of only previewing it. ")
+	return cmd
+}
+
+// Complete fills in o.Namespace, o.Name, and the clients needed by Run.
+func (o *CommandRebalanceOptions) Complete(f ctlutil.Factory, args []string) error {
+	namespace, name, found := strings.Cut(args[0], "/")
+	if !found {
+		return fmt.Errorf("invalid target %q, expected <namespace>/<name>", args[0])
+	}
+	o.Namespace, o.Name = namespace, name
+
+	restConfig, err := f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	o.KosmosClient, err = versioned.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	o.Client, err = client.New(restConfig, client.Options{Scheme: scheme.NewSchema()})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Validate checks that the options are valid.
+func (o *CommandRebalanceOptions) Validate() error {
+	if o.Name == "" {
+		return fmt.Errorf("virtualcluster name must not be empty")
+	}
+	return nil
+}
+
+// Run plans, prints, and (with --confirm) applies the rebalance.
+func (o *CommandRebalanceOptions) Run() error {
+	c := &controller.VirtualClusterInitController{
+		Client:       o.Client,
+		KosmosClient: o.KosmosClient,
+	}
+
+	virtualCluster, err := o.KosmosClient.KosmosV1alpha1().VirtualClusters(o.Namespace).Get(context.TODO(), o.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("get virtualcluster %s/%s: %w", o.Namespace, o.Name, err)
+	}
+
+	rebalance, err := c.PlanRebalance(context.TODO(), virtualCluster)
+	if err != nil {
+		return fmt.Errorf("plan rebalance for %s/%s: %w", o.Namespace, o.Name, err)
+	}
+	o.printProposed(rebalance)
+
+	if len(rebalance.Proposed) == 0 {
+		return nil
+	}
+	if !o.Confirm {
+		fmt.Fprintln(o.Streams.Out, "Re-run with --confirm to claim the nodes above. ")
+		return nil
+	}
+
+	if err := c.ApplyRebalance(context.TODO(), virtualCluster, rebalance); err != nil {
+		return fmt.Errorf("apply rebalance for %s/%s: %w", o.Namespace, o.Name, err)
+	}
+	fmt.Fprintf(o.Streams.Out, "Claimed %d node(s) for %s/%s.\n", len(rebalance.Proposed), o.Namespace, o.Name)
+	return nil
+}
+
+func (o *CommandRebalanceOptions) printProposed(rebalance *controller.Rebalance) {
+	if len(rebalance.Proposed) == 0 {
+		fmt.Fprintf(o.Streams.Out, "%s/%s is already balanced, no free node matches an unsatisfied PromotePolicy.\n", o.Namespace, o.Name)
+		return
+	}
+
+	w := printers.GetNewTabWriter(o.Streams.Out)
+	defer w.Flush()
+	fmt.Fprintln(w, "NODE")
+	for _, nodeInfo := range rebalance.Proposed {
+		fmt.Fprintln(w, nodeInfo.NodeName)
+	}
+}
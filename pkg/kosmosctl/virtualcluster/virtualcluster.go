@@ -0,0 +1,19 @@
+package virtualcluster
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	ctlutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/i18n"
+)
+
+// NewCmdVirtualCluster groups day-2 operator commands for kosmos VirtualClusters.
+func NewCmdVirtualCluster(f ctlutil.Factory, streams genericclioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "virtualcluster",
+		Short: i18n.T("Manage the lifecycle of kosmos VirtualClusters. "),
+	}
+
+	cmd.AddCommand(NewCmdRebalance(f, streams))
+	return cmd
+}
@@ -27,6 +27,148 @@ const (
 	Label                            = "virtualCluster-app"
 	ComponentBeReadyTimeout          = 300 * time.Second
 	ComponentBeDeletedTimeout        = 300 * time.Second
+	// NodeJoinReadinessCheckTimeout bounds how long the node controller
+	// probes the virtual apiserver before giving up on a join attempt, so a
+	// control plane that isn't ready yet fails the probe quickly instead of
+	// letting kubelets register against it and fail.
+	NodeJoinReadinessCheckTimeout = 10 * time.Second
+	AdminKubeconfigControllerName = "admin-kubeconfig-controller"
+	// AdminKubeconfigTokenSAName is the tenant-side ServiceAccount the
+	// admin-kubeconfig controller mints short-lived tokens for when a
+	// VirtualCluster opts into token-based admin kubeconfigs.
+	AdminKubeconfigTokenSAName = "kosmos-admin"
+	// AdminKubeconfigTokenRefreshBuffer is how long before expiry the
+	// admin-kubeconfig controller re-issues the token.
+	AdminKubeconfigTokenRefreshBuffer = 5 * time.Minute
+
+	BootstrapTokenGCControllerName = "bootstrap-token-gc-controller"
+	// BootstrapTokenVirtualClusterLabelKey marks a host-cluster bootstrap-token
+	// Secret with the VirtualCluster it was minted for, so the GC controller
+	// can find and clean up a cluster's tokens.
+	BootstrapTokenVirtualClusterLabelKey = "kosmos.io/virtualcluster"
+	// BootstrapTokenGCInterval is how often the GC controller re-checks a
+	// VirtualCluster's bootstrap-token secrets for expiry.
+	BootstrapTokenGCInterval = 10 * time.Minute
+
+	NodeAutoscaleControllerName = "node-autoscale-controller"
+	// NodeAutoscaleCheckInterval is how often the node-autoscale controller
+	// re-evaluates a Completed VirtualCluster's pending pods.
+	NodeAutoscaleCheckInterval = 1 * time.Minute
+	// NodeAutoscaleDefaultPendingPodsWindow is how long a pod must stay
+	// unschedulable due to insufficient nodes before the node-autoscale
+	// controller scales up, when a PromotePolicy doesn't set its own
+	// AutoScale.PendingPodsWindow.
+	NodeAutoscaleDefaultPendingPodsWindow = 5 * time.Minute
+	// NodeAutoscaleCooldown is how long the node-autoscale controller waits
+	// after scaling a VirtualCluster up before it will scale the same
+	// VirtualCluster back down, so a newly-added node gets a chance to pick
+	// up pending pods before utilization is re-evaluated, instead of the
+	// controller immediately reversing itself on the next reconcile.
+	NodeAutoscaleCooldown = 5 * time.Minute
+	// NodeAutoscaleLastScaleUpAnnotation records the RFC3339 timestamp of the
+	// node-autoscale controller's most recent scale-up of a VirtualCluster,
+	// for enforcing NodeAutoscaleCooldown across reconciles.
+	NodeAutoscaleLastScaleUpAnnotation = "kosmos.io/node-autoscale-last-scale-up"
+
+	EtcdHealthControllerName = "etcd-health-controller"
+	// EtcdHealthCheckInterval is how often the etcd-health controller probes
+	// a Completed VirtualCluster's etcd cluster for lost members.
+	EtcdHealthCheckInterval = 2 * time.Minute
+	// EtcdClientDialTimeout bounds how long the etcd-health controller waits
+	// to establish a connection before declaring a member unreachable.
+	EtcdClientDialTimeout = 5 * time.Second
+	// EtcdQuorumWaitTimeout bounds how long the wait-etcd-quorum task polls
+	// the etcd cluster for a quorum of started members before failing the
+	// VirtualCluster's init workflow.
+	EtcdQuorumWaitTimeout = 5 * time.Minute
+	// EtcdQuorumPollInterval is how often the wait-etcd-quorum task re-lists
+	// etcd members while waiting for quorum.
+	EtcdQuorumPollInterval = 5 * time.Second
+
+	// AdminConfigSecretPollTimeout bounds how long createVirtualCluster polls
+	// for the admin-config secret to appear right after executer.Execute()
+	// returns, tolerating the secret not yet being visible in the API
+	// server's cache instead of failing the whole create outright.
+	AdminConfigSecretPollTimeout = 30 * time.Second
+	// AdminConfigSecretPollInterval is how often createVirtualCluster
+	// re-checks for the admin-config secret while within
+	// AdminConfigSecretPollTimeout.
+	AdminConfigSecretPollInterval = 2 * time.Second
+
+	// VirtualClusterDegradedThreshold is how long a VirtualCluster can stay
+	// stuck in the Pending phase before the init controller raises its
+	// Degraded status condition, when KubeInKubeConfig.ReconcileFailureThreshold
+	// doesn't set its own.
+	VirtualClusterDegradedThreshold = 15 * time.Minute
+
+	// StuckPhaseTimeout bounds how long a VirtualCluster may sit in the
+	// Preparing or Pending phase without a status update before the init
+	// controller treats it as stuck - most commonly the signature of a
+	// restart landing between the two status writes that normally carry a
+	// cluster through creation - and re-drives it.
+	StuckPhaseTimeout = 10 * time.Minute
+	// MaxStuckPhaseRedriveAttempts caps how many times the init controller
+	// will re-drive a stuck VirtualCluster (tracked via Status.FailureCount)
+	// before giving up and leaving it for an operator to investigate, so a
+	// permanently broken cluster doesn't re-drive forever.
+	MaxStuckPhaseRedriveAttempts = 10
+
+	VirtualClusterTTLControllerName = "virtual-cluster-ttl-controller"
+	// VirtualClusterTTLCheckInterval is how often the TTL controller
+	// re-checks a VirtualCluster that has a Spec.TTL set but hasn't expired
+	// or entered its expiry warning window yet.
+	VirtualClusterTTLCheckInterval = 5 * time.Minute
+	// VirtualClusterTTLExpiryWarningWindow is how long before a
+	// VirtualCluster's TTL deadline the TTL controller starts emitting a
+	// warning event on every reconcile.
+	VirtualClusterTTLExpiryWarningWindow = 1 * time.Hour
+
+	OrphanedNodeControllerName = "orphaned-node-controller"
+	// OrphanedNodeReclaimCheckInterval is how often the orphaned-node
+	// controller re-checks an in-use GlobalNode whose owning VirtualCluster
+	// has disappeared.
+	OrphanedNodeReclaimCheckInterval = 2 * time.Minute
+	// OrphanedNodeReclaimGracePeriod is how long a GlobalNode must have been
+	// claimed before the orphaned-node controller will reclaim it over a
+	// missing VirtualCluster, so a cluster that is still being created -
+	// whose VirtualCluster object hasn't been created yet, or isn't visible
+	// in this reconcile's cache - doesn't have its nodes pulled out from
+	// under it.
+	OrphanedNodeReclaimGracePeriod = 10 * time.Minute
+
+	CertRenewalControllerName = "cert-renewal-controller"
+	// CertRenewalWindow is how long before a control-plane certificate's
+	// expiry the cert-renewal controller regenerates it.
+	CertRenewalWindow = 30 * 24 * time.Hour
+	// CertRenewalCheckInterval is how often the cert-renewal controller
+	// re-checks a Completed VirtualCluster's certificates once none of them
+	// are within CertRenewalWindow of expiring.
+	CertRenewalCheckInterval = 1 * time.Hour
+	// CertRenewedAtAnnotation is bumped on a control-plane workload's pod
+	// template whenever the cert-renewal controller regenerates a
+	// certificate it consumes, to trigger a rollout that picks up the new
+	// cert.
+	CertRenewedAtAnnotation = "kosmos.io/cert-renewed-at"
+
+	// PausedAnnotation stops the init controller from making any further
+	// changes to a VirtualCluster when set to "true", same as
+	// Spec.Paused but settable without a spec update. Takes precedence over
+	// Spec.Paused if both are set.
+	PausedAnnotation = "kosmos.io/paused"
+
+	// ReadinessSkipNamespacesAnnotation holds a comma-separated list of tenant
+	// namespaces ensureAllPodsRunning excludes entirely from its readiness
+	// poll, for namespaces that run add-ons never expected to reach full
+	// availability.
+	ReadinessSkipNamespacesAnnotation = "kosmos.io/readiness-skip-namespaces"
+	// ReadinessSkipWorkloadsAnnotation holds a comma-separated list of
+	// "Kind/namespace/name" entries (Kind one of Deployment, StatefulSet,
+	// DaemonSet) ensureAllPodsRunning excludes from its readiness poll, for
+	// individual workloads that never reach full replicas by design.
+	ReadinessSkipWorkloadsAnnotation = "kosmos.io/readiness-skip-workloads"
+	// ReadinessNamespaceConcurrency bounds how many namespaces
+	// ensureAllPodsRunning polls for readiness at once.
+	ReadinessNamespaceConcurrency = 8
 
 	// CertificateBlockType is a possible value for pem.Block.Type.
 	CertificateBlockType           = "CERTIFICATE"
@@ -65,6 +207,9 @@ const (
 	EtcdListenClientPort = 2379
 	EtcdListenPeerPort   = 2380
 	EtcdSuffix           = "-etcd-client"
+	// EtcdDefaultSnapshotCount is used for --snapshot-count when
+	// KubeInKubeConfig.EtcdSnapshotCount is left unset.
+	EtcdDefaultSnapshotCount = 10000
 
 	//controlplane kube-controller
 	KubeControllerReplicas           = 2
@@ -105,6 +250,12 @@ const (
 	APIServerNetworkProxyAdminPortKey  = "apiserver-network-proxy-admin-port"
 	VirtualClusterPortNum              = 5
 
+	// namespace node quota: a ConfigMap in KosmosNs whose Data is keyed by the
+	// tenant namespace name, each value the maximum total GlobalNodes that
+	// namespace's VirtualClusters may collectively claim. A namespace with no
+	// entry is unquota'd, preserving today's behavior.
+	NamespaceNodeQuotaConfigMapName = "kosmos-namespace-node-quota"
+
 	// vip
 	VipPoolConfigMapName        = "kosmos-vip-pool"
 	VipPoolKey                  = "vip-config.yaml"
@@ -135,6 +286,10 @@ const (
 
 	//in virtual cluster
 	APIServerExternalService = "api-server-external-service"
+
+	// NodeNotReadyTaintKey marks a newly-promoted node as unschedulable until its
+	// required add-ons report ready, preventing premature pod scheduling.
+	NodeNotReadyTaintKey = "node.kosmos.io/not-ready"
 )
 
 type Action string
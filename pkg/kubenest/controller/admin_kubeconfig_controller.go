@@ -0,0 +1,185 @@
+package controller
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/constants"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/util"
+	"github.com/kosmos.io/kosmos/pkg/utils"
+)
+
+// AdminKubeconfigController keeps a VirtualCluster's admin kubeconfig on a
+// short-lived token instead of a long-lived client certificate, for clusters
+// that opt in via KubeInKubeConfig.AdminKubeconfigTokenTTL. It mints a new
+// token for the tenant-side kosmos-admin ServiceAccount before the current
+// one expires and stores it back onto the VirtualCluster.
+type AdminKubeconfigController struct {
+	client.Client
+	// GenerateTenantClient builds a client for the virtual cluster's own
+	// apiserver from its current admin kubeconfig. Defaults to
+	// util.GenerateKubeclient; overridable in tests.
+	GenerateTenantClient func(*v1alpha1.VirtualCluster) (kubernetes.Interface, error)
+}
+
+func (r *AdminKubeconfigController) SetupWithManager(mgr manager.Manager) error {
+	if r.Client == nil {
+		r.Client = mgr.GetClient()
+	}
+	if r.GenerateTenantClient == nil {
+		r.GenerateTenantClient = util.GenerateKubeclient
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(constants.AdminKubeconfigControllerName).
+		For(&v1alpha1.VirtualCluster{}).
+		Complete(r)
+}
+
+func (r *AdminKubeconfigController) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	var vc v1alpha1.VirtualCluster
+	if err := r.Get(ctx, request.NamespacedName, &vc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if vc.Spec.KubeInKubeConfig == nil || vc.Spec.KubeInKubeConfig.AdminKubeconfigTokenTTL == "" {
+		return reconcile.Result{}, nil
+	}
+	if vc.Status.Phase != v1alpha1.Completed && vc.Status.Phase != v1alpha1.WorkersScaledDown {
+		return reconcile.Result{}, nil
+	}
+
+	ttl, err := time.ParseDuration(vc.Spec.KubeInKubeConfig.AdminKubeconfigTokenTTL)
+	if err != nil {
+		klog.Errorf("admin-kubeconfig-controller: invalid adminKubeconfigTokenTTL for %s: %v", request.NamespacedName, err)
+		return reconcile.Result{}, nil
+	}
+
+	if !shouldRefreshAdminKubeconfigToken(vc.Status.AdminKubeconfigTokenExpirationTimestamp, time.Now()) {
+		return reconcile.Result{RequeueAfter: time.Until(vc.Status.AdminKubeconfigTokenExpirationTimestamp.Add(-constants.AdminKubeconfigTokenRefreshBuffer))}, nil
+	}
+
+	if err := r.refreshAdminKubeconfigToken(ctx, request.NamespacedName, ttl); err != nil {
+		klog.Errorf("admin-kubeconfig-controller: refresh admin kubeconfig token for %s error: %v", request.NamespacedName, err)
+		return reconcile.Result{RequeueAfter: constants.AdminKubeconfigTokenRefreshBuffer}, nil
+	}
+
+	return reconcile.Result{RequeueAfter: ttl - constants.AdminKubeconfigTokenRefreshBuffer}, nil
+}
+
+// shouldRefreshAdminKubeconfigToken reports whether a new token must be
+// minted: true when no token has been issued yet, or the current one is
+// within AdminKubeconfigTokenRefreshBuffer of expiring.
+func shouldRefreshAdminKubeconfigToken(expiresAt *metav1.Time, now time.Time) bool {
+	if expiresAt == nil {
+		return true
+	}
+	return !now.Before(expiresAt.Add(-constants.AdminKubeconfigTokenRefreshBuffer))
+}
+
+func (r *AdminKubeconfigController) refreshAdminKubeconfigToken(ctx context.Context, name types.NamespacedName, ttl time.Duration) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var vc v1alpha1.VirtualCluster
+		if err := r.Get(ctx, name, &vc); err != nil {
+			return err
+		}
+
+		tenantClient, err := r.GenerateTenantClient(&vc)
+		if err != nil {
+			return fmt.Errorf("generate tenant kubeclient: %w", err)
+		}
+
+		if err := ensureAdminTokenServiceAccount(tenantClient); err != nil {
+			return fmt.Errorf("ensure admin token serviceaccount: %w", err)
+		}
+
+		restConfig, err := tenantRestConfig(vc.Spec.Kubeconfig)
+		if err != nil {
+			return fmt.Errorf("parse current admin kubeconfig: %w", err)
+		}
+
+		expirationSeconds := int64(ttl.Seconds())
+		tokenRequest, err := tenantClient.CoreV1().ServiceAccounts(constants.SystemNs).CreateToken(ctx, constants.AdminKubeconfigTokenSAName, &authenticationv1.TokenRequest{
+			Spec: authenticationv1.TokenRequestSpec{ExpirationSeconds: &expirationSeconds},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("create token for %s/%s: %w", constants.SystemNs, constants.AdminKubeconfigTokenSAName, err)
+		}
+
+		tokenConfig := util.CreateWithToken(restConfig.Host, constants.ClusterName, constants.UserName, restConfig.CAData, tokenRequest.Status.Token)
+		tokenConfigBytes, err := clientcmd.Write(*tokenConfig)
+		if err != nil {
+			return fmt.Errorf("marshal token kubeconfig: %w", err)
+		}
+
+		vc.Spec.Kubeconfig = base64.StdEncoding.EncodeToString(tokenConfigBytes)
+		vc.Status.AdminKubeconfigTokenExpirationTimestamp = &tokenRequest.Status.ExpirationTimestamp
+		return r.Update(ctx, &vc)
+	})
+}
+
+// ensureAdminTokenServiceAccount makes sure the tenant cluster has a
+// cluster-admin bound ServiceAccount to mint the admin kubeconfig's
+// short-lived tokens from.
+func ensureAdminTokenServiceAccount(tenantClient kubernetes.Interface) error {
+	sa := &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      constants.AdminKubeconfigTokenSAName,
+			Namespace: constants.SystemNs,
+		},
+	}
+	if err := util.CreateOrUpdateServiceAccount(tenantClient, sa); err != nil {
+		return err
+	}
+
+	binding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: constants.AdminKubeconfigTokenSAName,
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     "cluster-admin",
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      constants.AdminKubeconfigTokenSAName,
+				Namespace: constants.SystemNs,
+			},
+		},
+	}
+	return util.CreateOrUpdateClusterRoleBinding(tenantClient, binding)
+}
+
+// tenantRestConfig parses a base64-encoded kubeconfig and returns its
+// effective rest.Config, used to recover the tenant apiserver's address and
+// CA certificate when minting a token-based replacement.
+func tenantRestConfig(base64Kubeconfig string) (*rest.Config, error) {
+	kubeconfigStream, err := base64.StdEncoding.DecodeString(base64Kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	return utils.NewConfigFromBytes(kubeconfigStream)
+}
@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/constants"
+)
+
+// vcStoreClient stubs the client.Client Get/Update methods AdminKubeconfigController needs.
+type vcStoreClient struct {
+	client.Client
+	vc *v1alpha1.VirtualCluster
+}
+
+func (c *vcStoreClient) Get(_ context.Context, _ types.NamespacedName, obj client.Object, _ ...client.GetOption) error {
+	vc, ok := obj.(*v1alpha1.VirtualCluster)
+	if !ok {
+		return fmt.Errorf("unexpected object type %T", obj)
+	}
+	*vc = *c.vc.DeepCopy()
+	return nil
+}
+
+func (c *vcStoreClient) Update(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+	vc, ok := obj.(*v1alpha1.VirtualCluster)
+	if !ok {
+		return fmt.Errorf("unexpected object type %T", obj)
+	}
+	c.vc = vc.DeepCopy()
+	return nil
+}
+
+func TestShouldRefreshAdminKubeconfigToken(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name      string
+		expiresAt *metav1.Time
+		want      bool
+	}{
+		{name: "no token issued yet", expiresAt: nil, want: true},
+		{name: "far from expiry", expiresAt: timePtr(now.Add(time.Hour)), want: false},
+		{name: "within refresh buffer", expiresAt: timePtr(now.Add(time.Minute)), want: true},
+		{name: "already expired", expiresAt: timePtr(now.Add(-time.Minute)), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shouldRefreshAdminKubeconfigToken(tt.expiresAt, now); got != tt.want {
+				t.Errorf("shouldRefreshAdminKubeconfigToken() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func timePtr(t time.Time) *metav1.Time {
+	mt := metav1.NewTime(t)
+	return &mt
+}
+
+func TestRefreshAdminKubeconfigTokenUsesToken(t *testing.T) {
+	certKubeconfig := clientcmdapi.Config{
+		Clusters: map[string]*clientcmdapi.Cluster{
+			"test": {Server: "https://10.0.0.1:6443", CertificateAuthorityData: []byte("ca-data")},
+		},
+		Contexts:       map[string]*clientcmdapi.Context{"test": {Cluster: "test", AuthInfo: constants.UserName}},
+		AuthInfos:      map[string]*clientcmdapi.AuthInfo{constants.UserName: {ClientCertificateData: []byte("cert"), ClientKeyData: []byte("key")}},
+		CurrentContext: "test",
+	}
+	certKubeconfigBytes, err := clientcmd.Write(certKubeconfig)
+	if err != nil {
+		t.Fatalf("failed to write cert kubeconfig fixture: %s", err)
+	}
+
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1", Namespace: "default"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			Kubeconfig:       base64.StdEncoding.EncodeToString(certKubeconfigBytes),
+			KubeInKubeConfig: &v1alpha1.KubeInKubeConfig{AdminKubeconfigTokenTTL: "1h"},
+		},
+		Status: v1alpha1.VirtualClusterStatus{Phase: v1alpha1.Completed},
+	}
+
+	tenantClient := fake.NewSimpleClientset()
+	tenantClient.PrependReactor("create", "serviceaccounts", func(action ktesting.Action) (bool, runtime.Object, error) {
+		createAction, ok := action.(ktesting.CreateActionImpl)
+		if !ok || createAction.GetSubresource() != "token" {
+			return false, nil, nil
+		}
+		tokenRequest := createAction.GetObject().(*authenticationv1.TokenRequest).DeepCopy()
+		tokenRequest.Status = authenticationv1.TokenRequestStatus{
+			Token:               "minted-token",
+			ExpirationTimestamp: metav1.NewTime(time.Now().Add(time.Hour)),
+		}
+		return true, tokenRequest, nil
+	})
+
+	store := &vcStoreClient{vc: vc}
+	controller := &AdminKubeconfigController{
+		Client: store,
+		GenerateTenantClient: func(*v1alpha1.VirtualCluster) (kubernetes.Interface, error) {
+			return tenantClient, nil
+		},
+	}
+
+	if err := controller.refreshAdminKubeconfigToken(context.TODO(), types.NamespacedName{Name: "vc1", Namespace: "default"}, time.Hour); err != nil {
+		t.Fatalf("refreshAdminKubeconfigToken() failed: %s", err)
+	}
+
+	updatedBytes, err := base64.StdEncoding.DecodeString(store.vc.Spec.Kubeconfig)
+	if err != nil {
+		t.Fatalf("updated kubeconfig is not valid base64: %s", err)
+	}
+	updatedConfig, err := clientcmd.Load(updatedBytes)
+	if err != nil {
+		t.Fatalf("updated kubeconfig failed to parse: %s", err)
+	}
+
+	authInfo := updatedConfig.AuthInfos[constants.UserName]
+	if authInfo == nil {
+		t.Fatalf("expected authinfo %s in updated kubeconfig", constants.UserName)
+	}
+	if authInfo.Token != "minted-token" {
+		t.Errorf("expected updated kubeconfig to use the minted token, got token %q", authInfo.Token)
+	}
+	if authInfo.ClientCertificateData != nil {
+		t.Errorf("expected token-based kubeconfig to drop the client certificate")
+	}
+	if store.vc.Status.AdminKubeconfigTokenExpirationTimestamp == nil {
+		t.Fatalf("expected AdminKubeconfigTokenExpirationTimestamp to be set")
+	}
+
+	// Approaching expiry should trigger another refresh.
+	if !shouldRefreshAdminKubeconfigToken(store.vc.Status.AdminKubeconfigTokenExpirationTimestamp, time.Now().Add(55*time.Minute)) {
+		t.Errorf("expected a token nearing expiry to be due for refresh")
+	}
+}
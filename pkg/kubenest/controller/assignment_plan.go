@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+)
+
+// PolicyAssignmentPlan describes the claims and releases a single
+// PromotePolicy would make if its VirtualCluster were applied against a
+// GlobalNode snapshot, and how many nodes it would still be short after
+// those claims.
+type PolicyAssignmentPlan struct {
+	// LabelSelector identifies the PromotePolicy this plan is for.
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+	// Claims lists the GlobalNodes that would newly be assigned to the
+	// virtual cluster for this policy.
+	Claims []string `json:"claims,omitempty"`
+	// Releases lists the currently-assigned GlobalNodes that would be freed
+	// for this policy.
+	Releases []string `json:"releases,omitempty"`
+	// Shortage is how many nodes this policy would still be missing after
+	// Claims, when there are not enough free matching GlobalNodes to satisfy
+	// NodeCount.
+	Shortage int32 `json:"shortage,omitempty"`
+}
+
+// AssignmentPlan is the structured, side-effect-free description of the
+// node claims and releases BuildAssignmentPlan computed for a
+// VirtualCluster's PromotePolicies.
+type AssignmentPlan struct {
+	Policies []PolicyAssignmentPlan `json:"policies,omitempty"`
+}
+
+// BuildAssignmentPlan computes, without mutating virtualCluster or
+// globalNodes, which GlobalNodes would be claimed or released for each of
+// virtualCluster's PromotePolicies against the given GlobalNode snapshot.
+// It applies the same label selector, tolerations and per-policy node count
+// as VirtualClusterInitController.assignWorkNodes, but claims free nodes in
+// name order and releases the last assigned nodes by name; it does not apply
+// the controller's NodeSelectionStrategy, preemption, node-readiness
+// filtering or utilization-based release, so a real apply can pick different
+// nodes. It performs no writes - callers such as a dry-run command or
+// approval tooling can review the plan before anything is actually changed.
+func BuildAssignmentPlan(virtualCluster *v1alpha1.VirtualCluster, globalNodes []v1alpha1.GlobalNode) (*AssignmentPlan, error) {
+	plan := &AssignmentPlan{Policies: make([]PolicyAssignmentPlan, 0, len(virtualCluster.Spec.PromotePolicies))}
+
+	for _, policy := range virtualCluster.Spec.PromotePolicies {
+		matchedNodes, err := retrieveGlobalNodesWithLabelSelector(globalNodes, policy.LabelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("retrieve globalnode with labelselector: %w", err)
+		}
+		matchedNodes = filterGlobalNodesByTolerations(matchedNodes, policy.Tolerations)
+		sort.Slice(matchedNodes, func(i, j int) bool {
+			return matchedNodes[i].Name < matchedNodes[j].Name
+		})
+
+		nodesAssigned, err := retrieveAssignedNodesByPolicy(virtualCluster, policy, matchedNodes)
+		if err != nil {
+			return nil, fmt.Errorf("retrieve assigned nodes by policy: %w", err)
+		}
+
+		policyPlan := PolicyAssignmentPlan{LabelSelector: policy.LabelSelector}
+		delta := policy.NodeCount - int32(len(nodesAssigned))
+		switch {
+		case delta > 0:
+			var claimed int32
+			for _, node := range matchedNodes {
+				if node.Spec.State != v1alpha1.NodeFreeState {
+					continue
+				}
+				policyPlan.Claims = append(policyPlan.Claims, node.Name)
+				claimed++
+				if claimed == delta {
+					break
+				}
+			}
+			policyPlan.Shortage = delta - claimed
+		case delta < 0:
+			release := int(-delta)
+			sort.Slice(nodesAssigned, func(i, j int) bool {
+				return nodesAssigned[i].NodeName < nodesAssigned[j].NodeName
+			})
+			for _, nodeInfo := range nodesAssigned[len(nodesAssigned)-release:] {
+				policyPlan.Releases = append(policyPlan.Releases, nodeInfo.NodeName)
+			}
+		}
+
+		plan.Policies = append(plan.Policies, policyPlan)
+	}
+
+	return plan, nil
+}
+
+// ComputeAssignmentDelta flattens BuildAssignmentPlan's per-policy claims and
+// releases into the NodeInfo entries that would be added to or removed from
+// virtualCluster's assigned nodes, without mutating virtualCluster or
+// globalNodes. It exists so callers that only care about "what would change"
+// - such as checkPromotePoliciesChanged - don't need to re-derive the diff
+// from virtualCluster's PromotePolicies themselves and risk drifting from
+// BuildAssignmentPlan's notion of a change.
+//
+// ComputeAssignmentDelta does not decide which free nodes a claim prefers
+// beyond name order, and does not take tenant-cluster utilization into
+// account for releases; VirtualClusterInitController.assignNodesByPolicy
+// still owns the actual claim/release execution, since it additionally
+// honors the controller's configurable NodeSelectionStrategy and drains
+// nodes before releasing them, neither of which is information available
+// from a GlobalNode snapshot alone.
+func ComputeAssignmentDelta(virtualCluster *v1alpha1.VirtualCluster, globalNodes []v1alpha1.GlobalNode) (toAdd, toRemove []v1alpha1.NodeInfo, err error) {
+	plan, err := BuildAssignmentPlan(virtualCluster, globalNodes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, policyPlan := range plan.Policies {
+		for _, name := range policyPlan.Claims {
+			toAdd = append(toAdd, v1alpha1.NodeInfo{NodeName: name, PromotePolicy: normalizePromotePolicySelector(policyPlan.LabelSelector)})
+		}
+		for _, name := range policyPlan.Releases {
+			toRemove = append(toRemove, v1alpha1.NodeInfo{NodeName: name})
+		}
+	}
+
+	return toAdd, toRemove, nil
+}
@@ -0,0 +1,211 @@
+package controller
+
+import (
+	"sort"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+)
+
+func planGlobalNode(name string, state v1alpha1.NodeState) v1alpha1.GlobalNode {
+	return v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1alpha1.GlobalNodeSpec{
+			State:  state,
+			Labels: labels.Set{"pool": "default"},
+		},
+	}
+}
+
+func TestBuildAssignmentPlanScaleUp(t *testing.T) {
+	globalNodes := []v1alpha1.GlobalNode{
+		planGlobalNode("node-1", v1alpha1.NodeInUse),
+		planGlobalNode("node-2", v1alpha1.NodeFreeState),
+		planGlobalNode("node-3", v1alpha1.NodeFreeState),
+	}
+	vc := &v1alpha1.VirtualCluster{
+		Spec: v1alpha1.VirtualClusterSpec{
+			PromotePolicies: []v1alpha1.PromotePolicy{{NodeCount: 2}},
+			PromoteResources: v1alpha1.PromoteResources{
+				NodeInfos: []v1alpha1.NodeInfo{{NodeName: "node-1"}},
+			},
+		},
+	}
+
+	plan, err := BuildAssignmentPlan(vc, globalNodes)
+	if err != nil {
+		t.Fatalf("BuildAssignmentPlan() error = %v", err)
+	}
+	if len(plan.Policies) != 1 {
+		t.Fatalf("expected 1 policy plan, got %d", len(plan.Policies))
+	}
+	got := plan.Policies[0]
+	if len(got.Claims) != 1 || got.Claims[0] != "node-2" {
+		t.Errorf("Claims = %v, want [node-2]", got.Claims)
+	}
+	if len(got.Releases) != 0 {
+		t.Errorf("Releases = %v, want none", got.Releases)
+	}
+	if got.Shortage != 0 {
+		t.Errorf("Shortage = %d, want 0", got.Shortage)
+	}
+}
+
+func TestBuildAssignmentPlanScaleDown(t *testing.T) {
+	globalNodes := []v1alpha1.GlobalNode{
+		planGlobalNode("node-1", v1alpha1.NodeInUse),
+		planGlobalNode("node-2", v1alpha1.NodeInUse),
+		planGlobalNode("node-3", v1alpha1.NodeInUse),
+	}
+	vc := &v1alpha1.VirtualCluster{
+		Spec: v1alpha1.VirtualClusterSpec{
+			PromotePolicies: []v1alpha1.PromotePolicy{{NodeCount: 1}},
+			PromoteResources: v1alpha1.PromoteResources{
+				NodeInfos: []v1alpha1.NodeInfo{{NodeName: "node-1"}, {NodeName: "node-2"}, {NodeName: "node-3"}},
+			},
+		},
+	}
+
+	plan, err := BuildAssignmentPlan(vc, globalNodes)
+	if err != nil {
+		t.Fatalf("BuildAssignmentPlan() error = %v", err)
+	}
+	got := plan.Policies[0]
+	if len(got.Claims) != 0 {
+		t.Errorf("Claims = %v, want none", got.Claims)
+	}
+	if len(got.Releases) != 2 || got.Releases[0] != "node-2" || got.Releases[1] != "node-3" {
+		t.Errorf("Releases = %v, want [node-2 node-3]", got.Releases)
+	}
+	if got.Shortage != 0 {
+		t.Errorf("Shortage = %d, want 0", got.Shortage)
+	}
+}
+
+func TestBuildAssignmentPlanShortage(t *testing.T) {
+	globalNodes := []v1alpha1.GlobalNode{
+		planGlobalNode("node-1", v1alpha1.NodeFreeState),
+	}
+	vc := &v1alpha1.VirtualCluster{
+		Spec: v1alpha1.VirtualClusterSpec{
+			PromotePolicies: []v1alpha1.PromotePolicy{{NodeCount: 3}},
+		},
+	}
+
+	plan, err := BuildAssignmentPlan(vc, globalNodes)
+	if err != nil {
+		t.Fatalf("BuildAssignmentPlan() error = %v", err)
+	}
+	got := plan.Policies[0]
+	if len(got.Claims) != 1 || got.Claims[0] != "node-1" {
+		t.Errorf("Claims = %v, want [node-1]", got.Claims)
+	}
+	if got.Shortage != 2 {
+		t.Errorf("Shortage = %d, want 2", got.Shortage)
+	}
+}
+
+func TestComputeAssignmentDelta(t *testing.T) {
+	globalNodes := []v1alpha1.GlobalNode{
+		planGlobalNode("scaleup-1", v1alpha1.NodeInUse),
+		planGlobalNode("scaleup-2", v1alpha1.NodeFreeState),
+		planGlobalNode("scaledown-1", v1alpha1.NodeInUse),
+		planGlobalNode("scaledown-2", v1alpha1.NodeInUse),
+	}
+	selector := func(prefix string) *metav1.LabelSelector {
+		return &metav1.LabelSelector{MatchLabels: map[string]string{"group": prefix}}
+	}
+	for i := range globalNodes {
+		globalNodes[i].Spec.Labels["group"] = "scaleup"
+	}
+	globalNodes[2].Spec.Labels["group"] = "scaledown"
+	globalNodes[3].Spec.Labels["group"] = "scaledown"
+
+	vc := &v1alpha1.VirtualCluster{
+		Spec: v1alpha1.VirtualClusterSpec{
+			PromotePolicies: []v1alpha1.PromotePolicy{
+				{NodeCount: 2, LabelSelector: selector("scaleup")},
+				{NodeCount: 1, LabelSelector: selector("scaledown")},
+			},
+			PromoteResources: v1alpha1.PromoteResources{
+				NodeInfos: []v1alpha1.NodeInfo{
+					{NodeName: "scaleup-1"},
+					{NodeName: "scaledown-1"},
+					{NodeName: "scaledown-2"},
+				},
+			},
+		},
+	}
+
+	toAdd, toRemove, err := ComputeAssignmentDelta(vc, globalNodes)
+	if err != nil {
+		t.Fatalf("ComputeAssignmentDelta() error = %v", err)
+	}
+	if len(toAdd) != 1 || toAdd[0].NodeName != "scaleup-2" {
+		t.Errorf("toAdd = %v, want [scaleup-2]", toAdd)
+	}
+	if len(toRemove) != 1 || toRemove[0].NodeName != "scaledown-2" {
+		t.Errorf("toRemove = %v, want [scaledown-2]", toRemove)
+	}
+}
+
+func TestComputeAssignmentDeltaNoChange(t *testing.T) {
+	globalNodes := []v1alpha1.GlobalNode{planGlobalNode("node-1", v1alpha1.NodeInUse)}
+	vc := &v1alpha1.VirtualCluster{
+		Spec: v1alpha1.VirtualClusterSpec{
+			PromotePolicies: []v1alpha1.PromotePolicy{{NodeCount: 1}},
+			PromoteResources: v1alpha1.PromoteResources{
+				NodeInfos: []v1alpha1.NodeInfo{{NodeName: "node-1"}},
+			},
+		},
+	}
+
+	toAdd, toRemove, err := ComputeAssignmentDelta(vc, globalNodes)
+	if err != nil {
+		t.Fatalf("ComputeAssignmentDelta() error = %v", err)
+	}
+	if len(toAdd) != 0 || len(toRemove) != 0 {
+		t.Errorf("expected no delta, got toAdd=%v toRemove=%v", toAdd, toRemove)
+	}
+}
+
+func TestRetrieveAssignedNodesByPolicyMatchesRecordedOwnershipOverLiveLabels(t *testing.T) {
+	policy := v1alpha1.PromotePolicy{LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "tenant"}}}
+	otherPolicy := v1alpha1.PromotePolicy{LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "other"}}}
+	vc := &v1alpha1.VirtualCluster{
+		Spec: v1alpha1.VirtualClusterSpec{
+			PromoteResources: v1alpha1.PromoteResources{
+				NodeInfos: []v1alpha1.NodeInfo{
+					// Claimed by policy, but its GlobalNode's live labels have
+					// since drifted away from policy's selector.
+					{NodeName: "node-owned", PromotePolicy: policy.LabelSelector},
+					// Claimed by a different policy; must not be counted here
+					// even though it happens to still carry matching labels.
+					{NodeName: "node-other", PromotePolicy: otherPolicy.LabelSelector},
+					// Legacy NodeInfo with no recorded ownership, falls back
+					// to a live label match.
+					{NodeName: "node-legacy"},
+				},
+			},
+		},
+	}
+	// Only node-legacy currently matches policy's selector by live labels.
+	policyMatchedGlobalNodes := []v1alpha1.GlobalNode{{ObjectMeta: metav1.ObjectMeta{Name: "node-legacy"}}}
+
+	got, err := retrieveAssignedNodesByPolicy(vc, policy, policyMatchedGlobalNodes)
+	if err != nil {
+		t.Fatalf("retrieveAssignedNodesByPolicy() error = %v", err)
+	}
+
+	var names []string
+	for _, nodeInfo := range got {
+		names = append(names, nodeInfo.NodeName)
+	}
+	sort.Strings(names)
+	if len(names) != 2 || names[0] != "node-legacy" || names[1] != "node-owned" {
+		t.Errorf("got %v, want [node-legacy node-owned]", names)
+	}
+}
@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	bootstrapapi "k8s.io/cluster-bootstrap/token/api"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/constants"
+)
+
+// BootstrapTokenGCController garbage-collects host-cluster bootstrap-token
+// Secrets minted by "kubeadm token create" while joining nodes to a
+// VirtualCluster (see labelBootstrapTokenSecret). It deletes a cluster's
+// tokens once they expire, and removes all of its remaining tokens when the
+// VirtualCluster is torn down.
+type BootstrapTokenGCController struct {
+	client.Client
+	RootClientSet kubernetes.Interface
+}
+
+func (r *BootstrapTokenGCController) SetupWithManager(mgr manager.Manager) error {
+	if r.Client == nil {
+		r.Client = mgr.GetClient()
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(constants.BootstrapTokenGCControllerName).
+		For(&v1alpha1.VirtualCluster{}).
+		Complete(r)
+}
+
+func (r *BootstrapTokenGCController) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	var vc v1alpha1.VirtualCluster
+	if err := r.Get(ctx, request.NamespacedName, &vc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	tokens, err := r.listBootstrapTokenSecrets(ctx, vc.Name)
+	if err != nil {
+		klog.Errorf("bootstrap-token-gc-controller: list bootstrap token secrets for %s error: %v", request.NamespacedName, err)
+		return reconcile.Result{RequeueAfter: constants.BootstrapTokenGCInterval}, nil
+	}
+
+	if !vc.DeletionTimestamp.IsZero() {
+		if err := r.deleteSecrets(ctx, tokens); err != nil {
+			klog.Errorf("bootstrap-token-gc-controller: remove bootstrap token secrets for %s error: %v", request.NamespacedName, err)
+			return reconcile.Result{RequeueAfter: constants.BootstrapTokenGCInterval}, nil
+		}
+		return reconcile.Result{}, nil
+	}
+
+	if err := r.deleteSecrets(ctx, expiredBootstrapTokenSecrets(tokens, time.Now())); err != nil {
+		klog.Errorf("bootstrap-token-gc-controller: remove expired bootstrap token secrets for %s error: %v", request.NamespacedName, err)
+	}
+	return reconcile.Result{RequeueAfter: constants.BootstrapTokenGCInterval}, nil
+}
+
+func (r *BootstrapTokenGCController) listBootstrapTokenSecrets(ctx context.Context, virtualClusterName string) ([]corev1.Secret, error) {
+	list, err := r.RootClientSet.CoreV1().Secrets(constants.SystemNs).List(ctx, metav1.ListOptions{
+		LabelSelector: constants.BootstrapTokenVirtualClusterLabelKey + "=" + virtualClusterName,
+	})
+	if err != nil {
+		return nil, err
+	}
+	tokens := make([]corev1.Secret, 0, len(list.Items))
+	for _, secret := range list.Items {
+		if secret.Type == bootstrapapi.SecretTypeBootstrapToken {
+			tokens = append(tokens, secret)
+		}
+	}
+	return tokens, nil
+}
+
+func (r *BootstrapTokenGCController) deleteSecrets(ctx context.Context, secrets []corev1.Secret) error {
+	for _, secret := range secrets {
+		if err := r.RootClientSet.CoreV1().Secrets(secret.Namespace).Delete(ctx, secret.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// expiredBootstrapTokenSecrets returns the secrets among tokens whose
+// BootstrapTokenExpirationKey has passed. A token with a missing or
+// unparsable expiration is left alone rather than treated as expired.
+func expiredBootstrapTokenSecrets(tokens []corev1.Secret, now time.Time) []corev1.Secret {
+	expired := make([]corev1.Secret, 0, len(tokens))
+	for _, secret := range tokens {
+		expiration, ok := secret.Data[bootstrapapi.BootstrapTokenExpirationKey]
+		if !ok {
+			continue
+		}
+		expiresAt, err := time.Parse(time.RFC3339, string(expiration))
+		if err != nil {
+			continue
+		}
+		if now.After(expiresAt) {
+			expired = append(expired, secret)
+		}
+	}
+	return expired
+}
@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	bootstrapapi "k8s.io/cluster-bootstrap/token/api"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/constants"
+)
+
+func bootstrapTokenSecret(name, virtualClusterName, expiration string) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bootstrapapi.BootstrapTokenSecretPrefix + name,
+			Namespace: constants.SystemNs,
+			Labels:    map[string]string{constants.BootstrapTokenVirtualClusterLabelKey: virtualClusterName},
+		},
+		Type: bootstrapapi.SecretTypeBootstrapToken,
+		Data: map[string][]byte{},
+	}
+	if expiration != "" {
+		secret.Data[bootstrapapi.BootstrapTokenExpirationKey] = []byte(expiration)
+	}
+	return secret
+}
+
+func TestBootstrapTokenGCControllerRemovesExpiredTokens(t *testing.T) {
+	vc := &v1alpha1.VirtualCluster{ObjectMeta: metav1.ObjectMeta{Name: "vc1", Namespace: "default"}}
+	expired := bootstrapTokenSecret("abcdef", "vc1", time.Now().Add(-time.Hour).Format(time.RFC3339))
+	fresh := bootstrapTokenSecret("ghijkl", "vc1", time.Now().Add(time.Hour).Format(time.RFC3339))
+	hostClient := fake.NewSimpleClientset(expired, fresh)
+
+	controller := &BootstrapTokenGCController{
+		Client:        &vcStoreClient{vc: vc},
+		RootClientSet: hostClient,
+	}
+
+	if _, err := controller.Reconcile(context.TODO(), reconcileRequest(vc)); err != nil {
+		t.Fatalf("Reconcile() failed: %s", err)
+	}
+
+	secrets, err := hostClient.CoreV1().Secrets(constants.SystemNs).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("list secrets failed: %s", err)
+	}
+	if len(secrets.Items) != 1 || secrets.Items[0].Name != fresh.Name {
+		t.Fatalf("expected only the unexpired token to remain, got %v", secrets.Items)
+	}
+}
+
+func TestBootstrapTokenGCControllerRemovesAllTokensOnTeardown(t *testing.T) {
+	now := metav1.Now()
+	vc := &v1alpha1.VirtualCluster{ObjectMeta: metav1.ObjectMeta{
+		Name: "vc1", Namespace: "default",
+		DeletionTimestamp: &now,
+		Finalizers:        []string{"kosmos.io/virtualcluster-controller"},
+	}}
+	fresh := bootstrapTokenSecret("ghijkl", "vc1", time.Now().Add(time.Hour).Format(time.RFC3339))
+	other := bootstrapTokenSecret("mnopqr", "vc2", time.Now().Add(time.Hour).Format(time.RFC3339))
+	hostClient := fake.NewSimpleClientset(fresh, other)
+
+	controller := &BootstrapTokenGCController{
+		Client:        &vcStoreClient{vc: vc},
+		RootClientSet: hostClient,
+	}
+
+	if _, err := controller.Reconcile(context.TODO(), reconcileRequest(vc)); err != nil {
+		t.Fatalf("Reconcile() failed: %s", err)
+	}
+
+	secrets, err := hostClient.CoreV1().Secrets(constants.SystemNs).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("list secrets failed: %s", err)
+	}
+	if len(secrets.Items) != 1 || secrets.Items[0].Name != other.Name {
+		t.Fatalf("expected only the other cluster's token to remain after teardown, got %v", secrets.Items)
+	}
+}
+
+func reconcileRequest(vc *v1alpha1.VirtualCluster) reconcile.Request {
+	return reconcile.Request{NamespacedName: types.NamespacedName{Name: vc.Name, Namespace: vc.Namespace}}
+}
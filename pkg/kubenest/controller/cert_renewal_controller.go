@@ -0,0 +1,373 @@
+package controller
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/constants"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/util"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/util/cert"
+)
+
+const (
+	kindDeployment  = "Deployment"
+	kindStatefulSet = "StatefulSet"
+)
+
+// affectedComponent is a workload whose pods must restart to pick up a
+// freshly renewed certificate.
+type affectedComponent struct {
+	kind   string
+	nameFn func(string) string
+}
+
+// certAffectedComponents maps a control-plane certificate's pairName (see
+// constants.*CertAndKeyName) to the workloads whose pods present or consume
+// it, so a renewal restarts exactly the pods that need the new cert.
+var certAffectedComponents = map[string][]affectedComponent{
+	constants.ApiserverCertAndKeyName:        {{kindDeployment, util.GetAPIServerName}},
+	constants.FrontProxyClientCertAndKeyName: {{kindDeployment, util.GetAPIServerName}},
+	constants.EtcdClientCertAndKeyName:       {{kindDeployment, util.GetAPIServerName}},
+	constants.EtcdServerCertAndKeyName:       {{kindStatefulSet, util.GetEtcdServerName}},
+	constants.ProxyServerCertAndKeyName:      {{kindDeployment, util.GetKonnectivityServerName}},
+}
+
+// CertRenewalController regenerates a Completed VirtualCluster's
+// control-plane certificates before they expire, and surfaces the earliest
+// expiry across all of them on VirtualClusterStatus.CertExpiry. The
+// cert.CertStore only ever issues certificates once, at init time, so
+// without this controller a long-lived VirtualCluster would eventually run
+// its apiserver and etcd on expired certs. Certificate authorities are
+// deliberately left alone -- rotating a CA requires redistributing trust to
+// every existing client, which this controller does not attempt.
+type CertRenewalController struct {
+	client.Client
+	RootClientSet kubernetes.Interface
+}
+
+func (r *CertRenewalController) SetupWithManager(mgr manager.Manager) error {
+	if r.Client == nil {
+		r.Client = mgr.GetClient()
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(constants.CertRenewalControllerName).
+		For(&v1alpha1.VirtualCluster{}).
+		Complete(r)
+}
+
+func (r *CertRenewalController) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	var vc v1alpha1.VirtualCluster
+	if err := r.Get(ctx, request.NamespacedName, &vc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if vc.Status.Phase != v1alpha1.Completed && vc.Status.Phase != v1alpha1.WorkersScaledDown {
+		return reconcile.Result{}, nil
+	}
+
+	secrets, err := r.getCertSecrets(ctx, &vc)
+	if err != nil {
+		klog.Errorf("cert-renewal-controller: get cert secrets for %s error: %v", request.NamespacedName, err)
+		return reconcile.Result{RequeueAfter: constants.CertRenewalCheckInterval}, nil
+	}
+
+	earliest, expiringCerts, err := inspectCertExpiry(secrets, time.Now(), constants.CertRenewalWindow)
+	if err != nil {
+		klog.Errorf("cert-renewal-controller: inspect cert expiry for %s error: %v", request.NamespacedName, err)
+		return reconcile.Result{RequeueAfter: constants.CertRenewalCheckInterval}, nil
+	}
+
+	if err := r.updateCertExpiryStatus(ctx, request.NamespacedName, earliest); err != nil {
+		klog.Errorf("cert-renewal-controller: update cert expiry status for %s error: %v", request.NamespacedName, err)
+	}
+
+	if len(expiringCerts) == 0 {
+		if requeueAfter := time.Until(earliest.Add(-constants.CertRenewalWindow)); requeueAfter > 0 {
+			return reconcile.Result{RequeueAfter: requeueAfter}, nil
+		}
+		return reconcile.Result{RequeueAfter: constants.CertRenewalCheckInterval}, nil
+	}
+
+	if err := r.renewCerts(ctx, &vc, secrets, expiringCerts); err != nil {
+		klog.Errorf("cert-renewal-controller: renew certs %v for %s error: %v", expiringCerts, request.NamespacedName, err)
+		return reconcile.Result{RequeueAfter: constants.CertRenewalCheckInterval}, nil
+	}
+
+	klog.InfoS("cert-renewal-controller: renewed certificates nearing expiry", "certs", expiringCerts, "virtualCluster", request.NamespacedName)
+	return reconcile.Result{RequeueAfter: constants.CertRenewalCheckInterval}, nil
+}
+
+// getCertSecrets returns whichever of vc's two cert secrets exist. The
+// etcd-cert secret is absent for VirtualClusters using an external etcd, so
+// its absence is not an error.
+func (r *CertRenewalController) getCertSecrets(ctx context.Context, vc *v1alpha1.VirtualCluster) ([]*corev1.Secret, error) {
+	var secrets []*corev1.Secret
+	for _, name := range []string{util.GetCertName(vc.Name), util.GetEtcdCertName(vc.Name)} {
+		secret, err := r.RootClientSet.CoreV1().Secrets(vc.Namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, fmt.Errorf("get secret %s/%s: %w", vc.Namespace, name, err)
+		}
+		secrets = append(secrets, secret)
+	}
+	return secrets, nil
+}
+
+// inspectCertExpiry returns the earliest NotAfter across every certificate
+// in secrets, and the sorted pairName of every certificate whose own NotAfter
+// is within window of now.
+func inspectCertExpiry(secrets []*corev1.Secret, now time.Time, window time.Duration) (time.Time, []string, error) {
+	var earliest time.Time
+	var expiring []string
+	for _, secret := range secrets {
+		for name, data := range secret.Data {
+			if !strings.HasSuffix(name, constants.CertExtension) {
+				continue
+			}
+
+			notAfter, err := certNotAfter(data)
+			if err != nil {
+				return time.Time{}, nil, fmt.Errorf("parse %s/%s: %w", secret.Name, name, err)
+			}
+			if earliest.IsZero() || notAfter.Before(earliest) {
+				earliest = notAfter
+			}
+			if !now.Before(notAfter.Add(-window)) {
+				expiring = append(expiring, strings.TrimSuffix(name, constants.CertExtension))
+			}
+		}
+	}
+	sort.Strings(expiring)
+	return earliest, expiring, nil
+}
+
+func certNotAfter(pemData []byte) (time.Time, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil {
+		return time.Time{}, errors.New("decode certificate PEM")
+	}
+	parsed, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return parsed.NotAfter, nil
+}
+
+func (r *CertRenewalController) updateCertExpiryStatus(ctx context.Context, name types.NamespacedName, expiry time.Time) error {
+	if expiry.IsZero() {
+		return nil
+	}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var vc v1alpha1.VirtualCluster
+		if err := r.Get(ctx, name, &vc); err != nil {
+			return err
+		}
+
+		if vc.Status.CertExpiry != nil && vc.Status.CertExpiry.Time.Equal(expiry) {
+			return nil
+		}
+
+		certExpiry := metav1.NewTime(expiry)
+		vc.Status.CertExpiry = &certExpiry
+		return r.Update(ctx, &vc)
+	})
+}
+
+// renewCerts regenerates every certificate named in expiringCerts, reusing
+// its existing certificate authority, and restarts the workloads that
+// consume it.
+func (r *CertRenewalController) renewCerts(ctx context.Context, vc *v1alpha1.VirtualCluster, secrets []*corev1.Secret, expiringCerts []string) error {
+	store := cert.NewCertStore()
+	for _, secret := range secrets {
+		if err := store.LoadCertFromSecret(secret); err != nil {
+			return fmt.Errorf("load certs from secret %s: %w", secret.Name, err)
+		}
+	}
+
+	altNamesConfig, err := r.buildAltNamesMutatorConfig(vc)
+	if err != nil {
+		return fmt.Errorf("build altNames config: %w", err)
+	}
+
+	renewed := map[string]*cert.VirtualClusterCert{}
+	for _, cc := range cert.GetDefaultCertList() {
+		if !containsString(expiringCerts, cc.Name) {
+			continue
+		}
+
+		if cc.CAName == "" {
+			klog.Warningf("cert-renewal-controller: certificate authority %s for %s/%s is nearing expiry; rotating a CA is not automated and requires manual intervention", cc.Name, vc.Namespace, vc.Name)
+			continue
+		}
+
+		caCert := store.GetCert(cc.CAName)
+		if caCert == nil {
+			return fmt.Errorf("no stored CA %s to renew %s", cc.CAName, cc.Name)
+		}
+
+		if cc.AltNamesMutatorFunc != nil {
+			if err := cc.AltNamesMutatorFunc(altNamesConfig, cc); err != nil {
+				return fmt.Errorf("mutate altNames for %s: %w", cc.Name, err)
+			}
+		}
+
+		newCert, err := cert.CreateCertAndKeyFilesWithCA(cc, caCert.CertData(), caCert.KeyData())
+		if err != nil {
+			return fmt.Errorf("regenerate %s: %w", cc.Name, err)
+		}
+		renewed[cc.Name] = newCert
+	}
+
+	if len(renewed) == 0 {
+		return nil
+	}
+
+	if err := r.writeRenewedCerts(ctx, vc, secrets, renewed); err != nil {
+		return err
+	}
+
+	return r.restartAffectedComponents(ctx, vc, renewed)
+}
+
+func (r *CertRenewalController) buildAltNamesMutatorConfig(vc *v1alpha1.VirtualCluster) (*cert.AltNamesMutatorConfig, error) {
+	controlplaneAddr, err := util.GetAPIServiceIP(r.RootClientSet)
+	if err != nil {
+		return nil, fmt.Errorf("get apiserver node ip: %w", err)
+	}
+	clusterIPs, err := util.GetServiceClusterIP(vc.Namespace, r.RootClientSet)
+	if err != nil {
+		return nil, fmt.Errorf("get service cluster ips: %w", err)
+	}
+
+	return &cert.AltNamesMutatorConfig{
+		Name:             vc.Name,
+		Namespace:        vc.Namespace,
+		ControlplaneAddr: controlplaneAddr,
+		ClusterIPs:       clusterIPs,
+		ExternalIP:       vc.Spec.ExternalIP,
+		ExternalIPs:      vc.Spec.ExternalIps,
+		ExternalHostName: vc.Spec.ExternalHostName,
+		VipMap:           vc.Status.VipMap,
+	}, nil
+}
+
+// writeRenewedCerts overwrites each renewed certificate's cert/key pair in
+// whichever secret it was originally stored in.
+func (r *CertRenewalController) writeRenewedCerts(ctx context.Context, vc *v1alpha1.VirtualCluster, secrets []*corev1.Secret, renewed map[string]*cert.VirtualClusterCert) error {
+	for _, secret := range secrets {
+		changed := false
+		for name, newCert := range renewed {
+			certKey := name + constants.CertExtension
+			keyKey := name + constants.KeyExtension
+			if _, ok := secret.Data[certKey]; !ok {
+				continue
+			}
+			secret.Data[certKey] = newCert.CertData()
+			secret.Data[keyKey] = newCert.KeyData()
+			changed = true
+		}
+		if !changed {
+			continue
+		}
+		if _, err := r.RootClientSet.CoreV1().Secrets(vc.Namespace).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("update secret %s: %w", secret.Name, err)
+		}
+	}
+	return nil
+}
+
+func (r *CertRenewalController) restartAffectedComponents(ctx context.Context, vc *v1alpha1.VirtualCluster, renewed map[string]*cert.VirtualClusterCert) error {
+	type workload struct {
+		kind string
+		name string
+	}
+
+	seen := map[workload]struct{}{}
+	for certName := range renewed {
+		for _, component := range certAffectedComponents[certName] {
+			seen[workload{kind: component.kind, name: component.nameFn(vc.Name)}] = struct{}{}
+		}
+	}
+
+	for wl := range seen {
+		if err := r.bumpRestartAnnotation(ctx, vc.Namespace, wl.kind, wl.name); err != nil {
+			return fmt.Errorf("restart %s %s: %w", wl.kind, wl.name, err)
+		}
+	}
+	return nil
+}
+
+// bumpRestartAnnotation stamps name's pod template with the current time, so
+// the Deployment/StatefulSet controller rolls every pod to pick up the
+// renewed certificate -- the same mechanism "kubectl rollout restart" uses.
+func (r *CertRenewalController) bumpRestartAnnotation(ctx context.Context, namespace, kind, name string) error {
+	restartedAt := time.Now().UTC().Format(time.RFC3339)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		switch kind {
+		case kindDeployment:
+			deployment, err := r.RootClientSet.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					return nil
+				}
+				return err
+			}
+			if deployment.Spec.Template.Annotations == nil {
+				deployment.Spec.Template.Annotations = map[string]string{}
+			}
+			deployment.Spec.Template.Annotations[constants.CertRenewedAtAnnotation] = restartedAt
+			_, err = r.RootClientSet.AppsV1().Deployments(namespace).Update(ctx, deployment, metav1.UpdateOptions{})
+			return err
+		case kindStatefulSet:
+			statefulSet, err := r.RootClientSet.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					return nil
+				}
+				return err
+			}
+			if statefulSet.Spec.Template.Annotations == nil {
+				statefulSet.Spec.Template.Annotations = map[string]string{}
+			}
+			statefulSet.Spec.Template.Annotations[constants.CertRenewedAtAnnotation] = restartedAt
+			_, err = r.RootClientSet.AppsV1().StatefulSets(namespace).Update(ctx, statefulSet, metav1.UpdateOptions{})
+			return err
+		default:
+			return fmt.Errorf("unknown workload kind %s", kind)
+		}
+	})
+}
+
+func containsString(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kosmos.io/kosmos/pkg/kubenest/constants"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/util/cert"
+)
+
+func testSelfSignedCertPEM(t *testing.T, notAfter time.Time) []byte {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate test key: %s", err)
+	}
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create test certificate: %s", err)
+	}
+
+	return cert.EncodeCertPEM(&x509.Certificate{Raw: der})
+}
+
+func TestCertNotAfter(t *testing.T) {
+	notAfter := time.Now().Add(90 * 24 * time.Hour).UTC().Truncate(time.Second)
+
+	got, err := certNotAfter(testSelfSignedCertPEM(t, notAfter))
+	if err != nil {
+		t.Fatalf("certNotAfter() error = %v", err)
+	}
+	if !got.Equal(notAfter) {
+		t.Errorf("certNotAfter() = %v, want %v", got, notAfter)
+	}
+}
+
+func TestCertNotAfterInvalidPEM(t *testing.T) {
+	if _, err := certNotAfter([]byte("not a cert")); err == nil {
+		t.Error("expected an error for invalid PEM data")
+	}
+}
+
+func TestInspectCertExpiry(t *testing.T) {
+	now := time.Now()
+	soonExpiring := now.Add(10 * 24 * time.Hour)
+	farOut := now.Add(200 * 24 * time.Hour)
+
+	secrets := []*corev1.Secret{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "vc1-cert"},
+			Data: map[string][]byte{
+				constants.CaCertAndKeyName + constants.CertExtension:        testSelfSignedCertPEM(t, farOut),
+				constants.ApiserverCertAndKeyName + constants.CertExtension: testSelfSignedCertPEM(t, soonExpiring),
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "vc1-etcd-cert"},
+			Data: map[string][]byte{
+				constants.EtcdServerCertAndKeyName + constants.CertExtension: testSelfSignedCertPEM(t, farOut),
+			},
+		},
+	}
+
+	earliest, expiring, err := inspectCertExpiry(secrets, now, 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("inspectCertExpiry() error = %v", err)
+	}
+	if !earliest.Equal(soonExpiring.Truncate(time.Second)) {
+		t.Errorf("expected earliest expiry to be the apiserver cert's NotAfter %v, got %v", soonExpiring, earliest)
+	}
+	if len(expiring) != 1 || expiring[0] != constants.ApiserverCertAndKeyName {
+		t.Errorf("expected only %q to be within the renewal window, got %v", constants.ApiserverCertAndKeyName, expiring)
+	}
+}
+
+func TestInspectCertExpiryNoneNearExpiry(t *testing.T) {
+	now := time.Now()
+	farOut := now.Add(200 * 24 * time.Hour)
+
+	secrets := []*corev1.Secret{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "vc1-cert"},
+			Data: map[string][]byte{
+				constants.ApiserverCertAndKeyName + constants.CertExtension: testSelfSignedCertPEM(t, farOut),
+			},
+		},
+	}
+
+	_, expiring, err := inspectCertExpiry(secrets, now, 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("inspectCertExpiry() error = %v", err)
+	}
+	if len(expiring) != 0 {
+		t.Errorf("expected no certs within the renewal window, got %v", expiring)
+	}
+}
+
+func TestContainsString(t *testing.T) {
+	if !containsString([]string{"a", "b"}, "b") {
+		t.Error("expected containsString to find an existing entry")
+	}
+	if containsString([]string{"a", "b"}, "c") {
+		t.Error("expected containsString to report false for a missing entry")
+	}
+}
@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	bootstrapapi "k8s.io/cluster-bootstrap/token/api"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/kosmos.io/kosmos/pkg/kubenest/util"
+)
+
+// ClusterInfoReconciler periodically verifies that the host cluster's
+// kube-public/cluster-info ConfigMap exists and still advertises the
+// current API server endpoint and CA. Nodes joining via "kubeadm join"
+// read this ConfigMap to discover and trust the cluster, so if it goes
+// missing or drifts out of sync with the real endpoint/CA (e.g. after a CA
+// rotation), joins start failing; this reconciler repairs it in place.
+type ClusterInfoReconciler struct {
+	RootClientSet kubernetes.Interface
+	// ServerURL is the host cluster's current API server endpoint.
+	ServerURL string
+	// CACert is the host cluster's current CA certificate, PEM encoded.
+	CACert []byte
+	// Interval controls how often the check runs. Defaults to 10 minutes.
+	Interval time.Duration
+}
+
+func (r *ClusterInfoReconciler) SetupWithManager(mgr manager.Manager) error {
+	return mgr.Add(r)
+}
+
+func (r *ClusterInfoReconciler) Start(ctx context.Context) error {
+	interval := r.Interval
+	if interval <= 0 {
+		interval = 10 * time.Minute
+	}
+	wait.Until(func() {
+		if err := r.sync(ctx); err != nil {
+			klog.Errorf("cluster-info configmap sync failed: %v", err)
+		}
+	}, interval, ctx.Done())
+	return nil
+}
+
+func (r *ClusterInfoReconciler) sync(ctx context.Context) error {
+	want, err := desiredClusterInfoConfigMap(r.ServerURL, r.CACert)
+	if err != nil {
+		return fmt.Errorf("build desired cluster-info configmap error: %v", err)
+	}
+
+	existing, err := r.RootClientSet.CoreV1().ConfigMaps(metav1.NamespacePublic).Get(ctx, bootstrapapi.ConfigMapClusterInfo, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		klog.Infof("cluster-info configmap is missing; recreating it")
+		_, err = r.RootClientSet.CoreV1().ConfigMaps(metav1.NamespacePublic).Create(ctx, want, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("get cluster-info configmap error: %v", err)
+	}
+
+	if existing.Data[bootstrapapi.KubeConfigKey] == want.Data[bootstrapapi.KubeConfigKey] {
+		return nil
+	}
+
+	klog.Infof("cluster-info configmap is out of date with the current endpoint/CA; repairing it")
+	updated := existing.DeepCopy()
+	if updated.Data == nil {
+		updated.Data = map[string]string{}
+	}
+	updated.Data[bootstrapapi.KubeConfigKey] = want.Data[bootstrapapi.KubeConfigKey]
+	_, err = r.RootClientSet.CoreV1().ConfigMaps(metav1.NamespacePublic).Update(ctx, updated, metav1.UpdateOptions{})
+	return err
+}
+
+// desiredClusterInfoConfigMap builds the cluster-info ConfigMap kubeadm
+// expects: an anonymous (credential-less) kubeconfig carrying only the
+// server endpoint and CA, used by joining nodes to discover and verify the
+// cluster before authenticating with their bootstrap token.
+func desiredClusterInfoConfigMap(serverURL string, caCert []byte) (*corev1.ConfigMap, error) {
+	kubeconfig := util.CreateBasic(serverURL, "", "", caCert)
+	kubeconfigBytes, err := clientcmd.Write(*kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("marshal cluster-info kubeconfig error: %v", err)
+	}
+
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bootstrapapi.ConfigMapClusterInfo,
+			Namespace: metav1.NamespacePublic,
+		},
+		Data: map[string]string{
+			bootstrapapi.KubeConfigKey: string(kubeconfigBytes),
+		},
+	}, nil
+}
@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	bootstrapapi "k8s.io/cluster-bootstrap/token/api"
+)
+
+func TestClusterInfoReconcilerCreatesMissingConfigMap(t *testing.T) {
+	hostClient := fake.NewSimpleClientset()
+	r := &ClusterInfoReconciler{
+		RootClientSet: hostClient,
+		ServerURL:     "https://host.example.com:6443",
+		CACert:        []byte("ca-cert-data"),
+	}
+
+	if err := r.sync(context.TODO()); err != nil {
+		t.Fatalf("sync() failed: %s", err)
+	}
+
+	cm, err := hostClient.CoreV1().ConfigMaps(metav1.NamespacePublic).Get(context.TODO(), bootstrapapi.ConfigMapClusterInfo, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get cluster-info configmap failed: %s", err)
+	}
+	if !strings.Contains(cm.Data[bootstrapapi.KubeConfigKey], r.ServerURL) {
+		t.Fatalf("expected regenerated cluster-info to reference %s, got %s", r.ServerURL, cm.Data[bootstrapapi.KubeConfigKey])
+	}
+}
+
+func TestClusterInfoReconcilerRepairsCorruptedConfigMap(t *testing.T) {
+	corrupted := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bootstrapapi.ConfigMapClusterInfo,
+			Namespace: metav1.NamespacePublic,
+		},
+		Data: map[string]string{
+			bootstrapapi.KubeConfigKey: "not a real kubeconfig",
+		},
+	}
+	hostClient := fake.NewSimpleClientset(corrupted)
+	r := &ClusterInfoReconciler{
+		RootClientSet: hostClient,
+		ServerURL:     "https://host.example.com:6443",
+		CACert:        []byte("ca-cert-data"),
+	}
+
+	if err := r.sync(context.TODO()); err != nil {
+		t.Fatalf("sync() failed: %s", err)
+	}
+
+	cm, err := hostClient.CoreV1().ConfigMaps(metav1.NamespacePublic).Get(context.TODO(), bootstrapapi.ConfigMapClusterInfo, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get cluster-info configmap failed: %s", err)
+	}
+	if !strings.Contains(cm.Data[bootstrapapi.KubeConfigKey], r.ServerURL) {
+		t.Fatalf("expected repaired cluster-info to reference %s, got %s", r.ServerURL, cm.Data[bootstrapapi.KubeConfigKey])
+	}
+}
+
+func TestClusterInfoReconcilerLeavesUpToDateConfigMapAlone(t *testing.T) {
+	r := &ClusterInfoReconciler{
+		RootClientSet: fake.NewSimpleClientset(),
+		ServerURL:     "https://host.example.com:6443",
+		CACert:        []byte("ca-cert-data"),
+	}
+	if err := r.sync(context.TODO()); err != nil {
+		t.Fatalf("first sync() failed: %s", err)
+	}
+	before, err := r.RootClientSet.CoreV1().ConfigMaps(metav1.NamespacePublic).Get(context.TODO(), bootstrapapi.ConfigMapClusterInfo, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get cluster-info configmap failed: %s", err)
+	}
+
+	if err := r.sync(context.TODO()); err != nil {
+		t.Fatalf("second sync() failed: %s", err)
+	}
+	after, err := r.RootClientSet.CoreV1().ConfigMaps(metav1.NamespacePublic).Get(context.TODO(), bootstrapapi.ConfigMapClusterInfo, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get cluster-info configmap failed: %s", err)
+	}
+	if before.ResourceVersion != after.ResourceVersion {
+		t.Fatalf("expected an already up-to-date cluster-info configmap to be left alone, resource version changed from %s to %s", before.ResourceVersion, after.ResourceVersion)
+	}
+}
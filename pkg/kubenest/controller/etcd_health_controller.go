@@ -0,0 +1,217 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/constants"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/util"
+)
+
+// EtcdMember is a single member of a VirtualCluster's etcd cluster, along
+// with whether it answered its last health probe.
+type EtcdMember struct {
+	Name    string
+	Healthy bool
+}
+
+// EtcdClusterProbe reports the health of a VirtualCluster's etcd cluster and
+// can evict a lost member so a freshly-restarted pod can rejoin in its
+// place. The real implementation talks to etcd over clientv3; tests supply a
+// stub.
+type EtcdClusterProbe interface {
+	ListMembers(ctx context.Context) ([]EtcdMember, error)
+	RemoveMember(ctx context.Context, name string) error
+	Close() error
+}
+
+// EtcdHealthController probes a Completed VirtualCluster's etcd cluster for
+// lost members and surfaces their health on VirtualClusterStatus. When the
+// VirtualCluster opts into KubeInKubeConfig.EtcdSelfHeal, a lost member is
+// also evicted from etcd and its pod deleted so it can rejoin in its place.
+type EtcdHealthController struct {
+	client.Client
+	RootClientSet kubernetes.Interface
+	// GenerateEtcdProbe builds a probe for a VirtualCluster's etcd cluster.
+	// Defaults to newClientv3EtcdProbe; overridable in tests.
+	GenerateEtcdProbe func(hostClient kubernetes.Interface, vc *v1alpha1.VirtualCluster) (EtcdClusterProbe, error)
+}
+
+func (r *EtcdHealthController) SetupWithManager(mgr manager.Manager) error {
+	if r.Client == nil {
+		r.Client = mgr.GetClient()
+	}
+	if r.GenerateEtcdProbe == nil {
+		r.GenerateEtcdProbe = newClientv3EtcdProbe
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(constants.EtcdHealthControllerName).
+		For(&v1alpha1.VirtualCluster{}).
+		Complete(r)
+}
+
+func (r *EtcdHealthController) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	var vc v1alpha1.VirtualCluster
+	if err := r.Get(ctx, request.NamespacedName, &vc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if vc.Status.Phase != v1alpha1.Completed && vc.Status.Phase != v1alpha1.WorkersScaledDown {
+		return reconcile.Result{}, nil
+	}
+
+	probe, err := r.GenerateEtcdProbe(r.RootClientSet, &vc)
+	if err != nil {
+		klog.Errorf("etcd-health-controller: build etcd probe for %s error: %v", request.NamespacedName, err)
+		return reconcile.Result{RequeueAfter: constants.EtcdHealthCheckInterval}, nil
+	}
+	defer probe.Close()
+
+	members, err := probe.ListMembers(ctx)
+	if err != nil {
+		klog.Errorf("etcd-health-controller: list etcd members for %s error: %v", request.NamespacedName, err)
+		return reconcile.Result{RequeueAfter: constants.EtcdHealthCheckInterval}, nil
+	}
+
+	healthy := true
+	var unhealthyNames []string
+	for _, member := range members {
+		if !member.Healthy {
+			healthy = false
+			unhealthyNames = append(unhealthyNames, member.Name)
+		}
+	}
+	sort.Strings(unhealthyNames)
+
+	if err := r.updateEtcdStatus(ctx, request.NamespacedName, healthy, unhealthyNames); err != nil {
+		klog.Errorf("etcd-health-controller: update etcd status for %s error: %v", request.NamespacedName, err)
+	}
+
+	if !healthy && vc.Spec.KubeInKubeConfig != nil && vc.Spec.KubeInKubeConfig.EtcdSelfHeal {
+		if err := r.healUnhealthyMembers(ctx, probe, vc.Namespace, unhealthyNames); err != nil {
+			klog.Errorf("etcd-health-controller: heal unhealthy etcd members for %s error: %v", request.NamespacedName, err)
+		}
+	}
+
+	return reconcile.Result{RequeueAfter: constants.EtcdHealthCheckInterval}, nil
+}
+
+// healUnhealthyMembers recovers each lost member by evicting it from the
+// etcd cluster and deleting its pod, so the StatefulSet recreates it and it
+// rejoins in its place.
+func (r *EtcdHealthController) healUnhealthyMembers(ctx context.Context, probe EtcdClusterProbe, namespace string, names []string) error {
+	for _, name := range names {
+		if err := probe.RemoveMember(ctx, name); err != nil {
+			return fmt.Errorf("remove etcd member %s: %w", name, err)
+		}
+		if err := r.RootClientSet.CoreV1().Pods(namespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("restart etcd pod %s: %w", name, err)
+		}
+		klog.InfoS("etcd-health-controller: evicted lost etcd member and restarted its pod to rejoin", "member", name, "namespace", namespace)
+	}
+	return nil
+}
+
+func (r *EtcdHealthController) updateEtcdStatus(ctx context.Context, name types.NamespacedName, healthy bool, unhealthyMembers []string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var vc v1alpha1.VirtualCluster
+		if err := r.Get(ctx, name, &vc); err != nil {
+			return err
+		}
+
+		if vc.Status.EtcdHealthy != nil && *vc.Status.EtcdHealthy == healthy && stringSlicesEqual(vc.Status.EtcdUnhealthyMembers, unhealthyMembers) {
+			return nil
+		}
+
+		vc.Status.EtcdHealthy = &healthy
+		vc.Status.EtcdUnhealthyMembers = unhealthyMembers
+		return r.Update(ctx, &vc)
+	})
+}
+
+func stringSlicesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// clientv3EtcdProbe is the real EtcdClusterProbe, backed by an etcd client
+// connected through the VirtualCluster's etcd-client Service.
+type clientv3EtcdProbe struct {
+	cli             *clientv3.Client
+	peerServiceFQDN string
+}
+
+// newClientv3EtcdProbe builds a clientv3EtcdProbe for vc, authenticating
+// with the etcd client certificate uploaded to the host cluster during
+// cluster creation (see runUploadEtcdCert).
+func newClientv3EtcdProbe(hostClient kubernetes.Interface, vc *v1alpha1.VirtualCluster) (EtcdClusterProbe, error) {
+	cli, err := util.NewEtcdClient(hostClient, vc)
+	if err != nil {
+		return nil, fmt.Errorf("build etcd client: %w", err)
+	}
+
+	return &clientv3EtcdProbe{
+		cli:             cli,
+		peerServiceFQDN: fmt.Sprintf("%s.%s.svc.cluster.local", util.GetEtcdServerName(vc.Name), vc.Namespace),
+	}, nil
+}
+
+// ListMembers lists the cluster's members and probes each one directly
+// through its peer-service DNS name, so a member the client-service load
+// balancer is quietly routing around is still reported as unhealthy.
+func (p *clientv3EtcdProbe) ListMembers(ctx context.Context) ([]EtcdMember, error) {
+	resp, err := p.cli.MemberList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]EtcdMember, 0, len(resp.Members))
+	for _, member := range resp.Members {
+		endpoint := fmt.Sprintf("https://%s.%s:%d", member.Name, p.peerServiceFQDN, constants.EtcdListenClientPort)
+		_, statusErr := p.cli.Status(ctx, endpoint)
+		members = append(members, EtcdMember{Name: member.Name, Healthy: statusErr == nil})
+	}
+	return members, nil
+}
+
+func (p *clientv3EtcdProbe) RemoveMember(ctx context.Context, name string) error {
+	resp, err := p.cli.MemberList(ctx)
+	if err != nil {
+		return err
+	}
+	for _, member := range resp.Members {
+		if member.Name == name {
+			_, err := p.cli.MemberRemove(ctx, member.ID)
+			return err
+		}
+	}
+	return fmt.Errorf("etcd member %s not found", name)
+}
+
+func (p *clientv3EtcdProbe) Close() error {
+	return p.cli.Close()
+}
@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+)
+
+// stubEtcdProbe simulates an etcd cluster, recording which members were
+// evicted via RemoveMember.
+type stubEtcdProbe struct {
+	members []EtcdMember
+	removed []string
+}
+
+func (p *stubEtcdProbe) ListMembers(context.Context) ([]EtcdMember, error) {
+	return p.members, nil
+}
+
+func (p *stubEtcdProbe) RemoveMember(_ context.Context, name string) error {
+	for _, member := range p.members {
+		if member.Name == name {
+			p.removed = append(p.removed, name)
+			return nil
+		}
+	}
+	return fmt.Errorf("etcd member %s not found", name)
+}
+
+func (p *stubEtcdProbe) Close() error { return nil }
+
+func TestEtcdHealthControllerSurfacesLostMember(t *testing.T) {
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1", Namespace: "default"},
+		Status:     v1alpha1.VirtualClusterStatus{Phase: v1alpha1.Completed},
+	}
+	probe := &stubEtcdProbe{members: []EtcdMember{
+		{Name: "vc1-etcd-0", Healthy: true},
+		{Name: "vc1-etcd-1", Healthy: false},
+		{Name: "vc1-etcd-2", Healthy: true},
+	}}
+	store := &vcStoreClient{vc: vc}
+
+	controller := &EtcdHealthController{
+		Client: store,
+		GenerateEtcdProbe: func(kubernetes.Interface, *v1alpha1.VirtualCluster) (EtcdClusterProbe, error) {
+			return probe, nil
+		},
+	}
+
+	if _, err := controller.Reconcile(context.TODO(), reconcileRequest(vc)); err != nil {
+		t.Fatalf("Reconcile() failed: %s", err)
+	}
+
+	if store.vc.Status.EtcdHealthy == nil || *store.vc.Status.EtcdHealthy {
+		t.Fatalf("expected EtcdHealthy=false, got %v", store.vc.Status.EtcdHealthy)
+	}
+	if len(store.vc.Status.EtcdUnhealthyMembers) != 1 || store.vc.Status.EtcdUnhealthyMembers[0] != "vc1-etcd-1" {
+		t.Fatalf("expected EtcdUnhealthyMembers = [vc1-etcd-1], got %v", store.vc.Status.EtcdUnhealthyMembers)
+	}
+	if len(probe.removed) != 0 {
+		t.Fatalf("expected no member to be evicted without EtcdSelfHeal enabled, got %v", probe.removed)
+	}
+}
+
+func TestEtcdHealthControllerHealsLostMemberWhenSelfHealEnabled(t *testing.T) {
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1", Namespace: "default"},
+		Spec:       v1alpha1.VirtualClusterSpec{KubeInKubeConfig: &v1alpha1.KubeInKubeConfig{EtcdSelfHeal: true}},
+		Status:     v1alpha1.VirtualClusterStatus{Phase: v1alpha1.Completed},
+	}
+	lostMemberPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "vc1-etcd-1", Namespace: "default"}}
+	hostClient := fake.NewSimpleClientset(lostMemberPod)
+	probe := &stubEtcdProbe{members: []EtcdMember{
+		{Name: "vc1-etcd-0", Healthy: true},
+		{Name: "vc1-etcd-1", Healthy: false},
+		{Name: "vc1-etcd-2", Healthy: true},
+	}}
+	store := &vcStoreClient{vc: vc}
+
+	controller := &EtcdHealthController{
+		Client:        store,
+		RootClientSet: hostClient,
+		GenerateEtcdProbe: func(kubernetes.Interface, *v1alpha1.VirtualCluster) (EtcdClusterProbe, error) {
+			return probe, nil
+		},
+	}
+
+	if _, err := controller.Reconcile(context.TODO(), reconcileRequest(vc)); err != nil {
+		t.Fatalf("Reconcile() failed: %s", err)
+	}
+
+	if len(probe.removed) != 1 || probe.removed[0] != "vc1-etcd-1" {
+		t.Fatalf("expected the lost member to be evicted from etcd, got %v", probe.removed)
+	}
+	if _, err := hostClient.CoreV1().Pods("default").Get(context.TODO(), "vc1-etcd-1", metav1.GetOptions{}); err == nil {
+		t.Fatalf("expected the lost member's pod to be deleted so it can rejoin")
+	}
+}
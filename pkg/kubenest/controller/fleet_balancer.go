@@ -0,0 +1,165 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+)
+
+// FleetNodeAssignment is the deterministic, side-effect-free node assignment
+// BuildFleetAssignment computes across every VirtualCluster sharing a
+// GlobalNode pool.
+type FleetNodeAssignment struct {
+	// Assignments maps a VirtualCluster's name to the GlobalNode names
+	// BuildFleetAssignment assigned it, including nodes it already held.
+	Assignments map[string][]string
+}
+
+// BuildFleetAssignment assigns globalNodes to virtualClusters' PromotePolicy
+// demand so as to minimize the maximum number of nodes any single
+// VirtualCluster ends up with in any one zone, the value of zoneLabelKey on
+// GlobalNode.Spec.Labels - a fair spread across zones per cluster, rather
+// than the first-come greedy result of resolving each VirtualCluster's
+// policies in isolation. It does not mutate virtualClusters or globalNodes.
+//
+// Nodes a VirtualCluster already holds, per GlobalNode.Status.VirtualCluster,
+// are left assigned to that cluster; only free nodes are newly distributed,
+// in rounds where each VirtualCluster with remaining demand greedily takes
+// one free node from the zone in which it currently has the fewest nodes.
+// VirtualClusters are visited in name order and free nodes in name order, so
+// calling BuildFleetAssignment twice with the same inputs always produces
+// the same assignment and callers built on top of it converge instead of
+// oscillating between equally-good layouts.
+func BuildFleetAssignment(virtualClusters []v1alpha1.VirtualCluster, globalNodes []v1alpha1.GlobalNode, zoneLabelKey string) *FleetNodeAssignment {
+	zoneOf := make(map[string]string, len(globalNodes))
+	for _, node := range globalNodes {
+		zoneOf[node.Name] = node.Spec.Labels[zoneLabelKey]
+	}
+
+	knownVirtualCluster := make(map[string]bool, len(virtualClusters))
+	for _, vc := range virtualClusters {
+		knownVirtualCluster[vc.Name] = true
+	}
+
+	assignment := &FleetNodeAssignment{Assignments: map[string][]string{}}
+	zoneCount := map[string]map[string]int{}
+	assign := func(vcName, nodeName string) {
+		assignment.Assignments[vcName] = append(assignment.Assignments[vcName], nodeName)
+		if zoneCount[vcName] == nil {
+			zoneCount[vcName] = map[string]int{}
+		}
+		zoneCount[vcName][zoneOf[nodeName]]++
+	}
+
+	var freeNames []string
+	for _, node := range globalNodes {
+		if node.Status.VirtualCluster != "" {
+			if knownVirtualCluster[node.Status.VirtualCluster] {
+				assign(node.Status.VirtualCluster, node.Name)
+			}
+			continue
+		}
+		if node.Spec.State != v1alpha1.NodeFreeState {
+			continue
+		}
+		freeNames = append(freeNames, node.Name)
+	}
+	sort.Strings(freeNames)
+
+	demand := make(map[string]int32, len(virtualClusters))
+	var wanting []string
+	for _, vc := range virtualClusters {
+		var want int32
+		for _, policy := range vc.Spec.PromotePolicies {
+			want += policy.NodeCount
+		}
+		if already := int32(len(assignment.Assignments[vc.Name])); want > already {
+			demand[vc.Name] = want - already
+			wanting = append(wanting, vc.Name)
+		}
+	}
+	sort.Strings(wanting)
+
+	for {
+		progressed := false
+		for _, vcName := range wanting {
+			if demand[vcName] <= 0 || len(freeNames) == 0 {
+				continue
+			}
+			bestIdx, bestCount := -1, 0
+			for i, nodeName := range freeNames {
+				count := zoneCount[vcName][zoneOf[nodeName]]
+				if bestIdx == -1 || count < bestCount {
+					bestIdx, bestCount = i, count
+				}
+			}
+			chosen := freeNames[bestIdx]
+			freeNames = append(freeNames[:bestIdx], freeNames[bestIdx+1:]...)
+			assign(vcName, chosen)
+			demand[vcName]--
+			progressed = true
+		}
+		if !progressed {
+			break
+		}
+	}
+
+	return assignment
+}
+
+// fleetAwareSyntheticVirtualCluster is the name FleetAwareNodeSelectionStrategy
+// registers with BuildFleetAssignment to stand in for the PromotePolicy
+// currently being resolved, so its demand competes fairly for zone balance
+// against the fleet's already-recorded GlobalNode ownership.
+const fleetAwareSyntheticVirtualCluster = "__fleet-aware-selection__"
+
+// FleetAwareNodeSelectionStrategy is an opt-in NodeSelectionStrategy that
+// looks beyond the policy being resolved: candidates already carry, via
+// GlobalNode.Status.VirtualCluster, which other VirtualClusters sharing this
+// same label pool hold which nodes, so Select can spread the new nodes
+// across zones with the fleet's existing occupancy in mind instead of
+// starting from a clean slate every time, which is what
+// TopologySpreadNodeSelectionStrategy does.
+type FleetAwareNodeSelectionStrategy struct {
+	// ZoneLabel is the key into GlobalNode.Spec.Labels whose distinct
+	// values define a zone, e.g. "topology.kubernetes.io/zone".
+	ZoneLabel string
+}
+
+func (s FleetAwareNodeSelectionStrategy) Select(candidates []v1alpha1.GlobalNode, count int32, _ v1alpha1.PromotePolicy) ([]v1alpha1.GlobalNode, error) {
+	owners := map[string]bool{}
+	for _, node := range candidates {
+		if node.Status.VirtualCluster != "" {
+			owners[node.Status.VirtualCluster] = true
+		}
+	}
+
+	virtualClusters := make([]v1alpha1.VirtualCluster, 0, len(owners)+1)
+	for name := range owners {
+		virtualClusters = append(virtualClusters, v1alpha1.VirtualCluster{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+	virtualClusters = append(virtualClusters, v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: fleetAwareSyntheticVirtualCluster},
+		Spec:       v1alpha1.VirtualClusterSpec{PromotePolicies: []v1alpha1.PromotePolicy{{NodeCount: count}}},
+	})
+
+	assignment := BuildFleetAssignment(virtualClusters, candidates, s.ZoneLabel)
+	selectedNames := assignment.Assignments[fleetAwareSyntheticVirtualCluster]
+
+	byName := make(map[string]v1alpha1.GlobalNode, len(candidates))
+	for _, node := range candidates {
+		byName[node.Name] = node
+	}
+	selected := make([]v1alpha1.GlobalNode, 0, len(selectedNames))
+	for _, name := range selectedNames {
+		selected = append(selected, byName[name])
+	}
+
+	if int32(len(selected)) < count {
+		return selected, fmt.Errorf("not enough free nodes: want %d, found %d", count, len(selected))
+	}
+	return selected, nil
+}
@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"sort"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+)
+
+func fleetGlobalNode(name string, zone string, owner string) v1alpha1.GlobalNode {
+	node := v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeFreeState, Labels: labels.Set{"zone": zone}},
+	}
+	if owner != "" {
+		node.Spec.State = v1alpha1.NodeInUse
+		node.Status.VirtualCluster = owner
+	}
+	return node
+}
+
+func TestBuildFleetAssignmentKeepsAlreadyOwnedNodes(t *testing.T) {
+	globalNodes := []v1alpha1.GlobalNode{
+		fleetGlobalNode("zone-a-1", "a", "vc-existing"),
+		fleetGlobalNode("zone-a-2", "a", "vc-existing"),
+		fleetGlobalNode("zone-a-3", "a", ""),
+		fleetGlobalNode("zone-b-1", "b", ""),
+	}
+	virtualClusters := []v1alpha1.VirtualCluster{
+		{ObjectMeta: metav1.ObjectMeta{Name: "vc-existing"}},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "vc-new"},
+			Spec:       v1alpha1.VirtualClusterSpec{PromotePolicies: []v1alpha1.PromotePolicy{{NodeCount: 1}}},
+		},
+	}
+
+	assignment := BuildFleetAssignment(virtualClusters, globalNodes, "zone")
+
+	existing := assignment.Assignments["vc-existing"]
+	sort.Strings(existing)
+	if len(existing) != 2 || existing[0] != "zone-a-1" || existing[1] != "zone-a-2" {
+		t.Errorf("vc-existing assignment = %v, want unchanged [zone-a-1 zone-a-2]", existing)
+	}
+	if got := assignment.Assignments["vc-new"]; len(got) != 1 {
+		t.Errorf("vc-new assignment = %v, want exactly 1 node", got)
+	}
+}
+
+func TestBuildFleetAssignmentSpreadsEachClusterAcrossZonesWhenSharingAPool(t *testing.T) {
+	globalNodes := []v1alpha1.GlobalNode{
+		fleetGlobalNode("zone-a-1", "a", ""),
+		fleetGlobalNode("zone-a-2", "a", ""),
+		fleetGlobalNode("zone-b-1", "b", ""),
+		fleetGlobalNode("zone-b-2", "b", ""),
+	}
+	virtualClusters := []v1alpha1.VirtualCluster{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "vc-1"},
+			Spec:       v1alpha1.VirtualClusterSpec{PromotePolicies: []v1alpha1.PromotePolicy{{NodeCount: 2}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "vc-2"},
+			Spec:       v1alpha1.VirtualClusterSpec{PromotePolicies: []v1alpha1.PromotePolicy{{NodeCount: 2}}},
+		},
+	}
+
+	assignment := BuildFleetAssignment(virtualClusters, globalNodes, "zone")
+
+	zonesOf := func(names []string) map[string]bool {
+		zones := map[string]bool{}
+		for _, name := range names {
+			zones[zoneOfTestNode(globalNodes, name)] = true
+		}
+		return zones
+	}
+	if zones := zonesOf(assignment.Assignments["vc-1"]); len(zones) != 2 {
+		t.Errorf("vc-1 assignment = %v, want one node from each zone", assignment.Assignments["vc-1"])
+	}
+	if zones := zonesOf(assignment.Assignments["vc-2"]); len(zones) != 2 {
+		t.Errorf("vc-2 assignment = %v, want one node from each zone", assignment.Assignments["vc-2"])
+	}
+}
+
+func zoneOfTestNode(globalNodes []v1alpha1.GlobalNode, name string) string {
+	for _, node := range globalNodes {
+		if node.Name == name {
+			return node.Spec.Labels["zone"]
+		}
+	}
+	return ""
+}
+
+func TestBuildFleetAssignmentDeterministic(t *testing.T) {
+	globalNodes := []v1alpha1.GlobalNode{
+		fleetGlobalNode("zone-a-1", "a", ""),
+		fleetGlobalNode("zone-b-1", "b", ""),
+		fleetGlobalNode("zone-b-2", "b", ""),
+	}
+	virtualClusters := []v1alpha1.VirtualCluster{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "vc-1"},
+			Spec:       v1alpha1.VirtualClusterSpec{PromotePolicies: []v1alpha1.PromotePolicy{{NodeCount: 1}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "vc-2"},
+			Spec:       v1alpha1.VirtualClusterSpec{PromotePolicies: []v1alpha1.PromotePolicy{{NodeCount: 1}}},
+		},
+	}
+
+	first := BuildFleetAssignment(virtualClusters, globalNodes, "zone")
+	second := BuildFleetAssignment(virtualClusters, globalNodes, "zone")
+
+	if got, want := first.Assignments["vc-1"], second.Assignments["vc-1"]; len(got) != len(want) || got[0] != want[0] {
+		t.Errorf("non-deterministic vc-1 assignment: %v vs %v", got, want)
+	}
+	if got, want := first.Assignments["vc-2"], second.Assignments["vc-2"]; len(got) != len(want) || got[0] != want[0] {
+		t.Errorf("non-deterministic vc-2 assignment: %v vs %v", got, want)
+	}
+}
+
+func TestFleetAwareNodeSelectionStrategySpreadsAcrossZones(t *testing.T) {
+	candidates := []v1alpha1.GlobalNode{
+		fleetGlobalNode("zone-a-1", "a", ""),
+		fleetGlobalNode("zone-a-2", "a", ""),
+		fleetGlobalNode("zone-b-1", "b", ""),
+	}
+	strategy := FleetAwareNodeSelectionStrategy{ZoneLabel: "zone"}
+
+	selected, err := strategy.Select(candidates, 2, v1alpha1.PromotePolicy{})
+	if err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+	zones := map[string]bool{}
+	for _, node := range selected {
+		zones[node.Spec.Labels["zone"]] = true
+	}
+	if len(selected) != 2 || len(zones) != 2 {
+		t.Errorf("Select() = %v, want one node from each of zone a and zone b", selected)
+	}
+}
+
+func TestFleetAwareNodeSelectionStrategyErrorsWhenNotEnoughFreeNodes(t *testing.T) {
+	candidates := []v1alpha1.GlobalNode{fleetGlobalNode("zone-a-1", "a", "")}
+	strategy := FleetAwareNodeSelectionStrategy{ZoneLabel: "zone"}
+
+	if _, err := strategy.Select(candidates, 2, v1alpha1.PromotePolicy{}); err == nil {
+		t.Fatal("expected an error when fewer free nodes than requested, got nil")
+	}
+}
@@ -211,6 +211,64 @@ func (r *GlobalNodeController) SyncLabel(ctx context.Context, globalNode *v1alph
 	return err
 }
 
+// SyncTaints mirrors the host node's taints onto the GlobalNode so that
+// PromotePolicy.Tolerations can exclude candidate nodes carrying a taint it
+// does not tolerate.
+func (r *GlobalNodeController) SyncTaints(ctx context.Context, globalNode *v1alpha1.GlobalNode) error {
+	if globalNode.Spec.State == v1alpha1.NodeInUse {
+		klog.V(4).Infof("global-node-controller: SyncTaints: node is in use %s, skip", globalNode.Name)
+		return nil
+	}
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		rootNode, err := r.RootClientSet.CoreV1().Nodes().Get(ctx, globalNode.Name, metav1.GetOptions{})
+		if err != nil {
+			klog.Errorf("global-node-controller: SyncTaints: can not get root node: %s", globalNode.Name)
+			return err
+		}
+
+		current, err := r.KosmosClient.KosmosV1alpha1().GlobalNodes().Get(ctx, globalNode.Name, metav1.GetOptions{})
+		if err != nil {
+			klog.Errorf("global-node-controller: SyncTaints: can not get global node: %s", globalNode.Name)
+			return err
+		}
+
+		if equalTaints(current.Spec.Taints, rootNode.Spec.Taints) {
+			return nil
+		}
+
+		updateGlobalNode := current.DeepCopy()
+		updateGlobalNode.Spec.Taints = rootNode.Spec.Taints
+
+		if _, err = r.KosmosClient.KosmosV1alpha1().GlobalNodes().Update(ctx, updateGlobalNode, metav1.UpdateOptions{}); err != nil {
+			klog.Errorf("global-node-controller: SyncTaints: update global node taints failed, err: %s", err)
+			return err
+		}
+		return nil
+	})
+	return err
+}
+
+// equalTaints reports whether two taint slices contain the same taints,
+// ignoring order.
+func equalTaints(taints1, taints2 []v1.Taint) bool {
+	if len(taints1) != len(taints2) {
+		return false
+	}
+	for _, taint1 := range taints1 {
+		found := false
+		for _, taint2 := range taints2 {
+			if taint1 == taint2 {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
 func (r *GlobalNodeController) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
 	klog.V(4).Infof("============ global-node-controller start to reconcile %s ============", request.NamespacedName)
 	defer klog.V(4).Infof("============ global-node-controller finish to reconcile %s ============", request.NamespacedName)
@@ -269,6 +327,12 @@ func (r *GlobalNodeController) Reconcile(ctx context.Context, request reconcile.
 	}
 	klog.V(4).Infof("sync label successed, %s", request.NamespacedName)
 
+	if err = r.SyncTaints(ctx, &globalNode); err != nil {
+		klog.Warningf("sync taints %s error: %v", request.NamespacedName, err)
+		return reconcile.Result{RequeueAfter: utils.DefaultRequeueTime}, nil
+	}
+	klog.V(4).Infof("sync taints successed, %s", request.NamespacedName)
+
 	if err = r.SyncTaint(ctx, &globalNode); err != nil {
 		klog.Errorf("sync taint %s error: %v", request.NamespacedName, err)
 		return reconcile.Result{RequeueAfter: utils.DefaultRequeueTime}, nil
@@ -0,0 +1,206 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/constants"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/util"
+)
+
+// NodeAutoscaleController grows a Completed VirtualCluster's assigned nodes
+// when tenant pods have been unable to schedule for too long, and shrinks
+// them again once the cluster is no longer under pressure, for any
+// PromotePolicy that opts in via AutoScale. Scaling is done by nudging the
+// PromotePolicy's NodeCount up or down by one node per reconcile; the
+// existing node-assignment and node-join/unjoin machinery does the rest,
+// draining a node's workloads the same way a manual NodeCount edit would.
+// A scale-down is withheld for constants.NodeAutoscaleCooldown after the
+// controller's own last scale-up, so a node added to relieve pending pods
+// isn't immediately reclaimed again before it has had a chance to.
+type NodeAutoscaleController struct {
+	client.Client
+	// GenerateTenantClient builds a client for the virtual cluster's own
+	// apiserver. Defaults to util.GenerateKubeclient; overridable in tests.
+	GenerateTenantClient func(*v1alpha1.VirtualCluster) (kubernetes.Interface, error)
+}
+
+func (r *NodeAutoscaleController) SetupWithManager(mgr manager.Manager) error {
+	if r.Client == nil {
+		r.Client = mgr.GetClient()
+	}
+	if r.GenerateTenantClient == nil {
+		r.GenerateTenantClient = util.GenerateKubeclient
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(constants.NodeAutoscaleControllerName).
+		For(&v1alpha1.VirtualCluster{}).
+		Complete(r)
+}
+
+func (r *NodeAutoscaleController) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	var vc v1alpha1.VirtualCluster
+	if err := r.Get(ctx, request.NamespacedName, &vc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if vc.Status.Phase != v1alpha1.Completed {
+		return reconcile.Result{}, nil
+	}
+	if !hasAutoScalePolicy(vc.Spec.PromotePolicies) {
+		return reconcile.Result{}, nil
+	}
+
+	tenantClient, err := r.GenerateTenantClient(&vc)
+	if err != nil {
+		klog.Errorf("node-autoscale-controller: generate tenant client for %s error: %v", request.NamespacedName, err)
+		return reconcile.Result{RequeueAfter: constants.NodeAutoscaleCheckInterval}, nil
+	}
+
+	pods, err := tenantClient.CoreV1().Pods(corev1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		klog.Errorf("node-autoscale-controller: list pods for %s error: %v", request.NamespacedName, err)
+		return reconcile.Result{RequeueAfter: constants.NodeAutoscaleCheckInterval}, nil
+	}
+
+	window := autoScalePendingPodsWindow(vc.Spec.PromotePolicies)
+	now := time.Now()
+	scaleUp := hasPersistentlyPendingPod(pods.Items, window, now)
+
+	if err := r.adjustNodeCount(ctx, request.NamespacedName, scaleUp, now); err != nil {
+		klog.Errorf("node-autoscale-controller: adjust node count for %s error: %v", request.NamespacedName, err)
+	}
+
+	return reconcile.Result{RequeueAfter: constants.NodeAutoscaleCheckInterval}, nil
+}
+
+func (r *NodeAutoscaleController) adjustNodeCount(ctx context.Context, name types.NamespacedName, scaleUp bool, now time.Time) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		var vc v1alpha1.VirtualCluster
+		if err := r.Get(ctx, name, &vc); err != nil {
+			return err
+		}
+
+		if !scaleUp && inNodeAutoscaleCooldown(vc.Annotations, now) {
+			klog.V(2).InfoS("node-autoscale-controller: skipping scale-down within cooldown of last scale-up", "virtualcluster", name)
+			return nil
+		}
+
+		idx := adjustAutoScaledPolicyNodeCount(vc.Spec.PromotePolicies, scaleUp)
+		if idx < 0 {
+			return nil
+		}
+
+		if scaleUp {
+			if vc.Annotations == nil {
+				vc.Annotations = map[string]string{}
+			}
+			vc.Annotations[constants.NodeAutoscaleLastScaleUpAnnotation] = now.Format(time.RFC3339)
+		}
+
+		klog.InfoS("node-autoscale-controller: adjusted promote policy node count", "virtualcluster", name, "policyIndex", idx, "nodeCount", vc.Spec.PromotePolicies[idx].NodeCount, "scaleUp", scaleUp)
+		return r.Update(ctx, &vc)
+	})
+}
+
+// inNodeAutoscaleCooldown reports whether annotations record a scale-up
+// within constants.NodeAutoscaleCooldown of now, so adjustNodeCount can
+// hold off on scaling back down immediately - a pod that just triggered a
+// scale-up needs time to actually land on the new node before utilization
+// is fairly re-evaluated, otherwise the next reconcile sees it still
+// pending and flaps straight back up.
+func inNodeAutoscaleCooldown(annotations map[string]string, now time.Time) bool {
+	raw := annotations[constants.NodeAutoscaleLastScaleUpAnnotation]
+	if raw == "" {
+		return false
+	}
+	lastScaleUp, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return false
+	}
+	return now.Sub(lastScaleUp) < constants.NodeAutoscaleCooldown
+}
+
+// hasAutoScalePolicy reports whether any of policies opts into node
+// autoscaling.
+func hasAutoScalePolicy(policies []v1alpha1.PromotePolicy) bool {
+	for _, policy := range policies {
+		if policy.AutoScale != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// autoScalePendingPodsWindow returns the pending-pods window to scale up on,
+// taken from the first autoscale-enabled policy that sets one, falling back
+// to constants.NodeAutoscaleDefaultPendingPodsWindow.
+func autoScalePendingPodsWindow(policies []v1alpha1.PromotePolicy) time.Duration {
+	for _, policy := range policies {
+		if policy.AutoScale == nil || policy.AutoScale.PendingPodsWindow == "" {
+			continue
+		}
+		if window, err := time.ParseDuration(policy.AutoScale.PendingPodsWindow); err == nil {
+			return window
+		}
+	}
+	return constants.NodeAutoscaleDefaultPendingPodsWindow
+}
+
+// hasPersistentlyPendingPod reports whether any pod in pods has been unable
+// to schedule for at least window, the signal the node-autoscale controller
+// scales up on.
+func hasPersistentlyPendingPod(pods []corev1.Pod, window time.Duration, now time.Time) bool {
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodPending {
+			continue
+		}
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type != corev1.PodScheduled || cond.Status != corev1.ConditionFalse {
+				continue
+			}
+			if now.Sub(cond.LastTransitionTime.Time) >= window {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// adjustAutoScaledPolicyNodeCount scales the first autoscale-enabled policy
+// with headroom by one node: up when scaleUp and it's below MaxNodeCount,
+// down when !scaleUp and it's above MinNodeCount. It mutates policies in
+// place and returns the index it changed, or -1 if none needed adjusting.
+func adjustAutoScaledPolicyNodeCount(policies []v1alpha1.PromotePolicy, scaleUp bool) int {
+	for i := range policies {
+		autoScale := policies[i].AutoScale
+		if autoScale == nil {
+			continue
+		}
+		if scaleUp && policies[i].NodeCount < autoScale.MaxNodeCount {
+			policies[i].NodeCount++
+			return i
+		}
+		if !scaleUp && policies[i].NodeCount > autoScale.MinNodeCount {
+			policies[i].NodeCount--
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,198 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/constants"
+)
+
+func newAutoScalePolicy(nodeCount, min, max int32) v1alpha1.PromotePolicy {
+	return v1alpha1.PromotePolicy{
+		NodeCount: nodeCount,
+		AutoScale: &v1alpha1.PromotePolicyAutoScale{MinNodeCount: min, MaxNodeCount: max},
+	}
+}
+
+func pendingUnschedulablePod(since time.Time) corev1.Pod {
+	return corev1.Pod{
+		Status: corev1.PodStatus{
+			Phase: corev1.PodPending,
+			Conditions: []corev1.PodCondition{
+				{
+					Type:               corev1.PodScheduled,
+					Status:             corev1.ConditionFalse,
+					Reason:             "Unschedulable",
+					LastTransitionTime: metav1.NewTime(since),
+				},
+			},
+		},
+	}
+}
+
+func TestHasPersistentlyPendingPod(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name string
+		pods []corev1.Pod
+		want bool
+	}{
+		{name: "no pods", pods: nil, want: false},
+		{name: "pending but still within window", pods: []corev1.Pod{pendingUnschedulablePod(now.Add(-time.Minute))}, want: false},
+		{name: "pending beyond window", pods: []corev1.Pod{pendingUnschedulablePod(now.Add(-10 * time.Minute))}, want: true},
+		{name: "running pod ignored", pods: []corev1.Pod{{Status: corev1.PodStatus{Phase: corev1.PodRunning}}}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasPersistentlyPendingPod(tt.pods, 5*time.Minute, now); got != tt.want {
+				t.Errorf("hasPersistentlyPendingPod() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdjustAutoScaledPolicyNodeCountScalesUpWithinMax(t *testing.T) {
+	policies := []v1alpha1.PromotePolicy{newAutoScalePolicy(2, 1, 3)}
+
+	idx := adjustAutoScaledPolicyNodeCount(policies, true)
+	if idx != 0 {
+		t.Fatalf("expected policy 0 to be adjusted, got %d", idx)
+	}
+	if policies[0].NodeCount != 3 {
+		t.Errorf("NodeCount = %d, want 3", policies[0].NodeCount)
+	}
+
+	// already at MaxNodeCount: no further scale-up.
+	if idx := adjustAutoScaledPolicyNodeCount(policies, true); idx != -1 {
+		t.Errorf("expected no adjustment once at MaxNodeCount, got index %d", idx)
+	}
+}
+
+func TestAdjustAutoScaledPolicyNodeCountScalesDownWithinMin(t *testing.T) {
+	policies := []v1alpha1.PromotePolicy{newAutoScalePolicy(2, 1, 3)}
+
+	idx := adjustAutoScaledPolicyNodeCount(policies, false)
+	if idx != 0 || policies[0].NodeCount != 1 {
+		t.Fatalf("expected policy 0 to scale down to 1, got index %d nodeCount %d", idx, policies[0].NodeCount)
+	}
+
+	// already at MinNodeCount: no further scale-down.
+	if idx := adjustAutoScaledPolicyNodeCount(policies, false); idx != -1 {
+		t.Errorf("expected no adjustment once at MinNodeCount, got index %d", idx)
+	}
+}
+
+func TestReconcileScalesUpOnPersistentlyPendingPods(t *testing.T) {
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1", Namespace: "default"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			PromotePolicies: []v1alpha1.PromotePolicy{newAutoScalePolicy(2, 1, 3)},
+		},
+		Status: v1alpha1.VirtualClusterStatus{Phase: v1alpha1.Completed},
+	}
+
+	pendingPod := pendingUnschedulablePod(time.Now().Add(-10 * time.Minute))
+	pendingPod.Name = "pending-pod"
+	pendingPod.Namespace = "tenant-ns"
+	tenantClient := fake.NewSimpleClientset(&pendingPod)
+
+	store := &vcStoreClient{vc: vc}
+	r := &NodeAutoscaleController{
+		Client: store,
+		GenerateTenantClient: func(*v1alpha1.VirtualCluster) (kubernetes.Interface, error) {
+			return tenantClient, nil
+		},
+	}
+
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: "vc1", Namespace: "default"}}
+
+	if _, err := r.Reconcile(context.TODO(), request); err != nil {
+		t.Fatalf("Reconcile() failed: %v", err)
+	}
+
+	if got := store.vc.Spec.PromotePolicies[0].NodeCount; got != 3 {
+		t.Errorf("NodeCount = %d, want 3 (scaled up by one, within MaxNodeCount)", got)
+	}
+
+	// a second reconcile must not scale past MaxNodeCount.
+	if _, err := r.Reconcile(context.TODO(), request); err != nil {
+		t.Fatalf("Reconcile() failed: %v", err)
+	}
+	if got := store.vc.Spec.PromotePolicies[0].NodeCount; got != 3 {
+		t.Errorf("NodeCount = %d, want to stay at MaxNodeCount 3", got)
+	}
+}
+
+func TestReconcileWithholdsScaleDownWithinCooldownOfScaleUp(t *testing.T) {
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1", Namespace: "default"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			PromotePolicies: []v1alpha1.PromotePolicy{newAutoScalePolicy(2, 1, 3)},
+		},
+		Status: v1alpha1.VirtualClusterStatus{Phase: v1alpha1.Completed},
+	}
+
+	pendingPod := pendingUnschedulablePod(time.Now().Add(-10 * time.Minute))
+	pendingPod.Name = "pending-pod"
+	pendingPod.Namespace = "tenant-ns"
+	tenantClient := fake.NewSimpleClientset(&pendingPod)
+
+	store := &vcStoreClient{vc: vc}
+	r := &NodeAutoscaleController{
+		Client: store,
+		GenerateTenantClient: func(*v1alpha1.VirtualCluster) (kubernetes.Interface, error) {
+			return tenantClient, nil
+		},
+	}
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Name: "vc1", Namespace: "default"}}
+
+	// first reconcile: pending pod triggers a scale-up.
+	if _, err := r.Reconcile(context.TODO(), request); err != nil {
+		t.Fatalf("Reconcile() failed: %v", err)
+	}
+	if got := store.vc.Spec.PromotePolicies[0].NodeCount; got != 3 {
+		t.Fatalf("NodeCount = %d, want 3 after scale-up", got)
+	}
+
+	// the pending pod clears, so the next reconcile would otherwise scale
+	// back down immediately; the cooldown must withhold it instead.
+	if err := tenantClient.CoreV1().Pods("tenant-ns").Delete(context.TODO(), "pending-pod", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("delete pending pod: %v", err)
+	}
+	if _, err := r.Reconcile(context.TODO(), request); err != nil {
+		t.Fatalf("Reconcile() failed: %v", err)
+	}
+	if got := store.vc.Spec.PromotePolicies[0].NodeCount; got != 3 {
+		t.Errorf("NodeCount = %d, want to stay at 3 immediately after scale-up (cooldown should withhold scale-down)", got)
+	}
+}
+
+func TestInNodeAutoscaleCooldown(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        bool
+	}{
+		{name: "no annotation", annotations: nil, want: false},
+		{name: "unparseable annotation", annotations: map[string]string{constants.NodeAutoscaleLastScaleUpAnnotation: "not-a-time"}, want: false},
+		{name: "recent scale-up", annotations: map[string]string{constants.NodeAutoscaleLastScaleUpAnnotation: now.Add(-time.Minute).Format(time.RFC3339)}, want: true},
+		{name: "scale-up beyond cooldown", annotations: map[string]string{constants.NodeAutoscaleLastScaleUpAnnotation: now.Add(-time.Hour).Format(time.RFC3339)}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := inNodeAutoscaleCooldown(tt.annotations, now); got != tt.want {
+				t.Errorf("inNodeAutoscaleCooldown() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package controller
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+)
+
+// NodeSelectionStrategy picks which of candidates to assign when a
+// PromotePolicy's NodeCount grows by count. candidates are the GlobalNodes
+// already matching the policy's LabelSelector and Tolerations, in both Free
+// and InUse states; implementations are responsible for only selecting nodes
+// in v1alpha1.NodeFreeState. Implementations must not mutate candidates, and
+// must return an error if fewer than count nodes can be selected.
+type NodeSelectionStrategy interface {
+	Select(candidates []v1alpha1.GlobalNode, count int32, policy v1alpha1.PromotePolicy) ([]v1alpha1.GlobalNode, error)
+}
+
+// FirstFitNodeSelectionStrategy selects the first count free nodes in
+// candidates' list order. This is the pre-existing greedy behavior; the
+// controller defaults to OrderedNodeSelectionStrategy instead.
+type FirstFitNodeSelectionStrategy struct{}
+
+func (FirstFitNodeSelectionStrategy) Select(candidates []v1alpha1.GlobalNode, count int32, _ v1alpha1.PromotePolicy) ([]v1alpha1.GlobalNode, error) {
+	var selected []v1alpha1.GlobalNode
+	for _, node := range candidates {
+		if node.Spec.State != v1alpha1.NodeFreeState {
+			continue
+		}
+		selected = append(selected, node)
+		if int32(len(selected)) == count {
+			break
+		}
+	}
+	if int32(len(selected)) < count {
+		return selected, fmt.Errorf("not enough free nodes: want %d, found %d", count, len(selected))
+	}
+	return selected, nil
+}
+
+// OrderedNodeSelectionStrategy selects the first count free candidates after
+// sorting them by policy.SelectionOrder, so which nodes get claimed is
+// deterministic and stable across reconciles -- for NodeSelectionOrderRandom,
+// merely reproducible for a fixed policy.RandomSeed -- instead of depending
+// on GlobalNode list order, which API pagination can reshuffle. This is the
+// controller's default strategy.
+type OrderedNodeSelectionStrategy struct{}
+
+func (OrderedNodeSelectionStrategy) Select(candidates []v1alpha1.GlobalNode, count int32, policy v1alpha1.PromotePolicy) ([]v1alpha1.GlobalNode, error) {
+	free := make([]v1alpha1.GlobalNode, 0, len(candidates))
+	for _, node := range candidates {
+		if node.Spec.State == v1alpha1.NodeFreeState {
+			free = append(free, node)
+		}
+	}
+
+	switch policy.SelectionOrder {
+	case v1alpha1.NodeSelectionOrderOldestFirst:
+		sort.SliceStable(free, func(i, j int) bool {
+			return free[i].CreationTimestamp.Before(&free[j].CreationTimestamp)
+		})
+	case v1alpha1.NodeSelectionOrderNewestFirst:
+		sort.SliceStable(free, func(i, j int) bool {
+			return free[j].CreationTimestamp.Before(&free[i].CreationTimestamp)
+		})
+	case v1alpha1.NodeSelectionOrderRandom:
+		rand.New(rand.NewSource(policy.RandomSeed)).Shuffle(len(free), func(i, j int) {
+			free[i], free[j] = free[j], free[i]
+		})
+	case v1alpha1.NodeSelectionOrderNameAsc, "":
+		sort.SliceStable(free, func(i, j int) bool {
+			return free[i].Name < free[j].Name
+		})
+	default:
+		return nil, fmt.Errorf("unknown PromotePolicy.SelectionOrder %q", policy.SelectionOrder)
+	}
+
+	if int32(len(free)) < count {
+		return free, fmt.Errorf("not enough free nodes: want %d, found %d", count, len(free))
+	}
+	return free[:count], nil
+}
+
+// TopologySpreadNodeSelectionStrategy spreads newly-assigned nodes across the
+// distinct values of a GlobalNode label, such as a failure-domain label, so a
+// PromotePolicy's nodes land across as many domains as possible rather than
+// piling onto whichever domain happens to come first in list order.
+type TopologySpreadNodeSelectionStrategy struct {
+	// TopologyLabel is the key into GlobalNode.Spec.Labels whose distinct
+	// values define the spread domains, e.g. "topology.kubernetes.io/zone".
+	// Nodes missing the label are grouped together under the empty-string
+	// domain.
+	TopologyLabel string
+}
+
+func (s TopologySpreadNodeSelectionStrategy) Select(candidates []v1alpha1.GlobalNode, count int32, _ v1alpha1.PromotePolicy) ([]v1alpha1.GlobalNode, error) {
+	var domainOrder []string
+	freeByDomain := map[string][]v1alpha1.GlobalNode{}
+	for _, node := range candidates {
+		if node.Spec.State != v1alpha1.NodeFreeState {
+			continue
+		}
+		domain := node.Spec.Labels[s.TopologyLabel]
+		if _, seen := freeByDomain[domain]; !seen {
+			domainOrder = append(domainOrder, domain)
+		}
+		freeByDomain[domain] = append(freeByDomain[domain], node)
+	}
+
+	var selected []v1alpha1.GlobalNode
+	for int32(len(selected)) < count {
+		progressed := false
+		for _, domain := range domainOrder {
+			remaining := freeByDomain[domain]
+			if len(remaining) == 0 {
+				continue
+			}
+			selected = append(selected, remaining[0])
+			freeByDomain[domain] = remaining[1:]
+			progressed = true
+			if int32(len(selected)) == count {
+				break
+			}
+		}
+		if !progressed {
+			break
+		}
+	}
+
+	if int32(len(selected)) < count {
+		return selected, fmt.Errorf("not enough free nodes: want %d, found %d", count, len(selected))
+	}
+	return selected, nil
+}
+
+// WeightedLabelNodeSelectionStrategy prefers candidates from higher-weighted
+// pools, so a single PromotePolicy whose LabelSelector draws nodes from
+// multiple pools via a matchExpressions In operator (e.g. "pool A OR pool B")
+// can still prefer one pool over the other instead of treating every
+// matching node interchangeably.
+type WeightedLabelNodeSelectionStrategy struct {
+	// Label is the key into GlobalNode.Spec.Labels distinguishing pools, e.g.
+	// "kosmos.io/pool".
+	Label string
+	// Weights maps a pool's label value to its selection weight; candidates
+	// from higher-weighted pools are selected first. A value absent from
+	// Weights, including an absent Label, is treated as weight 0.
+	Weights map[string]int
+}
+
+func (s WeightedLabelNodeSelectionStrategy) Select(candidates []v1alpha1.GlobalNode, count int32, _ v1alpha1.PromotePolicy) ([]v1alpha1.GlobalNode, error) {
+	free := make([]v1alpha1.GlobalNode, 0, len(candidates))
+	for _, node := range candidates {
+		if node.Spec.State == v1alpha1.NodeFreeState {
+			free = append(free, node)
+		}
+	}
+
+	sort.SliceStable(free, func(i, j int) bool {
+		return s.Weights[free[i].Spec.Labels[s.Label]] > s.Weights[free[j].Spec.Labels[s.Label]]
+	})
+
+	if int32(len(free)) < count {
+		return free, fmt.Errorf("not enough free nodes: want %d, found %d", count, len(free))
+	}
+	return free[:count], nil
+}
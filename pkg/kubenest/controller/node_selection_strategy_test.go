@@ -0,0 +1,223 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+)
+
+func globalNode(name string, state v1alpha1.NodeState, topologyLabels labels.Set) v1alpha1.GlobalNode {
+	return v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       v1alpha1.GlobalNodeSpec{State: state, Labels: topologyLabels},
+	}
+}
+
+func TestFirstFitNodeSelectionStrategySkipsInUseNodes(t *testing.T) {
+	candidates := []v1alpha1.GlobalNode{
+		globalNode("node-a", v1alpha1.NodeInUse, nil),
+		globalNode("node-b", v1alpha1.NodeFreeState, nil),
+		globalNode("node-c", v1alpha1.NodeFreeState, nil),
+	}
+
+	selected, err := FirstFitNodeSelectionStrategy{}.Select(candidates, 2, v1alpha1.PromotePolicy{})
+	if err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+	if len(selected) != 2 || selected[0].Name != "node-b" || selected[1].Name != "node-c" {
+		t.Errorf("Select() = %v, want [node-b node-c]", selected)
+	}
+}
+
+func TestFirstFitNodeSelectionStrategyErrorsWhenNotEnoughFreeNodes(t *testing.T) {
+	candidates := []v1alpha1.GlobalNode{globalNode("node-a", v1alpha1.NodeFreeState, nil)}
+
+	if _, err := (FirstFitNodeSelectionStrategy{}).Select(candidates, 2, v1alpha1.PromotePolicy{}); err == nil {
+		t.Fatal("expected an error when fewer free nodes than requested, got nil")
+	}
+}
+
+func TestTopologySpreadNodeSelectionStrategySpreadsAcrossDomains(t *testing.T) {
+	candidates := []v1alpha1.GlobalNode{
+		globalNode("zone-a-1", v1alpha1.NodeFreeState, labels.Set{"zone": "a"}),
+		globalNode("zone-a-2", v1alpha1.NodeFreeState, labels.Set{"zone": "a"}),
+		globalNode("zone-b-1", v1alpha1.NodeFreeState, labels.Set{"zone": "b"}),
+	}
+	strategy := TopologySpreadNodeSelectionStrategy{TopologyLabel: "zone"}
+
+	selected, err := strategy.Select(candidates, 2, v1alpha1.PromotePolicy{})
+	if err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+	if len(selected) != 2 {
+		t.Fatalf("Select() returned %d nodes, want 2", len(selected))
+	}
+	zones := map[string]bool{}
+	for _, node := range selected {
+		zones[node.Spec.Labels["zone"]] = true
+	}
+	if len(zones) != 2 {
+		t.Errorf("Select() = %v, want one node from each of zone a and zone b", selected)
+	}
+}
+
+func TestTopologySpreadNodeSelectionStrategyFallsBackWithinDomainWhenOthersExhausted(t *testing.T) {
+	candidates := []v1alpha1.GlobalNode{
+		globalNode("zone-a-1", v1alpha1.NodeFreeState, labels.Set{"zone": "a"}),
+		globalNode("zone-a-2", v1alpha1.NodeFreeState, labels.Set{"zone": "a"}),
+		globalNode("zone-b-1", v1alpha1.NodeFreeState, labels.Set{"zone": "b"}),
+	}
+	strategy := TopologySpreadNodeSelectionStrategy{TopologyLabel: "zone"}
+
+	selected, err := strategy.Select(candidates, 3, v1alpha1.PromotePolicy{})
+	if err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+	if len(selected) != 3 {
+		t.Errorf("Select() returned %d nodes, want all 3 candidates", len(selected))
+	}
+}
+
+func TestTopologySpreadNodeSelectionStrategyErrorsWhenNotEnoughFreeNodes(t *testing.T) {
+	candidates := []v1alpha1.GlobalNode{globalNode("zone-a-1", v1alpha1.NodeFreeState, labels.Set{"zone": "a"})}
+	strategy := TopologySpreadNodeSelectionStrategy{TopologyLabel: "zone"}
+
+	if _, err := strategy.Select(candidates, 2, v1alpha1.PromotePolicy{}); err == nil {
+		t.Fatal("expected an error when fewer free nodes than requested, got nil")
+	}
+}
+
+func TestWeightedLabelNodeSelectionStrategyPrefersHigherWeightedPool(t *testing.T) {
+	candidates := []v1alpha1.GlobalNode{
+		globalNode("pool-b-1", v1alpha1.NodeFreeState, labels.Set{"pool": "b"}),
+		globalNode("pool-a-1", v1alpha1.NodeFreeState, labels.Set{"pool": "a"}),
+		globalNode("pool-a-2", v1alpha1.NodeFreeState, labels.Set{"pool": "a"}),
+	}
+	strategy := WeightedLabelNodeSelectionStrategy{Label: "pool", Weights: map[string]int{"a": 10, "b": 1}}
+
+	selected, err := strategy.Select(candidates, 2, v1alpha1.PromotePolicy{})
+	if err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+	if len(selected) != 2 || selected[0].Spec.Labels["pool"] != "a" || selected[1].Spec.Labels["pool"] != "a" {
+		t.Errorf("Select() = %v, want both selections from the higher-weighted pool a", selected)
+	}
+}
+
+func TestWeightedLabelNodeSelectionStrategyFallsBackToLowerWeightedPoolWhenNeeded(t *testing.T) {
+	candidates := []v1alpha1.GlobalNode{
+		globalNode("pool-a-1", v1alpha1.NodeFreeState, labels.Set{"pool": "a"}),
+		globalNode("pool-b-1", v1alpha1.NodeFreeState, labels.Set{"pool": "b"}),
+	}
+	strategy := WeightedLabelNodeSelectionStrategy{Label: "pool", Weights: map[string]int{"a": 10, "b": 1}}
+
+	selected, err := strategy.Select(candidates, 2, v1alpha1.PromotePolicy{})
+	if err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+	if len(selected) != 2 {
+		t.Fatalf("Select() returned %d nodes, want both candidates", len(selected))
+	}
+}
+
+func TestWeightedLabelNodeSelectionStrategyErrorsWhenNotEnoughFreeNodes(t *testing.T) {
+	candidates := []v1alpha1.GlobalNode{globalNode("pool-a-1", v1alpha1.NodeFreeState, labels.Set{"pool": "a"})}
+	strategy := WeightedLabelNodeSelectionStrategy{Label: "pool", Weights: map[string]int{"a": 10}}
+
+	if _, err := strategy.Select(candidates, 2, v1alpha1.PromotePolicy{}); err == nil {
+		t.Fatal("expected an error when fewer free nodes than requested, got nil")
+	}
+}
+
+// globalNodeWithAge returns a free GlobalNode named name whose
+// CreationTimestamp is ageSeconds seconds after a fixed epoch, for tests of
+// OrderedNodeSelectionStrategy's OldestFirst/NewestFirst orders.
+func globalNodeWithAge(name string, ageSeconds int) v1alpha1.GlobalNode {
+	node := globalNode(name, v1alpha1.NodeFreeState, nil)
+	node.CreationTimestamp = metav1.NewTime(time.Unix(0, 0).Add(time.Duration(ageSeconds) * time.Second))
+	return node
+}
+
+func TestOrderedNodeSelectionStrategyDefaultsToNameAsc(t *testing.T) {
+	candidates := []v1alpha1.GlobalNode{
+		globalNode("node-c", v1alpha1.NodeFreeState, nil),
+		globalNode("node-a", v1alpha1.NodeInUse, nil),
+		globalNode("node-b", v1alpha1.NodeFreeState, nil),
+	}
+
+	selected, err := OrderedNodeSelectionStrategy{}.Select(candidates, 2, v1alpha1.PromotePolicy{})
+	if err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+	if len(selected) != 2 || selected[0].Name != "node-b" || selected[1].Name != "node-c" {
+		t.Errorf("Select() = %v, want [node-b node-c]", selected)
+	}
+}
+
+func TestOrderedNodeSelectionStrategyOldestFirst(t *testing.T) {
+	candidates := []v1alpha1.GlobalNode{
+		globalNodeWithAge("node-new", 300),
+		globalNodeWithAge("node-old", 100),
+		globalNodeWithAge("node-mid", 200),
+	}
+	policy := v1alpha1.PromotePolicy{SelectionOrder: v1alpha1.NodeSelectionOrderOldestFirst}
+
+	selected, err := OrderedNodeSelectionStrategy{}.Select(candidates, 2, policy)
+	if err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+	if len(selected) != 2 || selected[0].Name != "node-old" || selected[1].Name != "node-mid" {
+		t.Errorf("Select() = %v, want [node-old node-mid]", selected)
+	}
+}
+
+func TestOrderedNodeSelectionStrategyNewestFirst(t *testing.T) {
+	candidates := []v1alpha1.GlobalNode{
+		globalNodeWithAge("node-new", 300),
+		globalNodeWithAge("node-old", 100),
+		globalNodeWithAge("node-mid", 200),
+	}
+	policy := v1alpha1.PromotePolicy{SelectionOrder: v1alpha1.NodeSelectionOrderNewestFirst}
+
+	selected, err := OrderedNodeSelectionStrategy{}.Select(candidates, 2, policy)
+	if err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+	if len(selected) != 2 || selected[0].Name != "node-new" || selected[1].Name != "node-mid" {
+		t.Errorf("Select() = %v, want [node-new node-mid]", selected)
+	}
+}
+
+func TestOrderedNodeSelectionStrategyRandomIsStableForAFixedSeed(t *testing.T) {
+	candidates := []v1alpha1.GlobalNode{
+		globalNode("node-a", v1alpha1.NodeFreeState, nil),
+		globalNode("node-b", v1alpha1.NodeFreeState, nil),
+		globalNode("node-c", v1alpha1.NodeFreeState, nil),
+		globalNode("node-d", v1alpha1.NodeFreeState, nil),
+	}
+	policy := v1alpha1.PromotePolicy{SelectionOrder: v1alpha1.NodeSelectionOrderRandom, RandomSeed: 42}
+
+	first, err := OrderedNodeSelectionStrategy{}.Select(candidates, 2, policy)
+	if err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+	second, err := OrderedNodeSelectionStrategy{}.Select(candidates, 2, policy)
+	if err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+	if first[0].Name != second[0].Name || first[1].Name != second[1].Name {
+		t.Errorf("Select() with the same RandomSeed produced different orders: %v vs %v", first, second)
+	}
+}
+
+func TestOrderedNodeSelectionStrategyErrorsWhenNotEnoughFreeNodes(t *testing.T) {
+	candidates := []v1alpha1.GlobalNode{globalNode("node-a", v1alpha1.NodeFreeState, nil)}
+
+	if _, err := (OrderedNodeSelectionStrategy{}).Select(candidates, 2, v1alpha1.PromotePolicy{}); err == nil {
+		t.Fatal("expected an error when fewer free nodes than requested, got nil")
+	}
+}
@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/constants"
+)
+
+// OrphanedNodeEventReasonReclaimed is the reason recorded on the event
+// emitted when the orphaned-node controller reclaims a GlobalNode.
+const OrphanedNodeEventReasonReclaimed = "OrphanedNodeReclaimed"
+
+// OrphanedNodeController returns a GlobalNode to NodeFreeState and clears its
+// usage status once the VirtualCluster it was claimed by no longer exists.
+// A node can be left stranded in NodeInUse this way if the VirtualCluster
+// that claimed it is deleted by some path other than the normal teardown
+// workflow (e.g. the namespace it lived in was removed directly), since
+// nothing else notices that deletion and releases the node. Reclaiming is
+// gated by OrphanedNodeReclaimGracePeriod, measured from
+// Status.ClaimedAt, so a VirtualCluster that is still being created isn't
+// mistaken for an orphan before it has had a chance to exist.
+type OrphanedNodeController struct {
+	client.Client
+	EventRecorder record.EventRecorder
+}
+
+func (r *OrphanedNodeController) SetupWithManager(mgr manager.Manager) error {
+	if r.Client == nil {
+		r.Client = mgr.GetClient()
+	}
+	if r.EventRecorder == nil {
+		r.EventRecorder = mgr.GetEventRecorderFor(constants.OrphanedNodeControllerName)
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(constants.OrphanedNodeControllerName).
+		For(&v1alpha1.GlobalNode{}).
+		Complete(r)
+}
+
+func (r *OrphanedNodeController) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	var node v1alpha1.GlobalNode
+	if err := r.Get(ctx, request.NamespacedName, &node); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if node.Spec.State != v1alpha1.NodeInUse || node.Status.VirtualCluster == "" {
+		return reconcile.Result{RequeueAfter: constants.OrphanedNodeReclaimCheckInterval}, nil
+	}
+
+	if node.Status.ClaimedAt == nil {
+		return reconcile.Result{RequeueAfter: constants.OrphanedNodeReclaimCheckInterval}, nil
+	}
+	if age := time.Since(node.Status.ClaimedAt.Time); age < constants.OrphanedNodeReclaimGracePeriod {
+		return reconcile.Result{RequeueAfter: constants.OrphanedNodeReclaimGracePeriod - age}, nil
+	}
+
+	owner := node.Status.VirtualCluster
+	exists, err := r.virtualClusterExists(ctx, owner)
+	if err != nil {
+		klog.Errorf("orphaned-node-controller: check virtualcluster %s for globalnode %s error: %v", owner, node.Name, err)
+		return reconcile.Result{RequeueAfter: constants.OrphanedNodeReclaimCheckInterval}, nil
+	}
+	if exists {
+		return reconcile.Result{RequeueAfter: constants.OrphanedNodeReclaimCheckInterval}, nil
+	}
+
+	if err := r.reclaim(ctx, request.Name); err != nil {
+		klog.Errorf("orphaned-node-controller: reclaim globalnode %s error: %v", node.Name, err)
+		return reconcile.Result{RequeueAfter: constants.OrphanedNodeReclaimCheckInterval}, nil
+	}
+
+	klog.Infof("orphaned-node-controller: reclaimed globalnode %s, its virtualcluster %s no longer exists", node.Name, owner)
+	r.EventRecorder.Eventf(&node, corev1.EventTypeWarning, OrphanedNodeEventReasonReclaimed, "Reclaimed from deleted virtualcluster %s", owner)
+	return reconcile.Result{RequeueAfter: constants.OrphanedNodeReclaimCheckInterval}, nil
+}
+
+// virtualClusterExists reports whether any VirtualCluster named name exists,
+// searching cluster-wide since GlobalNodeStatus.VirtualCluster stores only a
+// bare name with no namespace.
+func (r *OrphanedNodeController) virtualClusterExists(ctx context.Context, name string) (bool, error) {
+	var list v1alpha1.VirtualClusterList
+	if err := r.List(ctx, &list); err != nil {
+		return false, err
+	}
+	for i := range list.Items {
+		if list.Items[i].Name == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *OrphanedNodeController) reclaim(ctx context.Context, name string) error {
+	updateSpecFunc := func() error {
+		var current v1alpha1.GlobalNode
+		if err := r.Get(ctx, client.ObjectKey{Name: name}, &current); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		updated := current.DeepCopy()
+		updated.Spec.State = v1alpha1.NodeFreeState
+		return r.Update(ctx, updated)
+	}
+	if err := retry.RetryOnConflict(retry.DefaultRetry, updateSpecFunc); err != nil {
+		return err
+	}
+
+	updateStatusFunc := func() error {
+		var current v1alpha1.GlobalNode
+		if err := r.Get(ctx, client.ObjectKey{Name: name}, &current); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		updated := current.DeepCopy()
+		updated.Status.VirtualCluster = ""
+		updated.Status.ClaimedAt = nil
+		return r.Status().Update(ctx, updated)
+	}
+	return retry.RetryOnConflict(retry.DefaultRetry, updateStatusFunc)
+}
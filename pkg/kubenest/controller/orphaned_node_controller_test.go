@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+)
+
+// orphanedNodeStoreClient stubs the client.Client methods
+// OrphanedNodeController needs against a single in-memory GlobalNode and a
+// fixed list of VirtualClusters.
+type orphanedNodeStoreClient struct {
+	client.Client
+	node *v1alpha1.GlobalNode
+	vcs  []v1alpha1.VirtualCluster
+}
+
+func (c *orphanedNodeStoreClient) Get(_ context.Context, _ types.NamespacedName, obj client.Object, _ ...client.GetOption) error {
+	node, ok := obj.(*v1alpha1.GlobalNode)
+	if !ok {
+		return fmt.Errorf("unexpected object type %T", obj)
+	}
+	c.node.DeepCopyInto(node)
+	return nil
+}
+
+func (c *orphanedNodeStoreClient) Update(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+	node, ok := obj.(*v1alpha1.GlobalNode)
+	if !ok {
+		return fmt.Errorf("unexpected object type %T", obj)
+	}
+	c.node = node.DeepCopy()
+	return nil
+}
+
+func (c *orphanedNodeStoreClient) Status() client.SubResourceWriter {
+	return orphanedNodeStatusWriter{c}
+}
+
+type orphanedNodeStatusWriter struct {
+	c *orphanedNodeStoreClient
+}
+
+func (w orphanedNodeStatusWriter) Update(_ context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+	node, ok := obj.(*v1alpha1.GlobalNode)
+	if !ok {
+		return fmt.Errorf("unexpected object type %T", obj)
+	}
+	w.c.node = node.DeepCopy()
+	return nil
+}
+
+func (w orphanedNodeStatusWriter) Create(context.Context, client.Object, client.Object, ...client.SubResourceCreateOption) error {
+	return fmt.Errorf("unexpected Status().Create() call")
+}
+
+func (w orphanedNodeStatusWriter) Patch(context.Context, client.Object, client.Patch, ...client.SubResourcePatchOption) error {
+	return fmt.Errorf("unexpected Status().Patch() call")
+}
+
+func (c *orphanedNodeStoreClient) List(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+	vcList, ok := list.(*v1alpha1.VirtualClusterList)
+	if !ok {
+		return fmt.Errorf("unexpected list type %T", list)
+	}
+	vcList.Items = c.vcs
+	return nil
+}
+
+func claimedGlobalNode(virtualCluster string, claimedAge time.Duration) *v1alpha1.GlobalNode {
+	claimedAt := metav1.NewTime(time.Now().Add(-claimedAge))
+	return &v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1"},
+		Spec:       v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeInUse},
+		Status: v1alpha1.GlobalNodeStatus{
+			VirtualCluster: virtualCluster,
+			ClaimedAt:      &claimedAt,
+		},
+	}
+}
+
+func reconcileOrphanedNode(t *testing.T, store *orphanedNodeStoreClient) {
+	t.Helper()
+	r := &OrphanedNodeController{Client: store, EventRecorder: record.NewFakeRecorder(1)}
+	if _, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: store.node.Name}}); err != nil {
+		t.Fatalf("Reconcile() error = %v, want nil", err)
+	}
+}
+
+func TestOrphanedNodeControllerReclaimsNodeWithDeletedVirtualCluster(t *testing.T) {
+	store := &orphanedNodeStoreClient{node: claimedGlobalNode("vc1", 20*time.Minute)}
+	reconcileOrphanedNode(t, store)
+
+	if store.node.Spec.State != v1alpha1.NodeFreeState {
+		t.Errorf("Spec.State = %q, want %q", store.node.Spec.State, v1alpha1.NodeFreeState)
+	}
+	if store.node.Status.VirtualCluster != "" {
+		t.Errorf("Status.VirtualCluster = %q, want empty", store.node.Status.VirtualCluster)
+	}
+	if store.node.Status.ClaimedAt != nil {
+		t.Errorf("Status.ClaimedAt = %v, want nil", store.node.Status.ClaimedAt)
+	}
+}
+
+func TestOrphanedNodeControllerKeepsNodeWithExistingVirtualCluster(t *testing.T) {
+	store := &orphanedNodeStoreClient{
+		node: claimedGlobalNode("vc1", 20*time.Minute),
+		vcs:  []v1alpha1.VirtualCluster{{ObjectMeta: metav1.ObjectMeta{Name: "vc1", Namespace: "ns1"}}},
+	}
+	reconcileOrphanedNode(t, store)
+
+	if store.node.Spec.State != v1alpha1.NodeInUse {
+		t.Errorf("Spec.State = %q, want %q", store.node.Spec.State, v1alpha1.NodeInUse)
+	}
+}
+
+func TestOrphanedNodeControllerHonorsGracePeriod(t *testing.T) {
+	store := &orphanedNodeStoreClient{node: claimedGlobalNode("vc1", 1*time.Minute)}
+	reconcileOrphanedNode(t, store)
+
+	if store.node.Spec.State != v1alpha1.NodeInUse {
+		t.Errorf("Spec.State = %q, want %q (still within grace period)", store.node.Spec.State, v1alpha1.NodeInUse)
+	}
+}
@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+)
+
+// Rebalance describes the outcome of a manual rebalance of virtualCluster:
+// the GlobalNodes ComputeAssignmentDelta proposed claiming for its
+// PromotePolicies, and - once Applied is true - which of those were actually
+// claimed.
+type Rebalance struct {
+	VirtualCluster string
+	Namespace      string
+	// Proposed lists every GlobalNode ComputeAssignmentDelta would newly
+	// claim for virtualCluster. It never includes a node
+	// ComputeAssignmentDelta would release: rebalancing only adds nodes, so
+	// a workload already running on a claimed node is never disturbed.
+	Proposed []v1alpha1.NodeInfo
+	// Applied is true once Proposed has actually been claimed and persisted.
+	Applied bool
+}
+
+// PlanRebalance computes, without claiming any GlobalNode or modifying
+// virtualCluster, which free GlobalNodes ComputeAssignmentDelta would newly
+// claim to better satisfy virtualCluster's existing PromotePolicies - e.g.
+// hardware added to the pool since virtualCluster was last assigned. Any
+// release ComputeAssignmentDelta would also propose is deliberately dropped:
+// a manual rebalance is additive-only, so it never risks disrupting a
+// workload already running on a claimed node.
+func (c *VirtualClusterInitController) PlanRebalance(ctx context.Context, virtualCluster *v1alpha1.VirtualCluster) (*Rebalance, error) {
+	globalNodeList, err := c.KosmosClient.KosmosV1alpha1().GlobalNodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list global nodes: %w", err)
+	}
+
+	toAdd, _, err := ComputeAssignmentDelta(virtualCluster, globalNodeList.Items)
+	if err != nil {
+		return nil, fmt.Errorf("compute assignment delta: %w", err)
+	}
+
+	return &Rebalance{
+		VirtualCluster: virtualCluster.Name,
+		Namespace:      virtualCluster.Namespace,
+		Proposed:       toAdd,
+	}, nil
+}
+
+// ApplyRebalance claims every GlobalNode in rebalance.Proposed and appends it
+// to virtualCluster.Spec.PromoteResources.NodeInfos, then persists
+// virtualCluster. A node no longer free by the time ApplyRebalance runs (lost
+// the race with another assignment since PlanRebalance computed the
+// proposal) is skipped rather than failing the whole rebalance.
+func (c *VirtualClusterInitController) ApplyRebalance(ctx context.Context, virtualCluster *v1alpha1.VirtualCluster, rebalance *Rebalance) error {
+	c.globalNodeLock.Lock()
+	defer c.globalNodeLock.Unlock()
+
+	var claimed []v1alpha1.NodeInfo
+	for _, nodeInfo := range rebalance.Proposed {
+		globalNode, err := c.KosmosClient.KosmosV1alpha1().GlobalNodes().Get(ctx, nodeInfo.NodeName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("get globalnode %s: %w", nodeInfo.NodeName, err)
+		}
+		if globalNode.Spec.State != v1alpha1.NodeFreeState {
+			continue
+		}
+		if err := c.setGlobalNodeUsageStatus(virtualCluster, globalNode); err != nil {
+			return fmt.Errorf("claim globalnode %s: %w", nodeInfo.NodeName, err)
+		}
+		claimed = append(claimed, nodeInfo)
+	}
+
+	virtualCluster.Spec.PromoteResources.NodeInfos = append(virtualCluster.Spec.PromoteResources.NodeInfos, claimed...)
+	c.recordEvent(virtualCluster, corev1.EventTypeNormal, EventReasonNodeAssignmentCompleted,
+		"Rebalance claimed %d additional node(s)", len(claimed))
+	if err := c.Update(virtualCluster); err != nil {
+		return fmt.Errorf("update virtualcluster %s: %w", virtualCluster.Name, err)
+	}
+
+	rebalance.Proposed = claimed
+	rebalance.Applied = true
+	return nil
+}
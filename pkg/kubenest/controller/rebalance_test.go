@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+	kosmosfake "github.com/kosmos.io/kosmos/pkg/generated/clientset/versioned/fake"
+)
+
+func TestPlanRebalanceProposesFreeMatchingNodesOnly(t *testing.T) {
+	kosmosClient := kosmosfake.NewSimpleClientset(
+		&planGlobalNode1,
+		&planGlobalNode2,
+		&planGlobalNode3,
+	)
+	c := &VirtualClusterInitController{KosmosClient: kosmosClient}
+	virtualCluster := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc-a", Namespace: "default"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			PromotePolicies: []v1alpha1.PromotePolicy{{NodeCount: 2}},
+			PromoteResources: v1alpha1.PromoteResources{
+				NodeInfos: []v1alpha1.NodeInfo{{NodeName: "node-1"}},
+			},
+		},
+	}
+
+	rebalance, err := c.PlanRebalance(context.TODO(), virtualCluster)
+	if err != nil {
+		t.Fatalf("PlanRebalance() error = %v", err)
+	}
+	if len(rebalance.Proposed) != 1 || rebalance.Proposed[0].NodeName != "node-2" {
+		t.Errorf("Proposed = %v, want [node-2]", rebalance.Proposed)
+	}
+	if rebalance.Applied {
+		t.Error("expected PlanRebalance to not apply anything")
+	}
+	// PlanRebalance must not mutate the GlobalNode pool or the VirtualCluster.
+	node2, err := kosmosClient.KosmosV1alpha1().GlobalNodes().Get(context.TODO(), "node-2", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get(node-2) error = %v", err)
+	}
+	if node2.Spec.State != v1alpha1.NodeFreeState {
+		t.Errorf("expected node-2 to remain free after PlanRebalance, got %q", node2.Spec.State)
+	}
+	if len(virtualCluster.Spec.PromoteResources.NodeInfos) != 1 {
+		t.Errorf("expected PlanRebalance to leave Spec.PromoteResources.NodeInfos untouched, got %v", virtualCluster.Spec.PromoteResources.NodeInfos)
+	}
+}
+
+func TestApplyRebalanceClaimsOnlyProposedNodes(t *testing.T) {
+	kosmosClient := kosmosfake.NewSimpleClientset(
+		&planGlobalNode1,
+		&planGlobalNode2,
+		&planGlobalNode3,
+	)
+	virtualCluster := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc-a", Namespace: "default"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			PromotePolicies: []v1alpha1.PromotePolicy{{NodeCount: 2}},
+			PromoteResources: v1alpha1.PromoteResources{
+				NodeInfos: []v1alpha1.NodeInfo{{NodeName: "node-1"}},
+			},
+		},
+	}
+	c := &VirtualClusterInitController{
+		Client:       &inMemoryVirtualClusterClient{vc: virtualCluster},
+		KosmosClient: kosmosClient,
+	}
+
+	rebalance, err := c.PlanRebalance(context.TODO(), virtualCluster)
+	if err != nil {
+		t.Fatalf("PlanRebalance() error = %v", err)
+	}
+
+	if err := c.ApplyRebalance(context.TODO(), virtualCluster, rebalance); err != nil {
+		t.Fatalf("ApplyRebalance() error = %v", err)
+	}
+	if !rebalance.Applied {
+		t.Error("expected rebalance.Applied to be true")
+	}
+
+	node2, err := kosmosClient.KosmosV1alpha1().GlobalNodes().Get(context.TODO(), "node-2", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get(node-2) error = %v", err)
+	}
+	if node2.Spec.State != v1alpha1.NodeInUse {
+		t.Errorf("expected node-2 to be claimed (%q), got %q", v1alpha1.NodeInUse, node2.Spec.State)
+	}
+	node3, err := kosmosClient.KosmosV1alpha1().GlobalNodes().Get(context.TODO(), "node-3", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get(node-3) error = %v", err)
+	}
+	if node3.Spec.State != v1alpha1.NodeFreeState {
+		t.Errorf("expected node-3 to remain free, only the shortfall should be claimed, got %q", node3.Spec.State)
+	}
+
+	var names []string
+	for _, nodeInfo := range virtualCluster.Spec.PromoteResources.NodeInfos {
+		names = append(names, nodeInfo.NodeName)
+	}
+	if len(names) != 2 {
+		t.Errorf("expected Spec.PromoteResources.NodeInfos to hold 2 nodes after rebalance, got %v", names)
+	}
+}
+
+var (
+	planGlobalNode1 = planGlobalNode("node-1", v1alpha1.NodeInUse)
+	planGlobalNode2 = planGlobalNode("node-2", v1alpha1.NodeFreeState)
+	planGlobalNode3 = planGlobalNode("node-3", v1alpha1.NodeFreeState)
+)
@@ -0,0 +1,157 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+)
+
+// VirtualClusterStatusServer exposes a small read-only HTTP API summarizing
+// VirtualCluster health, backed by the manager's cached client, so operators
+// can check cluster status without listing CRs through kubectl. It
+// implements manager.Runnable so it starts and stops alongside the manager
+// it's registered with via mgr.Add.
+type VirtualClusterStatusServer struct {
+	// Client is the manager's cached client used to read VirtualClusters and
+	// GlobalNodes.
+	Client client.Client
+	// BindAddress is the address the HTTP server listens on, e.g. ":8090".
+	BindAddress string
+}
+
+// virtualClusterSummary is the JSON shape returned for each VirtualCluster by
+// GET /virtualclusters.
+type virtualClusterSummary struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Phase     string `json:"phase"`
+	NodeCount int    `json:"nodeCount"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// virtualClusterNodeStatus is the JSON shape returned for each node by
+// GET /virtualclusters/{namespace}/{name}/nodes.
+type virtualClusterNodeStatus struct {
+	NodeName string `json:"nodeName"`
+	State    string `json:"state,omitempty"`
+	Found    bool   `json:"found"`
+}
+
+// Start runs the status server until ctx is cancelled, satisfying
+// manager.Runnable.
+func (s *VirtualClusterStatusServer) Start(ctx context.Context) error {
+	router := mux.NewRouter()
+	router.HandleFunc("/healthz", s.handleHealthz).Methods(http.MethodGet)
+	router.HandleFunc("/virtualclusters", s.handleListVirtualClusters).Methods(http.MethodGet)
+	router.HandleFunc("/virtualclusters/{namespace}/{name}/nodes", s.handleVirtualClusterNodes).Methods(http.MethodGet)
+
+	srv := &http.Server{Addr: s.BindAddress, Handler: router, ReadHeaderTimeout: 10 * time.Second}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+	klog.V(2).Infof("VirtualCluster status server listening on %s", s.BindAddress)
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	case err := <-errCh:
+		if err != nil && err != http.ErrServerClosed {
+			return fmt.Errorf("virtualcluster status server: %w", err)
+		}
+		return nil
+	}
+}
+
+// handleHealthz reports the status server itself is up, independent of
+// whether the backing cache has synced.
+func (s *VirtualClusterStatusServer) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, r, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleListVirtualClusters serves GET /virtualclusters: every
+// VirtualCluster's name, namespace, phase, assigned node count, and reason.
+func (s *VirtualClusterStatusServer) handleListVirtualClusters(w http.ResponseWriter, r *http.Request) {
+	var list v1alpha1.VirtualClusterList
+	if err := s.Client.List(r.Context(), &list); err != nil {
+		writeJSONError(w, r, http.StatusInternalServerError, fmt.Errorf("list virtualclusters: %w", err))
+		return
+	}
+
+	summaries := make([]virtualClusterSummary, 0, len(list.Items))
+	for _, vc := range list.Items {
+		summaries = append(summaries, virtualClusterSummary{
+			Name:      vc.Name,
+			Namespace: vc.Namespace,
+			Phase:     string(vc.Status.Phase),
+			NodeCount: len(vc.Spec.PromoteResources.NodeInfos),
+			Reason:    vc.Status.Reason,
+		})
+	}
+	writeJSON(w, r, http.StatusOK, summaries)
+}
+
+// handleVirtualClusterNodes serves GET /virtualclusters/{namespace}/{name}/nodes:
+// the VirtualCluster's NodeInfos together with each node's live GlobalNode
+// state, so an operator can see at a glance whether a claimed node has
+// drifted out of the Free/InUse state it expects.
+func (s *VirtualClusterStatusServer) handleVirtualClusterNodes(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	namespace, name := vars["namespace"], vars["name"]
+
+	var vc v1alpha1.VirtualCluster
+	if err := s.Client.Get(r.Context(), client.ObjectKey{Namespace: namespace, Name: name}, &vc); err != nil {
+		writeJSONError(w, r, http.StatusNotFound, fmt.Errorf("get virtualcluster %s/%s: %w", namespace, name, err))
+		return
+	}
+
+	var globalNodes v1alpha1.GlobalNodeList
+	if err := s.Client.List(r.Context(), &globalNodes); err != nil {
+		writeJSONError(w, r, http.StatusInternalServerError, fmt.Errorf("list globalnodes: %w", err))
+		return
+	}
+
+	nodes := make([]virtualClusterNodeStatus, 0, len(vc.Spec.PromoteResources.NodeInfos))
+	for _, nodeInfo := range vc.Spec.PromoteResources.NodeInfos {
+		status := virtualClusterNodeStatus{NodeName: nodeInfo.NodeName}
+		for _, globalNode := range globalNodes.Items {
+			if globalNode.Name == nodeInfo.NodeName {
+				status.State = string(globalNode.Spec.State)
+				status.Found = true
+				break
+			}
+		}
+		nodes = append(nodes, status)
+	}
+	writeJSON(w, r, http.StatusOK, nodes)
+}
+
+// writeJSON negotiates a JSON response for r's Accept header and encodes
+// body, or responds 406 if the client explicitly asked for something else.
+func writeJSON(w http.ResponseWriter, r *http.Request, statusCode int, body interface{}) {
+	if accept := r.Header.Get("Accept"); accept != "" && !strings.Contains(accept, "application/json") && !strings.Contains(accept, "*/*") {
+		http.Error(w, "only application/json is supported", http.StatusNotAcceptable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		klog.Errorf("Failed to encode status server response: %v", err)
+	}
+}
+
+// writeJSONError writes err as a JSON error body with statusCode.
+func writeJSONError(w http.ResponseWriter, r *http.Request, statusCode int, err error) {
+	writeJSON(w, r, statusCode, map[string]string{"error": err.Error()})
+}
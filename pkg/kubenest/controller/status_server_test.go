@@ -0,0 +1,143 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+)
+
+// statusServerFakeClient stubs just the client.Client methods
+// VirtualClusterStatusServer needs, serving fixed in-memory objects.
+type statusServerFakeClient struct {
+	client.Client
+	virtualClusters []v1alpha1.VirtualCluster
+	globalNodes     []v1alpha1.GlobalNode
+}
+
+func (c *statusServerFakeClient) List(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+	switch l := list.(type) {
+	case *v1alpha1.VirtualClusterList:
+		l.Items = c.virtualClusters
+		return nil
+	case *v1alpha1.GlobalNodeList:
+		l.Items = c.globalNodes
+		return nil
+	default:
+		return fmt.Errorf("unexpected list type %T", list)
+	}
+}
+
+func (c *statusServerFakeClient) Get(_ context.Context, key client.ObjectKey, obj client.Object, _ ...client.GetOption) error {
+	vc, ok := obj.(*v1alpha1.VirtualCluster)
+	if !ok {
+		return fmt.Errorf("unexpected object type %T", obj)
+	}
+	for _, candidate := range c.virtualClusters {
+		if candidate.Namespace == key.Namespace && candidate.Name == key.Name {
+			*vc = candidate
+			return nil
+		}
+	}
+	return fmt.Errorf("virtualcluster %s not found", key)
+}
+
+func TestStatusServerListVirtualClusters(t *testing.T) {
+	fakeClient := &statusServerFakeClient{
+		virtualClusters: []v1alpha1.VirtualCluster{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "vc-a", Namespace: "default"},
+				Spec:       v1alpha1.VirtualClusterSpec{PromoteResources: v1alpha1.PromoteResources{NodeInfos: []v1alpha1.NodeInfo{{NodeName: "node-a"}}}},
+				Status:     v1alpha1.VirtualClusterStatus{Phase: v1alpha1.Completed, Reason: "all good"},
+			},
+		},
+	}
+	s := &VirtualClusterStatusServer{Client: fakeClient}
+
+	req := httptest.NewRequest(http.MethodGet, "/virtualclusters", nil)
+	rec := httptest.NewRecorder()
+	s.handleListVirtualClusters(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	var summaries []virtualClusterSummary
+	if err := json.Unmarshal(rec.Body.Bytes(), &summaries); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(summaries) != 1 || summaries[0].Name != "vc-a" || summaries[0].NodeCount != 1 || summaries[0].Phase != string(v1alpha1.Completed) {
+		t.Errorf("unexpected summaries: %+v", summaries)
+	}
+}
+
+func TestStatusServerVirtualClusterNodesReportsGlobalNodeState(t *testing.T) {
+	fakeClient := &statusServerFakeClient{
+		virtualClusters: []v1alpha1.VirtualCluster{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "vc-a", Namespace: "default"},
+				Spec: v1alpha1.VirtualClusterSpec{
+					PromoteResources: v1alpha1.PromoteResources{NodeInfos: []v1alpha1.NodeInfo{{NodeName: "node-a"}, {NodeName: "node-missing"}}},
+				},
+			},
+		},
+		globalNodes: []v1alpha1.GlobalNode{
+			{ObjectMeta: metav1.ObjectMeta{Name: "node-a"}, Spec: v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeInUse}},
+		},
+	}
+	s := &VirtualClusterStatusServer{Client: fakeClient}
+
+	req := httptest.NewRequest(http.MethodGet, "/virtualclusters/default/vc-a/nodes", nil)
+	req = mux.SetURLVars(req, map[string]string{"namespace": "default", "name": "vc-a"})
+	rec := httptest.NewRecorder()
+	s.handleVirtualClusterNodes(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	var nodes []virtualClusterNodeStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &nodes); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(nodes) != 2 {
+		t.Fatalf("expected 2 nodes, got %v", nodes)
+	}
+	if nodes[0].NodeName != "node-a" || !nodes[0].Found || nodes[0].State != string(v1alpha1.NodeInUse) {
+		t.Errorf("unexpected node-a status: %+v", nodes[0])
+	}
+	if nodes[1].NodeName != "node-missing" || nodes[1].Found {
+		t.Errorf("expected node-missing to be reported as not found, got %+v", nodes[1])
+	}
+}
+
+func TestStatusServerVirtualClusterNodesNotFound(t *testing.T) {
+	s := &VirtualClusterStatusServer{Client: &statusServerFakeClient{}}
+
+	req := httptest.NewRequest(http.MethodGet, "/virtualclusters/default/missing/nodes", nil)
+	req = mux.SetURLVars(req, map[string]string{"namespace": "default", "name": "missing"})
+	rec := httptest.NewRecorder()
+	s.handleVirtualClusterNodes(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+}
+
+func TestStatusServerWriteJSONRejectsUnacceptableContentType(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	req.Header.Set("Accept", "text/plain")
+	rec := httptest.NewRecorder()
+
+	writeJSON(rec, req, http.StatusOK, map[string]string{"status": "ok"})
+
+	if rec.Code != http.StatusNotAcceptable {
+		t.Errorf("status = %d, want 406", rec.Code)
+	}
+}
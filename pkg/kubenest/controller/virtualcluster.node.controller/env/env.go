@@ -137,6 +137,18 @@ func GetWaitNodeReadTime() int {
 	return num
 }
 
+func GetWaitAddonsReadyTime() int {
+	readTimeSeconds := os.Getenv("WAIT_ADDONS_READY_TIME")
+	if len(readTimeSeconds) == 0 {
+		readTimeSeconds = "60"
+	}
+	num, err := strconv.Atoi(readTimeSeconds)
+	if err != nil {
+		klog.Fatalf("convert WAIT_ADDONS_READY_TIME failed, err: %s", err)
+	}
+	return num
+}
+
 func GetNodeTaskMaxGoroutines() int {
 	maxGoroutines := os.Getenv("NODE_TASK_MAX_GOROUTINES")
 	if len(maxGoroutines) == 0 {
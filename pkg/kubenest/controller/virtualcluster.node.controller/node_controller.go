@@ -30,6 +30,7 @@ import (
 	"github.com/kosmos.io/kosmos/pkg/kubenest/controller/virtualcluster.node.controller/workflow"
 	"github.com/kosmos.io/kosmos/pkg/kubenest/controller/virtualcluster.node.controller/workflow/task"
 	"github.com/kosmos.io/kosmos/pkg/kubenest/util"
+	apiclient "github.com/kosmos.io/kosmos/pkg/kubenest/util/api-client"
 	"github.com/kosmos.io/kosmos/pkg/utils"
 )
 
@@ -40,6 +41,11 @@ type NodeController struct {
 	KosmosClient  versioned.Interface
 	Options       *v1alpha1.KubeNestConfiguration
 	sem           chan struct{}
+	// JoinReadinessCheck probes whether the virtual cluster's control plane
+	// is ready to accept node joins before DoNodeTask initiates one.
+	// Defaults to probing the virtual apiserver's /healthz; overridable in
+	// tests.
+	JoinReadinessCheck func(kubernetes.Interface) error
 }
 
 func NewNodeController(client client.Client, RootClientSet kubernetes.Interface, EventRecorder record.EventRecorder, KosmosClient versioned.Interface, options *v1alpha1.KubeNestConfiguration) *NodeController {
@@ -83,6 +89,13 @@ func (r *NodeController) SetupWithManager(mgr manager.Manager) error {
 		Complete(r)
 }
 
+// defaultJoinReadinessCheck probes the virtual apiserver's /healthz so a
+// control plane that isn't ready yet fails the probe quickly instead of
+// letting a join proceed and kubelets retry registration noisily.
+func defaultJoinReadinessCheck(k8sClient kubernetes.Interface) error {
+	return apiclient.NewVirtualClusterChecker(k8sClient, constants.NodeJoinReadinessCheckTimeout).WaitForAPI()
+}
+
 func hasItemInArray(name string, f func(string) bool) bool {
 	return f(name)
 }
@@ -197,6 +210,14 @@ func (r *NodeController) DoNodeTask(ctx context.Context, virtualCluster v1alpha1
 		}
 	}
 	if len(joinNodes) > 0 {
+		// joins race ahead of the control plane being ready otherwise fail noisily as kubelets retry registration
+		check := r.JoinReadinessCheck
+		if check == nil {
+			check = defaultJoinReadinessCheck
+		}
+		if err := check(k8sClient); err != nil {
+			return fmt.Errorf("virtualcluster %s apiserver is not ready, deferring node join: %v", virtualCluster.Name, err)
+		}
 		// join node
 		if err := r.joinNode(ctx, joinNodes, virtualCluster, k8sClient); err != nil {
 			return fmt.Errorf("virtualcluster %s join node failed: %v", virtualCluster.Name, err)
@@ -313,12 +334,22 @@ func (r *NodeController) joinNode(ctx context.Context, nodeInfos []v1alpha1.Glob
 		return nil
 	}
 
-	clusterDNS := ""
 	dnssvc, err := k8sClient.CoreV1().Services(constants.SystemNs).Get(ctx, constants.KubeDNSSVCName, metav1.GetOptions{})
 	if err != nil {
 		return fmt.Errorf("get kube-dns service failed: %s", err)
 	}
-	clusterDNS = dnssvc.Spec.ClusterIP
+	clusterDNS := dnssvc.Spec.ClusterIP
+
+	if virtualCluster.Spec.KubeInKubeConfig != nil && virtualCluster.Spec.KubeInKubeConfig.ClusterDNS != "" {
+		configuredDNS := virtualCluster.Spec.KubeInKubeConfig.ClusterDNS
+		if err := util.ValidateClusterDNS(configuredDNS, constants.APIServerServiceSubnet); err != nil {
+			return fmt.Errorf("invalid clusterDNS for virtualcluster %s: %s", virtualCluster.Name, err)
+		}
+		if configuredDNS != clusterDNS {
+			klog.Warningf("virtualcluster %s kube-dns service clusterIP %s does not match configured clusterDNS %s, using configured value for node join", virtualCluster.Name, clusterDNS, configuredDNS)
+		}
+		clusterDNS = configuredDNS
+	}
 
 	return r.BatchProcessNodes(nodeInfos, func(nodeInfo v1alpha1.GlobalNode) error {
 		return workflow.NewJoinWorkFlow().RunTask(ctx, task.TaskOpt{
@@ -0,0 +1,134 @@
+package vcnodecontroller
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+)
+
+// globalNodeListClient stubs the client.Client List method
+// compareAndTranformNodes needs to resolve target node names to GlobalNodes.
+type globalNodeListClient struct {
+	client.Client
+	nodes []v1alpha1.GlobalNode
+}
+
+func (c *globalNodeListClient) List(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+	globalNodeList, ok := list.(*v1alpha1.GlobalNodeList)
+	if !ok {
+		return fmt.Errorf("unexpected list type %T", list)
+	}
+	globalNodeList.Items = c.nodes
+	return nil
+}
+
+// newFakeVirtualClusterKubeconfig builds a minimal kubeconfig pointing at
+// server, good enough for kubernetes.NewForConfig to build a working client.
+func newFakeVirtualClusterKubeconfig(server string) string {
+	kubeconfig := fmt.Sprintf(`
+apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: %s
+  name: vc
+contexts:
+- context:
+    cluster: vc
+    user: vc
+  name: vc
+current-context: vc
+users:
+- name: vc
+  user: {}
+`, server)
+	return base64.StdEncoding.EncodeToString([]byte(kubeconfig))
+}
+
+func newJoinTestVirtualCluster(server string) v1alpha1.VirtualCluster {
+	return v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1", Namespace: "default"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			Kubeconfig: newFakeVirtualClusterKubeconfig(server),
+			PromoteResources: v1alpha1.PromoteResources{
+				NodeInfos: []v1alpha1.NodeInfo{{NodeName: "node-1"}},
+			},
+		},
+		Status: v1alpha1.VirtualClusterStatus{Phase: v1alpha1.Initialized},
+	}
+}
+
+func TestDoNodeTaskDefersJoinUntilControlPlaneReady(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/nodes" {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"kind":"NodeList","apiVersion":"v1","items":[]}`))
+			return
+		}
+		t.Fatalf("unexpected request to %s while control plane is not ready", r.URL.Path)
+	}))
+	defer server.Close()
+
+	r := &NodeController{
+		Client: &globalNodeListClient{nodes: []v1alpha1.GlobalNode{
+			{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+		}},
+		sem: make(chan struct{}, 1),
+		JoinReadinessCheck: func(kubernetes.Interface) error {
+			return fmt.Errorf("apiserver not ready")
+		},
+	}
+
+	err := r.DoNodeTask(context.TODO(), newJoinTestVirtualCluster(server.URL))
+	if err == nil {
+		t.Fatalf("expected DoNodeTask to fail while the control plane is not ready")
+	}
+	if !strings.Contains(err.Error(), "not ready") {
+		t.Fatalf("expected a not-ready error, got: %v", err)
+	}
+}
+
+func TestDoNodeTaskJoinsOnceControlPlaneReady(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/nodes" {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"kind":"NodeList","apiVersion":"v1","items":[]}`))
+			return
+		}
+		// the join path looks up the kube-dns service next; returning 404 here
+		// is proof the readiness gate let execution reach the join attempt.
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	r := &NodeController{
+		Client: &globalNodeListClient{nodes: []v1alpha1.GlobalNode{
+			{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+		}},
+		sem: make(chan struct{}, 1),
+		JoinReadinessCheck: func(kubernetes.Interface) error {
+			return nil
+		},
+	}
+
+	err := r.DoNodeTask(context.TODO(), newJoinTestVirtualCluster(server.URL))
+	if err == nil {
+		t.Fatalf("expected DoNodeTask to surface the join failure once past the readiness gate")
+	}
+	if strings.Contains(err.Error(), "not ready") {
+		t.Fatalf("expected the readiness gate to have passed, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "kube-dns") {
+		t.Fatalf("expected the join attempt to have started (kube-dns lookup), got: %v", err)
+	}
+}
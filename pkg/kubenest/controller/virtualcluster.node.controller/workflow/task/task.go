@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -14,6 +15,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/util/retry"
+	bootstrapapi "k8s.io/cluster-bootstrap/token/api"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -248,15 +250,47 @@ func NewRemoteUpdateConfigYamlTask() Task {
 	}
 }
 
+// formatKubeletExtraArgs renders kubeletExtraArgs as a space-separated list of
+// "key=value" pairs, sorted by key so the resulting join command is
+// deterministic across reconciles.
+func formatKubeletExtraArgs(kubeletExtraArgs map[string]string) string {
+	keys := make([]string, 0, len(kubeletExtraArgs))
+	for key := range kubeletExtraArgs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	args := make([]string, 0, len(keys))
+	for _, key := range keys {
+		args = append(args, fmt.Sprintf("%s=%s", key, kubeletExtraArgs[key]))
+	}
+	return strings.Join(args, " ")
+}
+
+// buildJoinCmd builds the "bash <script> join <dnsAddress> [kubeletExtraArgs]"
+// command string, appending the formatted kubeletExtraArgs only when the node
+// has any configured.
+func buildJoinCmd(dnsAddress string, kubeletExtraArgs map[string]string) string {
+	cmd := fmt.Sprintf("bash %s join %s", env.GetExectorShellName(), dnsAddress)
+	if len(kubeletExtraArgs) == 0 {
+		return cmd
+	}
+	return fmt.Sprintf("%s %s", cmd, formatKubeletExtraArgs(kubeletExtraArgs))
+}
+
 func NewRemoteNodeJoinTask() Task {
 	return Task{
 		Name:  "remote join node to virtual control plane",
 		Retry: true,
 		Run: func(ctx context.Context, to TaskOpt, _ interface{}) (interface{}, error) {
+			if err := util.ValidateKubeletExtraArgs(to.NodeInfo.Spec.KubeletExtraArgs); err != nil {
+				return nil, fmt.Errorf("invalid kubeletExtraArgs for node %s: %s", to.NodeInfo.Name, err)
+			}
+
 			exectHelper := exector.NewExectorHelper(to.NodeInfo.Spec.NodeIP, "")
 
 			joinCmd := &exector.CMDExector{
-				Cmd: fmt.Sprintf("bash %s join %s", env.GetExectorShellName(), to.KubeDNSAddress),
+				Cmd: buildJoinCmd(to.KubeDNSAddress, to.NodeInfo.Spec.KubeletExtraArgs),
 			}
 			to.Loger().Infof("join node %s with cmd: %s", to.NodeInfo.Name, joinCmd.Cmd)
 			ret := exectHelper.DoExector(ctx.Done(), joinCmd)
@@ -336,6 +370,118 @@ func NewWaitNodeReadyTask(isHost bool) Task {
 	}
 }
 
+func NewTaintNotReadyNodeTask() Task {
+	return Task{
+		Name:  "taint new node until add-ons are ready",
+		Retry: true,
+		Run: func(ctx context.Context, to TaskOpt, _ interface{}) (interface{}, error) {
+			err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+				node, err := to.VirtualK8sClient.CoreV1().Nodes().Get(ctx, to.NodeInfo.Name, metav1.GetOptions{})
+				if err != nil {
+					return err
+				}
+
+				for _, taint := range node.Spec.Taints {
+					if taint.Key == constants.NodeNotReadyTaintKey {
+						return nil
+					}
+				}
+
+				updateNode := node.DeepCopy()
+				updateNode.Spec.Taints = append(updateNode.Spec.Taints, v1.Taint{
+					Key:    constants.NodeNotReadyTaintKey,
+					Effect: v1.TaintEffectNoSchedule,
+				})
+
+				_, err = to.VirtualK8sClient.CoreV1().Nodes().Update(ctx, updateNode, metav1.UpdateOptions{})
+				return err
+			})
+			return nil, err
+		},
+	}
+}
+
+func NewWaitAddonsReadyTask() Task {
+	return Task{
+		Name: "wait node add-ons ready",
+		Run: func(ctx context.Context, to TaskOpt, _ interface{}) (interface{}, error) {
+			fieldSelector := fmt.Sprintf("spec.nodeName=%s", to.NodeInfo.Name)
+			err := wait.PollImmediate(5*time.Second, time.Duration(env.GetWaitAddonsReadyTime())*time.Second, func() (bool, error) {
+				pods, err := to.VirtualK8sClient.CoreV1().Pods(constants.SystemNs).List(ctx, metav1.ListOptions{FieldSelector: fieldSelector})
+				if err != nil {
+					return false, nil
+				}
+				if len(pods.Items) == 0 {
+					return true, nil
+				}
+				for _, pod := range pods.Items {
+					if pod.Status.Phase != v1.PodRunning {
+						return false, nil
+					}
+				}
+				return true, nil
+			})
+			if err != nil {
+				to.Loger().Infof("add-ons on node %s not ready before timeout, removing not-ready taint anyway", to.NodeInfo.Name)
+			}
+			return nil, nil
+		},
+	}
+}
+
+func NewRemoveNotReadyTaintTask() Task {
+	return Task{
+		Name:  "remove new-node not-ready taint",
+		Retry: true,
+		Run: func(ctx context.Context, to TaskOpt, _ interface{}) (interface{}, error) {
+			err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+				node, err := to.VirtualK8sClient.CoreV1().Nodes().Get(ctx, to.NodeInfo.Name, metav1.GetOptions{})
+				if err != nil {
+					return err
+				}
+
+				taints := make([]v1.Taint, 0, len(node.Spec.Taints))
+				for _, taint := range node.Spec.Taints {
+					if taint.Key != constants.NodeNotReadyTaintKey {
+						taints = append(taints, taint)
+					}
+				}
+				if len(taints) == len(node.Spec.Taints) {
+					return nil
+				}
+
+				updateNode := node.DeepCopy()
+				updateNode.Spec.Taints = taints
+
+				_, err = to.VirtualK8sClient.CoreV1().Nodes().Update(ctx, updateNode, metav1.UpdateOptions{})
+				return err
+			})
+			return nil, err
+		},
+	}
+}
+
+// mergeTaints appends any sourceTaints not already present (by key and
+// effect) in existing, so previously-applied taints such as
+// constants.NodeNotReadyTaintKey are preserved alongside them.
+func mergeTaints(existing, sourceTaints []v1.Taint) []v1.Taint {
+	merged := make([]v1.Taint, len(existing))
+	copy(merged, existing)
+	for _, taint := range sourceTaints {
+		found := false
+		for _, t := range merged {
+			if t.Key == taint.Key && t.Effect == taint.Effect {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, taint)
+		}
+	}
+	return merged
+}
+
 // nolint:dupl
 func NewUpdateVirtualNodeLabelsTask() Task {
 	return Task{
@@ -357,6 +503,17 @@ func NewUpdateVirtualNodeLabelsTask() Task {
 				// add free label
 				updateNode.Labels[constants.StateLabelKey] = string(v1alpha1.NodeInUse)
 
+				// Carry the GlobalNode's taints (e.g. a dedicated/reserved taint
+				// covered by the PromotePolicy's tolerations) onto the virtual
+				// node, so the virtual cluster's own scheduler keeps honoring the
+				// same dedication instead of treating the node as untainted. This
+				// only works if spec.taints is also listed in the GlobalNode CRD's
+				// schema (deploy/crds/kosmos.io_globalnodes.yaml) - otherwise the
+				// API server prunes it from NodeInfo before this task ever runs.
+				if len(to.NodeInfo.Spec.Taints) > 0 {
+					updateNode.Spec.Taints = mergeTaints(updateNode.Spec.Taints, to.NodeInfo.Spec.Taints)
+				}
+
 				if _, err := to.VirtualK8sClient.CoreV1().Nodes().Update(ctx, updateNode, metav1.UpdateOptions{}); err != nil {
 					to.Loger().Infof("add label to node %s failed: %s", to.NodeInfo.Name, err)
 					return err
@@ -417,12 +574,16 @@ func NewUpdateNodePoolItemStatusTask(nodeState v1alpha1.NodeState, isClean bool)
 				updateGlobalNode := targetGlobalNode.DeepCopy()
 
 				updateGlobalNode.Spec.State = nodeState
+				if isClean {
+					clearControllerManagedNodeMetadata(updateGlobalNode)
+				}
 				if err := to.HostClient.Update(ctx, updateGlobalNode); err != nil {
 					to.Loger().Errorf("update global node %s spec.state failed: %s", updateGlobalNode.Name, err)
 					return err
 				}
 				if isClean {
 					updateGlobalNode.Status.VirtualCluster = ""
+					updateGlobalNode.Status.ClaimedAt = nil
 					if err := to.HostClient.Status().Update(ctx, updateGlobalNode); err != nil {
 						to.Loger().Errorf("update global node %s status failed: %s", updateGlobalNode.Name, err)
 						return err
@@ -436,6 +597,27 @@ func NewUpdateNodePoolItemStatusTask(nodeState v1alpha1.NodeState, isClean bool)
 	}
 }
 
+// clearControllerManagedNodeMetadata strips the label and taint the kosmos
+// controllers applied to globalNode while it was assigned (its in-use state
+// label, its not-ready taint) and drops all of its annotations, so the node
+// returns to a pristine free state before the next tenant can claim it.
+func clearControllerManagedNodeMetadata(globalNode *v1alpha1.GlobalNode) {
+	delete(globalNode.Spec.Labels, constants.StateLabelKey)
+
+	if len(globalNode.Spec.Taints) > 0 {
+		taints := make([]v1.Taint, 0, len(globalNode.Spec.Taints))
+		for _, taint := range globalNode.Spec.Taints {
+			if taint.Key == constants.NodeNotReadyTaintKey {
+				continue
+			}
+			taints = append(taints, taint)
+		}
+		globalNode.Spec.Taints = taints
+	}
+
+	globalNode.Annotations = nil
+}
+
 func NewRemoveNodeFromVirtualTask() Task {
 	return Task{
 		Name: "remove node from virtual control-plane",
@@ -516,11 +698,42 @@ func NewGetJoinNodeToHostCmdTask() Task {
 			if err != nil {
 				return nil, err
 			}
+
+			if _, token, _, err := getJoinCmdArgs(joinCmdStr); err == nil {
+				tokenID := strings.SplitN(token, ".", 2)[0]
+				if err := labelBootstrapTokenSecret(ctx, to.HostK8sClient, tokenID, to.VirtualCluster.Name); err != nil {
+					to.Loger().Infof("label bootstrap token secret for token %s failed: %s", tokenID, err)
+				}
+			}
+
 			return joinCmdStr, nil
 		},
 	}
 }
 
+// labelBootstrapTokenSecret marks the host-cluster bootstrap-token Secret
+// minted by "kubeadm token create" with the VirtualCluster it was created
+// for, so BootstrapTokenGCController can find and clean it up later.
+func labelBootstrapTokenSecret(ctx context.Context, hostK8sClient kubernetes.Interface, tokenID, virtualClusterName string) error {
+	secretName := bootstrapapi.BootstrapTokenSecretPrefix + tokenID
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret, err := hostK8sClient.CoreV1().Secrets(constants.SystemNs).Get(ctx, secretName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if secret.Labels[constants.BootstrapTokenVirtualClusterLabelKey] == virtualClusterName {
+			return nil
+		}
+		updated := secret.DeepCopy()
+		if updated.Labels == nil {
+			updated.Labels = map[string]string{}
+		}
+		updated.Labels[constants.BootstrapTokenVirtualClusterLabelKey] = virtualClusterName
+		_, err = hostK8sClient.CoreV1().Secrets(constants.SystemNs).Update(ctx, updated, metav1.UpdateOptions{})
+		return err
+	})
+}
+
 func NewExecJoinNodeToHostCmdTask() Task {
 	return Task{
 		Name:  "remote join node to host",
@@ -0,0 +1,254 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/constants"
+	env "github.com/kosmos.io/kosmos/pkg/kubenest/controller/virtualcluster.node.controller/env"
+)
+
+// globalNodeStoreClient stubs the client.Client Get/Update/Status().Update
+// methods NewUpdateNodePoolItemStatusTask needs.
+type globalNodeStoreClient struct {
+	client.Client
+	node *v1alpha1.GlobalNode
+}
+
+func (c *globalNodeStoreClient) Get(_ context.Context, _ types.NamespacedName, obj client.Object, _ ...client.GetOption) error {
+	gn, ok := obj.(*v1alpha1.GlobalNode)
+	if !ok {
+		return fmt.Errorf("unexpected object type %T", obj)
+	}
+	*gn = *c.node.DeepCopy()
+	return nil
+}
+
+func (c *globalNodeStoreClient) Update(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+	gn, ok := obj.(*v1alpha1.GlobalNode)
+	if !ok {
+		return fmt.Errorf("unexpected object type %T", obj)
+	}
+	c.node = gn.DeepCopy()
+	return nil
+}
+
+func (c *globalNodeStoreClient) Status() client.SubResourceWriter {
+	return &globalNodeStatusWriter{store: c}
+}
+
+type globalNodeStatusWriter struct {
+	store *globalNodeStoreClient
+}
+
+func (w *globalNodeStatusWriter) Create(_ context.Context, _ client.Object, _ client.Object, _ ...client.SubResourceCreateOption) error {
+	return nil
+}
+
+func (w *globalNodeStatusWriter) Update(_ context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+	gn, ok := obj.(*v1alpha1.GlobalNode)
+	if !ok {
+		return fmt.Errorf("unexpected object type %T", obj)
+	}
+	w.store.node = gn.DeepCopy()
+	return nil
+}
+
+func (w *globalNodeStatusWriter) Patch(_ context.Context, _ client.Object, _ client.Patch, _ ...client.SubResourcePatchOption) error {
+	return nil
+}
+
+func hasNotReadyTaint(node *v1.Node) bool {
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == constants.NodeNotReadyTaintKey {
+			return true
+		}
+	}
+	return false
+}
+
+func TestTaintAndRemoveNotReadyTaint(t *testing.T) {
+	nodeName := "test-node"
+	client := fake.NewSimpleClientset(&v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: nodeName},
+	})
+
+	to := TaskOpt{
+		NodeInfo:         v1alpha1.GlobalNode{ObjectMeta: metav1.ObjectMeta{Name: nodeName}},
+		VirtualK8sClient: client,
+	}
+
+	if _, err := NewTaintNotReadyNodeTask().Run(context.TODO(), to, nil); err != nil {
+		t.Fatalf("taint node failed: %s", err)
+	}
+
+	node, err := client.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get node failed: %s", err)
+	}
+	if !hasNotReadyTaint(node) {
+		t.Fatalf("expected node to carry the not-ready taint after join")
+	}
+
+	if _, err := NewRemoveNotReadyTaintTask().Run(context.TODO(), to, nil); err != nil {
+		t.Fatalf("remove taint failed: %s", err)
+	}
+
+	node, err = client.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get node failed: %s", err)
+	}
+	if hasNotReadyTaint(node) {
+		t.Fatalf("expected not-ready taint to be removed once add-ons are ready")
+	}
+}
+
+func TestUpdateVirtualNodeLabelsTaskPropagatesTaints(t *testing.T) {
+	nodeName := "test-node"
+	client := fake.NewSimpleClientset(&v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: nodeName, Labels: map[string]string{}},
+		Spec: v1.NodeSpec{
+			Taints: []v1.Taint{{Key: constants.NodeNotReadyTaintKey, Effect: v1.TaintEffectNoSchedule}},
+		},
+	})
+
+	to := TaskOpt{
+		NodeInfo: v1alpha1.GlobalNode{
+			ObjectMeta: metav1.ObjectMeta{Name: nodeName},
+			Spec: v1alpha1.GlobalNodeSpec{
+				Labels: labels.Set{"topology.kubernetes.io/zone": "a"},
+				Taints: []v1.Taint{{Key: "dedicated", Value: "vc", Effect: v1.TaintEffectNoSchedule}},
+			},
+		},
+		VirtualK8sClient: client,
+	}
+
+	if _, err := NewUpdateVirtualNodeLabelsTask().Run(context.TODO(), to, nil); err != nil {
+		t.Fatalf("update labels task failed: %s", err)
+	}
+
+	node, err := client.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get node failed: %s", err)
+	}
+	if node.Labels["topology.kubernetes.io/zone"] != "a" {
+		t.Errorf("expected GlobalNode labels to be copied onto the virtual node")
+	}
+	if !hasNotReadyTaint(node) {
+		t.Errorf("expected the pre-existing not-ready taint to survive the update")
+	}
+	foundDedicated := false
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == "dedicated" {
+			foundDedicated = true
+		}
+	}
+	if !foundDedicated {
+		t.Errorf("expected the GlobalNode's dedicated taint to be propagated onto the virtual node")
+	}
+}
+
+func TestUpdateNodePoolItemStatusTaskClearsControllerMetadataOnRelease(t *testing.T) {
+	nodeName := "test-node"
+	store := &globalNodeStoreClient{
+		node: &v1alpha1.GlobalNode{
+			ObjectMeta: metav1.ObjectMeta{Name: nodeName},
+			Spec: v1alpha1.GlobalNodeSpec{
+				State: v1alpha1.NodeInUse,
+				Labels: labels.Set{
+					"topology.kubernetes.io/zone": "a",
+					constants.StateLabelKey:       string(v1alpha1.NodeInUse),
+				},
+				Taints: []v1.Taint{
+					{Key: constants.NodeNotReadyTaintKey, Effect: v1.TaintEffectNoSchedule},
+					{Key: "dedicated", Effect: v1.TaintEffectNoSchedule},
+				},
+			},
+			Status: v1alpha1.GlobalNodeStatus{VirtualCluster: "tenant-a"},
+		},
+	}
+
+	to := TaskOpt{
+		NodeInfo:   *store.node,
+		HostClient: store,
+	}
+
+	if _, err := NewUpdateNodePoolItemStatusTask(v1alpha1.NodeFreeState, true).Run(context.TODO(), to, nil); err != nil {
+		t.Fatalf("release task failed: %s", err)
+	}
+
+	if store.node.Spec.State != v1alpha1.NodeFreeState {
+		t.Errorf("Spec.State = %s, want %s", store.node.Spec.State, v1alpha1.NodeFreeState)
+	}
+	if store.node.Status.VirtualCluster != "" {
+		t.Errorf("Status.VirtualCluster = %q, want cleared", store.node.Status.VirtualCluster)
+	}
+	if _, ok := store.node.Spec.Labels[constants.StateLabelKey]; ok {
+		t.Errorf("expected the controller-applied state label to be cleared")
+	}
+	if store.node.Spec.Labels["topology.kubernetes.io/zone"] != "a" {
+		t.Errorf("expected unrelated labels to survive release")
+	}
+	if store.node.Annotations != nil {
+		t.Errorf("Annotations = %v, want cleared", store.node.Annotations)
+	}
+	foundDedicated := false
+	for _, taint := range store.node.Spec.Taints {
+		if taint.Key == constants.NodeNotReadyTaintKey {
+			t.Errorf("expected the not-ready taint to be cleared")
+		}
+		if taint.Key == "dedicated" {
+			foundDedicated = true
+		}
+	}
+	if !foundDedicated {
+		t.Errorf("expected unrelated taints to survive release")
+	}
+}
+
+func TestBuildJoinCmdIncludesKubeletExtraArgs(t *testing.T) {
+	cmd := buildJoinCmd("10.96.0.10", map[string]string{
+		"max-pods":        "64",
+		"system-reserved": "cpu=200m,memory=500Mi",
+	})
+
+	if !strings.Contains(cmd, "max-pods=64") {
+		t.Errorf("join cmd %q does not contain configured kubelet extra arg max-pods", cmd)
+	}
+	if !strings.Contains(cmd, "system-reserved=cpu=200m,memory=500Mi") {
+		t.Errorf("join cmd %q does not contain configured kubelet extra arg system-reserved", cmd)
+	}
+}
+
+func TestBuildJoinCmdWithoutKubeletExtraArgs(t *testing.T) {
+	cmd := buildJoinCmd("10.96.0.10", nil)
+	want := fmt.Sprintf("bash %s join 10.96.0.10", env.GetExectorShellName())
+	if cmd != want {
+		t.Errorf("buildJoinCmd() = %q, want %q", cmd, want)
+	}
+}
+
+func TestNewRemoteNodeJoinTaskRejectsUnrecognizedKubeletExtraArg(t *testing.T) {
+	to := TaskOpt{
+		NodeInfo: v1alpha1.GlobalNode{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-node"},
+			Spec: v1alpha1.GlobalNodeSpec{
+				KubeletExtraArgs: map[string]string{"not-a-real-flag": "1"},
+			},
+		},
+	}
+
+	if _, err := NewRemoteNodeJoinTask().Run(context.TODO(), to, nil); err == nil {
+		t.Fatalf("expected an error for an unrecognized kubeletExtraArgs flag")
+	}
+}
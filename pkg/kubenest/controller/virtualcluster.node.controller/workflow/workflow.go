@@ -90,6 +90,9 @@ func NewJoinWorkFlow() WorkflowData {
 		task.NewRemoteUpdateConfigYamlTask(),
 		task.NewRemoteNodeJoinTask(),
 		task.NewWaitNodeReadyTask(false),
+		task.NewTaintNotReadyNodeTask(),
+		task.NewWaitAddonsReadyTask(),
+		task.NewRemoveNotReadyTaintTask(),
 		task.NewUpdateVirtualNodeLabelsTask(),
 		task.NewUpdateNodePoolItemStatusTask(v1alpha1.NodeInUse, false),
 	}
@@ -21,8 +21,17 @@ type Executor struct {
 	config         *rest.Config
 }
 
+// NewExecutor builds the default Executor for virtualCluster: an
+// init-or-uninstall phase assembled from kubenest's built-in task registry,
+// with no extra tasks registered. Callers that need to extend the task list
+// (e.g. to inject an org-mandated CNI step) build their own *workflow.Phase
+// via kubenest.NewInitPhase/UninstallPhase with
+// kubenest.NewInitOptWithExtraInitTasks/NewInitOptWithExtraUninstallTasks and
+// wrap it the same way, then assign it through
+// VirtualClusterInitController.ExecutorFactory instead of calling NewExecutor.
 func NewExecutor(virtualCluster *v1alpha1.VirtualCluster, c client.Client, config *rest.Config, kubeNestOptions *v1alpha1.KubeNestConfiguration) (*Executor, error) {
 	var phase *workflow.Phase
+	var err error
 
 	opts := []kubenest.InitOpt{
 		kubenest.NewInitOptWithVirtualCluster(virtualCluster),
@@ -33,12 +42,15 @@ func NewExecutor(virtualCluster *v1alpha1.VirtualCluster, c client.Client, confi
 	action := recognizeActionFor(virtualCluster)
 	switch action {
 	case constants.InitAction:
-		phase = kubenest.NewInitPhase(options)
+		phase, err = kubenest.NewInitPhase(options)
 	case constants.DeInitAction:
-		phase = kubenest.UninstallPhase(options)
+		phase, err = kubenest.UninstallPhase(options)
 	default:
 		return nil, fmt.Errorf("failed to recognize action for virtual cluster %s", virtualCluster.Name)
 	}
+	if err != nil {
+		return nil, err
+	}
 
 	return &Executor{
 		virtualCluster: virtualCluster,
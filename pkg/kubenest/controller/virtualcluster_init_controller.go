@@ -4,18 +4,26 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"net"
+	"net/url"
+	"reflect"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
 	"gopkg.in/yaml.v3"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -29,15 +37,20 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
 	"github.com/kosmos.io/kosmos/pkg/generated/clientset/versioned"
 	"github.com/kosmos.io/kosmos/pkg/kubenest/constants"
 	env "github.com/kosmos.io/kosmos/pkg/kubenest/controller/virtualcluster.node.controller/env"
 	"github.com/kosmos.io/kosmos/pkg/kubenest/controller/virtualcluster.node.controller/exector"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/metrics"
+	"github.com/kosmos.io/kosmos/pkg/scheduler/lifted/helpers"
 	"github.com/kosmos.io/kosmos/pkg/kubenest/tasks"
 	"github.com/kosmos.io/kosmos/pkg/kubenest/util"
 	apiclient "github.com/kosmos.io/kosmos/pkg/kubenest/util/api-client"
@@ -50,8 +63,70 @@ type VirtualClusterInitController struct {
 	EventRecorder   record.EventRecorder
 	RootClientSet   kubernetes.Interface
 	KosmosClient    versioned.Interface
-	lock            sync.Mutex
 	KubeNestOptions *v1alpha1.KubeNestConfiguration
+	// Concurrency contract: MaxConcurrentReconciles (set via SetupWithManager)
+	// lets multiple VirtualClusters reconcile in parallel, so every shared
+	// resource a reconcile can touch has its own lock below rather than one
+	// coarse mutex -- otherwise a slow GlobalNode assignment for one cluster
+	// would needlessly block an unrelated cluster's host port allocation.
+	// Each lock only ever guards its own resource and none call into another
+	// locked method, so they can't deadlock against each other.
+	//
+	// globalNodeLock guards assignWorkNodes, since GlobalNodes are a single
+	// pool shared by every VirtualCluster and two concurrent reconciles must
+	// not claim the same free node.
+	globalNodeLock sync.Mutex
+	// hostPortLock guards AllocateHostPort's host-network port pool.
+	hostPortLock sync.Mutex
+	// SubFinalizers lets other parts of kosmos gate deletion of a
+	// VirtualCluster on their own cleanup step finishing, in addition to
+	// destroyVirtualCluster. See the SubFinalizer doc comment for the
+	// ordering guarantee this provides.
+	SubFinalizers []SubFinalizer
+	// vipLock guards AllocateVip's VIP pool.
+	vipLock sync.Mutex
+	// retryRequestedLock guards retryRequested.
+	retryRequestedLock sync.Mutex
+	// retryRequested records VirtualClusters that newGlobalNodeMapFunc has
+	// just enqueued because a GlobalNode freed up that might satisfy them,
+	// keyed by NamespacedName. The Preparing/Pending Reconcile case consumes
+	// and clears the entry for its own key to bypass isPhaseStuck for that
+	// one reconcile, so the freed GlobalNode actually unblocks the retry it
+	// triggered instead of the watch being a no-op until StuckPhaseTimeout
+	// elapses on its own.
+	retryRequested map[types.NamespacedName]bool
+	// NodeSelectionStrategy picks which free nodes to assign when a
+	// PromotePolicy's NodeCount grows. Defaults to OrderedNodeSelectionStrategy,
+	// which honors each PromotePolicy's SelectionOrder (NameAsc when unset)
+	// instead of the arbitrary, pagination-dependent GlobalNode list order.
+	NodeSelectionStrategy NodeSelectionStrategy
+	// PortManager tracks host port allocations across the pool configured in
+	// the kosmos-hostports ConfigMap, synced from existing VirtualClusters at
+	// startup so a controller restart doesn't forget ports already claimed.
+	PortManager util.HostPortAllocator
+	// ExecutorFactory builds the VirtualClusterExecutor createVirtualCluster
+	// and destroyVirtualCluster run to apply/tear down a VirtualCluster's
+	// control plane. Defaults to a factory wrapping NewExecutor, preserving
+	// today's behavior; set it to plug in a VirtualClusterExecutor built from
+	// a task registry with extra tasks registered (see
+	// kubenest.NewInitOptWithExtraInitTasks/NewInitOptWithExtraUninstallTasks),
+	// or, in tests, to substitute a stub that skips actually running the
+	// init/uninstall workflow.
+	ExecutorFactory func(virtualCluster *v1alpha1.VirtualCluster, c client.Client, config *rest.Config, kubeNestOptions *v1alpha1.KubeNestConfiguration) (VirtualClusterExecutor, error)
+	// clientCacheLock guards virtualClusterClients.
+	clientCacheLock sync.Mutex
+	// virtualClusterClients caches the clientset virtualClusterClient built
+	// for each VirtualCluster, keyed by UID so a kubeconfig rotation (bumping
+	// ResourceVersion) invalidates the entry instead of silently reusing a
+	// stale client.
+	virtualClusterClients map[types.UID]cachedVirtualClusterClient
+}
+
+// cachedVirtualClusterClient is a virtualClusterClient result cached against
+// the VirtualCluster UID+ResourceVersion it was built from.
+type cachedVirtualClusterClient struct {
+	resourceVersion string
+	client          kubernetes.Interface
 }
 
 type NodePool struct {
@@ -61,6 +136,73 @@ type NodePool struct {
 	State   string            `json:"state" yaml:"state"`
 }
 
+// validNodePoolStates are the NodePool.State values recognized by the node
+// controller, mirroring v1alpha1.NodeState's occupied/free/reserved values.
+var validNodePoolStates = map[string]bool{
+	string(v1alpha1.NodeInUse):     true,
+	string(v1alpha1.NodeFreeState): true,
+	string(v1alpha1.NodeReserved):  true,
+}
+
+// ParseNodePool unmarshals data as either YAML or JSON (JSON is valid YAML,
+// so a single yaml.Unmarshal handles both) into a NodePool and validates it,
+// for the ConfigMap-sourced node pool config GetHostPortPoolFromConfigMap and
+// GetVipFromConfigMap sibling functions read the same way.
+func ParseNodePool(data []byte) (*NodePool, error) {
+	var pool NodePool
+	if err := yaml.Unmarshal(data, &pool); err != nil {
+		return nil, fmt.Errorf("unmarshal NodePool: %w", err)
+	}
+	if err := pool.Validate(); err != nil {
+		return nil, err
+	}
+	return &pool, nil
+}
+
+// Validate rejects a NodePool whose Address isn't a valid IP or hostname,
+// whose State isn't one of the known NodeState values, or whose Labels keys
+// aren't valid label keys.
+func (n *NodePool) Validate() error {
+	if n.Address == "" {
+		return fmt.Errorf("nodepool address must not be empty")
+	}
+	if net.ParseIP(n.Address) == nil {
+		if errs := validation.IsDNS1123Subdomain(n.Address); len(errs) > 0 {
+			return fmt.Errorf("nodepool address %q is not a valid IP address or hostname: %s", n.Address, strings.Join(errs, ", "))
+		}
+	}
+
+	if n.State != "" && !validNodePoolStates[n.State] {
+		return fmt.Errorf("nodepool state %q is not one of %q, %q, %q", n.State, v1alpha1.NodeInUse, v1alpha1.NodeFreeState, v1alpha1.NodeReserved)
+	}
+
+	for key := range n.Labels {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("nodepool label key %q is invalid: %s", key, strings.Join(errs, ", "))
+		}
+	}
+
+	return nil
+}
+
+// GetNodePoolFromConfigMap reads and parses the node pool config stored
+// under dataKey in the cmName ConfigMap, the same way
+// GetHostPortPoolFromConfigMap and GetVipFromConfigMap read their own pool
+// configs.
+func GetNodePoolFromConfigMap(client kubernetes.Interface, ns, cmName, dataKey string) (*NodePool, error) {
+	cm, err := client.CoreV1().ConfigMaps(ns).Get(context.TODO(), cmName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	data, exist := cm.Data[dataKey]
+	if !exist {
+		return nil, fmt.Errorf("key '%s' not found in ConfigMap '%s'", dataKey, cmName)
+	}
+
+	return ParseNodePool([]byte(data))
+}
+
 type HostPortPool struct {
 	PortsPool []int32 `yaml:"portsPool"`
 }
@@ -72,6 +214,58 @@ type VipPool struct {
 const (
 	VirtualClusterControllerFinalizer = "kosmos.io/virtualcluster-controller"
 	RequeueTime                       = 10 * time.Second
+	// ReconcileBackoffBaseDelay and ReconcileBackoffMaxDelay bound the
+	// exponential backoff reconcileBackoff applies to a VirtualCluster that
+	// keeps failing createVirtualCluster or pod-readiness, so a
+	// misconfigured cluster retries with increasing delay instead of
+	// hammering the API and host at RequeueTime's fixed cadence.
+	ReconcileBackoffBaseDelay = 10 * time.Second
+	ReconcileBackoffMaxDelay  = 5 * time.Minute
+)
+
+// SubFinalizer lets a kosmos sub-component (e.g. node cleanup) gate deletion
+// of a VirtualCluster on its own cleanup step finishing, independent of the
+// main VirtualClusterControllerFinalizer. ensureFinalizer adds every
+// registered SubFinalizer's Name alongside VirtualClusterControllerFinalizer;
+// removeFinalizer then removes each one as soon as its Ready reports the
+// cleanup it guards has completed.
+//
+// Deletion ordering guarantee: VirtualClusterControllerFinalizer, the
+// finalizer destroyVirtualCluster's cleanup depends on, is always removed
+// last -- only once every registered SubFinalizer has already been removed.
+// This means a VirtualCluster can't be garbage-collected by the API server
+// while a registered sub-finalizer's cleanup is still pending, regardless of
+// the order SubFinalizers were registered in. Finalizers owned by unrelated,
+// third-party controllers are never touched here: controllerutil's
+// Add/RemoveFinalizer only ever add or remove the single name they're given,
+// so a third-party finalizer added to coordinate its own deletion ordering
+// with kosmos is left exactly as that controller set it.
+type SubFinalizer struct {
+	// Name is the finalizer string added to the VirtualCluster, e.g.
+	// "kosmos.io/node-cleanup".
+	Name string
+	// Ready reports whether this sub-finalizer's cleanup has completed and
+	// it is safe to remove Name from the VirtualCluster.
+	Ready func(*v1alpha1.VirtualCluster) bool
+}
+
+// Event reasons recorded by VirtualClusterInitController on the
+// VirtualCluster object, surfaced by `kubectl describe virtualcluster`.
+const (
+	EventReasonPreparing               = "Preparing"
+	EventReasonNodeAssignmentStarted   = "NodeAssignmentStarted"
+	EventReasonNodeAssignmentCompleted = "NodeAssignmentCompleted"
+	EventReasonControlPlaneExecStarted = "ControlPlaneExecutionStarted"
+	EventReasonControlPlaneExecFailed  = "ControlPlaneExecutionFailed"
+	EventReasonReadinessWaitTimeout    = "ReadinessWaitTimeout"
+	EventReasonPhaseTransition         = "PhaseTransition"
+	EventReasonNodeOwnershipConflict   = "NodeOwnershipConflict"
+	EventReasonPaused                  = "Paused"
+	EventReasonNodePreempted           = "NodePreempted"
+	EventReasonNodePreemption          = "NodePreemption"
+	EventReasonPhaseStuck              = "PhaseStuck"
+	EventReasonPhaseStuckExhausted     = "PhaseStuckExhausted"
+	EventReasonReadinessCheckSkipped   = "ReadinessCheckSkipped"
 )
 
 var nameMap = map[string]int{
@@ -81,6 +275,28 @@ var nameMap = map[string]int{
 	"adminport":  4,
 }
 
+// VirtualClusterExecutor is the subset of *Executor createVirtualCluster and
+// destroyVirtualCluster need. It's the extension point this package exposes
+// for customizing provisioning: a caller that needs extra tasks builds its
+// own *workflow.Phase (see kubenest.NewInitOptWithExtraInitTasks/
+// NewInitOptWithExtraUninstallTasks) and wraps it in a type satisfying this
+// interface, then plugs it in via VirtualClusterInitController.ExecutorFactory
+// instead of forking createVirtualCluster/destroyVirtualCluster. Tests
+// likewise substitute a stub that skips actually running the init/uninstall
+// workflow.
+type VirtualClusterExecutor interface {
+	Execute() error
+}
+
+// newExecutor builds the VirtualClusterExecutor for virtualCluster, via
+// c.ExecutorFactory if set, otherwise NewExecutor.
+func (c *VirtualClusterInitController) newExecutor(virtualCluster *v1alpha1.VirtualCluster, config *rest.Config, kubeNestOptions *v1alpha1.KubeNestConfiguration) (VirtualClusterExecutor, error) {
+	if c.ExecutorFactory != nil {
+		return c.ExecutorFactory(virtualCluster, c.Client, config, kubeNestOptions)
+	}
+	return NewExecutor(virtualCluster, c.Client, config, kubeNestOptions)
+}
+
 func (c *VirtualClusterInitController) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
 	startTime := time.Now()
 	klog.V(4).InfoS("Started syncing virtual cluster", "virtual cluster", request, "startTime", startTime)
@@ -94,8 +310,14 @@ func (c *VirtualClusterInitController) Reconcile(ctx context.Context, request re
 			klog.V(2).InfoS("Virtual Cluster has been deleted", "Virtual Cluster", request)
 			return reconcile.Result{}, nil
 		}
-		return reconcile.Result{RequeueAfter: RequeueTime}, nil
+		return reconcile.Result{RequeueAfter: jitteredRequeueTime()}, nil
 	}
+	defer func() {
+		metrics.VirtualClusterReconcileDuration.WithLabelValues(string(originalCluster.Status.Phase)).Observe(time.Since(startTime).Seconds())
+	}()
+	currentPhase := originalCluster.Status.Phase
+	ctx = log.IntoContext(ctx, log.FromContext(ctx).WithValues(
+		"virtualcluster", request.NamespacedName, "uid", originalCluster.UID, "phase", currentPhase))
 	updatedCluster := originalCluster.DeepCopy()
 	updatedCluster.Status.Reason = ""
 
@@ -126,29 +348,52 @@ func (c *VirtualClusterInitController) Reconcile(ctx context.Context, request re
 		return c.removeFinalizer(updatedCluster)
 	}
 
+	if isVirtualClusterPaused(originalCluster) {
+		klog.V(2).InfoS("Virtual Cluster is paused, skipping reconciliation", "Virtual Cluster", request)
+		c.recordEvent(originalCluster, corev1.EventTypeNormal, EventReasonPaused, "VirtualCluster is paused, skipping reconciliation")
+		return reconcile.Result{}, nil
+	}
+
 	switch originalCluster.Status.Phase {
 	case "":
 		//create request
 		updatedCluster.Status.Phase = v1alpha1.Preparing
+		updatedCluster.Status.CreationStartTime = &metav1.Time{Time: time.Now()}
+		updatedCluster.Status.CreationDuration = ""
+		c.recordEvent(updatedCluster, corev1.EventTypeNormal, EventReasonPreparing, "VirtualCluster is preparing for creation")
+		recordPhaseTransition(currentPhase, updatedCluster.Status.Phase)
+		currentPhase = updatedCluster.Status.Phase
 		err := c.Update(updatedCluster)
 		if err != nil {
 			klog.Errorf("Error update virtualcluster %s status, err: %v", updatedCluster.Name, err)
-			return reconcile.Result{RequeueAfter: RequeueTime}, errors.Wrapf(err, "Error update virtualcluster %s status", updatedCluster.Name)
+			return reconcile.Result{RequeueAfter: jitteredRequeueTime()}, errors.Wrapf(err, "Error update virtualcluster %s status", updatedCluster.Name)
 		}
 
-		err = c.createVirtualCluster(updatedCluster, c.KubeNestOptions)
+		err = c.createVirtualCluster(ctx, updatedCluster, c.KubeNestOptions)
 		if err != nil {
 			klog.Errorf("Failed to create virtualcluster %s. err: %s", updatedCluster.Name, err.Error())
-			updatedCluster.Status.Reason = err.Error()
+			deriveReasonFromConditions(updatedCluster, err.Error())
 			updatedCluster.Status.Phase = v1alpha1.Pending
+			c.recordEvent(updatedCluster, corev1.EventTypeWarning, EventReasonControlPlaneExecFailed, "Failed to create virtual cluster: %v", err)
+			c.recordReconcileFailure(updatedCluster, time.Now())
+			recordPhaseTransition(currentPhase, updatedCluster.Status.Phase)
+			currentPhase = updatedCluster.Status.Phase
 			err := c.Update(updatedCluster)
 			if err != nil {
 				klog.Errorf("Error update virtualcluster %s. err: %s", updatedCluster.Name, err.Error())
 				return reconcile.Result{}, errors.Wrapf(err, "Error update virtualcluster %s status", updatedCluster.Name)
 			}
-			return reconcile.Result{}, errors.Wrap(err, "Error createVirtualCluster")
+			return reconcile.Result{RequeueAfter: reconcileBackoff(updatedCluster.Status.FailureCount)}, nil
+		}
+		if updatedCluster.Spec.DryRun {
+			updatedCluster.Status.Phase = v1alpha1.DryRunComplete
+		} else {
+			updatedCluster.Status.Phase = v1alpha1.Initialized
 		}
-		updatedCluster.Status.Phase = v1alpha1.Initialized
+		c.recordEvent(updatedCluster, corev1.EventTypeNormal, EventReasonPhaseTransition, "Phase transitioning to %s", updatedCluster.Status.Phase)
+		c.recordReconcileSuccess(updatedCluster)
+		recordPhaseTransition(currentPhase, updatedCluster.Status.Phase)
+		currentPhase = updatedCluster.Status.Phase
 		err = c.Update(updatedCluster)
 		if err != nil {
 			klog.Errorf("Error update virtualcluster %s status to %s. %v", updatedCluster.Name, updatedCluster.Status.Phase, err)
@@ -159,7 +404,7 @@ func (c *VirtualClusterInitController) Reconcile(ctx context.Context, request re
 		// check if the vc enable vip
 		if len(originalCluster.Status.VipMap) > 0 {
 			// label node for keepalived
-			vcClient, err := tasks.GetVcClientset(c.RootClientSet, name, namespace)
+			vcClient, err := tasks.GetVcClientset(c.RootClientSet, name, namespace, originalCluster.Spec.KubeInKubeConfig)
 			if err != nil {
 				klog.Errorf("Get vc client failed. err: %s", err.Error())
 				return reconcile.Result{}, errors.Wrapf(err, "Get vc client failed. err: %s", err.Error())
@@ -172,36 +417,146 @@ func (c *VirtualClusterInitController) Reconcile(ctx context.Context, request re
 			klog.V(2).Infof("Label %d node for keepalived", reps)
 		}
 
-		err := c.ensureAllPodsRunning(updatedCluster, constants.WaitAllPodsRunningTimeoutSeconds*time.Second)
-		if err != nil {
-			klog.Errorf("Check all pods running err: %s", err.Error())
-			updatedCluster.Status.Reason = err.Error()
-			updatedCluster.Status.Phase = v1alpha1.Pending
+		var requeueAfter time.Duration
+		var err error
+		if updatedCluster.Spec.SkipReadinessCheck {
+			setAllPodsRunningCondition(updatedCluster, metav1.ConditionTrue, "ReadinessCheckSkipped", "readiness gate skipped by Spec.SkipReadinessCheck")
+			updatedCluster.Status.Phase = workersCompletionPhase(updatedCluster)
+			c.recordEvent(updatedCluster, corev1.EventTypeNormal, EventReasonReadinessCheckSkipped, "Skipped waiting for all pods running, per Spec.SkipReadinessCheck")
+			c.recordEvent(updatedCluster, corev1.EventTypeNormal, EventReasonPhaseTransition, "Phase transitioning to %s", updatedCluster.Status.Phase)
+			recordCreationDuration(updatedCluster, time.Now())
+			c.recordReconcileSuccess(updatedCluster)
 		} else {
-			updatedCluster.Status.Phase = v1alpha1.Completed
+			err = c.ensureAllPodsRunning(ctx, updatedCluster, readinessTimeout(updatedCluster))
+			if err != nil {
+				klog.Errorf("Check all pods running err: %s", err.Error())
+				setAllPodsRunningCondition(updatedCluster, metav1.ConditionFalse, "Timeout", err.Error())
+				deriveReasonFromConditions(updatedCluster, err.Error())
+				updatedCluster.Status.Phase = v1alpha1.Pending
+				c.recordEvent(updatedCluster, corev1.EventTypeWarning, EventReasonReadinessWaitTimeout, "Timed out waiting for all pods running: %v", err)
+				c.recordReconcileFailure(updatedCluster, time.Now())
+				requeueAfter = reconcileBackoff(updatedCluster.Status.FailureCount)
+			} else {
+				setAllPodsRunningCondition(updatedCluster, metav1.ConditionTrue, "AllRunning", "all pods are running")
+				updatedCluster.Status.Phase = workersCompletionPhase(updatedCluster)
+				c.recordEvent(updatedCluster, corev1.EventTypeNormal, EventReasonPhaseTransition, "Phase transitioning to %s", updatedCluster.Status.Phase)
+				recordCreationDuration(updatedCluster, time.Now())
+				c.recordReconcileSuccess(updatedCluster)
+			}
 		}
+		recordPhaseTransition(currentPhase, updatedCluster.Status.Phase)
+		currentPhase = updatedCluster.Status.Phase
 		err = c.Update(updatedCluster)
 		if err != nil {
 			klog.Errorf("Error update virtualcluster %s status to %s", updatedCluster.Name, updatedCluster.Status.Phase)
 			return reconcile.Result{}, errors.Wrapf(err, "Error update virtualcluster %s status", updatedCluster.Name)
 		}
-	case v1alpha1.Completed:
+		if requeueAfter > 0 {
+			result, err := c.ensureFinalizer(updatedCluster)
+			if err != nil {
+				return result, err
+			}
+			result.RequeueAfter = requeueAfter
+			return result, nil
+		}
+	case v1alpha1.Preparing, v1alpha1.Pending:
+		// Preparing/Pending are normally transient: case "" above carries a
+		// cluster through Preparing to Initialized/Pending within a single
+		// Reconcile call. Observing one of them here means a previous
+		// Reconcile was interrupted (e.g. the controller restarted) between
+		// status writes, leaving no in-memory requeue to retry it. Only
+		// re-drive once that's been true for a while, to avoid fighting the
+		// fast exponential backoff a normal createVirtualCluster failure
+		// already scheduled for itself. requestRetry bypasses this wait when
+		// this reconcile was triggered by a GlobalNode freeing up that may
+		// satisfy this cluster's PromotePolicy: there's no point waiting out
+		// the rest of StuckPhaseTimeout when the thing it was waiting for
+		// just became available.
+		if !isPhaseStuck(originalCluster, time.Now()) && !c.consumeRetryRequested(request.NamespacedName) {
+			return reconcile.Result{RequeueAfter: constants.StuckPhaseTimeout}, nil
+		}
+		if updatedCluster.Status.FailureCount >= constants.MaxStuckPhaseRedriveAttempts {
+			klog.Warningf("virtualcluster %s has been stuck in %s for over %s and exhausted %d re-drive attempts, leaving it for an operator", updatedCluster.Name, currentPhase, constants.StuckPhaseTimeout, updatedCluster.Status.FailureCount)
+			c.recordEvent(updatedCluster, corev1.EventTypeWarning, EventReasonPhaseStuckExhausted, "VirtualCluster has been stuck in %s and exhausted %d re-drive attempts; manual intervention required", currentPhase, updatedCluster.Status.FailureCount)
+			return reconcile.Result{}, nil
+		}
+		klog.Warningf("virtualcluster %s has been stuck in %s for over %s, re-driving creation", updatedCluster.Name, currentPhase, constants.StuckPhaseTimeout)
+		c.recordEvent(updatedCluster, corev1.EventTypeWarning, EventReasonPhaseStuck, "VirtualCluster has been stuck in %s for over %s, re-driving creation", currentPhase, constants.StuckPhaseTimeout)
+
+		var requeueAfter time.Duration
+		if err := c.createVirtualCluster(ctx, updatedCluster, c.KubeNestOptions); err != nil {
+			klog.Errorf("Failed to re-drive stuck virtualcluster %s. err: %s", updatedCluster.Name, err.Error())
+			deriveReasonFromConditions(updatedCluster, err.Error())
+			updatedCluster.Status.Phase = v1alpha1.Pending
+			c.recordEvent(updatedCluster, corev1.EventTypeWarning, EventReasonControlPlaneExecFailed, "Failed to create virtual cluster: %v", err)
+			c.recordReconcileFailure(updatedCluster, time.Now())
+			requeueAfter = reconcileBackoff(updatedCluster.Status.FailureCount)
+		} else {
+			if updatedCluster.Spec.DryRun {
+				updatedCluster.Status.Phase = v1alpha1.DryRunComplete
+			} else {
+				updatedCluster.Status.Phase = v1alpha1.Initialized
+			}
+			c.recordEvent(updatedCluster, corev1.EventTypeNormal, EventReasonPhaseTransition, "Phase transitioning to %s", updatedCluster.Status.Phase)
+			c.recordReconcileSuccess(updatedCluster)
+		}
+		recordPhaseTransition(currentPhase, updatedCluster.Status.Phase)
+		currentPhase = updatedCluster.Status.Phase
+		if err := c.Update(updatedCluster); err != nil {
+			klog.Errorf("Error update virtualcluster %s status to %s. %v", updatedCluster.Name, updatedCluster.Status.Phase, err)
+			return reconcile.Result{}, errors.Wrapf(err, "Error update virtualcluster %s status", updatedCluster.Name)
+		}
+		if requeueAfter > 0 {
+			return reconcile.Result{RequeueAfter: requeueAfter}, nil
+		}
+	case v1alpha1.Completed, v1alpha1.WorkersScaledDown:
+		endpointRefreshed, err := c.refreshAPIServerEndpoint(updatedCluster)
+		if err != nil {
+			klog.Errorf("Error refresh virtualcluster %s apiserver endpoint. err: %s", updatedCluster.Name, err.Error())
+			return reconcile.Result{RequeueAfter: jitteredRequeueTime()}, errors.Wrapf(err, "Error refresh virtualcluster %s apiserver endpoint", updatedCluster.Name)
+		}
+		if endpointRefreshed {
+			if err := c.Update(updatedCluster); err != nil {
+				klog.Errorf("Error update virtualcluster %s status to refresh apiserver endpoint. err: %s", updatedCluster.Name, err.Error())
+				return reconcile.Result{}, errors.Wrapf(err, "Error update virtualcluster %s status", updatedCluster.Name)
+			}
+		}
+
 		//update request, check if promotepolicy nodes increase or decrease.
-		// only 2 scenarios matched update request with status 'completed'.
-		// 1. node scale request, original status is 'completed'. 2. node scale process finished by NodeController, the controller changes status from 'updating' to 'completed'
+		// several scenarios matched an update request with status 'completed' or 'workersscaleddown':
+		// 1. node scale request, original status is 'completed'. 2. node scale process finished by NodeController, the controller changes status from 'updating' to 'completed'.
+		// 3. a scaled-to-zero cluster (status 'workersscaleddown') has its PromotePolicies raised again, re-entering the normal assignment path.
+		//
+		// Status.ObservedGeneration tracks the last Generation this check ran
+		// for, so a reconcile triggered purely by a status write (e.g. the
+		// endpoint refresh above, or another controller's status update) with
+		// no spec change doesn't re-list every GlobalNode to re-derive an
+		// answer we already know.
+		if updatedCluster.Status.ObservedGeneration == updatedCluster.Generation {
+			return reconcile.Result{}, nil
+		}
 		policyChanged, err := c.checkPromotePoliciesChanged(updatedCluster)
 		if err != nil {
 			klog.Errorf("Error check promote policies changed. err: %s", err.Error())
-			return reconcile.Result{RequeueAfter: RequeueTime}, errors.Wrapf(err, "Error checkPromotePoliciesChanged virtualcluster %s", updatedCluster.Name)
+			return reconcile.Result{RequeueAfter: jitteredRequeueTime()}, errors.Wrapf(err, "Error checkPromotePoliciesChanged virtualcluster %s", updatedCluster.Name)
 		}
 		if !policyChanged {
+			updatedCluster.Status.ObservedGeneration = updatedCluster.Generation
+			if err := c.Update(updatedCluster); err != nil {
+				klog.Errorf("Error update virtualcluster %s status ObservedGeneration. %v", updatedCluster.Name, err)
+				return reconcile.Result{}, errors.Wrapf(err, "Error update virtualcluster %s status", updatedCluster.Name)
+			}
 			return reconcile.Result{}, nil
 		}
-		err = c.assignWorkNodes(updatedCluster)
+		claimedNodes, err := c.assignWorkNodes(ctx, updatedCluster)
 		if err != nil {
-			return reconcile.Result{RequeueAfter: RequeueTime}, errors.Wrapf(err, "Error update virtualcluster %s", updatedCluster.Name)
+			c.releaseClaimedNodes(claimedNodes)
+			return reconcile.Result{RequeueAfter: jitteredRequeueTime()}, errors.Wrapf(err, "Error update virtualcluster %s", updatedCluster.Name)
 		}
 		updatedCluster.Status.Phase = v1alpha1.Updating
+		updatedCluster.Status.ObservedGeneration = updatedCluster.Generation
+		c.recordEvent(updatedCluster, corev1.EventTypeNormal, EventReasonPhaseTransition, "Phase transitioning to %s", updatedCluster.Status.Phase)
+		recordPhaseTransition(currentPhase, updatedCluster.Status.Phase)
 		err = c.Update(updatedCluster)
 		if err != nil {
 			klog.Errorf("Error update virtualcluster %s status to %s", updatedCluster.Name, updatedCluster.Status.Phase)
@@ -214,10 +569,21 @@ func (c *VirtualClusterInitController) Reconcile(ctx context.Context, request re
 	return c.ensureFinalizer(updatedCluster)
 }
 
+// defaultMaxConcurrentReconciles is used when
+// KubeNestOptions.KubeInKubeConfig.MaxConcurrentReconciles is unset.
+const defaultMaxConcurrentReconciles = 5
+
 func (c *VirtualClusterInitController) SetupWithManager(mgr manager.Manager) error {
+	if c.NodeSelectionStrategy == nil {
+		c.NodeSelectionStrategy = OrderedNodeSelectionStrategy{}
+	}
+	maxConcurrentReconciles := defaultMaxConcurrentReconciles
+	if c.KubeNestOptions != nil && c.KubeNestOptions.KubeInKubeConfig.MaxConcurrentReconciles > 0 {
+		maxConcurrentReconciles = c.KubeNestOptions.KubeInKubeConfig.MaxConcurrentReconciles
+	}
 	return controllerruntime.NewControllerManagedBy(mgr).
 		Named(constants.InitControllerName).
-		WithOptions(controller.Options{MaxConcurrentReconciles: 5}).
+		WithOptions(controller.Options{MaxConcurrentReconciles: maxConcurrentReconciles}).
 		For(&v1alpha1.VirtualCluster{},
 			builder.WithPredicates(predicate.Funcs{
 				//	UpdateFunc: c.onVirtualClusterUpdate,
@@ -227,9 +593,95 @@ func (c *VirtualClusterInitController) SetupWithManager(mgr manager.Manager) err
 				UpdateFunc: func(updateEvent event.UpdateEvent) bool { return true },
 				DeleteFunc: func(deleteEvent event.DeleteEvent) bool { return true },
 			})).
+		Watches(&source.Kind{Type: &v1alpha1.GlobalNode{}}, handler.EnqueueRequestsFromMapFunc(c.newGlobalNodeMapFunc())).
+		Watches(&source.Kind{Type: &corev1.Service{}}, handler.EnqueueRequestsFromMapFunc(c.newAPIServerServiceMapFunc())).
 		Complete(c)
 }
 
+// newAPIServerServiceMapFunc re-enqueues a VirtualCluster when its apiserver
+// Service changes (e.g. NodePort reassigned, LoadBalancer IP re-provisioned),
+// so the stored endpoint can be refreshed instead of going stale.
+func (c *VirtualClusterInitController) newAPIServerServiceMapFunc() handler.MapFunc {
+	return func(a client.Object) []reconcile.Request {
+		service, ok := a.(*corev1.Service)
+		if !ok || !strings.HasSuffix(service.GetName(), "-apiserver") {
+			return nil
+		}
+		name := strings.TrimSuffix(service.GetName(), "-apiserver")
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name, Namespace: service.GetNamespace()}}}
+	}
+}
+
+// newGlobalNodeMapFunc retries Pending/Preparing VirtualClusters promptly
+// when a GlobalNode frees up elsewhere (e.g. another cluster scaling down),
+// instead of waiting for that GlobalNode's own VirtualCluster to next
+// reconcile. Only a GlobalNode entering NodeFreeState can newly satisfy a
+// stuck cluster's unmet PromotePolicy, so other transitions are ignored.
+func (c *VirtualClusterInitController) newGlobalNodeMapFunc() handler.MapFunc {
+	return func(a client.Object) []reconcile.Request {
+		globalNode, ok := a.(*v1alpha1.GlobalNode)
+		if !ok || globalNode.Spec.State != v1alpha1.NodeFreeState {
+			return nil
+		}
+		return c.enqueuePendingClustersMatching(*globalNode)
+	}
+}
+
+// enqueuePendingClustersMatching lists VirtualClusters stuck in Pending or
+// Preparing (see the Reconcile case for those phases: both retry node
+// assignment the same way) and returns a reconcile request for each one that
+// has a PromotePolicy globalNode now satisfies, so its next reconcile can
+// retry node assignment immediately instead of waiting on its own resync
+// period or isPhaseStuck timeout.
+func (c *VirtualClusterInitController) enqueuePendingClustersMatching(globalNode v1alpha1.GlobalNode) []reconcile.Request {
+	var clusterList v1alpha1.VirtualClusterList
+	if err := c.Client.List(context.TODO(), &clusterList); err != nil {
+		klog.Errorf("list virtualclusters to retry against freed globalnode %s error: %v", globalNode.Name, err)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for i := range clusterList.Items {
+		vc := clusterList.Items[i]
+		if vc.Status.Phase != v1alpha1.Pending && vc.Status.Phase != v1alpha1.Preparing {
+			continue
+		}
+		if !c.matchesAnyPromotePolicy(&vc, globalNode) {
+			continue
+		}
+		klog.V(2).Infof("Globalnode %s freed up and may satisfy %s virtualcluster %s/%s, enqueueing it for retry", globalNode.Name, vc.Status.Phase, vc.Namespace, vc.Name)
+		key := types.NamespacedName{Namespace: vc.Namespace, Name: vc.Name}
+		c.requestRetry(key)
+		requests = append(requests, reconcile.Request{NamespacedName: key})
+	}
+	return requests
+}
+
+// requestRetry marks key so the next Reconcile for it bypasses isPhaseStuck,
+// for enqueuePendingClustersMatching to flag a VirtualCluster whose freed
+// GlobalNode warrants an immediate retry instead of waiting out
+// constants.StuckPhaseTimeout.
+func (c *VirtualClusterInitController) requestRetry(key types.NamespacedName) {
+	c.retryRequestedLock.Lock()
+	defer c.retryRequestedLock.Unlock()
+	if c.retryRequested == nil {
+		c.retryRequested = map[types.NamespacedName]bool{}
+	}
+	c.retryRequested[key] = true
+}
+
+// consumeRetryRequested reports whether key was marked by requestRetry,
+// clearing the mark so it only bypasses isPhaseStuck once.
+func (c *VirtualClusterInitController) consumeRetryRequested(key types.NamespacedName) bool {
+	c.retryRequestedLock.Lock()
+	defer c.retryRequestedLock.Unlock()
+	if !c.retryRequested[key] {
+		return false
+	}
+	delete(c.retryRequested, key)
+	return true
+}
+
 func (c *VirtualClusterInitController) Update(updated *v1alpha1.VirtualCluster) error {
 	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		current := &v1alpha1.VirtualCluster{}
@@ -248,9 +700,16 @@ func (c *VirtualClusterInitController) Update(updated *v1alpha1.VirtualCluster)
 }
 
 func (c *VirtualClusterInitController) ensureFinalizer(virtualCluster *v1alpha1.VirtualCluster) (reconcile.Result, error) {
-	if controllerutil.ContainsFinalizer(virtualCluster, VirtualClusterControllerFinalizer) {
+	needsFinalizer := !controllerutil.ContainsFinalizer(virtualCluster, VirtualClusterControllerFinalizer)
+	for _, sf := range c.SubFinalizers {
+		if !controllerutil.ContainsFinalizer(virtualCluster, sf.Name) {
+			needsFinalizer = true
+		}
+	}
+	if !needsFinalizer {
 		return reconcile.Result{}, nil
 	}
+
 	current := &v1alpha1.VirtualCluster{}
 	if err := c.Client.Get(context.TODO(), types.NamespacedName{
 		Namespace: virtualCluster.Namespace,
@@ -262,6 +721,9 @@ func (c *VirtualClusterInitController) ensureFinalizer(virtualCluster *v1alpha1.
 
 	updated := current.DeepCopy()
 	controllerutil.AddFinalizer(updated, VirtualClusterControllerFinalizer)
+	for _, sf := range c.SubFinalizers {
+		controllerutil.AddFinalizer(updated, sf.Name)
+	}
 	err := c.Client.Update(context.TODO(), updated)
 	if err != nil {
 		klog.Errorf("update virtualcluster %s error. %v", virtualCluster.Name, err)
@@ -273,7 +735,15 @@ func (c *VirtualClusterInitController) ensureFinalizer(virtualCluster *v1alpha1.
 }
 
 func (c *VirtualClusterInitController) removeFinalizer(virtualCluster *v1alpha1.VirtualCluster) (reconcile.Result, error) {
-	if !controllerutil.ContainsFinalizer(virtualCluster, VirtualClusterControllerFinalizer) {
+	hasMainFinalizer := controllerutil.ContainsFinalizer(virtualCluster, VirtualClusterControllerFinalizer)
+	hasSubFinalizer := false
+	for _, sf := range c.SubFinalizers {
+		if controllerutil.ContainsFinalizer(virtualCluster, sf.Name) {
+			hasSubFinalizer = true
+			break
+		}
+	}
+	if !hasMainFinalizer && !hasSubFinalizer {
 		return reconcile.Result{}, nil
 	}
 
@@ -287,20 +757,66 @@ func (c *VirtualClusterInitController) removeFinalizer(virtualCluster *v1alpha1.
 	}
 	updated := current.DeepCopy()
 
-	controllerutil.RemoveFinalizer(updated, VirtualClusterControllerFinalizer)
+	// Clear the persisted admin kubeconfig so a stale base64-encoded
+	// credential isn't left behind in etcd once the Secret it mirrors
+	// (deleted by destroyVirtualCluster) and the virtual cluster itself are
+	// gone.
+	updated.Spec.Kubeconfig = ""
+
+	// Remove every SubFinalizer whose cleanup has completed. Any that isn't
+	// ready yet is left in place, and its presence below keeps
+	// VirtualClusterControllerFinalizer from being removed this pass -- see
+	// the SubFinalizer doc comment for the ordering guarantee this gives.
+	subFinalizerPending := false
+	for _, sf := range c.SubFinalizers {
+		if !controllerutil.ContainsFinalizer(updated, sf.Name) {
+			continue
+		}
+		if sf.Ready == nil || !sf.Ready(virtualCluster) {
+			subFinalizerPending = true
+			continue
+		}
+		controllerutil.RemoveFinalizer(updated, sf.Name)
+	}
+
+	if !subFinalizerPending {
+		controllerutil.RemoveFinalizer(updated, VirtualClusterControllerFinalizer)
+	}
+
 	err := c.Client.Update(context.TODO(), updated)
 	if err != nil {
 		klog.Errorf("Failed to remove finalizer to VirtualCluster %s/%s: %v", virtualCluster.Namespace, virtualCluster.Name, err)
 		return reconcile.Result{Requeue: true}, err
 	}
+	if subFinalizerPending {
+		return reconcile.Result{Requeue: true}, nil
+	}
 
 	return reconcile.Result{}, nil
 }
 
 // nolint:revive
 // createVirtualCluster assign work nodes, create control plane and create compoennts from manifests
-func (c *VirtualClusterInitController) createVirtualCluster(virtualCluster *v1alpha1.VirtualCluster, kubeNestOptions *v1alpha1.KubeNestConfiguration) error {
-	klog.V(2).Infof("Reconciling virtual cluster", "name", virtualCluster.Name)
+func (c *VirtualClusterInitController) createVirtualCluster(ctx context.Context, virtualCluster *v1alpha1.VirtualCluster, kubeNestOptions *v1alpha1.KubeNestConfiguration) error {
+	logger := log.FromContext(ctx)
+	logger.V(2).Info("Reconciling virtual cluster")
+
+	kubernetesVersion := util.ResolveKubernetesVersion(virtualCluster, kubeNestOptions)
+	if err := util.ValidateKubernetesVersion(kubernetesVersion); err != nil {
+		return errors.Wrap(err, "Error resolving virtual cluster Kubernetes version")
+	}
+	virtualCluster.Status.KubernetesVersion = kubernetesVersion
+
+	if virtualCluster.Spec.DryRun {
+		logger.V(2).Info("DryRun virtual cluster, only computing node assignment")
+		_, err := c.assignWorkNodes(ctx, virtualCluster)
+		return err
+	}
+
+	if virtualCluster.Spec.ExternalControlPlane != nil {
+		logger.V(2).Info("External control plane configured, skipping control plane provisioning")
+		return c.registerExternalControlPlane(ctx, virtualCluster)
+	}
 
 	//Assign host port
 	_, err := c.AllocateHostPort(virtualCluster, kubeNestOptions)
@@ -310,7 +826,7 @@ func (c *VirtualClusterInitController) createVirtualCluster(virtualCluster *v1al
 	// check if enable vip
 	vipPool, err := GetVipFromConfigMap(c.RootClientSet, constants.KosmosNs, constants.VipPoolConfigMapName, constants.VipPoolKey)
 	if err == nil && vipPool != nil && len(vipPool.Vips) > 0 {
-		klog.V(2).Infof("Enable vip for virtual cluster %s", virtualCluster.Name)
+		logger.V(2).Info("Enable vip for virtual cluster")
 		//Allocate vip
 		err = c.AllocateVip(virtualCluster, vipPool)
 		if err != nil {
@@ -318,49 +834,267 @@ func (c *VirtualClusterInitController) createVirtualCluster(virtualCluster *v1al
 		}
 	}
 
-	executer, err := NewExecutor(virtualCluster, c.Client, c.Config, kubeNestOptions)
+	executer, err := c.newExecutor(virtualCluster, c.Config, kubeNestOptions)
 	if err != nil {
 		return err
 	}
-	err = c.assignWorkNodes(virtualCluster)
+	claimedNodes, err := c.assignWorkNodes(ctx, virtualCluster)
 	if err != nil {
+		c.releaseClaimedNodes(claimedNodes)
 		return errors.Wrap(err, "Error in assign work nodes")
 	}
-	klog.V(2).Infof("Successfully assigned work node for virtual cluster %s", virtualCluster.Name)
+	logger.V(2).Info("Successfully assigned work node for virtual cluster")
 	getKubeconfig := func() (string, error) {
-		secretName := fmt.Sprintf("%s-%s", virtualCluster.GetName(), constants.AdminConfig)
-		secret, err := c.RootClientSet.CoreV1().Secrets(virtualCluster.GetNamespace()).Get(context.TODO(), secretName, metav1.GetOptions{})
+		secretName := util.GetAdminConfigSecretName(virtualCluster.GetName())
+		var secret *corev1.Secret
+		err := wait.PollImmediate(constants.AdminConfigSecretPollInterval, constants.AdminConfigSecretPollTimeout, func() (bool, error) {
+			var getErr error
+			secret, getErr = c.RootClientSet.CoreV1().Secrets(virtualCluster.GetNamespace()).Get(context.TODO(), secretName, metav1.GetOptions{})
+			if getErr != nil {
+				if apierrors.IsNotFound(getErr) {
+					// executer.Execute() just wrote this secret; tolerate it
+					// not having propagated to the API server's cache yet
+					// instead of failing the whole create outright.
+					return false, nil
+				}
+				return false, getErr
+			}
+			return true, nil
+		})
 		if err != nil {
 			return "", errors.Wrapf(err, "Failed to get secret %s for virtual cluster %s", secretName, virtualCluster.GetName())
 		}
 		return base64.StdEncoding.EncodeToString(secret.Data[constants.KubeConfig]), nil
 	}
+	c.recordEvent(virtualCluster, corev1.EventTypeNormal, EventReasonControlPlaneExecStarted, "Starting control plane execution")
 	err = executer.Execute()
 	if err != nil {
+		c.recordEvent(virtualCluster, corev1.EventTypeWarning, EventReasonControlPlaneExecFailed, "Control plane execution failed: %v", err)
+		setControlPlaneReadyCondition(virtualCluster, metav1.ConditionFalse, "ExecutionFailed", err.Error())
+		c.releaseClaimedNodes(claimedNodes)
 		virtualCluster.Spec.Kubeconfig, _ = getKubeconfig()
 		return err
 	}
+	setControlPlaneReadyCondition(virtualCluster, metav1.ConditionTrue, "ExecutionSucceeded", "control plane components executed successfully")
 	virtualCluster.Spec.Kubeconfig, err = getKubeconfig()
 	return err
 }
 
+// registerExternalControlPlane handles createVirtualCluster for a
+// VirtualCluster with Spec.ExternalControlPlane set: instead of running any
+// executor control-plane provisioning task, it loads the referenced
+// kubeconfig secret, validates connectivity to the existing apiserver, and
+// proceeds straight to node assignment, which createVirtualCluster otherwise
+// runs just before its own executer.Execute().
+func (c *VirtualClusterInitController) registerExternalControlPlane(ctx context.Context, virtualCluster *v1alpha1.VirtualCluster) error {
+	logger := log.FromContext(ctx)
+	externalControlPlane := virtualCluster.Spec.ExternalControlPlane
+
+	secret, err := c.RootClientSet.CoreV1().Secrets(virtualCluster.GetNamespace()).Get(ctx, externalControlPlane.KubeconfigSecretRef, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "Failed to get kubeconfig secret %s for external control plane of virtual cluster %s", externalControlPlane.KubeconfigSecretRef, virtualCluster.GetName())
+	}
+	kubeconfigBytes := secret.Data[constants.KubeConfig]
+	if len(kubeconfigBytes) == 0 {
+		return fmt.Errorf("secret %s for external control plane of virtual cluster %s has no %q data", externalControlPlane.KubeconfigSecretRef, virtualCluster.GetName(), constants.KubeConfig)
+	}
+
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfigBytes)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to parse kubeconfig secret %s for external control plane of virtual cluster %s", externalControlPlane.KubeconfigSecretRef, virtualCluster.GetName())
+	}
+	externalClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return errors.Wrap(err, "Error building client for external control plane")
+	}
+	if _, err := externalClient.Discovery().ServerVersion(); err != nil {
+		c.recordEvent(virtualCluster, corev1.EventTypeWarning, EventReasonControlPlaneExecFailed, "Failed to reach external control plane %s: %v", externalControlPlane.APIServerEndpoint, err)
+		setControlPlaneReadyCondition(virtualCluster, metav1.ConditionFalse, "ExternalControlPlaneUnreachable", err.Error())
+		return errors.Wrapf(err, "Failed to validate connectivity to external control plane %s", externalControlPlane.APIServerEndpoint)
+	}
+	logger.V(2).Info("Validated connectivity to external control plane", "endpoint", externalControlPlane.APIServerEndpoint)
+	setControlPlaneReadyCondition(virtualCluster, metav1.ConditionTrue, "ExternalControlPlaneReachable", "external control plane is reachable")
+	virtualCluster.Spec.Kubeconfig = base64.StdEncoding.EncodeToString(kubeconfigBytes)
+
+	claimedNodes, err := c.assignWorkNodes(ctx, virtualCluster)
+	if err != nil {
+		c.releaseClaimedNodes(claimedNodes)
+		return errors.Wrap(err, "Error in assign work nodes")
+	}
+	return nil
+}
+
+// setControlPlaneReadyCondition records the outcome of the most recent
+// control plane executer.Execute() call as the ControlPlaneReady condition.
+func setControlPlaneReadyCondition(vc *v1alpha1.VirtualCluster, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&vc.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.ControlPlaneReadyConditionType,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
+// setAllPodsRunningCondition records the outcome of the most recent
+// ensureAllPodsRunning call as the AllPodsRunning condition.
+func setAllPodsRunningCondition(vc *v1alpha1.VirtualCluster, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&vc.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.AllPodsRunningConditionType,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
+// trackedConditionTypes are the Status.Conditions types deriveReasonFromConditions
+// considers when repopulating Status.Reason for backward compatibility.
+var trackedConditionTypes = []string{
+	v1alpha1.NodesAssignedConditionType,
+	v1alpha1.ControlPlaneReadyConditionType,
+	v1alpha1.AllPodsRunningConditionType,
+}
+
+// deriveReasonFromConditions sets vc.Status.Reason to the Message of the
+// most recently transitioned False condition among trackedConditionTypes,
+// so Status.Reason keeps reflecting the current failure for consumers that
+// haven't moved to reading Status.Conditions yet. Falls back to fallback if
+// none of the tracked conditions are currently False.
+func deriveReasonFromConditions(vc *v1alpha1.VirtualCluster, fallback string) {
+	var latest *metav1.Condition
+	for _, conditionType := range trackedConditionTypes {
+		condition := meta.FindStatusCondition(vc.Status.Conditions, conditionType)
+		if condition == nil || condition.Status != metav1.ConditionFalse {
+			continue
+		}
+		if latest == nil || condition.LastTransitionTime.After(latest.LastTransitionTime.Time) {
+			latest = condition
+		}
+	}
+	if latest != nil {
+		vc.Status.Reason = latest.Message
+		return
+	}
+	vc.Status.Reason = fallback
+}
+
+// releaseClaimedNodes reverts GlobalNodes this reconcile claimed (transitioned
+// to NodeInUse by assignWorkNodes) back to NodeFreeState, so a
+// createVirtualCluster failure partway through doesn't leave them stuck in
+// use forever. Only the node names returned by that same assignWorkNodes
+// call are touched, so nodes claimed by an earlier successful reconcile or
+// already in use by another cluster are left alone.
+func (c *VirtualClusterInitController) releaseClaimedNodes(claimedNodes []string) {
+	for _, name := range claimedNodes {
+		node, err := c.KosmosClient.KosmosV1alpha1().GlobalNodes().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				klog.Errorf("rollback: failed to get globalnode %s: %v", name, err)
+			}
+			continue
+		}
+		if err := c.releaseGlobalNodeUsageStatus(node); err != nil {
+			klog.Errorf("rollback: failed to release claimed globalnode %s: %v", name, err)
+		}
+	}
+}
+
 func (c *VirtualClusterInitController) destroyVirtualCluster(virtualCluster *v1alpha1.VirtualCluster) error {
 	klog.V(2).Infof("Destroying virtual cluster %s", virtualCluster.Name)
-	execute, err := NewExecutor(virtualCluster, c.Client, c.Config, c.KubeNestOptions)
+	execute, err := c.newExecutor(virtualCluster, c.Config, c.KubeNestOptions)
 	if err != nil {
 		return err
 	}
-	return execute.Execute()
+	if err := execute.Execute(); err != nil {
+		return err
+	}
+	if err := c.releaseClusterNodesOnDestroy(virtualCluster); err != nil {
+		return err
+	}
+	c.releaseClusterPortsOnDestroy(virtualCluster)
+	return c.deleteAdminKubeconfigSecret(virtualCluster)
+}
+
+// releaseClusterPortsOnDestroy returns virtualCluster's host-network ports to
+// c.PortManager's free pool, so they can be handed out to another
+// VirtualCluster instead of staying marked allocated forever. A no-op when
+// c.PortManager is unset (e.g. NodePort-only deployments) or when none of
+// these ports came from the pool to begin with - ReleasePorts ignores ports
+// it doesn't recognize as allocated.
+func (c *VirtualClusterInitController) releaseClusterPortsOnDestroy(virtualCluster *v1alpha1.VirtualCluster) {
+	if c.PortManager == nil {
+		return
+	}
+	ports := make([]int32, 0, len(virtualCluster.Status.PortMap)+1)
+	if virtualCluster.Status.Port != 0 {
+		ports = append(ports, virtualCluster.Status.Port)
+	}
+	for _, port := range virtualCluster.Status.PortMap {
+		ports = append(ports, port)
+	}
+	c.PortManager.ReleasePorts(ports)
 }
 
-func (c *VirtualClusterInitController) assignWorkNodes(virtualCluster *v1alpha1.VirtualCluster) error {
-	c.lock.Lock()
-	defer c.lock.Unlock()
+// releaseClusterNodesOnDestroy returns every GlobalNode still claimed by
+// virtualCluster (Status.VirtualCluster == virtualCluster.Name) to
+// NodeFreeState and clears its usage status. By the time destroyVirtualCluster
+// runs, virtualCluster.Spec.PromoteResources.NodeInfos has already been
+// cleared by the earlier Deleting transition, so GlobalNodes are looked up by
+// their recorded owner instead - the same field OrphanedNodeController uses.
+// This ties node reclamation to the deletion path this controller controls,
+// rather than depending on NodeController having freed them first: if the
+// tenant apiserver is already gone by the time NodeController runs, it can
+// find zero actual nodes to unjoin and reach AllNodeDeleted without ever
+// touching the GlobalNode. Already-free or already-gone GlobalNodes are not
+// an error.
+func (c *VirtualClusterInitController) releaseClusterNodesOnDestroy(virtualCluster *v1alpha1.VirtualCluster) error {
 	globalNodeList, err := c.KosmosClient.KosmosV1alpha1().GlobalNodes().List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
 		return fmt.Errorf("list global nodes: %w", err)
 	}
+	for i := range globalNodeList.Items {
+		node := &globalNodeList.Items[i]
+		if node.Status.VirtualCluster != virtualCluster.Name {
+			continue
+		}
+		if err := c.releaseGlobalNodeUsageStatus(node); err != nil {
+			return fmt.Errorf("release globalnode %s: %w", node.Name, err)
+		}
+	}
+	return nil
+}
+
+// deleteAdminKubeconfigSecret deletes the "<name>-<AdminConfig>" Secret the
+// control plane's admin kubeconfig was persisted into, so it doesn't linger
+// in the host namespace if the executor's own teardown doesn't cover it.
+// Already-gone is not an error.
+func (c *VirtualClusterInitController) deleteAdminKubeconfigSecret(virtualCluster *v1alpha1.VirtualCluster) error {
+	secretName := fmt.Sprintf("%s-%s", virtualCluster.GetName(), constants.AdminConfig)
+	err := c.RootClientSet.CoreV1().Secrets(virtualCluster.GetNamespace()).Delete(context.TODO(), secretName, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete admin kubeconfig secret %s/%s", virtualCluster.GetNamespace(), secretName)
+	}
+	return nil
+}
+
+// assignWorkNodes assigns GlobalNodes to virtualCluster's PromotePolicies. It
+// returns the names of any GlobalNodes this call itself transitioned to
+// NodeInUse, so a caller that fails later in the same reconcile can roll
+// back exactly those claims via releaseClaimedNodes, without touching nodes
+// claimed by an earlier, already-successful reconcile or by another cluster.
+func (c *VirtualClusterInitController) assignWorkNodes(ctx context.Context, virtualCluster *v1alpha1.VirtualCluster) ([]string, error) {
+	logger := log.FromContext(ctx)
+	c.globalNodeLock.Lock()
+	defer c.globalNodeLock.Unlock()
+	c.recordEvent(virtualCluster, corev1.EventTypeNormal, EventReasonNodeAssignmentStarted,
+		"Assigning work nodes for policies: %s", promotePoliciesSummary(virtualCluster.Spec.PromotePolicies))
+	globalNodeList, err := c.KosmosClient.KosmosV1alpha1().GlobalNodes().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("list global nodes: %w", err)
+	}
+	recordGlobalNodeStateCounts(globalNodeList.Items)
+	previouslyAssigned := virtualCluster.Spec.PromoteResources.NodeInfos
 	allNodeInfos := make([]v1alpha1.NodeInfo, 0)
+	policyStatuses := make([]v1alpha1.PromotePolicyStatus, 0, len(virtualCluster.Spec.PromotePolicies))
 	globalNodes := globalNodeList.Items
 	sort.Slice(globalNodes, func(i, j int) bool {
 		return globalNodes[i].Name < globalNodes[j].Name
@@ -368,24 +1102,74 @@ func (c *VirtualClusterInitController) assignWorkNodes(virtualCluster *v1alpha1.
 	for _, policy := range virtualCluster.Spec.PromotePolicies {
 		globalNodes, err := retrieveGlobalNodesWithLabelSelector(globalNodeList.Items, policy.LabelSelector)
 		if err != nil {
-			return fmt.Errorf("retrieve globalnode with labelselector: %w", err)
+			return nil, fmt.Errorf("retrieve globalnode with labelselector: %w", err)
+		}
+		globalNodes = filterGlobalNodesByTolerations(globalNodes, policy.Tolerations)
+		if err := util.ValidatePromotePolicyNodeCount(policy, len(globalNodes)); err != nil {
+			policyStatuses = append(policyStatuses, newPromotePolicyStatus(policy, 0))
+			virtualCluster.Status.PromotePolicyStatuses = policyStatuses
+			metrics.NodeAssignmentFailures.WithLabelValues(virtualCluster.Namespace, virtualCluster.Name).Inc()
+			setNodesAssignedCondition(virtualCluster, metav1.ConditionFalse, "InsufficientNodes", err.Error())
+			return nil, fmt.Errorf("validate promote policy node count: %w", err)
 		}
 		sort.Slice(globalNodes, func(i, j int) bool {
 			return globalNodes[i].Name < globalNodes[j].Name
 		})
-		klog.V(4).Infof("LabelSelected Globalnode count %d", len(globalNodes))
-		nodeInfos, err := c.assignNodesByPolicy(virtualCluster, policy, globalNodes)
+		logger.V(4).Info("LabelSelected Globalnode count", "count", len(globalNodes))
+		nodeInfos, err := c.assignNodesByPolicy(virtualCluster, policy, globalNodes, globalNodeList.Items)
 		if err != nil {
-			return fmt.Errorf("assign nodes by policy: %w", err)
+			policyStatuses = append(policyStatuses, newPromotePolicyStatus(policy, 0))
+			virtualCluster.Status.PromotePolicyStatuses = policyStatuses
+			metrics.NodeAssignmentFailures.WithLabelValues(virtualCluster.Namespace, virtualCluster.Name).Inc()
+			setNodesAssignedCondition(virtualCluster, metav1.ConditionFalse, "InsufficientNodes", err.Error())
+			return nil, fmt.Errorf("assign nodes by policy: %w", err)
 		}
+		policyStatuses = append(policyStatuses, newPromotePolicyStatus(policy, len(nodeInfos)))
 		allNodeInfos = append(allNodeInfos, nodeInfos...)
 	}
+	virtualCluster.Status.PromotePolicyStatuses = policyStatuses
+
+	var otherClusterList v1alpha1.VirtualClusterList
+	if err := c.Client.List(context.TODO(), &otherClusterList); err != nil {
+		return nil, fmt.Errorf("list virtualclusters to check node ownership conflicts: %w", err)
+	}
+	otherClusters := make([]v1alpha1.VirtualCluster, 0, len(otherClusterList.Items))
+	for _, other := range otherClusterList.Items {
+		if other.Name == virtualCluster.Name && other.Namespace == virtualCluster.Namespace {
+			continue
+		}
+		otherClusters = append(otherClusters, other)
+	}
+	if conflicts := detectNodeOwnershipConflicts(virtualCluster, allNodeInfos, globalNodeList.Items, otherClusters); len(conflicts) > 0 {
+		msg := strings.Join(conflicts, "; ")
+		c.recordEvent(virtualCluster, corev1.EventTypeWarning, EventReasonNodeOwnershipConflict, msg)
+		setNodeOwnershipConflictCondition(virtualCluster, metav1.ConditionTrue, "ConflictingOwnership", msg)
+		return nil, fmt.Errorf("node ownership conflict detected: %s", msg)
+	}
+	setNodeOwnershipConflictCondition(virtualCluster, metav1.ConditionFalse, "NoConflict", "no conflicting node ownership detected")
+
+	if err := c.enforceNamespaceNodeQuota(virtualCluster, allNodeInfos, otherClusters); err != nil {
+		metrics.NodeAssignmentFailures.WithLabelValues(virtualCluster.Namespace, virtualCluster.Name).Inc()
+		setNodesAssignedCondition(virtualCluster, metav1.ConditionFalse, "QuotaExceeded", err.Error())
+		return nil, fmt.Errorf("enforce namespace node quota: %w", err)
+	}
+
+	if virtualCluster.Spec.DryRun {
+		virtualCluster.Status.PlannedNodes = allNodeInfos
+		c.recordEvent(virtualCluster, corev1.EventTypeNormal, EventReasonNodeAssignmentCompleted,
+			"Dry run planned %d nodes for policies: %s", len(allNodeInfos), promotePoliciesSummary(virtualCluster.Spec.PromotePolicies))
+		setNodesAssignedCondition(virtualCluster, metav1.ConditionTrue, "Assigned", fmt.Sprintf("planned %d nodes", len(allNodeInfos)))
+		return nil, nil
+	}
+
+	c.releaseDriftedNodes(virtualCluster, previouslyAssigned, allNodeInfos, globalNodeList.Items)
 
 	// set all node status in usage
+	var claimedNodes []string
 	for _, nodeInfo := range allNodeInfos {
 		globalNode, ok := util.FindGlobalNode(nodeInfo.NodeName, globalNodeList.Items)
 		if !ok {
-			return fmt.Errorf("assigned node %s doesn't exist in globalnode list. this should not happen normally", nodeInfo.NodeName)
+			return claimedNodes, fmt.Errorf("assigned node %s doesn't exist in globalnode list. this should not happen normally", nodeInfo.NodeName)
 		}
 
 		// only new assigned nodes' status is not `InUse`
@@ -395,34 +1179,208 @@ func (c *VirtualClusterInitController) assignWorkNodes(virtualCluster *v1alpha1.
 			// But virutalcluster's NodeInfos have not been updated yet.
 			err = c.setGlobalNodeUsageStatus(virtualCluster, globalNode)
 			if err != nil {
-				return fmt.Errorf("set globalnode %s InUse error. %v", globalNode.Name, err)
+				return claimedNodes, fmt.Errorf("set globalnode %s InUse error. %v", globalNode.Name, err)
 			}
+			claimedNodes = append(claimedNodes, globalNode.Name)
 
-			// Preventive programming. Sometimes promotePolicies may not be well-designed，not absolutely non-overlapping.
+			// Preventive programming. Sometimes promotePolicies may not be well-designed, not absolutely non-overlapping.
 			// this may lead to multiple same node in `allNodeInfos`.
 			globalNode.Spec.State = v1alpha1.NodeInUse
 		}
 	}
 	virtualCluster.Spec.PromoteResources.NodeInfos = allNodeInfos
-	return nil
+	c.recordEvent(virtualCluster, corev1.EventTypeNormal, EventReasonNodeAssignmentCompleted,
+		"Assigned %d nodes for policies: %s", len(allNodeInfos), promotePoliciesSummary(virtualCluster.Spec.PromotePolicies))
+	setNodesAssignedCondition(virtualCluster, metav1.ConditionTrue, "Assigned", fmt.Sprintf("assigned %d nodes", len(allNodeInfos)))
+	return claimedNodes, nil
+}
+
+// setNodesAssignedCondition records the outcome of the most recent
+// assignWorkNodes call as the NodesAssigned condition.
+func setNodesAssignedCondition(vc *v1alpha1.VirtualCluster, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&vc.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.NodesAssignedConditionType,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
+// setNodeOwnershipConflictCondition records the outcome of the most recent
+// detectNodeOwnershipConflicts call as the NodeOwnershipConflict condition.
+func setNodeOwnershipConflictCondition(vc *v1alpha1.VirtualCluster, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&vc.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.NodeOwnershipConflictConditionType,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
+// detectNodeOwnershipConflicts reports every node in nodeInfos whose
+// GlobalNode.Status.VirtualCluster still points at a different
+// VirtualCluster (in otherClusters) that itself still lists the same node in
+// its own Spec.PromoteResources.NodeInfos -- i.e. a node a bug or manual edit
+// left double-owned across clusters, rather than released by the cluster
+// that no longer needs it. otherClusters must not include virtualCluster
+// itself. Returns one human-readable description per conflicting node.
+func detectNodeOwnershipConflicts(virtualCluster *v1alpha1.VirtualCluster, nodeInfos []v1alpha1.NodeInfo, globalNodes []v1alpha1.GlobalNode, otherClusters []v1alpha1.VirtualCluster) []string {
+	var conflicts []string
+	for _, nodeInfo := range nodeInfos {
+		globalNode, ok := util.FindGlobalNode(nodeInfo.NodeName, globalNodes)
+		if !ok {
+			continue
+		}
+		owner := globalNode.Status.VirtualCluster
+		if owner == "" || owner == virtualCluster.Name {
+			continue
+		}
+		for _, other := range otherClusters {
+			if other.Name != owner || !clusterClaimsNode(other, nodeInfo.NodeName) {
+				continue
+			}
+			conflicts = append(conflicts, fmt.Sprintf(
+				"node %s is claimed by both %s (status.virtualCluster) and %s (spec.promoteResources.nodeInfos)",
+				nodeInfo.NodeName, owner, virtualCluster.Name))
+		}
+	}
+	return conflicts
+}
+
+// clusterClaimsNode reports whether vc's Spec.PromoteResources.NodeInfos
+// lists nodeName.
+func clusterClaimsNode(vc v1alpha1.VirtualCluster, nodeName string) bool {
+	for _, nodeInfo := range vc.Spec.PromoteResources.NodeInfos {
+		if nodeInfo.NodeName == nodeName {
+			return true
+		}
+	}
+	return false
+}
+
+// recordGlobalNodeStateCounts sets GlobalNodeStateCount to the number of
+// nodes, out of all currently-known GlobalNodes, in the Free and InUse
+// states respectively.
+func recordGlobalNodeStateCounts(globalNodes []v1alpha1.GlobalNode) {
+	var free, inUse int
+	for _, node := range globalNodes {
+		switch node.Spec.State {
+		case v1alpha1.NodeFreeState:
+			free++
+		case v1alpha1.NodeInUse:
+			inUse++
+		}
+	}
+	metrics.GlobalNodeStateCount.WithLabelValues(string(v1alpha1.NodeFreeState)).Set(float64(free))
+	metrics.GlobalNodeStateCount.WithLabelValues(string(v1alpha1.NodeInUse)).Set(float64(inUse))
+}
+
+// newPromotePolicyStatus builds the PromotePolicyStatus reported for policy
+// given how many nodes were actually assigned to it, so operators can see
+// exactly which policy is short of nodes instead of only an aggregate
+// cluster-wide Phase/Reason.
+func newPromotePolicyStatus(policy v1alpha1.PromotePolicy, assigned int) v1alpha1.PromotePolicyStatus {
+	shortage := policy.NodeCount - int32(assigned)
+	if shortage < 0 {
+		shortage = 0
+	}
+	return v1alpha1.PromotePolicyStatus{
+		Selector: metav1.FormatLabelSelector(policy.LabelSelector),
+		Desired:  policy.NodeCount,
+		Assigned: int32(assigned),
+		Ready:    int32(assigned) >= policy.NodeCount,
+		Shortage: shortage,
+	}
+}
+
+// promotePoliciesSummary renders each PromotePolicy's label selector and
+// desired node count for inclusion in node-assignment event messages, so
+// `kubectl describe virtualcluster` shows what was being assigned without
+// requiring a trip to the spec.
+func promotePoliciesSummary(policies []v1alpha1.PromotePolicy) string {
+	if len(policies) == 0 {
+		return "none"
+	}
+	parts := make([]string, 0, len(policies))
+	for _, policy := range policies {
+		selector := metav1.FormatLabelSelector(policy.LabelSelector)
+		parts = append(parts, fmt.Sprintf("[selector=%s count=%d]", selector, policy.NodeCount))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// isVirtualClusterPaused reports whether Reconcile should skip making any
+// changes to virtualCluster, other than proceeding with a deletion already
+// in progress. The kosmos.io/paused annotation takes precedence over
+// Spec.Paused when both are set, since an annotation can be toggled without
+// a spec update.
+func isVirtualClusterPaused(virtualCluster *v1alpha1.VirtualCluster) bool {
+	if paused, ok := virtualCluster.Annotations[constants.PausedAnnotation]; ok {
+		return paused == "true"
+	}
+	return virtualCluster.Spec.Paused
+}
+
+// workersCompletionPhase returns the phase a VirtualCluster settles in once
+// its nodes have joined and become ready: WorkersScaledDown if every
+// PromotePolicy has been scaled down to zero assigned nodes, leaving the
+// control plane running with no workers, otherwise the normal Completed.
+func workersCompletionPhase(virtualCluster *v1alpha1.VirtualCluster) v1alpha1.Phase {
+	if len(virtualCluster.Spec.PromoteResources.NodeInfos) == 0 {
+		return v1alpha1.WorkersScaledDown
+	}
+	return v1alpha1.Completed
+}
+
+// isPhaseStuck reports whether virtualCluster has sat in its current phase
+// longer than constants.StuckPhaseTimeout without a status update, the
+// signature of a controller restart landing between two status writes that
+// would otherwise have carried it out of the phase.
+func isPhaseStuck(virtualCluster *v1alpha1.VirtualCluster, now time.Time) bool {
+	return virtualCluster.Status.UpdateTime != nil && now.Sub(virtualCluster.Status.UpdateTime.Time) > constants.StuckPhaseTimeout
+}
+
+// readinessTimeout returns how long the AllNodeReady phase should wait for
+// virtualCluster's workloads to become ready: virtualCluster's own
+// Spec.ReadinessTimeoutSeconds when set, otherwise
+// constants.WaitAllPodsRunningTimeoutSeconds.
+func readinessTimeout(virtualCluster *v1alpha1.VirtualCluster) time.Duration {
+	if seconds := virtualCluster.Spec.ReadinessTimeoutSeconds; seconds != nil {
+		return time.Duration(*seconds) * time.Second
+	}
+	return constants.WaitAllPodsRunningTimeoutSeconds * time.Second
 }
 
+// recordEvent records a Kubernetes event on virtualCluster if c.EventRecorder
+// is set, so controllers constructed without one (e.g. in unit tests) don't
+// need to stub it out.
+func (c *VirtualClusterInitController) recordEvent(virtualCluster *v1alpha1.VirtualCluster, eventType, reason, messageFmt string, args ...interface{}) {
+	if c.EventRecorder == nil {
+		return
+	}
+	c.EventRecorder.Eventf(virtualCluster, eventType, reason, messageFmt, args...)
+}
+
+// checkPromotePoliciesChanged reports whether assignWorkNodes has anything to
+// do for virtualCluster: a node to claim or release, or a policy that's
+// short of free matching GlobalNodes for its NodeCount. It builds this off
+// BuildAssignmentPlan instead of comparing PromotePolicy.NodeCount against
+// the assigned count directly, so the two never drift - a policy the plan
+// considers unchanged is never reported here as changed, and vice versa.
 func (c *VirtualClusterInitController) checkPromotePoliciesChanged(virtualCluster *v1alpha1.VirtualCluster) (bool, error) {
 	globalNodeList, err := c.KosmosClient.KosmosV1alpha1().GlobalNodes().List(context.TODO(), metav1.ListOptions{})
 	if err != nil {
 		return false, fmt.Errorf("list global nodes: %w", err)
 	}
-	for _, policy := range virtualCluster.Spec.PromotePolicies {
-		globalNodes, err := retrieveGlobalNodesWithLabelSelector(globalNodeList.Items, policy.LabelSelector)
-		if err != nil {
-			return false, fmt.Errorf("retrieve globalnode with labelselector: %w", err)
-		}
-		nodesAssigned, err := retrieveAssignedNodesByPolicy(virtualCluster, globalNodes)
-		if err != nil {
-			return false, errors.Wrapf(err, "Parse assigned nodes by policy %s error", policy.LabelSelector.String())
-		}
-		if policy.NodeCount != int32(len(nodesAssigned)) {
-			klog.V(2).Infof("Promote policy node count changed from %d to %d", len(nodesAssigned), policy.NodeCount)
+
+	plan, err := BuildAssignmentPlan(virtualCluster, globalNodeList.Items)
+	if err != nil {
+		return false, errors.Wrap(err, "build assignment plan")
+	}
+
+	for _, policyPlan := range plan.Policies {
+		if len(policyPlan.Claims) > 0 || len(policyPlan.Releases) > 0 || policyPlan.Shortage > 0 {
+			klog.V(2).Infof("Promote policy node assignment changed for virtualcluster %s: %d to claim, %d to release, shortage %d", virtualCluster.GetName(), len(policyPlan.Claims), len(policyPlan.Releases), policyPlan.Shortage)
 			return true, nil
 		}
 	}
@@ -443,8 +1401,8 @@ func IsLabelsMatchSelector(selector *metav1.LabelSelector, targetLabels labels.S
 }
 
 // nodesChangeCalculate calculate nodes changed when update virtualcluster.
-func (c *VirtualClusterInitController) assignNodesByPolicy(virtualCluster *v1alpha1.VirtualCluster, policy v1alpha1.PromotePolicy, policyMatchedGlobalNodes []v1alpha1.GlobalNode) ([]v1alpha1.NodeInfo, error) {
-	nodesAssigned, err := retrieveAssignedNodesByPolicy(virtualCluster, policyMatchedGlobalNodes)
+func (c *VirtualClusterInitController) assignNodesByPolicy(virtualCluster *v1alpha1.VirtualCluster, policy v1alpha1.PromotePolicy, policyMatchedGlobalNodes []v1alpha1.GlobalNode, allGlobalNodes []v1alpha1.GlobalNode) ([]v1alpha1.NodeInfo, error) {
+	nodesAssigned, err := retrieveAssignedNodesByPolicy(virtualCluster, policy, policyMatchedGlobalNodes)
 	if err != nil {
 		return nil, fmt.Errorf("parse assigned nodes by policy %v error", policy.LabelSelector)
 	}
@@ -456,22 +1414,29 @@ func (c *VirtualClusterInitController) assignNodesByPolicy(virtualCluster *v1alp
 	} else if requestNodesChanged > 0 {
 		// nodes needs to increase
 		klog.V(2).Infof("Try allocate %d nodes for policy %v", requestNodesChanged, policy.LabelSelector)
-		var newAssignNodesIndex []int
-		for i, globalNode := range policyMatchedGlobalNodes {
-			if globalNode.Spec.State == v1alpha1.NodeFreeState {
-				newAssignNodesIndex = append(newAssignNodesIndex, i)
-			}
-			if int32(len(newAssignNodesIndex)) == requestNodesChanged {
-				break
+		candidates, filteredForReadiness := c.filterReadyFreeNodes(policyMatchedGlobalNodes)
+		if virtualCluster.Spec.EnablePreemption {
+			if shortfall := requestNodesChanged - countFreeNodes(candidates); shortfall > 0 {
+				candidates = c.preemptNodesForPolicy(virtualCluster, policy, candidates, shortfall, allGlobalNodes)
 			}
 		}
-		if int32(len(newAssignNodesIndex)) < requestNodesChanged {
-			return nodesAssigned, errors.Errorf("There is not enough work nodes for promotepolicy %v. Desired %d, matched %d", policy.LabelSelector, requestNodesChanged, len(newAssignNodesIndex))
+		strategy := c.NodeSelectionStrategy
+		if strategy == nil {
+			strategy = OrderedNodeSelectionStrategy{}
 		}
-		for _, index := range newAssignNodesIndex {
-			klog.V(2).Infof("Assign node %s for virtualcluster %s policy %v", policyMatchedGlobalNodes[index].Name, virtualCluster.GetName(), policy.LabelSelector)
-			nodesAssigned = append(nodesAssigned, v1alpha1.NodeInfo{
-				NodeName: policyMatchedGlobalNodes[index].Name,
+		newAssignNodes, err := strategy.Select(candidates, requestNodesChanged, policy)
+		if err != nil {
+			msg := fmt.Sprintf("There is not enough work nodes for promotepolicy %v. Desired %d", policy.LabelSelector, requestNodesChanged)
+			if filteredForReadiness > 0 {
+				msg = fmt.Sprintf("%s (%d candidate(s) filtered out as not Ready)", msg, filteredForReadiness)
+			}
+			return nodesAssigned, errors.Wrap(err, msg)
+		}
+		for _, globalNode := range newAssignNodes {
+			klog.V(2).Infof("Assign node %s for virtualcluster %s policy %v", globalNode.Name, virtualCluster.GetName(), policy.LabelSelector)
+			nodesAssigned = append(nodesAssigned, v1alpha1.NodeInfo{
+				NodeName:      globalNode.Name,
+				PromotePolicy: normalizePromotePolicySelector(policy.LabelSelector),
 			})
 		}
 	} else {
@@ -481,17 +1446,348 @@ func (c *VirtualClusterInitController) assignNodesByPolicy(virtualCluster *v1alp
 		if len(nodesAssigned) < decrease {
 			return nil, errors.Errorf("Illegal work nodes decrease operation for promotepolicy %v. Desired %d, matched %d", policy.LabelSelector, decrease, len(nodesAssigned))
 		}
-		nodesAssigned = nodesAssigned[:len(nodesAssigned)-decrease]
+		previouslyAssigned := nodesAssigned
+		nodesAssigned = c.selectNodesToRelease(virtualCluster, nodesAssigned, decrease)
+		c.drainNodesBeforeRelease(virtualCluster, policy, releasedNodeInfos(previouslyAssigned, nodesAssigned))
 		// note: node pool will not be modified here. NodeController will modify it when node delete success
 	}
 	return nodesAssigned, nil
 }
 
-// retrieveAssignedNodesByPolicy retrieve nodes assigned by policy from virtual cluster spec.
-// Note: this function only retrieves nodes that match the policy's label selector.
-func retrieveAssignedNodesByPolicy(virtualCluster *v1alpha1.VirtualCluster, policyMatchedGlobalNodes []v1alpha1.GlobalNode) ([]v1alpha1.NodeInfo, error) {
+// countFreeNodes returns how many of candidates are currently free.
+func countFreeNodes(candidates []v1alpha1.GlobalNode) int32 {
+	var free int32
+	for _, node := range candidates {
+		if node.Spec.State == v1alpha1.NodeFreeState {
+			free++
+		}
+	}
+	return free
+}
+
+// filterReadyFreeNodes drops free candidates whose underlying host Node
+// isn't Ready, when KubeNestOptions.KubeInKubeConfig.RequireNodeReady is
+// set, since a GlobalNode can sit in NodeFreeState before its kubelet is
+// actually reporting healthy. InUse candidates are left untouched -
+// readiness doesn't affect preempting/reclaiming them - and a candidate
+// whose Node object can't be fetched is conservatively treated as not
+// ready. Returns the filtered candidates and how many free nodes were
+// dropped, so callers can surface that count on an insufficient-nodes
+// error.
+func (c *VirtualClusterInitController) filterReadyFreeNodes(candidates []v1alpha1.GlobalNode) ([]v1alpha1.GlobalNode, int) {
+	if c.KubeNestOptions == nil || !c.KubeNestOptions.KubeInKubeConfig.RequireNodeReady {
+		return candidates, 0
+	}
+	var filtered int
+	ready := make([]v1alpha1.GlobalNode, 0, len(candidates))
+	for _, node := range candidates {
+		if node.Spec.State != v1alpha1.NodeFreeState {
+			ready = append(ready, node)
+			continue
+		}
+		rootNode, err := c.RootClientSet.CoreV1().Nodes().Get(context.TODO(), node.Name, metav1.GetOptions{})
+		if err != nil || !util.IsNodeReady(rootNode.Status.Conditions) {
+			filtered++
+			continue
+		}
+		ready = append(ready, node)
+	}
+	return ready, filtered
+}
+
+// preemptNodesForPolicy reclaims up to shortfall InUse nodes among
+// candidates from VirtualClusters with a strictly lower Spec.Priority than
+// virtualCluster's, releasing each one back to the free pool so policy's
+// NodeSelectionStrategy can then pick it like any other free candidate.
+// Equal-or-higher-priority clusters' nodes are never touched. Victims are
+// preempted from lowest-priority first, then by node name, so repeated
+// reconciles with the same inputs preempt the same nodes. Nodes that fail
+// to preempt (e.g. a concurrent update to the victim) are left untouched
+// and simply don't count toward shortfall; candidates is returned with only
+// the successfully preempted entries' Spec.State updated to Free, as a new
+// slice - candidates itself is never mutated. Successfully preempted nodes
+// are also updated in place within allGlobalNodes, the full node list
+// assignWorkNodes read before this reconcile started, so the claiming loop
+// further up the call stack sees them as free rather than still InUse for
+// the victim it just released them from.
+func (c *VirtualClusterInitController) preemptNodesForPolicy(virtualCluster *v1alpha1.VirtualCluster, policy v1alpha1.PromotePolicy, candidates []v1alpha1.GlobalNode, shortfall int32, allGlobalNodes []v1alpha1.GlobalNode) []v1alpha1.GlobalNode {
+	type preemptionCandidate struct {
+		index    int
+		priority int32
+	}
+	victimPriority := map[string]int32{}
+	var ordered []preemptionCandidate
+	for i, node := range candidates {
+		owner := node.Status.VirtualCluster
+		if node.Spec.State != v1alpha1.NodeInUse || owner == "" || owner == virtualCluster.Name {
+			continue
+		}
+		priority, ok := victimPriority[owner]
+		if !ok {
+			victim, err := c.KosmosClient.KosmosV1alpha1().VirtualClusters(virtualCluster.Namespace).Get(context.TODO(), owner, metav1.GetOptions{})
+			if err != nil {
+				klog.Errorf("Failed to get virtualcluster %s to check preemption priority: %v", owner, err)
+				continue
+			}
+			priority = victim.Spec.Priority
+			victimPriority[owner] = priority
+		}
+		if priority >= virtualCluster.Spec.Priority {
+			continue
+		}
+		ordered = append(ordered, preemptionCandidate{index: i, priority: priority})
+	}
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if ordered[i].priority != ordered[j].priority {
+			return ordered[i].priority < ordered[j].priority
+		}
+		return candidates[ordered[i].index].Name < candidates[ordered[j].index].Name
+	})
+
+	result := append([]v1alpha1.GlobalNode{}, candidates...)
+	var reclaimed int32
+	for _, oc := range ordered {
+		if reclaimed >= shortfall {
+			break
+		}
+		node := candidates[oc.index]
+		if err := c.preemptNode(virtualCluster, policy, &node); err != nil {
+			klog.Errorf("Failed to preempt node %s from virtualcluster %s: %v", node.Name, node.Status.VirtualCluster, err)
+			continue
+		}
+		result[oc.index] = node
+		for i := range allGlobalNodes {
+			if allGlobalNodes[i].Name == node.Name {
+				allGlobalNodes[i].Spec.State = node.Spec.State
+				allGlobalNodes[i].Status.VirtualCluster = node.Status.VirtualCluster
+				break
+			}
+		}
+		reclaimed++
+	}
+	return result
+}
+
+// preemptNode reclaims node, an InUse GlobalNode currently owned by a
+// lower-priority VirtualCluster, on behalf of virtualCluster: it drops node
+// from the victim's Spec.PromoteResources.NodeInfos, which triggers the
+// victim's own next reconcile to scale down rather than this reconcile
+// draining it on the victim's behalf, then releases node back to the free
+// pool. Records an event on both the victim and virtualCluster. node is
+// updated in place to reflect the released state.
+func (c *VirtualClusterInitController) preemptNode(virtualCluster *v1alpha1.VirtualCluster, policy v1alpha1.PromotePolicy, node *v1alpha1.GlobalNode) error {
+	victimName := node.Status.VirtualCluster
+	victim, err := c.removeNodeFromVirtualCluster(virtualCluster.Namespace, victimName, node.Name)
+	if err != nil {
+		return fmt.Errorf("remove node %s from virtualcluster %s: %w", node.Name, victimName, err)
+	}
+	if err := c.releaseGlobalNodeUsageStatus(node); err != nil {
+		return fmt.Errorf("release globalnode %s: %w", node.Name, err)
+	}
+	node.Spec.State = v1alpha1.NodeFreeState
+	node.Status.VirtualCluster = ""
+	node.Status.ClaimedAt = nil
+
+	c.recordEvent(victim, corev1.EventTypeWarning, EventReasonNodePreempted,
+		"Node %s was preempted by higher-priority virtualcluster %s for policy %v", node.Name, virtualCluster.Name, policy.LabelSelector)
+	c.recordEvent(virtualCluster, corev1.EventTypeNormal, EventReasonNodePreemption,
+		"Preempted node %s from lower-priority virtualcluster %s for policy %v", node.Name, victimName, policy.LabelSelector)
+	return nil
+}
+
+// removeNodeFromVirtualCluster drops nodeName from victimName's
+// Spec.PromoteResources.NodeInfos, mirroring what an operator shrinking a
+// PromotePolicy's NodeCount would cause: the victim's own assignWorkNodes
+// notices the drop on its next reconcile and drains/releases the node
+// through its normal scale-down path.
+func (c *VirtualClusterInitController) removeNodeFromVirtualCluster(namespace, victimName, nodeName string) (*v1alpha1.VirtualCluster, error) {
+	var victim *v1alpha1.VirtualCluster
+	updateFunc := func() error {
+		current, err := c.KosmosClient.KosmosV1alpha1().VirtualClusters(namespace).Get(context.TODO(), victimName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		updated := current.DeepCopy()
+		nodeInfos := make([]v1alpha1.NodeInfo, 0, len(updated.Spec.PromoteResources.NodeInfos))
+		for _, nodeInfo := range updated.Spec.PromoteResources.NodeInfos {
+			if nodeInfo.NodeName != nodeName {
+				nodeInfos = append(nodeInfos, nodeInfo)
+			}
+		}
+		updated.Spec.PromoteResources.NodeInfos = nodeInfos
+		result, err := c.KosmosClient.KosmosV1alpha1().VirtualClusters(namespace).Update(context.TODO(), updated, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+		victim = result
+		return nil
+	}
+	if err := retry.RetryOnConflict(retry.DefaultRetry, updateFunc); err != nil {
+		return nil, err
+	}
+	return victim, nil
+}
+
+// selectNodesToRelease returns nodesAssigned with the decrease least-utilized
+// nodes removed, so scale-down releases the nodes with the least impact on
+// running workloads. If the tenant cluster's utilization cannot be read, it
+// falls back to releasing the tail of nodesAssigned.
+func (c *VirtualClusterInitController) selectNodesToRelease(virtualCluster *v1alpha1.VirtualCluster, nodesAssigned []v1alpha1.NodeInfo, decrease int) []v1alpha1.NodeInfo {
+	tenantClient, err := util.GenerateKubeclient(virtualCluster)
+	if err != nil {
+		klog.Warningf("generate tenant kubeclient for virtualcluster %s failed, release nodes by order instead: %v", virtualCluster.Name, err)
+		return nodesAssigned[:len(nodesAssigned)-decrease]
+	}
+	return selectLeastUtilizedNodes(tenantClient, nodesAssigned, decrease)
+}
+
+// selectLeastUtilizedNodes ranks nodesAssigned by current utilization (pod
+// count and requested resources, read from the tenant cluster) and returns
+// nodesAssigned with the decrease lowest-utilized nodes removed.
+func selectLeastUtilizedNodes(tenantClient kubernetes.Interface, nodesAssigned []v1alpha1.NodeInfo, decrease int) []v1alpha1.NodeInfo {
+	utilizationByNode, err := calculateNodeUtilization(tenantClient)
+	if err != nil {
+		klog.Warningf("calculate tenant cluster node utilization failed, release nodes by order instead: %v", err)
+		return nodesAssigned[:len(nodesAssigned)-decrease]
+	}
+
+	type nodeUtilization struct {
+		nodeInfo    v1alpha1.NodeInfo
+		utilization int64
+	}
+	utilizations := make([]nodeUtilization, 0, len(nodesAssigned))
+	for _, nodeInfo := range nodesAssigned {
+		utilizations = append(utilizations, nodeUtilization{nodeInfo: nodeInfo, utilization: utilizationByNode[nodeInfo.NodeName]})
+	}
+
+	sort.SliceStable(utilizations, func(i, j int) bool {
+		return utilizations[i].utilization < utilizations[j].utilization
+	})
+
+	release := make(map[string]bool, decrease)
+	for _, u := range utilizations[:decrease] {
+		release[u.nodeInfo.NodeName] = true
+	}
+
+	remaining := make([]v1alpha1.NodeInfo, 0, len(nodesAssigned)-decrease)
+	for _, nodeInfo := range nodesAssigned {
+		if !release[nodeInfo.NodeName] {
+			remaining = append(remaining, nodeInfo)
+		}
+	}
+	return remaining
+}
+
+// releasedNodeInfos returns the nodes present in before but not in after, so
+// callers that trim nodesAssigned down to a smaller slice can recover which
+// nodes were actually dropped.
+func releasedNodeInfos(before, after []v1alpha1.NodeInfo) []v1alpha1.NodeInfo {
+	remaining := make(map[string]bool, len(after))
+	for _, nodeInfo := range after {
+		remaining[nodeInfo.NodeName] = true
+	}
+	released := make([]v1alpha1.NodeInfo, 0, len(before)-len(after))
+	for _, nodeInfo := range before {
+		if !remaining[nodeInfo.NodeName] {
+			released = append(released, nodeInfo)
+		}
+	}
+	return released
+}
+
+// drainNodesBeforeRelease cordons and evicts pods from released nodes in the
+// tenant cluster before they're handed off to NodeController for removal, so
+// scale-down doesn't abruptly kill workloads still running on them.
+func (c *VirtualClusterInitController) drainNodesBeforeRelease(virtualCluster *v1alpha1.VirtualCluster, policy v1alpha1.PromotePolicy, released []v1alpha1.NodeInfo) {
+	if len(released) == 0 {
+		return
+	}
+
+	tenantClient, err := util.GenerateKubeclient(virtualCluster)
+	if err != nil {
+		klog.Warningf("generate tenant kubeclient for virtualcluster %s failed, skip graceful drain before release: %v", virtualCluster.Name, err)
+		return
+	}
+	drainReleasedNodes(virtualCluster, tenantClient, policy, released)
+}
+
+// drainReleasedNodes does the actual cordon-and-evict work against
+// tenantClient. A node that doesn't finish draining within
+// policy.DrainGracePeriodSeconds (env.GetDrainWaitSeconds() when unset) is
+// still released -- NodeController force-removes it regardless -- but the
+// failure is recorded in virtualCluster.Status.Reason instead of being
+// silently dropped.
+func drainReleasedNodes(virtualCluster *v1alpha1.VirtualCluster, tenantClient kubernetes.Interface, policy v1alpha1.PromotePolicy, released []v1alpha1.NodeInfo) {
+	drainWaitSeconds := env.GetDrainWaitSeconds()
+	if policy.DrainGracePeriodSeconds != nil {
+		drainWaitSeconds = int(*policy.DrainGracePeriodSeconds)
+	}
+
+	var failures []string
+	for _, nodeInfo := range released {
+		node, err := tenantClient.CoreV1().Nodes().Get(context.TODO(), nodeInfo.NodeName, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			failures = append(failures, fmt.Sprintf("%s: get node: %v", nodeInfo.NodeName, err))
+			continue
+		}
+		if err := util.DrainNode(context.TODO(), nodeInfo.NodeName, tenantClient, node, drainWaitSeconds, false); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", nodeInfo.NodeName, err))
+		}
+	}
+
+	if len(failures) > 0 {
+		virtualCluster.Status.Reason = fmt.Sprintf("failed to gracefully drain %d node(s) before release: %s", len(failures), strings.Join(failures, "; "))
+		klog.Warningf("virtualcluster %s: %s", virtualCluster.Name, virtualCluster.Status.Reason)
+	}
+}
+
+// calculateNodeUtilization lists the tenant cluster's pods and scores each
+// node's current utilization as its running pod count plus requested CPU
+// (millicores) and memory (MiB), used to rank nodes for release on scale-down.
+func calculateNodeUtilization(tenantClient kubernetes.Interface) (map[string]int64, error) {
+	pods, err := tenantClient.CoreV1().Pods(corev1.NamespaceAll).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	utilization := make(map[string]int64, len(pods.Items))
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		utilization[pod.Spec.NodeName]++
+		for _, container := range pod.Spec.Containers {
+			if cpu, ok := container.Resources.Requests[corev1.ResourceCPU]; ok {
+				utilization[pod.Spec.NodeName] += cpu.MilliValue()
+			}
+			if memory, ok := container.Resources.Requests[corev1.ResourceMemory]; ok {
+				utilization[pod.Spec.NodeName] += memory.Value() / (1024 * 1024)
+			}
+		}
+	}
+	return utilization, nil
+}
+
+// retrieveAssignedNodesByPolicy retrieves the nodes already assigned to
+// policy. A NodeInfo that recorded which policy claimed it (NodeInfo.
+// PromotePolicy) is matched back by that ownership, not by re-evaluating the
+// GlobalNode's current labels - otherwise relabeling an in-use node could
+// silently drop it out of its policy's count and trigger an unwanted
+// scale-up. NodeInfos persisted before PromotePolicy existed have it nil and
+// fall back to matching policyMatchedGlobalNodes by live labels, same as
+// before.
+func retrieveAssignedNodesByPolicy(virtualCluster *v1alpha1.VirtualCluster, policy v1alpha1.PromotePolicy, policyMatchedGlobalNodes []v1alpha1.GlobalNode) ([]v1alpha1.NodeInfo, error) {
 	var nodesAssignedMatchedPolicy []v1alpha1.NodeInfo
 	for _, nodeInfo := range virtualCluster.Spec.PromoteResources.NodeInfos {
+		if nodeInfo.PromotePolicy != nil {
+			if promotePolicySelectorsEqual(nodeInfo.PromotePolicy, policy.LabelSelector) {
+				nodesAssignedMatchedPolicy = append(nodesAssignedMatchedPolicy, nodeInfo)
+			}
+			continue
+		}
 		if _, ok := util.FindGlobalNode(nodeInfo.NodeName, policyMatchedGlobalNodes); ok {
 			nodesAssignedMatchedPolicy = append(nodesAssignedMatchedPolicy, nodeInfo)
 		}
@@ -499,6 +1795,24 @@ func retrieveAssignedNodesByPolicy(virtualCluster *v1alpha1.VirtualCluster, poli
 	return nodesAssignedMatchedPolicy, nil
 }
 
+// normalizePromotePolicySelector returns selector, or an empty non-nil
+// LabelSelector if selector is nil, so a PromotePolicy with no LabelSelector
+// (matching every node) can still be recorded on a claimed NodeInfo and told
+// apart from a NodeInfo that never recorded ownership at all.
+func normalizePromotePolicySelector(selector *metav1.LabelSelector) *metav1.LabelSelector {
+	if selector == nil {
+		return &metav1.LabelSelector{}
+	}
+	return selector
+}
+
+// promotePolicySelectorsEqual reports whether a claimed NodeInfo's recorded
+// PromotePolicy selector is the same one as policySelector, after
+// normalizing both sides.
+func promotePolicySelectorsEqual(claimed, policySelector *metav1.LabelSelector) bool {
+	return reflect.DeepEqual(*normalizePromotePolicySelector(claimed), *normalizePromotePolicySelector(policySelector))
+}
+
 func matchesWithLabelSelector(metaLabels labels.Set, labelSelector *metav1.LabelSelector) (bool, error) {
 	if labelSelector == nil {
 		return true, nil
@@ -527,6 +1841,247 @@ func retrieveGlobalNodesWithLabelSelector(nodes []v1alpha1.GlobalNode, labelSele
 	return matchedNodes, nil
 }
 
+// recordCreationDuration sets Status.CreationDuration and emits the
+// VirtualClusterCreationDuration metric when CreationStartTime has been set,
+// i.e. the VirtualCluster has gone through Preparing since it was last
+// (re)created. If CreationStartTime is nil, it is a no-op so a stale
+// CreationDuration from a previous incarnation of the cluster is left alone
+// until the cluster re-enters Preparing and gets a fresh start time.
+func recordCreationDuration(vc *v1alpha1.VirtualCluster, now time.Time) {
+	if vc.Status.CreationStartTime == nil {
+		return
+	}
+	elapsed := now.Sub(vc.Status.CreationStartTime.Time)
+	vc.Status.CreationDuration = elapsed.String()
+	metrics.VirtualClusterCreationDuration.Observe(elapsed.Seconds())
+}
+
+// recordPhaseTransition adjusts VirtualClusterPhaseCount for a VirtualCluster
+// moving from oldPhase to newPhase, so the gauge reflects how many clusters
+// currently sit in each phase rather than a running total. oldPhase=="" (a
+// newly-created VirtualCluster) has no prior count to decrement.
+func recordPhaseTransition(oldPhase, newPhase v1alpha1.Phase) {
+	if oldPhase == newPhase {
+		return
+	}
+	if oldPhase != "" {
+		metrics.VirtualClusterPhaseCount.WithLabelValues(string(oldPhase)).Dec()
+	}
+	metrics.VirtualClusterPhaseCount.WithLabelValues(string(newPhase)).Inc()
+}
+
+// recordReconcileFailure tracks how long vc has been continuously stuck in
+// the Pending phase and raises DegradedConditionType once that exceeds
+// reconcileFailureThreshold, so operators get one alertable signal instead of
+// having to mine logs for sustained reconcile failures.
+func (c *VirtualClusterInitController) recordReconcileFailure(vc *v1alpha1.VirtualCluster, now time.Time) {
+	if vc.Status.FailureSince == nil {
+		failureSince := metav1.NewTime(now)
+		vc.Status.FailureSince = &failureSince
+	}
+	vc.Status.FailureCount++
+	lastFailureTime := metav1.NewTime(now)
+	vc.Status.LastFailureTime = &lastFailureTime
+
+	if now.Sub(vc.Status.FailureSince.Time) < c.reconcileFailureThreshold() {
+		return
+	}
+
+	meta.SetStatusCondition(&vc.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.DegradedConditionType,
+		Status:  metav1.ConditionTrue,
+		Reason:  "ReconcileFailing",
+		Message: fmt.Sprintf("virtualcluster has been Pending since %s: %s", vc.Status.FailureSince.Time.Format(time.RFC3339), vc.Status.Reason),
+	})
+}
+
+// recordReconcileSuccess clears any in-progress failure streak and resolves
+// DegradedConditionType once a VirtualCluster reconciles successfully again.
+func (c *VirtualClusterInitController) recordReconcileSuccess(vc *v1alpha1.VirtualCluster) {
+	vc.Status.FailureSince = nil
+	vc.Status.FailureCount = 0
+	vc.Status.LastFailureTime = nil
+	if meta.FindStatusCondition(vc.Status.Conditions, v1alpha1.DegradedConditionType) == nil {
+		return
+	}
+	meta.SetStatusCondition(&vc.Status.Conditions, metav1.Condition{
+		Type:    v1alpha1.DegradedConditionType,
+		Status:  metav1.ConditionFalse,
+		Reason:  "ReconcileSucceeded",
+		Message: "virtualcluster reconciled successfully",
+	})
+}
+
+// reconcileFailureThreshold returns how long a VirtualCluster must stay
+// continuously Pending before recordReconcileFailure raises
+// DegradedConditionType, taken from
+// KubeInKubeConfig.ReconcileFailureThreshold or
+// constants.VirtualClusterDegradedThreshold when unset or invalid.
+func (c *VirtualClusterInitController) reconcileFailureThreshold() time.Duration {
+	if c.KubeNestOptions == nil || c.KubeNestOptions.KubeInKubeConfig.ReconcileFailureThreshold == "" {
+		return constants.VirtualClusterDegradedThreshold
+	}
+	threshold, err := time.ParseDuration(c.KubeNestOptions.KubeInKubeConfig.ReconcileFailureThreshold)
+	if err != nil {
+		klog.Warningf("invalid ReconcileFailureThreshold %q, falling back to default: %v", c.KubeNestOptions.KubeInKubeConfig.ReconcileFailureThreshold, err)
+		return constants.VirtualClusterDegradedThreshold
+	}
+	return threshold
+}
+
+// reconcileBackoff returns how long to wait before the next reconcile of a
+// VirtualCluster that has failed failureCount times in a row:
+// ReconcileBackoffBaseDelay * 2^failureCount, capped at
+// ReconcileBackoffMaxDelay. failureCount <= 0 returns ReconcileBackoffBaseDelay.
+func reconcileBackoff(failureCount int32) time.Duration {
+	delay := ReconcileBackoffBaseDelay
+	for i := int32(0); i < failureCount; i++ {
+		delay *= 2
+		if delay >= ReconcileBackoffMaxDelay {
+			return ReconcileBackoffMaxDelay
+		}
+	}
+	return delay
+}
+
+// jitteredRequeueTime returns RequeueTime randomized to within ±50% (i.e.
+// uniformly in [RequeueTime/2, RequeueTime*3/2)), so a fleet of
+// VirtualClusters that all hit the same requeue-triggering condition at once
+// don't all re-reconcile in lockstep and spike API server load. The average
+// delay across many calls is still RequeueTime.
+func jitteredRequeueTime() time.Duration {
+	return wait.Jitter(RequeueTime/2, 2.0)
+}
+
+// filterGlobalNodesByTolerations excludes any candidate node carrying a taint
+// that is not tolerated by tolerations, so a PromotePolicy never lands a
+// VirtualCluster on a node reserved for something else even if its labels
+// would otherwise match.
+func filterGlobalNodesByTolerations(nodes []v1alpha1.GlobalNode, tolerations []corev1.Toleration) []v1alpha1.GlobalNode {
+	filtered := make([]v1alpha1.GlobalNode, 0, len(nodes))
+	for _, node := range nodes {
+		tolerated := true
+		for i := range node.Spec.Taints {
+			if !helpers.TolerationsTolerateTaint(tolerations, &node.Spec.Taints[i]) {
+				tolerated = false
+				break
+			}
+		}
+		if tolerated {
+			filtered = append(filtered, node)
+		}
+	}
+	return filtered
+}
+
+// releaseDriftedNodes detects nodes that were assigned to virtualCluster but
+// fell out of allNodeInfos because their labels drifted out from under every
+// PromotePolicy's LabelSelector, logs the drift, and releases them back to
+// the free pool. A node that dropped out of allNodeInfos while still
+// matching some policy's selector is an intentional scale-down instead; that
+// case is left alone, since the NodeController releases it once the node is
+// actually removed from the tenant cluster.
+//
+// A NodeInfo that records its owning PromotePolicy (see
+// retrieveAssignedNodesByPolicy) never drops out of allNodeInfos from label
+// drift alone, so in practice this now only fires for NodeInfos persisted
+// before that field existed.
+func (c *VirtualClusterInitController) releaseDriftedNodes(virtualCluster *v1alpha1.VirtualCluster, previouslyAssigned, newlyAssigned []v1alpha1.NodeInfo, globalNodes []v1alpha1.GlobalNode) {
+	stillAssigned := make(map[string]struct{}, len(newlyAssigned))
+	for _, nodeInfo := range newlyAssigned {
+		stillAssigned[nodeInfo.NodeName] = struct{}{}
+	}
+
+	for _, nodeInfo := range previouslyAssigned {
+		if _, ok := stillAssigned[nodeInfo.NodeName]; ok {
+			continue
+		}
+		globalNode, ok := util.FindGlobalNode(nodeInfo.NodeName, globalNodes)
+		if !ok {
+			continue
+		}
+		if c.matchesAnyPromotePolicy(virtualCluster, *globalNode) {
+			continue
+		}
+		klog.Warningf("Globalnode %s assigned to virtualcluster %s no longer matches any promote policy, its labels likely drifted; releasing it back to the free pool", globalNode.Name, virtualCluster.Name)
+		if err := c.releaseGlobalNodeUsageStatus(globalNode); err != nil {
+			klog.Errorf("failed to release drifted globalnode %s: %v", globalNode.Name, err)
+		}
+	}
+}
+
+// matchesAnyPromotePolicy reports whether globalNode currently matches the
+// LabelSelector and Tolerations of at least one of virtualCluster's
+// PromotePolicies.
+func (c *VirtualClusterInitController) matchesAnyPromotePolicy(virtualCluster *v1alpha1.VirtualCluster, globalNode v1alpha1.GlobalNode) bool {
+	for _, policy := range virtualCluster.Spec.PromotePolicies {
+		matched, err := matchesWithLabelSelector(globalNode.Spec.Labels, policy.LabelSelector)
+		if err != nil || !matched {
+			continue
+		}
+		if len(filterGlobalNodesByTolerations([]v1alpha1.GlobalNode{globalNode}, policy.Tolerations)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// releaseGlobalNodeUsageStatus reverts a globalnode claimed by
+// setGlobalNodeUsageStatus back to the free pool, clearing the virtualcluster
+// that had claimed it.
+func (c *VirtualClusterInitController) releaseGlobalNodeUsageStatus(node *v1alpha1.GlobalNode) error {
+	updateSpecFunc := func() error {
+		current, err := c.KosmosClient.KosmosV1alpha1().GlobalNodes().Get(context.TODO(), node.Name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return fmt.Errorf("failed to get globalNode %s: %v", node.Name, err)
+		}
+
+		updated := current.DeepCopy()
+		updated.Spec.State = v1alpha1.NodeFreeState
+		_, err = c.KosmosClient.KosmosV1alpha1().GlobalNodes().Update(context.TODO(), updated, metav1.UpdateOptions{})
+		if err != nil {
+			if apierrors.IsConflict(err) {
+				return err
+			}
+			klog.Errorf("failed to update globalNode spec for %s: %v", updated.Name, err)
+			return err
+		}
+		return nil
+	}
+
+	if err := retry.RetryOnConflict(retry.DefaultRetry, updateSpecFunc); err != nil {
+		return err
+	}
+
+	updateStatusFunc := func() error {
+		current, err := c.KosmosClient.KosmosV1alpha1().GlobalNodes().Get(context.TODO(), node.Name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return fmt.Errorf("failed to get globalNode %s: %v", node.Name, err)
+		}
+
+		updated := current.DeepCopy()
+		updated.Status.VirtualCluster = ""
+		updated.Status.ClaimedAt = nil
+		_, err = c.KosmosClient.KosmosV1alpha1().GlobalNodes().UpdateStatus(context.TODO(), updated, metav1.UpdateOptions{})
+		if err != nil {
+			if apierrors.IsConflict(err) {
+				return err
+			}
+			klog.Errorf("failed to update globalNode status for %s: %v", updated.Name, err)
+			return err
+		}
+		return nil
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, updateStatusFunc)
+}
+
 func (c *VirtualClusterInitController) setGlobalNodeUsageStatus(virtualCluster *v1alpha1.VirtualCluster, node *v1alpha1.GlobalNode) error {
 	updateSpecFunc := func() error {
 		current, err := c.KosmosClient.KosmosV1alpha1().GlobalNodes().Get(context.TODO(), node.Name, metav1.GetOptions{})
@@ -569,6 +2124,8 @@ func (c *VirtualClusterInitController) setGlobalNodeUsageStatus(virtualCluster *
 
 		updated := current.DeepCopy()
 		updated.Status.VirtualCluster = virtualCluster.Name
+		now := metav1.Now()
+		updated.Status.ClaimedAt = &now
 		_, err = c.KosmosClient.KosmosV1alpha1().GlobalNodes().UpdateStatus(context.TODO(), updated, metav1.UpdateOptions{})
 		if err != nil {
 			if apierrors.IsConflict(err) {
@@ -584,78 +2141,338 @@ func (c *VirtualClusterInitController) setGlobalNodeUsageStatus(virtualCluster *
 	return retry.RetryOnConflict(retry.DefaultRetry, updateStatusFunc)
 }
 
-func (c *VirtualClusterInitController) ensureAllPodsRunning(virtualCluster *v1alpha1.VirtualCluster, timeout time.Duration) error {
-	secret, err := c.RootClientSet.CoreV1().Secrets(virtualCluster.GetNamespace()).Get(context.TODO(),
-		fmt.Sprintf("%s-%s", virtualCluster.GetName(), constants.AdminConfig), metav1.GetOptions{})
+// virtualClusterClient returns a clientset for virtualCluster's tenant
+// control plane, decoding its admin kubeconfig from Spec.Kubeconfig and
+// falling back to the admin kubeconfig Secret when Spec.Kubeconfig hasn't
+// been populated yet (e.g. mid-createVirtualCluster). The built clientset is
+// cached against virtualCluster's UID+ResourceVersion, so repeated callers
+// within the same reconcile -- or across reconciles where the kubeconfig
+// hasn't rotated -- don't each re-fetch the Secret and rebuild a rest.Config.
+func (c *VirtualClusterInitController) virtualClusterClient(virtualCluster *v1alpha1.VirtualCluster) (kubernetes.Interface, error) {
+	c.clientCacheLock.Lock()
+	defer c.clientCacheLock.Unlock()
+
+	if cached, ok := c.virtualClusterClients[virtualCluster.UID]; ok && cached.resourceVersion == virtualCluster.ResourceVersion {
+		return cached.client, nil
+	}
+
+	kubeconfig, err := c.decodeVirtualClusterKubeconfig(virtualCluster)
 	if err != nil {
-		return errors.Wrap(err, "Get virtualcluster kubeconfig secret error")
+		return nil, err
 	}
-	config, err := clientcmd.RESTConfigFromKubeConfig(secret.Data[constants.KubeConfig])
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.virtualClusterClients == nil {
+		c.virtualClusterClients = make(map[types.UID]cachedVirtualClusterClient)
+	}
+	c.virtualClusterClients[virtualCluster.UID] = cachedVirtualClusterClient{
+		resourceVersion: virtualCluster.ResourceVersion,
+		client:          clientset,
+	}
+	return clientset, nil
+}
+
+// decodeVirtualClusterKubeconfig returns virtualCluster's admin kubeconfig,
+// preferring the base64-encoded copy in Spec.Kubeconfig and falling back to
+// the admin kubeconfig Secret when Spec.Kubeconfig hasn't been populated yet.
+func (c *VirtualClusterInitController) decodeVirtualClusterKubeconfig(virtualCluster *v1alpha1.VirtualCluster) ([]byte, error) {
+	if virtualCluster.Spec.Kubeconfig != "" {
+		kubeconfig, err := base64.StdEncoding.DecodeString(virtualCluster.Spec.Kubeconfig)
+		if err != nil {
+			return nil, errors.Wrap(err, "Decode virtualcluster kubeconfig error")
+		}
+		return kubeconfig, nil
+	}
+
+	secret, err := c.RootClientSet.CoreV1().Secrets(virtualCluster.GetNamespace()).Get(context.TODO(),
+		util.GetAdminConfigSecretName(virtualCluster.GetName()), metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "Get virtualcluster kubeconfig secret error")
+	}
+	return secret.Data[constants.KubeConfig], nil
+}
+
+// refreshAPIServerEndpoint checks the apiserver Service's current NodePort
+// against the one recorded in virtualCluster.Status and, if it has drifted
+// (Service recreated, port reassigned), rewrites the admin kubeconfig
+// Secrets to the new endpoint and updates the stored status port. It returns
+// whether anything changed, so the caller knows whether updatedCluster needs
+// persisting. A non-NodePort apiserver (e.g. LoadBalancer/ClusterIP) is a
+// no-op, since only the NodePort endpoint is baked into the kubeconfigs.
+func (c *VirtualClusterInitController) refreshAPIServerEndpoint(virtualCluster *v1alpha1.VirtualCluster) (bool, error) {
+	if virtualCluster.Spec.KubeInKubeConfig == nil || virtualCluster.Spec.KubeInKubeConfig.APIServerServiceType != v1alpha1.NodePort {
+		return false, nil
+	}
+
+	service, err := c.RootClientSet.CoreV1().Services(virtualCluster.GetNamespace()).Get(context.TODO(), util.GetAPIServerName(virtualCluster.GetName()), metav1.GetOptions{})
+	if err != nil {
+		return false, errors.Wrap(err, "Get apiserver service error")
+	}
+	var nodePort int32
+	for _, port := range service.Spec.Ports {
+		if port.Name == constants.APIServerSVCPortName {
+			nodePort = port.NodePort
+			break
+		}
+	}
+	if nodePort == 0 || virtualCluster.Status.PortMap[constants.APIServerPortKey] == nodePort {
+		return false, nil
+	}
+
+	klog.Infof("Apiserver service NodePort for virtualcluster %s/%s changed from %d to %d, refreshing stored endpoint",
+		virtualCluster.GetNamespace(), virtualCluster.GetName(), virtualCluster.Status.PortMap[constants.APIServerPortKey], nodePort)
+	if err := c.rewriteKubeconfigEndpoints(virtualCluster, nodePort); err != nil {
+		return false, err
+	}
+	if virtualCluster.Status.PortMap == nil {
+		virtualCluster.Status.PortMap = make(map[string]int32)
+	}
+	virtualCluster.Status.PortMap[constants.APIServerPortKey] = nodePort
+	return true, nil
+}
+
+// rewriteKubeconfigEndpoints rewrites the NodePort-based admin kubeconfig
+// Secrets (the controlplane-address one, plus the external-hostname one when
+// configured) to point at newNodePort. The ClusterIP-based kubeconfig Secret
+// is left untouched, since a NodePort reassignment doesn't affect it.
+func (c *VirtualClusterInitController) rewriteKubeconfigEndpoints(virtualCluster *v1alpha1.VirtualCluster, newNodePort int32) error {
+	secretNames := []string{util.GetAdminConfigSecretName(virtualCluster.GetName())}
+	if virtualCluster.Spec.ExternalHostName != "" {
+		secretNames = append(secretNames, util.GetAdminConfigExternalSecretName(virtualCluster.GetName()))
+	}
+	for _, secretName := range secretNames {
+		if err := c.rewriteKubeconfigSecretPort(virtualCluster.GetNamespace(), secretName, newNodePort); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rewriteKubeconfigSecretPort updates the port of every cluster entry in the
+// kubeconfig stored at secretName, preserving its host and all credentials.
+// A missing secret is tolerated, since not every sink type writes one (see
+// KubeconfigSink).
+func (c *VirtualClusterInitController) rewriteKubeconfigSecretPort(namespace, secretName string, newNodePort int32) error {
+	secret, err := c.RootClientSet.CoreV1().Secrets(namespace).Get(context.TODO(), secretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "Get kubeconfig secret %s error", secretName)
+	}
+
+	config, err := clientcmd.Load(secret.Data[constants.KubeConfig])
+	if err != nil {
+		return errors.Wrapf(err, "Parse kubeconfig secret %s error", secretName)
+	}
+	for _, cluster := range config.Clusters {
+		serverURL, err := url.Parse(cluster.Server)
+		if err != nil {
+			return errors.Wrapf(err, "Parse kubeconfig secret %s server URL error", secretName)
+		}
+		host, _, err := net.SplitHostPort(serverURL.Host)
+		if err != nil {
+			return errors.Wrapf(err, "Split kubeconfig secret %s server host/port error", secretName)
+		}
+		serverURL.Host = utils.GenerateAddrStr(host, fmt.Sprintf("%d", newNodePort))
+		cluster.Server = serverURL.String()
+	}
+	updatedConfig, err := clientcmd.Write(*config)
+	if err != nil {
+		return errors.Wrapf(err, "Marshal kubeconfig secret %s error", secretName)
+	}
+
+	updated := secret.DeepCopy()
+	updated.Data[constants.KubeConfig] = updatedConfig
+	_, err = c.RootClientSet.CoreV1().Secrets(namespace).Update(context.TODO(), updated, metav1.UpdateOptions{})
+	return err
+}
+
+func (c *VirtualClusterInitController) ensureAllPodsRunning(ctx context.Context, virtualCluster *v1alpha1.VirtualCluster, timeout time.Duration) error {
+	clientset, err := c.virtualClusterClient(virtualCluster)
 	if err != nil {
 		return err
 	}
 
-	namespaceList, err := clientset.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+	namespaceList, err := clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return errors.Wrap(err, "List namespaces error")
 	}
-	endTime := time.Now().Second() + int(timeout.Seconds())
-	for _, namespace := range namespaceList.Items {
-		startTime := time.Now().Second()
-		if startTime > endTime {
-			return errors.New("Timeout waiting for all pods running")
+	skipNamespaces, skipWorkloads := parseReadinessExclusions(virtualCluster)
+	return pollNamespacesReady(ctx, clientset, virtualCluster.Name, namespaceList.Items, skipNamespaces, skipWorkloads, timeout)
+}
+
+// pollNamespacesReady polls every namespace in namespaces (other than those
+// skipNamespaces excludes) concurrently, bounded by
+// constants.ReadinessNamespaceConcurrency, so a single slow namespace
+// doesn't serialize the rest. It returns the first error encountered,
+// cancelling the other namespaces' polls once one fails.
+func pollNamespacesReady(ctx context.Context, clientset kubernetes.Interface, virtualClusterName string, namespaces []corev1.Namespace, skipNamespaces, skipWorkloads map[string]bool, timeout time.Duration) error {
+	logger := log.FromContext(ctx)
+	startTime := time.Now()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(constants.ReadinessNamespaceConcurrency)
+
+	for i := range namespaces {
+		namespaceName := namespaces[i].Name
+		if skipNamespaces[namespaceName] {
+			logger.V(2).Info("Skip readiness check for namespace, excluded by annotation", "namespace", namespaceName, "annotation", constants.ReadinessSkipNamespacesAnnotation)
+			continue
+		}
+		group.Go(func() error {
+			return waitForNamespacePodsReady(groupCtx, clientset, virtualClusterName, namespaceName, skipWorkloads, startTime, timeout)
+		})
+	}
+	return group.Wait()
+}
+
+// waitForNamespacePodsReady polls namespaceName's Deployments, StatefulSets
+// and DaemonSets (other than those skipWorkloads excludes) until every one
+// of them is fully available, or until timeout has elapsed since start.
+// ensureAllPodsRunning runs this concurrently across namespaces, so it must
+// not mutate any state shared with other namespaces' calls.
+func waitForNamespacePodsReady(ctx context.Context, clientset kubernetes.Interface, virtualClusterName, namespaceName string, skipWorkloads map[string]bool, start time.Time, timeout time.Duration) error {
+	logger := log.FromContext(ctx).WithValues("namespace", namespaceName)
+	remaining, ok := remainingTimeout(time.Now(), start, timeout)
+	if !ok {
+		return fmt.Errorf("timed out waiting for all pods running in namespace %s", namespaceName)
+	}
+	logger.V(2).Info("Check if all pods ready in namespace")
+	var lastNotReady string
+	err := wait.PollWithContext(ctx, 5*time.Second, remaining, func(ctx context.Context) (done bool, err error) {
+		logger.V(2).Info("Check if virtualcluster all deployments ready in namespace", "virtualcluster", virtualClusterName)
+		deployList, err := clientset.AppsV1().Deployments(namespaceName).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				logger.V(2).Info("Namespace disappeared mid-poll, skipping it")
+				return true, nil
+			}
+			return false, errors.Wrapf(err, "Get deployment list in namespace %s error", namespaceName)
 		}
-		klog.V(2).Infof("Check if all pods ready in namespace %s", namespace.Name)
-		err := wait.PollWithContext(context.TODO(), 5*time.Second, time.Duration(endTime-startTime)*time.Second, func(ctx context.Context) (done bool, err error) {
-			klog.V(2).Infof("Check if virtualcluster %s all deployments ready in namespace %s", virtualCluster.Name, namespace.Name)
-			deployList, err := clientset.AppsV1().Deployments(namespace.Name).List(ctx, metav1.ListOptions{})
-			if err != nil {
-				return false, errors.Wrapf(err, "Get deployment list in namespace %s error", namespace.Name)
+		for _, deploy := range deployList.Items {
+			if skipWorkloads[workloadExclusionKey("Deployment", namespaceName, deploy.Name)] {
+				continue
 			}
-			for _, deploy := range deployList.Items {
-				if deploy.Status.AvailableReplicas != deploy.Status.Replicas {
-					klog.V(2).Infof("Deployment %s/%s is not ready yet. Available replicas: %d, Desired: %d. Waiting...", deploy.Name, namespace.Name, deploy.Status.AvailableReplicas, deploy.Status.Replicas)
-					return false, nil
-				}
+			if deploy.Status.AvailableReplicas != deploy.Status.Replicas {
+				lastNotReady = fmt.Sprintf("deployment %s/%s not ready (%d/%d replicas available)", namespaceName, deploy.Name, deploy.Status.AvailableReplicas, deploy.Status.Replicas)
+				logger.V(2).Info("Deployment is not ready yet, waiting", "deployment", deploy.Name, "availableReplicas", deploy.Status.AvailableReplicas, "desiredReplicas", deploy.Status.Replicas)
+				return false, nil
 			}
+		}
 
-			klog.V(2).Infof("Check if virtualcluster %s all statefulset ready in namespace %s", virtualCluster.Name, namespace.Name)
-			stsList, err := clientset.AppsV1().StatefulSets(namespace.Name).List(ctx, metav1.ListOptions{})
-			if err != nil {
-				return false, errors.Wrapf(err, "Get statefulset list in namespace %s error", namespace.Name)
+		logger.V(2).Info("Check if virtualcluster all statefulset ready in namespace", "virtualcluster", virtualClusterName)
+		stsList, err := clientset.AppsV1().StatefulSets(namespaceName).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				logger.V(2).Info("Namespace disappeared mid-poll, skipping it")
+				return true, nil
 			}
-			for _, sts := range stsList.Items {
-				if sts.Status.AvailableReplicas != sts.Status.Replicas {
-					klog.V(2).Infof("Statefulset %s/%s is not ready yet. Available replicas: %d, Desired: %d. Waiting...", sts.Name, namespace.Name, sts.Status.AvailableReplicas, sts.Status.Replicas)
-					return false, nil
-				}
+			return false, errors.Wrapf(err, "Get statefulset list in namespace %s error", namespaceName)
+		}
+		for _, sts := range stsList.Items {
+			if skipWorkloads[workloadExclusionKey("StatefulSet", namespaceName, sts.Name)] {
+				continue
+			}
+			if sts.Status.AvailableReplicas != sts.Status.Replicas {
+				lastNotReady = fmt.Sprintf("statefulset %s/%s not ready (%d/%d replicas available)", namespaceName, sts.Name, sts.Status.AvailableReplicas, sts.Status.Replicas)
+				logger.V(2).Info("Statefulset is not ready yet, waiting", "statefulset", sts.Name, "availableReplicas", sts.Status.AvailableReplicas, "desiredReplicas", sts.Status.Replicas)
+				return false, nil
 			}
+		}
 
-			klog.V(2).Infof("Check if virtualcluster %s all daemonset ready in namespace %s", virtualCluster.Name, namespace.Name)
-			damonsetList, err := clientset.AppsV1().DaemonSets(namespace.Name).List(ctx, metav1.ListOptions{})
-			if err != nil {
-				return false, errors.Wrapf(err, "Get daemonset list in namespace %s error", namespace.Name)
+		logger.V(2).Info("Check if virtualcluster all daemonset ready in namespace", "virtualcluster", virtualClusterName)
+		damonsetList, err := clientset.AppsV1().DaemonSets(namespaceName).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				logger.V(2).Info("Namespace disappeared mid-poll, skipping it")
+				return true, nil
 			}
-			for _, daemonset := range damonsetList.Items {
-				if daemonset.Status.CurrentNumberScheduled != daemonset.Status.NumberReady {
-					klog.V(2).Infof("Daemonset %s/%s is not ready yet. Scheduled replicas: %d, Ready: %d. Waiting...", daemonset.Name, namespace.Name, daemonset.Status.CurrentNumberScheduled, daemonset.Status.NumberReady)
-					return false, nil
-				}
+			return false, errors.Wrapf(err, "Get daemonset list in namespace %s error", namespaceName)
+		}
+		for _, daemonset := range damonsetList.Items {
+			if skipWorkloads[workloadExclusionKey("DaemonSet", namespaceName, daemonset.Name)] {
+				continue
 			}
+			if daemonset.Status.CurrentNumberScheduled != daemonset.Status.NumberReady {
+				lastNotReady = fmt.Sprintf("daemonset %s/%s not ready (%d/%d replicas ready)", namespaceName, daemonset.Name, daemonset.Status.NumberReady, daemonset.Status.CurrentNumberScheduled)
+				logger.V(2).Info("Daemonset is not ready yet, waiting", "daemonset", daemonset.Name, "scheduledReplicas", daemonset.Status.CurrentNumberScheduled, "readyReplicas", daemonset.Status.NumberReady)
+				return false, nil
+			}
+		}
 
-			return true, nil
-		})
-		if err != nil {
-			return err
+		return true, nil
+	})
+	if err != nil {
+		if lastNotReady != "" {
+			return fmt.Errorf("timed out waiting for all pods running in namespace %s: %s", namespaceName, lastNotReady)
 		}
+		return errors.Wrapf(err, "timed out waiting for all pods running in namespace %s", namespaceName)
 	}
 	return nil
 }
 
+// remainingTimeout returns how much of timeout is left when now is compared
+// against start, and whether any budget remains at all. Measuring elapsed
+// time as now.Sub(start) keeps the budget correct across a wall-clock minute
+// boundary, unlike a prior version that tracked time.Now().Second() (the
+// 0-59 seconds-of-minute field) directly and so could wrap around and time
+// out almost immediately or wait far longer than requested.
+func remainingTimeout(now, start time.Time, timeout time.Duration) (time.Duration, bool) {
+	remaining := timeout - now.Sub(start)
+	return remaining, remaining > 0
+}
+
+// workloadExclusionKey builds the map key used to look up an entry from
+// constants.ReadinessSkipWorkloadsAnnotation.
+func workloadExclusionKey(kind, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s", kind, namespace, name)
+}
+
+// parseReadinessExclusions reads virtualCluster's
+// constants.ReadinessSkipNamespacesAnnotation and
+// constants.ReadinessSkipWorkloadsAnnotation, returning the set of namespaces
+// ensureAllPodsRunning should skip entirely and the set of individual
+// "Kind/namespace/name" workloads it should skip within the namespaces it
+// does check. Absent or empty annotations yield empty sets, preserving
+// ensureAllPodsRunning's pre-existing check-everything behavior.
+func parseReadinessExclusions(virtualCluster *v1alpha1.VirtualCluster) (map[string]bool, map[string]bool) {
+	skipNamespaces := map[string]bool{}
+	for _, ns := range splitAnnotationList(virtualCluster.Annotations[constants.ReadinessSkipNamespacesAnnotation]) {
+		skipNamespaces[ns] = true
+	}
+
+	skipWorkloads := map[string]bool{}
+	for _, workload := range splitAnnotationList(virtualCluster.Annotations[constants.ReadinessSkipWorkloadsAnnotation]) {
+		skipWorkloads[workload] = true
+	}
+
+	return skipNamespaces, skipWorkloads
+}
+
+// splitAnnotationList splits a comma-separated annotation value, trimming
+// whitespace and dropping empty entries so a trailing comma or stray spaces
+// don't produce a spurious exclusion.
+func splitAnnotationList(value string) []string {
+	var entries []string
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
 func GetHostPortPoolFromConfigMap(client kubernetes.Interface, ns, cmName, dataKey string) (*HostPortPool, error) {
 	hostPorts, err := client.CoreV1().ConfigMaps(ns).Get(context.TODO(), cmName, metav1.GetOptions{})
 	if err != nil {
@@ -675,6 +2492,68 @@ func GetHostPortPoolFromConfigMap(client kubernetes.Interface, ns, cmName, dataK
 	return &hostPool, nil
 }
 
+// GetNamespaceNodeQuota reads the per-namespace GlobalNode quota for ns from
+// the NamespaceNodeQuotaConfigMapName ConfigMap, the same way
+// GetHostPortPoolFromConfigMap and GetVipFromConfigMap read their own pool
+// configs, except keyed by namespace name directly rather than by a single
+// fixed data key. A missing ConfigMap, or no entry for ns, both come back as
+// found=false rather than an error: neither not configuring the ConfigMap at
+// all nor leaving a namespace out of it is a misconfiguration, it just means
+// that namespace has no quota.
+func GetNamespaceNodeQuota(client kubernetes.Interface, ns string) (quota int, found bool, err error) {
+	cm, err := client.CoreV1().ConfigMaps(constants.KosmosNs).Get(context.TODO(), constants.NamespaceNodeQuotaConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+
+	raw, ok := cm.Data[ns]
+	if !ok {
+		return 0, false, nil
+	}
+
+	quota, err = strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil {
+		return 0, false, fmt.Errorf("namespace node quota ConfigMap %s/%s has a non-integer value %q for namespace %q", constants.KosmosNs, constants.NamespaceNodeQuotaConfigMapName, raw, ns)
+	}
+	if quota < 0 {
+		return 0, false, fmt.Errorf("namespace node quota ConfigMap %s/%s has a negative quota %d for namespace %q", constants.KosmosNs, constants.NamespaceNodeQuotaConfigMapName, quota, ns)
+	}
+	return quota, true, nil
+}
+
+// enforceNamespaceNodeQuota rejects allNodeInfos if, combined with every
+// other VirtualCluster's already-assigned NodeInfos in the same namespace,
+// it would push the namespace's cumulative claimed GlobalNode count over the
+// quota configured for it in the NamespaceNodeQuotaConfigMapName ConfigMap.
+// A namespace with no configured quota is never rejected.
+func (c *VirtualClusterInitController) enforceNamespaceNodeQuota(virtualCluster *v1alpha1.VirtualCluster, allNodeInfos []v1alpha1.NodeInfo, otherClusters []v1alpha1.VirtualCluster) error {
+	if c.RootClientSet == nil {
+		return nil
+	}
+	quota, found, err := GetNamespaceNodeQuota(c.RootClientSet, virtualCluster.Namespace)
+	if err != nil {
+		return fmt.Errorf("get namespace node quota: %w", err)
+	}
+	if !found {
+		return nil
+	}
+
+	used := len(allNodeInfos)
+	for _, other := range otherClusters {
+		if other.Namespace != virtualCluster.Namespace {
+			continue
+		}
+		used += len(other.Spec.PromoteResources.NodeInfos)
+	}
+	if used > quota {
+		return fmt.Errorf("namespace %s would claim %d node(s) across its virtualclusters, exceeding its quota of %d", virtualCluster.Namespace, used, quota)
+	}
+	return nil
+}
+
 func GetVipFromConfigMap(client kubernetes.Interface, ns, cmName, key string) (*VipPool, error) {
 	vipPoolCm, err := client.CoreV1().ConfigMaps(ns).Get(context.TODO(), cmName, metav1.GetOptions{})
 	if err != nil {
@@ -768,7 +2647,11 @@ func CheckPortOnHost(addr string, port int32) (bool, error) {
 }
 
 func (c *VirtualClusterInitController) findHostAddresses() ([]string, error) {
-	nodes, err := c.RootClientSet.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{
+	return findHostAddressesWithClient(c.RootClientSet)
+}
+
+func findHostAddressesWithClient(client kubernetes.Interface) ([]string, error) {
+	nodes, err := client.CoreV1().Nodes().List(context.TODO(), metav1.ListOptions{
 		LabelSelector: env.GetControlPlaneLabel(),
 	})
 	if err != nil {
@@ -788,6 +2671,173 @@ func (c *VirtualClusterInitController) findHostAddresses() ([]string, error) {
 	return ret, nil
 }
 
+// HostPortReconciler is a one-shot startup runnable that cross-checks the
+// configured host port pool against live VirtualClusters once the manager
+// starts. A port that is still bound on the host but claimed by no live
+// VirtualCluster likely belongs to a cluster that was deleted while this
+// controller was down, so it is logged for operators to reclaim. Start only
+// logs such a port; it does not release it from any HostPortManager.
+type HostPortReconciler struct {
+	client.Client
+	RootClientSet kubernetes.Interface
+	// PortChecker reports whether port is bound on addr. Defaults to
+	// CheckPortOnHost; overridable in tests.
+	PortChecker func(addr string, port int32) (bool, error)
+}
+
+func (r *HostPortReconciler) SetupWithManager(mgr manager.Manager) error {
+	return mgr.Add(r)
+}
+
+func (r *HostPortReconciler) Start(ctx context.Context) error {
+	klog.V(2).Infof("Reconciling host port pool against live virtual clusters")
+
+	hostPool, err := GetHostPortPoolFromConfigMap(r.RootClientSet, constants.KosmosNs, constants.HostPortsCMName, constants.HostPortsCMDataName)
+	if err != nil {
+		return fmt.Errorf("get host port pool error: %v", err)
+	}
+
+	vcList := &v1alpha1.VirtualClusterList{}
+	if err := r.List(ctx, vcList); err != nil {
+		return fmt.Errorf("list virtual cluster error: %v", err)
+	}
+	claimedPorts := claimedPortsFromVirtualClusters(vcList.Items)
+
+	hostAddress, err := findHostAddressesWithClient(r.RootClientSet)
+	if err != nil {
+		return fmt.Errorf("find host addresses error: %v", err)
+	}
+
+	portChecker := r.PortChecker
+	if portChecker == nil {
+		portChecker = CheckPortOnHost
+	}
+
+	for _, port := range hostPool.PortsPool {
+		if claimedPorts[port] {
+			continue
+		}
+		for _, addr := range hostAddress {
+			bound, err := portChecker(addr, port)
+			if err != nil {
+				klog.Warningf("check host port %d on %s failed: %v", port, addr, err)
+				continue
+			}
+			if bound {
+				klog.Warningf("host port %d is bound on %s but claimed by no live virtual cluster; it may be leaked by a cluster deleted while this controller was down", port, addr)
+			}
+		}
+	}
+
+	return nil
+}
+
+// claimedPortsFromVirtualClusters returns the set of host ports currently
+// allocated to live virtual clusters.
+func claimedPortsFromVirtualClusters(virtualClusters []v1alpha1.VirtualCluster) map[int32]bool {
+	claimed := make(map[int32]bool)
+	for _, vc := range virtualClusters {
+		if vc.Status.Port != 0 {
+			claimed[vc.Status.Port] = true
+		}
+		for _, port := range vc.Status.PortMap {
+			claimed[port] = true
+		}
+	}
+	return claimed
+}
+
+// OrphanNamespaceReconciler periodically sweeps the host cluster for
+// kosmos-managed control-plane namespaces whose owning VirtualCluster no
+// longer exists -- e.g. because the VirtualCluster's finalizer was
+// force-removed before its namespace was cleaned up -- leaving the namespace
+// to linger forever. Deletion is gated by CleanupEnabled; regardless of that
+// flag, every orphan found is logged first so operators can see what the
+// sweep would remove.
+type OrphanNamespaceReconciler struct {
+	client.Client
+	RootClientSet kubernetes.Interface
+	// Interval controls how often the sweep runs. Defaults to 10 minutes.
+	Interval time.Duration
+	// CleanupEnabled gates whether orphaned namespaces are actually deleted.
+	// When false, orphans are only logged (dry-run).
+	CleanupEnabled bool
+}
+
+func (r *OrphanNamespaceReconciler) SetupWithManager(mgr manager.Manager) error {
+	return mgr.Add(r)
+}
+
+func (r *OrphanNamespaceReconciler) Start(ctx context.Context) error {
+	interval := r.Interval
+	if interval <= 0 {
+		interval = 10 * time.Minute
+	}
+	wait.Until(func() {
+		if err := r.sweep(ctx); err != nil {
+			klog.Errorf("orphaned control-plane namespace sweep failed: %v", err)
+		}
+	}, interval, ctx.Done())
+	return nil
+}
+
+func (r *OrphanNamespaceReconciler) sweep(ctx context.Context) error {
+	orphans, err := r.findOrphanedNamespaces(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, ns := range orphans {
+		if !r.CleanupEnabled {
+			klog.Infof("[dry-run] control-plane namespace %s has no owning VirtualCluster and would be deleted; set orphanNamespaceCleanup to actually remove it", ns)
+			continue
+		}
+
+		klog.Infof("control-plane namespace %s has no owning VirtualCluster; deleting it", ns)
+		if err := r.RootClientSet.CoreV1().Namespaces().Delete(ctx, ns, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			klog.Errorf("failed to delete orphaned control-plane namespace %s: %v", ns, err)
+		}
+	}
+
+	return nil
+}
+
+// findOrphanedNamespaces returns the names of kosmos-managed control-plane
+// namespaces (identified by VirtualClusterControllerLabel on the resources
+// uploaded into them) that no live VirtualCluster claims.
+func (r *OrphanNamespaceReconciler) findOrphanedNamespaces(ctx context.Context) ([]string, error) {
+	secrets, err := r.RootClientSet.CoreV1().Secrets(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		LabelSelector: tasks.VirtualClusterControllerLabel.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list control-plane secrets error: %v", err)
+	}
+
+	candidateNamespaces := make(map[string]bool)
+	for _, secret := range secrets.Items {
+		candidateNamespaces[secret.Namespace] = true
+	}
+
+	vcList := &v1alpha1.VirtualClusterList{}
+	if err := r.List(ctx, vcList); err != nil {
+		return nil, fmt.Errorf("list virtual clusters error: %v", err)
+	}
+	claimedNamespaces := make(map[string]bool, len(vcList.Items))
+	for _, vc := range vcList.Items {
+		claimedNamespaces[vc.Namespace] = true
+	}
+
+	var orphans []string
+	for ns := range candidateNamespaces {
+		if !claimedNamespaces[ns] {
+			orphans = append(orphans, ns)
+		}
+	}
+
+	sort.Strings(orphans)
+	return orphans, nil
+}
+
 func (c *VirtualClusterInitController) GetHostPortNextFunc(_ *v1alpha1.VirtualCluster) (func() (int32, error), error) {
 	var hostPool *HostPortPool
 	var err error
@@ -900,7 +2950,26 @@ func (c *VirtualClusterInitController) GetNodePorts(client kubernetes.Interface,
 	return ports, nil
 }
 
+// GetHostNetworkPorts allocates the host-network ports a VirtualCluster's
+// control plane needs. When c.PortManager is set and no port was manually
+// specified, allocation goes through it so the in-memory allocation table
+// stays the source of truth and a controller restart doesn't hand out a
+// port some other VirtualCluster already holds; otherwise it falls back to
+// probing the configured pool live, since PortManager's pool-based
+// AllocatePortRange has no way to reserve one specific caller-chosen port
+// alongside the rest.
 func (c *VirtualClusterInitController) GetHostNetworkPorts(virtualCluster *v1alpha1.VirtualCluster) ([]int32, error) {
+	// 检查是否手动指定了 APIServerPortKey 的端口号
+	var specifiedAPIServerPort int32
+	if virtualCluster.Spec.KubeInKubeConfig != nil && virtualCluster.Spec.KubeInKubeConfig.ExternalPort != 0 {
+		specifiedAPIServerPort = virtualCluster.Spec.KubeInKubeConfig.ExternalPort
+		klog.V(4).InfoS("APIServerPortKey specified manually", "port", specifiedAPIServerPort)
+	}
+
+	if c.PortManager != nil && specifiedAPIServerPort == 0 {
+		return c.PortManager.AllocatePortRange(constants.VirtualClusterPortNum)
+	}
+
 	next, err := c.GetHostPortNextFunc(virtualCluster)
 	if err != nil {
 		return nil, err
@@ -911,13 +2980,6 @@ func (c *VirtualClusterInitController) GetHostNetworkPorts(virtualCluster *v1alp
 		return nil, err
 	}
 
-	// 检查是否手动指定了 APIServerPortKey 的端口号
-	var specifiedAPIServerPort int32
-	if virtualCluster.Spec.KubeInKubeConfig != nil && virtualCluster.Spec.KubeInKubeConfig.ExternalPort != 0 {
-		specifiedAPIServerPort = virtualCluster.Spec.KubeInKubeConfig.ExternalPort
-		klog.V(4).InfoS("APIServerPortKey specified manually", "port", specifiedAPIServerPort)
-	}
-
 	// 保存最终的分配结果
 	ports := make([]int32, 0)
 
@@ -956,8 +3018,8 @@ func (c *VirtualClusterInitController) GetHostNetworkPorts(virtualCluster *v1alp
 // AllocateHostPort allocate host port for virtual cluster
 // #nosec G602
 func (c *VirtualClusterInitController) AllocateHostPort(virtualCluster *v1alpha1.VirtualCluster, _ *v1alpha1.KubeNestConfiguration) (int32, error) {
-	c.lock.Lock()
-	defer c.lock.Unlock()
+	c.hostPortLock.Lock()
+	defer c.hostPortLock.Unlock()
 	if len(virtualCluster.Status.PortMap) > 0 || virtualCluster.Status.Port != 0 {
 		return 0, nil
 	}
@@ -995,8 +3057,8 @@ func (c *VirtualClusterInitController) AllocateHostPort(virtualCluster *v1alpha1
 // nolint:revive
 // #nosec G602
 func (c *VirtualClusterInitController) AllocateVip(virtualCluster *v1alpha1.VirtualCluster, vipPool *VipPool) error {
-	c.lock.Lock()
-	defer c.lock.Unlock()
+	c.vipLock.Lock()
+	defer c.vipLock.Unlock()
 	if len(virtualCluster.Status.VipMap) > 0 {
 		return nil
 	}
@@ -1,31 +1,2164 @@
 package controller
 
 import (
+	"context"
+	"encoding/base64"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	ktesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+	kosmosfake "github.com/kosmos.io/kosmos/pkg/generated/clientset/versioned/fake"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/constants"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/tasks"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/util"
+	fakeutil "github.com/kosmos.io/kosmos/pkg/kubenest/util/fake"
 )
 
-func TestNetxFunc(_ *testing.T) {
-	portsPool := []int32{1, 2, 3, 4, 5}
-	type nextfunc func() (int32, error)
-	// var next nextfunc
-	next := func() nextfunc {
-		i := 0
-		return func() (int32, error) {
-			if i >= len(portsPool) {
-				return 0, fmt.Errorf("no available ports")
+// fakeKubeconfig is a minimal but valid kubeconfig, used to exercise
+// virtualClusterClient without talking to a real apiserver.
+var fakeKubeconfig = []byte(`
+apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://127.0.0.1:6443
+  name: test-cluster
+contexts:
+- context:
+    cluster: test-cluster
+    user: test-user
+  name: test-context
+current-context: test-context
+users:
+- name: test-user
+  user:
+    token: test-token
+`)
+
+// virtualClusterListerClient stubs the single client.Client method
+// HostPortReconciler.Start needs, returning a fixed VirtualClusterList.
+type virtualClusterListerClient struct {
+	client.Client
+	vcList v1alpha1.VirtualClusterList
+}
+
+func (c *virtualClusterListerClient) List(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+	vcList, ok := list.(*v1alpha1.VirtualClusterList)
+	if !ok {
+		return fmt.Errorf("unexpected list type %T", list)
+	}
+	*vcList = c.vcList
+	return nil
+}
+
+// inMemoryVirtualClusterClient stubs the client.Client methods Reconcile's
+// status-write path needs (Get/Update/Patch) against a single in-memory
+// VirtualCluster, so Reconcile can be driven end-to-end without a real
+// apiserver. Patch is applied by simply storing the already-merged object
+// Update/c.Update hands it, since neither caller relies on server-side
+// merge semantics beyond "the object I'm writing is the full desired state".
+type inMemoryVirtualClusterClient struct {
+	client.Client
+	vc *v1alpha1.VirtualCluster
+}
+
+func (c *inMemoryVirtualClusterClient) Get(_ context.Context, _ client.ObjectKey, out client.Object, _ ...client.GetOption) error {
+	vc, ok := out.(*v1alpha1.VirtualCluster)
+	if !ok {
+		return fmt.Errorf("unexpected object type %T", out)
+	}
+	c.vc.DeepCopyInto(vc)
+	return nil
+}
+
+func (c *inMemoryVirtualClusterClient) Update(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+	vc, ok := obj.(*v1alpha1.VirtualCluster)
+	if !ok {
+		return fmt.Errorf("unexpected object type %T", obj)
+	}
+	c.vc = vc.DeepCopy()
+	return nil
+}
+
+func (c *inMemoryVirtualClusterClient) List(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+	vcList, ok := list.(*v1alpha1.VirtualClusterList)
+	if !ok {
+		return fmt.Errorf("unexpected list type %T", list)
+	}
+	vcList.Items = []v1alpha1.VirtualCluster{*c.vc}
+	return nil
+}
+
+func (c *inMemoryVirtualClusterClient) Patch(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+	vc, ok := obj.(*v1alpha1.VirtualCluster)
+	if !ok {
+		return fmt.Errorf("unexpected object type %T", obj)
+	}
+	c.vc = vc.DeepCopy()
+	return nil
+}
+
+// stubExecutor is a VirtualClusterExecutor that records invocation instead of
+// actually running the init/uninstall workflow.
+type stubExecutor struct {
+	executed *int
+}
+
+func (s *stubExecutor) Execute() error {
+	*s.executed++
+	return nil
+}
+
+// TestReconcileDrivesPhaseTransitionsAndClaimsNodes exercises Reconcile
+// end-to-end against a fake GlobalNode clientset, a hand-rolled in-memory
+// VirtualCluster client and a stubbed executor, asserting that a fresh
+// VirtualCluster is carried from "" through Preparing to Initialized, that
+// its GlobalNodes move Free->InUse, and that the controller finalizer gets
+// added.
+//
+// Status.Port is pre-populated so AllocateHostPort short-circuits: the real
+// allocation path either goes through PortManager.AllocatePortRange or, with
+// PortManager left unset as it is here, probes host addresses over the
+// network via CheckPortOnHost, neither of which has a test seam appropriate
+// to exercise here.
+func TestReconcileDrivesPhaseTransitionsAndClaimsNodes(t *testing.T) {
+	nodeA := v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+		Spec:       v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeFreeState},
+	}
+	nodeB := v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-b"},
+		Spec:       v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeFreeState},
+	}
+	kosmosClient := kosmosfake.NewSimpleClientset(&nodeA, &nodeB)
+
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			PromotePolicies: []v1alpha1.PromotePolicy{{NodeCount: 2}},
+			KubeInKubeConfig: &v1alpha1.KubeInKubeConfig{KubernetesVersion: "v1.27.6"},
+		},
+		Status: v1alpha1.VirtualClusterStatus{Port: 40010},
+	}
+	ctrlClient := &inMemoryVirtualClusterClient{vc: vc}
+
+	adminKubeconfigSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: util.GetAdminConfigSecretName(vc.Name), Namespace: vc.Namespace},
+		Data:       map[string][]byte{constants.KubeConfig: fakeKubeconfig},
+	}
+
+	var executed int
+	c := &VirtualClusterInitController{
+		Client:        ctrlClient,
+		RootClientSet: fake.NewSimpleClientset(adminKubeconfigSecret),
+		KosmosClient:  kosmosClient,
+		ExecutorFactory: func(_ *v1alpha1.VirtualCluster, _ client.Client, _ *rest.Config, _ *v1alpha1.KubeNestConfiguration) (VirtualClusterExecutor, error) {
+			return &stubExecutor{executed: &executed}, nil
+		},
+	}
+
+	if _, err := c.Reconcile(context.TODO(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(vc)}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	if ctrlClient.vc.Status.Phase != v1alpha1.Initialized {
+		t.Fatalf("expected phase %q, got %q", v1alpha1.Initialized, ctrlClient.vc.Status.Phase)
+	}
+	if executed != 1 {
+		t.Fatalf("expected the stub executor to run exactly once, got %d", executed)
+	}
+	if !controllerutil.ContainsFinalizer(ctrlClient.vc, VirtualClusterControllerFinalizer) {
+		t.Fatal("expected Reconcile to add the controller finalizer")
+	}
+
+	for _, name := range []string{"node-a", "node-b"} {
+		node, err := kosmosClient.KosmosV1alpha1().GlobalNodes().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Get(%s) error = %v", name, err)
+		}
+		if node.Spec.State != v1alpha1.NodeInUse {
+			t.Errorf("expected %s to be claimed, got state %q", name, node.Spec.State)
+		}
+		if node.Status.VirtualCluster != "vc1" {
+			t.Errorf("expected %s to record vc1 as its owner, got %q", name, node.Status.VirtualCluster)
+		}
+	}
+}
+
+func TestReconcileSkipsReadinessCheckWhenConfigured(t *testing.T) {
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			SkipReadinessCheck: true,
+			PromoteResources:   v1alpha1.PromoteResources{NodeInfos: []v1alpha1.NodeInfo{{NodeName: "node-a"}}},
+		},
+		Status: v1alpha1.VirtualClusterStatus{Phase: v1alpha1.AllNodeReady},
+	}
+	ctrlClient := &inMemoryVirtualClusterClient{vc: vc}
+
+	c := &VirtualClusterInitController{
+		Client:        ctrlClient,
+		RootClientSet: fake.NewSimpleClientset(),
+		KosmosClient:  kosmosfake.NewSimpleClientset(),
+	}
+
+	if _, err := c.Reconcile(context.TODO(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(vc)}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	if ctrlClient.vc.Status.Phase != v1alpha1.Completed {
+		t.Fatalf("expected phase %q, got %q", v1alpha1.Completed, ctrlClient.vc.Status.Phase)
+	}
+}
+
+// TestReconcileCompletedSkipsPolicyCheckWhenGenerationUnchanged asserts that a
+// Completed VirtualCluster whose Status.ObservedGeneration already matches
+// Generation short-circuits before checkPromotePoliciesChanged, so a
+// reconcile driven purely by an unrelated status write doesn't re-list every
+// GlobalNode.
+func TestReconcileCompletedSkipsPolicyCheckWhenGenerationUnchanged(t *testing.T) {
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1", Generation: 3},
+		Status:     v1alpha1.VirtualClusterStatus{Phase: v1alpha1.Completed, ObservedGeneration: 3},
+	}
+	ctrlClient := &inMemoryVirtualClusterClient{vc: vc}
+
+	var listCalls int
+	kosmosClient := kosmosfake.NewSimpleClientset()
+	kosmosClient.PrependReactor("list", "globalnodes", func(ktesting.Action) (bool, runtime.Object, error) {
+		listCalls++
+		return false, nil, nil
+	})
+
+	c := &VirtualClusterInitController{Client: ctrlClient, RootClientSet: fake.NewSimpleClientset(), KosmosClient: kosmosClient}
+
+	if _, err := c.Reconcile(context.TODO(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(vc)}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	if listCalls != 0 {
+		t.Errorf("expected checkPromotePoliciesChanged to be skipped, but GlobalNodes was listed %d time(s)", listCalls)
+	}
+	if ctrlClient.vc.Status.Phase != v1alpha1.Completed {
+		t.Errorf("expected phase to remain %q, got %q", v1alpha1.Completed, ctrlClient.vc.Status.Phase)
+	}
+}
+
+// TestReconcileCompletedRunsPolicyCheckOnGenerationChange asserts that a
+// Completed VirtualCluster whose Generation has moved past
+// Status.ObservedGeneration still runs checkPromotePoliciesChanged, and that
+// ObservedGeneration is advanced to match once it has.
+func TestReconcileCompletedRunsPolicyCheckOnGenerationChange(t *testing.T) {
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1", Generation: 4},
+		Status:     v1alpha1.VirtualClusterStatus{Phase: v1alpha1.Completed, ObservedGeneration: 3},
+	}
+	ctrlClient := &inMemoryVirtualClusterClient{vc: vc}
+
+	var listCalls int
+	kosmosClient := kosmosfake.NewSimpleClientset()
+	kosmosClient.PrependReactor("list", "globalnodes", func(ktesting.Action) (bool, runtime.Object, error) {
+		listCalls++
+		return false, nil, nil
+	})
+
+	c := &VirtualClusterInitController{Client: ctrlClient, RootClientSet: fake.NewSimpleClientset(), KosmosClient: kosmosClient}
+
+	if _, err := c.Reconcile(context.TODO(), reconcile.Request{NamespacedName: client.ObjectKeyFromObject(vc)}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	if listCalls != 1 {
+		t.Errorf("expected checkPromotePoliciesChanged to run exactly once, got %d", listCalls)
+	}
+	if ctrlClient.vc.Status.ObservedGeneration != 4 {
+		t.Errorf("expected ObservedGeneration to advance to 4, got %d", ctrlClient.vc.Status.ObservedGeneration)
+	}
+}
+
+func newPodOnNode(name, nodeName, cpu string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+			Containers: []corev1.Container{
+				{
+					Name: "app",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse(cpu),
+						},
+					},
+				},
+			},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func TestHostPortReconcilerChecksUnclaimedPort(t *testing.T) {
+	ctrlClient := &virtualClusterListerClient{
+		vcList: v1alpha1.VirtualClusterList{
+			Items: []v1alpha1.VirtualCluster{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "live-vc"},
+					Status:     v1alpha1.VirtualClusterStatus{Port: 40010},
+				},
+			},
+		},
+	}
+
+	controlPlaneNode := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "control-plane-node",
+			Labels: map[string]string{"node-role.kubernetes.io/control-plane": ""},
+		},
+		Status: corev1.NodeStatus{
+			Addresses: []corev1.NodeAddress{
+				{Type: corev1.NodeInternalIP, Address: "192.168.0.10"},
+			},
+		},
+	}
+	hostClient := fake.NewSimpleClientset(controlPlaneNode, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "kosmos-hostports", Namespace: "kosmos-system"},
+		Data: map[string]string{
+			"config.yaml": "portsPool:\n  - 40010\n  - 40020\n",
+		},
+	})
+
+	var checkedLeakedPort bool
+	reconciler := &HostPortReconciler{
+		Client:        ctrlClient,
+		RootClientSet: hostClient,
+		PortChecker: func(_ string, port int32) (bool, error) {
+			// 40010 is claimed by liveVC; 40020 is bound on the host but
+			// claimed by no live virtual cluster, simulating a port leaked
+			// by a cluster deleted while the controller was down.
+			if port == 40020 {
+				checkedLeakedPort = true
+				return true, nil
+			}
+			return false, nil
+		},
+	}
+
+	if err := reconciler.Start(context.TODO()); err != nil {
+		t.Fatalf("HostPortReconciler.Start() failed: %s", err)
+	}
+	if !checkedLeakedPort {
+		t.Fatalf("expected reconciler to check the unclaimed port 40020 against the host")
+	}
+}
+
+func TestClaimedPortsFromVirtualClusters(t *testing.T) {
+	virtualClusters := []v1alpha1.VirtualCluster{
+		{Status: v1alpha1.VirtualClusterStatus{Port: 40010}},
+		{Status: v1alpha1.VirtualClusterStatus{PortMap: map[string]int32{"apiserver": 40020}}},
+	}
+
+	claimed := claimedPortsFromVirtualClusters(virtualClusters)
+	for _, port := range []int32{40010, 40020} {
+		if !claimed[port] {
+			t.Errorf("expected port %d to be claimed", port)
+		}
+	}
+	if claimed[40030] {
+		t.Errorf("expected port 40030 to be unclaimed")
+	}
+}
+
+func TestOrphanNamespaceReconcilerDryRunIdentifiesButDoesNotDelete(t *testing.T) {
+	ctrlClient := &virtualClusterListerClient{
+		vcList: v1alpha1.VirtualClusterList{
+			Items: []v1alpha1.VirtualCluster{
+				{ObjectMeta: metav1.ObjectMeta{Name: "live-vc", Namespace: "live-vc-ns"}},
+			},
+		},
+	}
+	hostClient := fake.NewSimpleClientset(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "live-vc-admin-config", Namespace: "live-vc-ns", Labels: tasks.VirtualClusterControllerLabel},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "orphan-vc-admin-config", Namespace: "orphan-vc-ns", Labels: tasks.VirtualClusterControllerLabel},
+		},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "orphan-vc-ns"}},
+	)
+
+	reconciler := &OrphanNamespaceReconciler{Client: ctrlClient, RootClientSet: hostClient}
+
+	orphans, err := reconciler.findOrphanedNamespaces(context.TODO())
+	if err != nil {
+		t.Fatalf("findOrphanedNamespaces() error = %v", err)
+	}
+	if len(orphans) != 1 || orphans[0] != "orphan-vc-ns" {
+		t.Fatalf("findOrphanedNamespaces() = %v, want [orphan-vc-ns]", orphans)
+	}
+
+	if err := reconciler.sweep(context.TODO()); err != nil {
+		t.Fatalf("sweep() error = %v", err)
+	}
+	if _, err := hostClient.CoreV1().Namespaces().Get(context.TODO(), "orphan-vc-ns", metav1.GetOptions{}); err != nil {
+		t.Fatalf("expected orphan-vc-ns to survive a dry-run sweep, but Get failed: %v", err)
+	}
+}
+
+func TestOrphanNamespaceReconcilerCleanupEnabledDeletesOrphan(t *testing.T) {
+	ctrlClient := &virtualClusterListerClient{}
+	hostClient := fake.NewSimpleClientset(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "orphan-vc-admin-config", Namespace: "orphan-vc-ns", Labels: tasks.VirtualClusterControllerLabel},
+		},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "orphan-vc-ns"}},
+	)
+
+	reconciler := &OrphanNamespaceReconciler{Client: ctrlClient, RootClientSet: hostClient, CleanupEnabled: true}
+
+	if err := reconciler.sweep(context.TODO()); err != nil {
+		t.Fatalf("sweep() error = %v", err)
+	}
+
+	if _, err := hostClient.CoreV1().Namespaces().Get(context.TODO(), "orphan-vc-ns", metav1.GetOptions{}); err == nil {
+		t.Fatalf("expected orphan-vc-ns to be deleted when CleanupEnabled is true")
+	}
+}
+
+func TestSelectLeastUtilizedNodes(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		newPodOnNode("busy-pod-1", "node-busy", "500m"),
+		newPodOnNode("busy-pod-2", "node-busy", "500m"),
+		newPodOnNode("idle-pod", "node-idle", "10m"),
+	)
+
+	nodesAssigned := []v1alpha1.NodeInfo{
+		{NodeName: "node-busy"},
+		{NodeName: "node-idle"},
+	}
+
+	remaining := selectLeastUtilizedNodes(client, nodesAssigned, 1)
+
+	if len(remaining) != 1 {
+		t.Fatalf("expected 1 node to remain, got %d", len(remaining))
+	}
+	if remaining[0].NodeName != "node-busy" {
+		t.Fatalf("expected least-utilized node-idle to be released, node-busy to remain, got %s", remaining[0].NodeName)
+	}
+}
+
+func TestReleasedNodeInfosReturnsDroppedNodes(t *testing.T) {
+	before := []v1alpha1.NodeInfo{{NodeName: "node-a"}, {NodeName: "node-b"}, {NodeName: "node-c"}}
+	after := []v1alpha1.NodeInfo{{NodeName: "node-a"}, {NodeName: "node-c"}}
+
+	released := releasedNodeInfos(before, after)
+
+	if len(released) != 1 || released[0].NodeName != "node-b" {
+		t.Fatalf("expected only node-b to be released, got %v", released)
+	}
+}
+
+func TestDrainReleasedNodesRecordsFailureReason(t *testing.T) {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-gone"}}
+	tenantClient := fake.NewSimpleClientset(node)
+	tenantClient.PrependReactor("get", "nodes", func(ktesting.Action) (bool, runtime.Object, error) {
+		return true, nil, fmt.Errorf("apiserver unreachable")
+	})
+
+	vc := &v1alpha1.VirtualCluster{ObjectMeta: metav1.ObjectMeta{Name: "vc1"}}
+	drainReleasedNodes(vc, tenantClient, v1alpha1.PromotePolicy{}, []v1alpha1.NodeInfo{{NodeName: "node-gone"}})
+
+	if !strings.Contains(vc.Status.Reason, "node-gone") {
+		t.Fatalf("expected Status.Reason to mention the node that failed to drain, got %q", vc.Status.Reason)
+	}
+}
+
+func TestDrainReleasedNodesSkipsAlreadyGoneNodes(t *testing.T) {
+	tenantClient := fake.NewSimpleClientset()
+
+	vc := &v1alpha1.VirtualCluster{ObjectMeta: metav1.ObjectMeta{Name: "vc1"}}
+	drainReleasedNodes(vc, tenantClient, v1alpha1.PromotePolicy{}, []v1alpha1.NodeInfo{{NodeName: "already-removed"}})
+
+	if vc.Status.Reason != "" {
+		t.Fatalf("expected no failure reason for a node that's already gone, got %q", vc.Status.Reason)
+	}
+}
+
+func TestFilterGlobalNodesByTolerations(t *testing.T) {
+	taintedNode := v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "tainted-node"},
+		Spec: v1alpha1.GlobalNodeSpec{
+			Taints: []corev1.Taint{
+				{Key: "dedicated", Value: "tenant-a", Effect: corev1.TaintEffectNoSchedule},
+			},
+		},
+	}
+	freeNode := v1alpha1.GlobalNode{ObjectMeta: metav1.ObjectMeta{Name: "free-node"}}
+	nodes := []v1alpha1.GlobalNode{taintedNode, freeNode}
+
+	filtered := filterGlobalNodesByTolerations(nodes, nil)
+	if len(filtered) != 1 || filtered[0].Name != "free-node" {
+		t.Fatalf("expected tainted node to be excluded absent a toleration, got %v", filtered)
+	}
+
+	tolerated := filterGlobalNodesByTolerations(nodes, []corev1.Toleration{
+		{Key: "dedicated", Value: "tenant-a", Operator: corev1.TolerationOpEqual, Effect: corev1.TaintEffectNoSchedule},
+	})
+	if len(tolerated) != 2 {
+		t.Fatalf("expected both nodes to be included when the taint is tolerated, got %v", tolerated)
+	}
+}
+
+func TestDeleteAdminKubeconfigSecretDeletesSecret(t *testing.T) {
+	vc := &v1alpha1.VirtualCluster{ObjectMeta: metav1.ObjectMeta{Name: "vc1", Namespace: "test-ns"}}
+	secretName := fmt.Sprintf("%s-%s", vc.GetName(), constants.AdminConfig)
+	client := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: vc.GetNamespace()},
+	})
+	c := &VirtualClusterInitController{RootClientSet: client}
+
+	if err := c.deleteAdminKubeconfigSecret(vc); err != nil {
+		t.Fatalf("deleteAdminKubeconfigSecret() error = %v", err)
+	}
+
+	if _, err := client.CoreV1().Secrets(vc.GetNamespace()).Get(context.TODO(), secretName, metav1.GetOptions{}); err == nil {
+		t.Fatal("expected the admin kubeconfig secret to be deleted")
+	}
+}
+
+func TestDeleteAdminKubeconfigSecretToleratesAlreadyGone(t *testing.T) {
+	vc := &v1alpha1.VirtualCluster{ObjectMeta: metav1.ObjectMeta{Name: "vc1", Namespace: "test-ns"}}
+	c := &VirtualClusterInitController{RootClientSet: fake.NewSimpleClientset()}
+
+	if err := c.deleteAdminKubeconfigSecret(vc); err != nil {
+		t.Fatalf("expected a missing secret to not be an error, got %v", err)
+	}
+}
+
+func TestDestroyVirtualClusterFreesClaimedGlobalNodes(t *testing.T) {
+	now := metav1.Now()
+	claimedNodeA := v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+		Spec:       v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeInUse},
+		Status:     v1alpha1.GlobalNodeStatus{VirtualCluster: "vc1", ClaimedAt: &now},
+	}
+	claimedNodeB := v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-b"},
+		Spec:       v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeInUse},
+		Status:     v1alpha1.GlobalNodeStatus{VirtualCluster: "vc1", ClaimedAt: &now},
+	}
+	otherClusterNode := v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-other"},
+		Spec:       v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeInUse},
+		Status:     v1alpha1.GlobalNodeStatus{VirtualCluster: "vc2", ClaimedAt: &now},
+	}
+	kosmosClient := kosmosfake.NewSimpleClientset(&claimedNodeA, &claimedNodeB, &otherClusterNode)
+
+	vc := &v1alpha1.VirtualCluster{ObjectMeta: metav1.ObjectMeta{Name: "vc1", Namespace: "test-ns"}}
+	c := &VirtualClusterInitController{
+		RootClientSet: fake.NewSimpleClientset(),
+		KosmosClient:  kosmosClient,
+		ExecutorFactory: func(_ *v1alpha1.VirtualCluster, _ client.Client, _ *rest.Config, _ *v1alpha1.KubeNestConfiguration) (VirtualClusterExecutor, error) {
+			return &stubExecutor{executed: new(int)}, nil
+		},
+	}
+
+	if err := c.destroyVirtualCluster(vc); err != nil {
+		t.Fatalf("destroyVirtualCluster() error = %v", err)
+	}
+
+	for _, name := range []string{"node-a", "node-b"} {
+		node, err := kosmosClient.KosmosV1alpha1().GlobalNodes().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Get(%s) error = %v", name, err)
+		}
+		if node.Spec.State != v1alpha1.NodeFreeState {
+			t.Errorf("expected %s to be freed, got state %q", name, node.Spec.State)
+		}
+		if node.Status.VirtualCluster != "" || node.Status.ClaimedAt != nil {
+			t.Errorf("expected %s usage status to be cleared, got %+v", name, node.Status)
+		}
+	}
+
+	other, err := kosmosClient.KosmosV1alpha1().GlobalNodes().Get(context.TODO(), "node-other", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get(node-other) error = %v", err)
+	}
+	if other.Spec.State != v1alpha1.NodeInUse || other.Status.VirtualCluster != "vc2" {
+		t.Errorf("expected node-other, owned by a different virtualcluster, to be left untouched, got %+v", other)
+	}
+}
+
+func TestDestroyVirtualClusterToleratesNoClaimedNodes(t *testing.T) {
+	vc := &v1alpha1.VirtualCluster{ObjectMeta: metav1.ObjectMeta{Name: "vc1", Namespace: "test-ns"}}
+	c := &VirtualClusterInitController{
+		RootClientSet: fake.NewSimpleClientset(),
+		KosmosClient:  kosmosfake.NewSimpleClientset(),
+		ExecutorFactory: func(_ *v1alpha1.VirtualCluster, _ client.Client, _ *rest.Config, _ *v1alpha1.KubeNestConfiguration) (VirtualClusterExecutor, error) {
+			return &stubExecutor{executed: new(int)}, nil
+		},
+	}
+
+	if err := c.destroyVirtualCluster(vc); err != nil {
+		t.Fatalf("destroyVirtualCluster() error = %v", err)
+	}
+}
+
+func TestDecodeVirtualClusterKubeconfigPrefersSpecField(t *testing.T) {
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1", Namespace: "test-ns"},
+		Spec:       v1alpha1.VirtualClusterSpec{Kubeconfig: base64.StdEncoding.EncodeToString([]byte("spec-kubeconfig"))},
+	}
+	c := &VirtualClusterInitController{RootClientSet: fake.NewSimpleClientset()}
+
+	kubeconfig, err := c.decodeVirtualClusterKubeconfig(vc)
+	if err != nil {
+		t.Fatalf("decodeVirtualClusterKubeconfig() error = %v", err)
+	}
+	if string(kubeconfig) != "spec-kubeconfig" {
+		t.Errorf("decodeVirtualClusterKubeconfig() = %q, want %q", kubeconfig, "spec-kubeconfig")
+	}
+}
+
+func TestDecodeVirtualClusterKubeconfigFallsBackToSecret(t *testing.T) {
+	vc := &v1alpha1.VirtualCluster{ObjectMeta: metav1.ObjectMeta{Name: "vc1", Namespace: "test-ns"}}
+	client := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: util.GetAdminConfigSecretName(vc.GetName()), Namespace: vc.GetNamespace()},
+		Data:       map[string][]byte{constants.KubeConfig: []byte("secret-kubeconfig")},
+	})
+	c := &VirtualClusterInitController{RootClientSet: client}
+
+	kubeconfig, err := c.decodeVirtualClusterKubeconfig(vc)
+	if err != nil {
+		t.Fatalf("decodeVirtualClusterKubeconfig() error = %v", err)
+	}
+	if string(kubeconfig) != "secret-kubeconfig" {
+		t.Errorf("decodeVirtualClusterKubeconfig() = %q, want %q", kubeconfig, "secret-kubeconfig")
+	}
+}
+
+func TestVirtualClusterClientCachesByResourceVersion(t *testing.T) {
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1", Namespace: "test-ns", UID: "vc1-uid", ResourceVersion: "1"},
+		Spec:       v1alpha1.VirtualClusterSpec{Kubeconfig: base64.StdEncoding.EncodeToString(fakeKubeconfig)},
+	}
+	c := &VirtualClusterInitController{RootClientSet: fake.NewSimpleClientset()}
+
+	first, err := c.virtualClusterClient(vc)
+	if err != nil {
+		t.Fatalf("virtualClusterClient() error = %v", err)
+	}
+	second, err := c.virtualClusterClient(vc)
+	if err != nil {
+		t.Fatalf("virtualClusterClient() error = %v", err)
+	}
+	if first != second {
+		t.Error("expected virtualClusterClient() to return the cached client for an unchanged ResourceVersion")
+	}
+
+	vc.ResourceVersion = "2"
+	third, err := c.virtualClusterClient(vc)
+	if err != nil {
+		t.Fatalf("virtualClusterClient() error = %v", err)
+	}
+	if third == first {
+		t.Error("expected virtualClusterClient() to rebuild the client after ResourceVersion changed")
+	}
+}
+
+func TestRefreshAPIServerEndpointRewritesKubeconfigOnNodePortChange(t *testing.T) {
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1", Namespace: "test-ns"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			KubeInKubeConfig: &v1alpha1.KubeInKubeConfig{APIServerServiceType: v1alpha1.NodePort},
+		},
+		Status: v1alpha1.VirtualClusterStatus{PortMap: map[string]int32{constants.APIServerPortKey: 30000}},
+	}
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: util.GetAPIServerName(vc.GetName()), Namespace: vc.GetNamespace()},
+		Spec: corev1.ServiceSpec{
+			Type:  corev1.ServiceTypeNodePort,
+			Ports: []corev1.ServicePort{{Name: constants.APIServerSVCPortName, NodePort: 30001}},
+		},
+	}
+	kubeconfig, err := clientcmd.Write(*util.CreateBasic("https://1.2.3.4:30000", "vc1", "vc1-admin", nil))
+	if err != nil {
+		t.Fatalf("build fake kubeconfig: %v", err)
+	}
+	secretName := util.GetAdminConfigSecretName(vc.GetName())
+	client := fake.NewSimpleClientset(service, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: secretName, Namespace: vc.GetNamespace()},
+		Data:       map[string][]byte{constants.KubeConfig: kubeconfig},
+	})
+	c := &VirtualClusterInitController{RootClientSet: client}
+
+	refreshed, err := c.refreshAPIServerEndpoint(vc)
+	if err != nil {
+		t.Fatalf("refreshAPIServerEndpoint() error = %v", err)
+	}
+	if !refreshed {
+		t.Fatal("expected refreshAPIServerEndpoint() to report a change")
+	}
+	if got := vc.Status.PortMap[constants.APIServerPortKey]; got != 30001 {
+		t.Errorf("expected stored apiserver port to be updated to 30001, got %d", got)
+	}
+
+	secret, err := client.CoreV1().Secrets(vc.GetNamespace()).Get(context.TODO(), secretName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get admin kubeconfig secret: %v", err)
+	}
+	updated, err := clientcmd.Load(secret.Data[constants.KubeConfig])
+	if err != nil {
+		t.Fatalf("parse updated kubeconfig: %v", err)
+	}
+	for name, cluster := range updated.Clusters {
+		if !strings.Contains(cluster.Server, ":30001") {
+			t.Errorf("expected cluster %q server %q to carry the refreshed NodePort", name, cluster.Server)
+		}
+	}
+}
+
+func TestRefreshAPIServerEndpointNoopWhenUnchanged(t *testing.T) {
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1", Namespace: "test-ns"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			KubeInKubeConfig: &v1alpha1.KubeInKubeConfig{APIServerServiceType: v1alpha1.NodePort},
+		},
+		Status: v1alpha1.VirtualClusterStatus{PortMap: map[string]int32{constants.APIServerPortKey: 30000}},
+	}
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: util.GetAPIServerName(vc.GetName()), Namespace: vc.GetNamespace()},
+		Spec: corev1.ServiceSpec{
+			Type:  corev1.ServiceTypeNodePort,
+			Ports: []corev1.ServicePort{{Name: constants.APIServerSVCPortName, NodePort: 30000}},
+		},
+	}
+	c := &VirtualClusterInitController{RootClientSet: fake.NewSimpleClientset(service)}
+
+	refreshed, err := c.refreshAPIServerEndpoint(vc)
+	if err != nil {
+		t.Fatalf("refreshAPIServerEndpoint() error = %v", err)
+	}
+	if refreshed {
+		t.Error("expected refreshAPIServerEndpoint() to be a no-op when the NodePort hasn't changed")
+	}
+}
+
+func TestMatchesWithLabelSelectorMatchExpressionsOperators(t *testing.T) {
+	poolALabels := labels.Set{"pool": "a", "dedicated": "true"}
+	poolBLabels := labels.Set{"pool": "b"}
+	poolCLabels := labels.Set{"pool": "c"}
+
+	inSelector := &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "pool", Operator: metav1.LabelSelectorOpIn, Values: []string{"a", "b"}},
+		},
+	}
+	for labelSet, want := range map[string]bool{"a": true, "b": true, "c": false} {
+		matched, err := matchesWithLabelSelector(labels.Set{"pool": labelSet}, inSelector)
+		if err != nil {
+			t.Fatalf("matchesWithLabelSelector() error = %v", err)
+		}
+		if matched != want {
+			t.Errorf("In[a,b] match for pool=%s = %v, want %v", labelSet, matched, want)
+		}
+	}
+
+	notInSelector := &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "pool", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"c"}},
+		},
+	}
+	if matched, err := matchesWithLabelSelector(poolALabels, notInSelector); err != nil || !matched {
+		t.Errorf("NotIn[c] should match pool=a, got matched=%v err=%v", matched, err)
+	}
+	if matched, err := matchesWithLabelSelector(poolCLabels, notInSelector); err != nil || matched {
+		t.Errorf("NotIn[c] should not match pool=c, got matched=%v err=%v", matched, err)
+	}
+
+	existsSelector := &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "dedicated", Operator: metav1.LabelSelectorOpExists},
+		},
+	}
+	if matched, err := matchesWithLabelSelector(poolALabels, existsSelector); err != nil || !matched {
+		t.Errorf("Exists[dedicated] should match a node carrying the label, got matched=%v err=%v", matched, err)
+	}
+	if matched, err := matchesWithLabelSelector(poolBLabels, existsSelector); err != nil || matched {
+		t.Errorf("Exists[dedicated] should not match a node missing the label, got matched=%v err=%v", matched, err)
+	}
+}
+
+func TestRecordCreationDuration(t *testing.T) {
+	start := metav1.NewTime(time.Now().Add(-5 * time.Minute))
+	vc := &v1alpha1.VirtualCluster{Status: v1alpha1.VirtualClusterStatus{CreationStartTime: &start}}
+
+	recordCreationDuration(vc, start.Add(5*time.Minute))
+
+	got, err := time.ParseDuration(vc.Status.CreationDuration)
+	if err != nil {
+		t.Fatalf("CreationDuration %q is not a valid duration: %v", vc.Status.CreationDuration, err)
+	}
+	if got != 5*time.Minute {
+		t.Fatalf("expected creation duration of 5m0s, got %s", got)
+	}
+}
+
+func TestRecordCreationDurationRecreation(t *testing.T) {
+	// Simulate a VirtualCluster that already completed once carrying a stale
+	// CreationDuration, recreated with a status that has not re-entered
+	// Preparing yet (no CreationStartTime). The stale value must be left
+	// untouched until the new incarnation sets a fresh start time.
+	vc := &v1alpha1.VirtualCluster{Status: v1alpha1.VirtualClusterStatus{CreationDuration: "5m0s"}}
+
+	recordCreationDuration(vc, time.Now())
+
+	if vc.Status.CreationDuration != "5m0s" {
+		t.Fatalf("expected CreationDuration to be left untouched until the cluster re-enters Preparing, got %q", vc.Status.CreationDuration)
+	}
+}
+
+func TestNetxFunc(_ *testing.T) {
+	portsPool := []int32{1, 2, 3, 4, 5}
+	type nextfunc func() (int32, error)
+	// var next nextfunc
+	next := func() nextfunc {
+		i := 0
+		return func() (int32, error) {
+			if i >= len(portsPool) {
+				return 0, fmt.Errorf("no available ports")
+			}
+			port := portsPool[i]
+			i++
+			return port, nil
+		}
+	}()
+
+	for p, err := next(); err == nil; p, err = next() {
+		fmt.Printf("port: %d\n", p)
+	}
+}
+
+func TestAssignWorkNodesDryRunDoesNotMutateGlobalNodes(t *testing.T) {
+	freeNode := v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+		Spec:       v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeFreeState},
+	}
+	kosmosClient := kosmosfake.NewSimpleClientset(&freeNode)
+
+	c := &VirtualClusterInitController{Client: &virtualClusterListerClient{}, KosmosClient: kosmosClient}
+	virtualCluster := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "dry-run-vc"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			DryRun: true,
+			PromotePolicies: []v1alpha1.PromotePolicy{
+				{NodeCount: 1},
+			},
+		},
+	}
+
+	if _, err := c.assignWorkNodes(context.TODO(), virtualCluster); err != nil {
+		t.Fatalf("assignWorkNodes() error = %v", err)
+	}
+
+	if len(virtualCluster.Status.PlannedNodes) != 1 || virtualCluster.Status.PlannedNodes[0].NodeName != "node-a" {
+		t.Fatalf("expected Status.PlannedNodes to contain node-a, got %v", virtualCluster.Status.PlannedNodes)
+	}
+	if len(virtualCluster.Spec.PromoteResources.NodeInfos) != 0 {
+		t.Errorf("expected Spec.PromoteResources.NodeInfos to stay empty in dry-run, got %v", virtualCluster.Spec.PromoteResources.NodeInfos)
+	}
+
+	updated, err := kosmosClient.KosmosV1alpha1().GlobalNodes().Get(context.TODO(), "node-a", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get(node-a) error = %v", err)
+	}
+	if updated.Spec.State != v1alpha1.NodeFreeState {
+		t.Errorf("expected node-a to remain %q after dry-run, got %q", v1alpha1.NodeFreeState, updated.Spec.State)
+	}
+}
+
+func TestAssignWorkNodesReleasesDriftedNode(t *testing.T) {
+	driftedNode := v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-drifted"},
+		Spec: v1alpha1.GlobalNodeSpec{
+			State:  v1alpha1.NodeInUse,
+			Labels: labels.Set{"pool": "other"},
+		},
+		Status: v1alpha1.GlobalNodeStatus{VirtualCluster: "drift-vc"},
+	}
+	replacementNode := v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-replacement"},
+		Spec:       v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeFreeState, Labels: labels.Set{"pool": "tenant"}},
+	}
+	kosmosClient := kosmosfake.NewSimpleClientset(&driftedNode, &replacementNode)
+
+	c := &VirtualClusterInitController{Client: &virtualClusterListerClient{}, KosmosClient: kosmosClient}
+	virtualCluster := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "drift-vc"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			PromotePolicies: []v1alpha1.PromotePolicy{
+				{
+					LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "tenant"}},
+					NodeCount:     1,
+				},
+			},
+			PromoteResources: v1alpha1.PromoteResources{
+				NodeInfos: []v1alpha1.NodeInfo{{NodeName: "node-drifted"}},
+			},
+		},
+	}
+
+	if _, err := c.assignWorkNodes(context.TODO(), virtualCluster); err != nil {
+		t.Fatalf("assignWorkNodes() error = %v", err)
+	}
+
+	if len(virtualCluster.Spec.PromoteResources.NodeInfos) != 1 || virtualCluster.Spec.PromoteResources.NodeInfos[0].NodeName != "node-replacement" {
+		t.Fatalf("expected node-drifted to be replaced by node-replacement, got %v", virtualCluster.Spec.PromoteResources.NodeInfos)
+	}
+
+	released, err := kosmosClient.KosmosV1alpha1().GlobalNodes().Get(context.TODO(), "node-drifted", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get(node-drifted) error = %v", err)
+	}
+	if released.Spec.State != v1alpha1.NodeFreeState {
+		t.Errorf("expected drifted node to be released back to %q, got %q", v1alpha1.NodeFreeState, released.Spec.State)
+	}
+	if released.Status.VirtualCluster != "" {
+		t.Errorf("expected drifted node's claiming virtualcluster to be cleared, got %q", released.Status.VirtualCluster)
+	}
+}
+
+func TestAssignWorkNodesKeepsRelabeledNodeWithRecordedOwnership(t *testing.T) {
+	policySelector := &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "tenant"}}
+	relabeledNode := v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-relabeled"},
+		Spec: v1alpha1.GlobalNodeSpec{
+			State:  v1alpha1.NodeInUse,
+			Labels: labels.Set{"pool": "other"},
+		},
+		Status: v1alpha1.GlobalNodeStatus{VirtualCluster: "owned-vc"},
+	}
+	// A free node that still matches the policy's selector, so the node
+	// count is satisfiable; it should stay free since node-relabeled already
+	// covers the policy's NodeCount via its recorded ownership.
+	candidateNode := v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-candidate"},
+		Spec:       v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeFreeState, Labels: labels.Set{"pool": "tenant"}},
+	}
+	kosmosClient := kosmosfake.NewSimpleClientset(&relabeledNode, &candidateNode)
+
+	c := &VirtualClusterInitController{Client: &virtualClusterListerClient{}, KosmosClient: kosmosClient}
+	virtualCluster := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "owned-vc"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			PromotePolicies: []v1alpha1.PromotePolicy{
+				{LabelSelector: policySelector, NodeCount: 1},
+			},
+			PromoteResources: v1alpha1.PromoteResources{
+				NodeInfos: []v1alpha1.NodeInfo{{NodeName: "node-relabeled", PromotePolicy: policySelector}},
+			},
+		},
+	}
+
+	if _, err := c.assignWorkNodes(context.TODO(), virtualCluster); err != nil {
+		t.Fatalf("assignWorkNodes() error = %v", err)
+	}
+
+	if len(virtualCluster.Spec.PromoteResources.NodeInfos) != 1 || virtualCluster.Spec.PromoteResources.NodeInfos[0].NodeName != "node-relabeled" {
+		t.Fatalf("expected node-relabeled to stay assigned despite its label drift, got %v", virtualCluster.Spec.PromoteResources.NodeInfos)
+	}
+
+	node, err := kosmosClient.KosmosV1alpha1().GlobalNodes().Get(context.TODO(), "node-relabeled", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get(node-relabeled) error = %v", err)
+	}
+	if node.Spec.State != v1alpha1.NodeInUse {
+		t.Errorf("expected relabeled node to remain %q, got %q", v1alpha1.NodeInUse, node.Spec.State)
+	}
+
+	candidate, err := kosmosClient.KosmosV1alpha1().GlobalNodes().Get(context.TODO(), "node-candidate", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get(node-candidate) error = %v", err)
+	}
+	if candidate.Spec.State != v1alpha1.NodeFreeState {
+		t.Errorf("expected node-candidate to remain free since node-relabeled still covers the policy's NodeCount, got %q", candidate.Spec.State)
+	}
+}
+
+func TestAssignWorkNodesPreemptsLowerPriorityVictimWhenNodesScarce(t *testing.T) {
+	node := v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+		Spec:       v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeInUse},
+		Status:     v1alpha1.GlobalNodeStatus{VirtualCluster: "victim-vc"},
+	}
+	victim := v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "victim-vc", Namespace: "default"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			Priority:         0,
+			PromoteResources: v1alpha1.PromoteResources{NodeInfos: []v1alpha1.NodeInfo{{NodeName: "node-a"}}},
+		},
+	}
+	kosmosClient := kosmosfake.NewSimpleClientset(&node, &victim)
+	c := &VirtualClusterInitController{Client: &virtualClusterListerClient{}, KosmosClient: kosmosClient}
+	preemptor := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "preemptor-vc", Namespace: "default"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			Priority:         10,
+			EnablePreemption: true,
+			PromotePolicies:  []v1alpha1.PromotePolicy{{NodeCount: 1}},
+		},
+	}
+
+	if _, err := c.assignWorkNodes(context.TODO(), preemptor); err != nil {
+		t.Fatalf("assignWorkNodes() error = %v", err)
+	}
+
+	if len(preemptor.Spec.PromoteResources.NodeInfos) != 1 || preemptor.Spec.PromoteResources.NodeInfos[0].NodeName != "node-a" {
+		t.Fatalf("expected preemptor to claim node-a, got %v", preemptor.Spec.PromoteResources.NodeInfos)
+	}
+
+	updatedVictim, err := kosmosClient.KosmosV1alpha1().VirtualClusters("default").Get(context.TODO(), "victim-vc", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get(victim-vc) error = %v", err)
+	}
+	if len(updatedVictim.Spec.PromoteResources.NodeInfos) != 0 {
+		t.Errorf("expected node-a to be removed from victim-vc's NodeInfos, got %v", updatedVictim.Spec.PromoteResources.NodeInfos)
+	}
+
+	updatedNode, err := kosmosClient.KosmosV1alpha1().GlobalNodes().Get(context.TODO(), "node-a", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get(node-a) error = %v", err)
+	}
+	if updatedNode.Spec.State != v1alpha1.NodeInUse || updatedNode.Status.VirtualCluster != "preemptor-vc" {
+		t.Errorf("expected node-a to end up claimed by preemptor-vc, got state=%q owner=%q", updatedNode.Spec.State, updatedNode.Status.VirtualCluster)
+	}
+}
+
+func TestAssignWorkNodesDoesNotPreemptWithoutOptIn(t *testing.T) {
+	node := v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+		Spec:       v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeInUse},
+		Status:     v1alpha1.GlobalNodeStatus{VirtualCluster: "victim-vc"},
+	}
+	victim := v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "victim-vc", Namespace: "default"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			PromoteResources: v1alpha1.PromoteResources{NodeInfos: []v1alpha1.NodeInfo{{NodeName: "node-a"}}},
+		},
+	}
+	kosmosClient := kosmosfake.NewSimpleClientset(&node, &victim)
+	c := &VirtualClusterInitController{Client: &virtualClusterListerClient{}, KosmosClient: kosmosClient}
+	preemptor := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "preemptor-vc", Namespace: "default"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			Priority:        10,
+			PromotePolicies: []v1alpha1.PromotePolicy{{NodeCount: 1}},
+		},
+	}
+
+	if _, err := c.assignWorkNodes(context.TODO(), preemptor); err == nil {
+		t.Fatal("expected assignWorkNodes() to error without EnablePreemption set and no free nodes available")
+	}
+
+	updatedNode, err := kosmosClient.KosmosV1alpha1().GlobalNodes().Get(context.TODO(), "node-a", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get(node-a) error = %v", err)
+	}
+	if updatedNode.Spec.State != v1alpha1.NodeInUse || updatedNode.Status.VirtualCluster != "victim-vc" {
+		t.Errorf("expected node-a to remain untouched, got state=%q owner=%q", updatedNode.Spec.State, updatedNode.Status.VirtualCluster)
+	}
+}
+
+func TestAssignWorkNodesDoesNotPreemptEqualOrHigherPriorityVictim(t *testing.T) {
+	node := v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+		Spec:       v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeInUse},
+		Status:     v1alpha1.GlobalNodeStatus{VirtualCluster: "victim-vc"},
+	}
+	victim := v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "victim-vc", Namespace: "default"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			Priority:         10,
+			PromoteResources: v1alpha1.PromoteResources{NodeInfos: []v1alpha1.NodeInfo{{NodeName: "node-a"}}},
+		},
+	}
+	kosmosClient := kosmosfake.NewSimpleClientset(&node, &victim)
+	c := &VirtualClusterInitController{Client: &virtualClusterListerClient{}, KosmosClient: kosmosClient}
+	preemptor := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "preemptor-vc", Namespace: "default"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			Priority:         10,
+			EnablePreemption: true,
+			PromotePolicies:  []v1alpha1.PromotePolicy{{NodeCount: 1}},
+		},
+	}
+
+	if _, err := c.assignWorkNodes(context.TODO(), preemptor); err == nil {
+		t.Fatal("expected assignWorkNodes() to error when the only candidate belongs to an equal-priority victim")
+	}
+
+	updatedVictim, err := kosmosClient.KosmosV1alpha1().VirtualClusters("default").Get(context.TODO(), "victim-vc", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get(victim-vc) error = %v", err)
+	}
+	if len(updatedVictim.Spec.PromoteResources.NodeInfos) != 1 {
+		t.Errorf("expected node-a to remain assigned to victim-vc, got %v", updatedVictim.Spec.PromoteResources.NodeInfos)
+	}
+}
+
+func TestAssignWorkNodesFiltersOutNotReadyFreeNodeWhenRequireNodeReadyEnabled(t *testing.T) {
+	notReadyNode := v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-not-ready"},
+		Spec:       v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeFreeState},
+	}
+	kosmosClient := kosmosfake.NewSimpleClientset(&notReadyNode)
+	rootClient := fake.NewSimpleClientset(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-not-ready"},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionFalse}},
+		},
+	})
+	c := &VirtualClusterInitController{
+		Client:        &virtualClusterListerClient{},
+		KosmosClient:  kosmosClient,
+		RootClientSet: rootClient,
+		KubeNestOptions: &v1alpha1.KubeNestConfiguration{
+			KubeInKubeConfig: v1alpha1.KubeInKubeConfig{RequireNodeReady: true},
+		},
+	}
+	virtualCluster := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1", Namespace: "default"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			PromotePolicies: []v1alpha1.PromotePolicy{{NodeCount: 1}},
+		},
+	}
+
+	_, err := c.assignWorkNodes(context.TODO(), virtualCluster)
+	if err == nil {
+		t.Fatal("expected assignWorkNodes() to error when the only free node isn't Ready")
+	}
+	if !strings.Contains(err.Error(), "filtered out as not Ready") {
+		t.Errorf("expected error to mention readiness filtering, got %q", err.Error())
+	}
+}
+
+func TestAssignWorkNodesIgnoresReadinessWhenRequireNodeReadyDisabled(t *testing.T) {
+	notReadyNode := v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-not-ready"},
+		Spec:       v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeFreeState},
+	}
+	kosmosClient := kosmosfake.NewSimpleClientset(&notReadyNode)
+	rootClient := fake.NewSimpleClientset(&corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-not-ready"},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionFalse}},
+		},
+	})
+	c := &VirtualClusterInitController{Client: &virtualClusterListerClient{}, KosmosClient: kosmosClient, RootClientSet: rootClient}
+	virtualCluster := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1", Namespace: "default"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			PromotePolicies: []v1alpha1.PromotePolicy{{NodeCount: 1}},
+		},
+	}
+
+	if _, err := c.assignWorkNodes(context.TODO(), virtualCluster); err != nil {
+		t.Fatalf("assignWorkNodes() error = %v, want nil with RequireNodeReady left disabled", err)
+	}
+}
+
+func TestDetectNodeOwnershipConflictsFindsDoubleOwnedNode(t *testing.T) {
+	virtualCluster := &v1alpha1.VirtualCluster{ObjectMeta: metav1.ObjectMeta{Name: "vc-b", Namespace: "default"}}
+	nodeInfos := []v1alpha1.NodeInfo{{NodeName: "node-a"}}
+	globalNodes := []v1alpha1.GlobalNode{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+			Status:     v1alpha1.GlobalNodeStatus{VirtualCluster: "vc-a"},
+		},
+	}
+	otherClusters := []v1alpha1.VirtualCluster{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "vc-a", Namespace: "default"},
+			Spec: v1alpha1.VirtualClusterSpec{
+				PromoteResources: v1alpha1.PromoteResources{NodeInfos: []v1alpha1.NodeInfo{{NodeName: "node-a"}}},
+			},
+		},
+	}
+
+	conflicts := detectNodeOwnershipConflicts(virtualCluster, nodeInfos, globalNodes, otherClusters)
+	if len(conflicts) != 1 {
+		t.Fatalf("expected exactly one conflict, got %v", conflicts)
+	}
+}
+
+func TestDetectNodeOwnershipConflictsIgnoresReleasedNode(t *testing.T) {
+	virtualCluster := &v1alpha1.VirtualCluster{ObjectMeta: metav1.ObjectMeta{Name: "vc-b", Namespace: "default"}}
+	nodeInfos := []v1alpha1.NodeInfo{{NodeName: "node-a"}}
+	globalNodes := []v1alpha1.GlobalNode{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+			Status:     v1alpha1.GlobalNodeStatus{VirtualCluster: "vc-a"},
+		},
+	}
+	// vc-a no longer lists node-a: its Status.VirtualCluster is merely stale,
+	// not a real double-ownership conflict.
+	otherClusters := []v1alpha1.VirtualCluster{
+		{ObjectMeta: metav1.ObjectMeta{Name: "vc-a", Namespace: "default"}},
+	}
+
+	if conflicts := detectNodeOwnershipConflicts(virtualCluster, nodeInfos, globalNodes, otherClusters); len(conflicts) != 0 {
+		t.Errorf("expected no conflicts, got %v", conflicts)
+	}
+}
+
+func TestAssignWorkNodesReportsNodeOwnershipConflict(t *testing.T) {
+	node := v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+		Spec:       v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeFreeState},
+		Status:     v1alpha1.GlobalNodeStatus{VirtualCluster: "vc-a"},
+	}
+	kosmosClient := kosmosfake.NewSimpleClientset(&node)
+	c := &VirtualClusterInitController{
+		KosmosClient: kosmosClient,
+		Client: &virtualClusterListerClient{
+			vcList: v1alpha1.VirtualClusterList{
+				Items: []v1alpha1.VirtualCluster{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "vc-a", Namespace: "default"},
+						Spec: v1alpha1.VirtualClusterSpec{
+							PromoteResources: v1alpha1.PromoteResources{NodeInfos: []v1alpha1.NodeInfo{{NodeName: "node-a"}}},
+						},
+					},
+				},
+			},
+		},
+	}
+	virtualCluster := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc-b", Namespace: "default"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			PromotePolicies: []v1alpha1.PromotePolicy{{NodeCount: 1}},
+		},
+	}
+
+	if _, err := c.assignWorkNodes(context.TODO(), virtualCluster); err == nil {
+		t.Fatal("expected assignWorkNodes() to error on a double-owned node")
+	}
+
+	condition := meta.FindStatusCondition(virtualCluster.Status.Conditions, v1alpha1.NodeOwnershipConflictConditionType)
+	if condition == nil || condition.Status != metav1.ConditionTrue {
+		t.Errorf("expected %s condition to be True, got %v", v1alpha1.NodeOwnershipConflictConditionType, condition)
+	}
+}
+
+func TestAssignWorkNodesRejectsWhenOverNamespaceQuota(t *testing.T) {
+	node := v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+		Spec:       v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeFreeState},
+	}
+	kosmosClient := kosmosfake.NewSimpleClientset(&node)
+	rootClient := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: constants.NamespaceNodeQuotaConfigMapName, Namespace: constants.KosmosNs},
+		Data:       map[string]string{"default": "0"},
+	})
+	c := &VirtualClusterInitController{
+		Client:        &virtualClusterListerClient{},
+		KosmosClient:  kosmosClient,
+		RootClientSet: rootClient,
+	}
+	virtualCluster := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc-a", Namespace: "default"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			PromotePolicies: []v1alpha1.PromotePolicy{{NodeCount: 1}},
+		},
+	}
+
+	if _, err := c.assignWorkNodes(context.TODO(), virtualCluster); err == nil {
+		t.Fatal("expected assignWorkNodes() to error when the namespace quota is exceeded")
+	}
+
+	condition := meta.FindStatusCondition(virtualCluster.Status.Conditions, v1alpha1.NodesAssignedConditionType)
+	if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "QuotaExceeded" {
+		t.Errorf("expected %s condition to be False/QuotaExceeded, got %v", v1alpha1.NodesAssignedConditionType, condition)
+	}
+
+	updated, err := kosmosClient.KosmosV1alpha1().GlobalNodes().Get(context.TODO(), "node-a", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get(node-a) error = %v", err)
+	}
+	if updated.Spec.State != v1alpha1.NodeFreeState {
+		t.Errorf("expected node-a to remain %q when quota rejects the assignment, got %q", v1alpha1.NodeFreeState, updated.Spec.State)
+	}
+}
+
+func TestAssignWorkNodesAllowsWhenNamespaceHasNoQuotaConfigured(t *testing.T) {
+	node := v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+		Spec:       v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeFreeState},
+	}
+	kosmosClient := kosmosfake.NewSimpleClientset(&node)
+	rootClient := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: constants.NamespaceNodeQuotaConfigMapName, Namespace: constants.KosmosNs},
+		Data:       map[string]string{"other-namespace": "0"},
+	})
+	c := &VirtualClusterInitController{
+		Client:        &virtualClusterListerClient{},
+		KosmosClient:  kosmosClient,
+		RootClientSet: rootClient,
+	}
+	virtualCluster := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc-a", Namespace: "default"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			PromotePolicies: []v1alpha1.PromotePolicy{{NodeCount: 1}},
+		},
+	}
+
+	if _, err := c.assignWorkNodes(context.TODO(), virtualCluster); err != nil {
+		t.Fatalf("assignWorkNodes() error = %v", err)
+	}
+	if len(virtualCluster.Spec.PromoteResources.NodeInfos) != 1 {
+		t.Errorf("expected 1 assigned node, got %v", virtualCluster.Spec.PromoteResources.NodeInfos)
+	}
+}
+
+func TestGetNamespaceNodeQuotaRejectsNonIntegerValue(t *testing.T) {
+	rootClient := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: constants.NamespaceNodeQuotaConfigMapName, Namespace: constants.KosmosNs},
+		Data:       map[string]string{"default": "not-a-number"},
+	})
+
+	if _, _, err := GetNamespaceNodeQuota(rootClient, "default"); err == nil {
+		t.Fatal("expected GetNamespaceNodeQuota() to error on a non-integer quota value")
+	}
+}
+
+func TestGetNamespaceNodeQuotaNotFoundWhenConfigMapMissing(t *testing.T) {
+	rootClient := fake.NewSimpleClientset()
+
+	quota, found, err := GetNamespaceNodeQuota(rootClient, "default")
+	if err != nil {
+		t.Fatalf("GetNamespaceNodeQuota() error = %v", err)
+	}
+	if found {
+		t.Errorf("expected found = false when the ConfigMap doesn't exist, got quota = %d", quota)
+	}
+}
+
+func TestReleaseClaimedNodesRevertsOnlyClaimedNodes(t *testing.T) {
+	claimedNode := v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-claimed"},
+		Spec:       v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeInUse},
+		Status:     v1alpha1.GlobalNodeStatus{VirtualCluster: "rollback-vc"},
+	}
+	otherNode := v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-other"},
+		Spec:       v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeInUse},
+		Status:     v1alpha1.GlobalNodeStatus{VirtualCluster: "other-vc"},
+	}
+	kosmosClient := kosmosfake.NewSimpleClientset(&claimedNode, &otherNode)
+	c := &VirtualClusterInitController{KosmosClient: kosmosClient}
+
+	c.releaseClaimedNodes([]string{"node-claimed", "node-missing"})
+
+	released, err := kosmosClient.KosmosV1alpha1().GlobalNodes().Get(context.TODO(), "node-claimed", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get(node-claimed) error = %v", err)
+	}
+	if released.Spec.State != v1alpha1.NodeFreeState {
+		t.Errorf("expected claimed node to be released back to %q, got %q", v1alpha1.NodeFreeState, released.Spec.State)
+	}
+	if released.Status.VirtualCluster != "" {
+		t.Errorf("expected claimed node's claiming virtualcluster to be cleared, got %q", released.Status.VirtualCluster)
+	}
+
+	untouched, err := kosmosClient.KosmosV1alpha1().GlobalNodes().Get(context.TODO(), "node-other", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get(node-other) error = %v", err)
+	}
+	if untouched.Spec.State != v1alpha1.NodeInUse || untouched.Status.VirtualCluster != "other-vc" {
+		t.Errorf("expected node-other to be untouched, got state=%q virtualCluster=%q", untouched.Spec.State, untouched.Status.VirtualCluster)
+	}
+}
+
+func TestRecordReconcileFailureRaisesDegradedAfterThreshold(t *testing.T) {
+	c := &VirtualClusterInitController{
+		KubeNestOptions: &v1alpha1.KubeNestConfiguration{
+			KubeInKubeConfig: v1alpha1.KubeInKubeConfig{ReconcileFailureThreshold: "10m"},
+		},
+	}
+	vc := &v1alpha1.VirtualCluster{ObjectMeta: metav1.ObjectMeta{Name: "flaky-vc"}}
+	start := time.Now()
+
+	c.recordReconcileFailure(vc, start)
+	if meta.IsStatusConditionTrue(vc.Status.Conditions, v1alpha1.DegradedConditionType) {
+		t.Fatalf("expected Degraded to stay unset before the threshold elapses")
+	}
+
+	c.recordReconcileFailure(vc, start.Add(15*time.Minute))
+	if !meta.IsStatusConditionTrue(vc.Status.Conditions, v1alpha1.DegradedConditionType) {
+		t.Fatalf("expected Degraded=True after the cluster has been Pending past the threshold, got %v", vc.Status.Conditions)
+	}
+
+	c.recordReconcileSuccess(vc)
+	if meta.IsStatusConditionTrue(vc.Status.Conditions, v1alpha1.DegradedConditionType) {
+		t.Fatalf("expected Degraded to clear once the cluster reconciles successfully, got %v", vc.Status.Conditions)
+	}
+	if vc.Status.FailureSince != nil {
+		t.Errorf("expected FailureSince to be cleared on success, got %v", vc.Status.FailureSince)
+	}
+}
+
+func TestIsVirtualClusterPaused(t *testing.T) {
+	tests := []struct {
+		name string
+		vc   *v1alpha1.VirtualCluster
+		want bool
+	}{
+		{name: "neither set", vc: &v1alpha1.VirtualCluster{}, want: false},
+		{
+			name: "spec paused",
+			vc:   &v1alpha1.VirtualCluster{Spec: v1alpha1.VirtualClusterSpec{Paused: true}},
+			want: true,
+		},
+		{
+			name: "annotation paused",
+			vc:   &v1alpha1.VirtualCluster{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{constants.PausedAnnotation: "true"}}},
+			want: true,
+		},
+		{
+			name: "annotation overrides spec",
+			vc: &v1alpha1.VirtualCluster{
+				ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{constants.PausedAnnotation: "false"}},
+				Spec:       v1alpha1.VirtualClusterSpec{Paused: true},
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isVirtualClusterPaused(tt.vc); got != tt.want {
+				t.Errorf("isVirtualClusterPaused() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadinessTimeout(t *testing.T) {
+	seconds := int32(120)
+	tests := []struct {
+		name string
+		vc   *v1alpha1.VirtualCluster
+		want time.Duration
+	}{
+		{
+			name: "unset falls back to the default",
+			vc:   &v1alpha1.VirtualCluster{},
+			want: constants.WaitAllPodsRunningTimeoutSeconds * time.Second,
+		},
+		{
+			name: "explicit override is honored",
+			vc:   &v1alpha1.VirtualCluster{Spec: v1alpha1.VirtualClusterSpec{ReadinessTimeoutSeconds: &seconds}},
+			want: 120 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := readinessTimeout(tt.vc); got != tt.want {
+				t.Errorf("readinessTimeout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcileBackoff(t *testing.T) {
+	tests := []struct {
+		failureCount int32
+		want         time.Duration
+	}{
+		{failureCount: 0, want: 10 * time.Second},
+		{failureCount: 1, want: 20 * time.Second},
+		{failureCount: 2, want: 40 * time.Second},
+		{failureCount: 10, want: 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := reconcileBackoff(tt.failureCount); got != tt.want {
+			t.Errorf("reconcileBackoff(%d) = %v, want %v", tt.failureCount, got, tt.want)
+		}
+	}
+}
+
+func TestJitteredRequeueTimeWithinBounds(t *testing.T) {
+	min := RequeueTime / 2
+	max := RequeueTime * 3 / 2
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 100; i++ {
+		got := jitteredRequeueTime()
+		if got < min || got >= max {
+			t.Fatalf("jitteredRequeueTime() = %v, want in [%v, %v)", got, min, max)
+		}
+		seen[got] = true
+	}
+	if len(seen) == 1 {
+		t.Error("jitteredRequeueTime() returned the same value every time, expected randomization")
+	}
+}
+
+func TestIsPhaseStuck(t *testing.T) {
+	now := time.Now()
+
+	noUpdateTime := &v1alpha1.VirtualCluster{}
+	if isPhaseStuck(noUpdateTime, now) {
+		t.Error("expected a VirtualCluster with no UpdateTime to not be considered stuck")
+	}
+
+	recentlyUpdated := &v1alpha1.VirtualCluster{Status: v1alpha1.VirtualClusterStatus{
+		UpdateTime: &metav1.Time{Time: now.Add(-time.Minute)},
+	}}
+	if isPhaseStuck(recentlyUpdated, now) {
+		t.Error("expected a recently-updated VirtualCluster to not be considered stuck")
+	}
+
+	staleUpdate := &v1alpha1.VirtualCluster{Status: v1alpha1.VirtualClusterStatus{
+		UpdateTime: &metav1.Time{Time: now.Add(-constants.StuckPhaseTimeout - time.Minute)},
+	}}
+	if !isPhaseStuck(staleUpdate, now) {
+		t.Error("expected a VirtualCluster whose status hasn't updated in over StuckPhaseTimeout to be considered stuck")
+	}
+}
+
+func TestWorkersCompletionPhase(t *testing.T) {
+	scaledDown := &v1alpha1.VirtualCluster{}
+	if got := workersCompletionPhase(scaledDown); got != v1alpha1.WorkersScaledDown {
+		t.Errorf("workersCompletionPhase() with no assigned nodes = %q, want %q", got, v1alpha1.WorkersScaledDown)
+	}
+
+	withWorkers := &v1alpha1.VirtualCluster{Spec: v1alpha1.VirtualClusterSpec{
+		PromoteResources: v1alpha1.PromoteResources{NodeInfos: []v1alpha1.NodeInfo{{NodeName: "node-a"}}},
+	}}
+	if got := workersCompletionPhase(withWorkers); got != v1alpha1.Completed {
+		t.Errorf("workersCompletionPhase() with assigned nodes = %q, want %q", got, v1alpha1.Completed)
+	}
+}
+
+func TestRecordReconcileFailureTracksFailureCount(t *testing.T) {
+	c := &VirtualClusterInitController{}
+	vc := &v1alpha1.VirtualCluster{ObjectMeta: metav1.ObjectMeta{Name: "flaky-vc"}}
+	now := time.Now()
+
+	c.recordReconcileFailure(vc, now)
+	c.recordReconcileFailure(vc, now.Add(time.Minute))
+	if vc.Status.FailureCount != 2 {
+		t.Fatalf("FailureCount = %d, want 2", vc.Status.FailureCount)
+	}
+	if vc.Status.LastFailureTime == nil {
+		t.Fatal("expected LastFailureTime to be set")
+	}
+
+	c.recordReconcileSuccess(vc)
+	if vc.Status.FailureCount != 0 {
+		t.Errorf("expected FailureCount to reset to 0 on success, got %d", vc.Status.FailureCount)
+	}
+	if vc.Status.LastFailureTime != nil {
+		t.Errorf("expected LastFailureTime to be cleared on success, got %v", vc.Status.LastFailureTime)
+	}
+}
+
+func TestDeriveReasonFromConditionsPicksMostRecentFailure(t *testing.T) {
+	vc := &v1alpha1.VirtualCluster{}
+
+	setNodesAssignedCondition(vc, metav1.ConditionFalse, "InsufficientNodes", "not enough nodes")
+	deriveReasonFromConditions(vc, "fallback")
+	if vc.Status.Reason != "not enough nodes" {
+		t.Fatalf("Status.Reason = %q, want %q", vc.Status.Reason, "not enough nodes")
+	}
+
+	// ControlPlaneReady fails after NodesAssigned, so it should win even
+	// though NodesAssigned is still False.
+	setControlPlaneReadyCondition(vc, metav1.ConditionFalse, "ExecutionFailed", "apiserver crashed")
+	deriveReasonFromConditions(vc, "fallback")
+	if vc.Status.Reason != "apiserver crashed" {
+		t.Fatalf("Status.Reason = %q, want the most recently failed condition's message", vc.Status.Reason)
+	}
+
+	setNodesAssignedCondition(vc, metav1.ConditionTrue, "Assigned", "assigned 3 nodes")
+	setControlPlaneReadyCondition(vc, metav1.ConditionTrue, "ExecutionSucceeded", "control plane components executed successfully")
+	deriveReasonFromConditions(vc, "fallback")
+	if vc.Status.Reason != "fallback" {
+		t.Fatalf("Status.Reason = %q, want fallback once every tracked condition is True", vc.Status.Reason)
+	}
+}
+
+func TestParseReadinessExclusionsEmptyAnnotationsPreserveDefaultBehavior(t *testing.T) {
+	vc := &v1alpha1.VirtualCluster{}
+
+	skipNamespaces, skipWorkloads := parseReadinessExclusions(vc)
+
+	if len(skipNamespaces) != 0 || len(skipWorkloads) != 0 {
+		t.Fatalf("expected no exclusions absent annotations, got namespaces=%v workloads=%v", skipNamespaces, skipWorkloads)
+	}
+}
+
+func TestParseReadinessExclusions(t *testing.T) {
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				"kosmos.io/readiness-skip-namespaces": "monitoring, logging,",
+				"kosmos.io/readiness-skip-workloads":  "Job/kube-system/migrate,Deployment/monitoring/optional-addon",
+			},
+		},
+	}
+
+	skipNamespaces, skipWorkloads := parseReadinessExclusions(vc)
+
+	for _, ns := range []string{"monitoring", "logging"} {
+		if !skipNamespaces[ns] {
+			t.Errorf("expected namespace %q to be excluded, got %v", ns, skipNamespaces)
+		}
+	}
+	if len(skipNamespaces) != 2 {
+		t.Errorf("expected exactly 2 excluded namespaces, got %v", skipNamespaces)
+	}
+
+	if !skipWorkloads[workloadExclusionKey("Deployment", "monitoring", "optional-addon")] {
+		t.Errorf("expected optional-addon deployment to be excluded, got %v", skipWorkloads)
+	}
+	if len(skipWorkloads) != 2 {
+		t.Errorf("expected exactly 2 excluded workloads, got %v", skipWorkloads)
+	}
+}
+
+func TestRemainingTimeoutSurvivesMinuteBoundary(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 58, 0, time.UTC)
+	timeout := 30 * time.Second
+
+	remaining, ok := remainingTimeout(start, start, timeout)
+	if !ok || remaining != timeout {
+		t.Fatalf("expected full budget remaining at start, got %v ok=%v", remaining, ok)
+	}
+
+	// now crosses the 0-59 second-of-minute boundary that time.Now().Second()
+	// would have wrapped on; the real elapsed time is still only 20s.
+	now := start.Add(20 * time.Second)
+	remaining, ok = remainingTimeout(now, start, timeout)
+	if !ok || remaining != 10*time.Second {
+		t.Fatalf("expected 10s remaining after crossing the minute boundary, got %v ok=%v", remaining, ok)
+	}
+
+	now = start.Add(35 * time.Second)
+	if remaining, ok = remainingTimeout(now, start, timeout); ok {
+		t.Fatalf("expected the budget to be exhausted after the full timeout elapsed, got %v ok=%v", remaining, ok)
+	}
+}
+
+func TestPollNamespacesReadyRunsNamespacesConcurrently(t *testing.T) {
+	// Every namespace's first deployment-list comes back not-ready, forcing
+	// waitForNamespacePodsReady to sit through one extra 5s poll interval
+	// before it settles, about 10s per namespace since the first check itself
+	// comes one interval in. If namespaces were still polled serially, 3
+	// namespaces would take roughly 3*10s; polled concurrently, they wait
+	// together and the whole call takes roughly 10s, under the 3*5s bound.
+	slowNamespaces := []string{"ns-a", "ns-b", "ns-c"}
+
+	var mu sync.Mutex
+	attempts := map[string]int{}
+
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("list", "deployments", func(action ktesting.Action) (bool, runtime.Object, error) {
+		mu.Lock()
+		attempts[action.GetNamespace()]++
+		attempt := attempts[action.GetNamespace()]
+		mu.Unlock()
+
+		if attempt == 1 {
+			return true, &appsv1.DeploymentList{Items: []appsv1.Deployment{
+				{Status: appsv1.DeploymentStatus{Replicas: 1, AvailableReplicas: 0}},
+			}}, nil
+		}
+		return true, &appsv1.DeploymentList{}, nil
+	})
+
+	var namespaces []corev1.Namespace
+	for _, ns := range slowNamespaces {
+		namespaces = append(namespaces, corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}})
+	}
+
+	start := time.Now()
+	err := pollNamespacesReady(context.TODO(), clientset, "vc1", namespaces, map[string]bool{}, map[string]bool{}, time.Minute)
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Fatalf("pollNamespacesReady() error = %v, want nil", err)
+	}
+	if serial := time.Duration(len(slowNamespaces)) * 5 * time.Second; elapsed >= serial {
+		t.Errorf("pollNamespacesReady() took %v, want well under %v if the namespaces were no longer serialized", elapsed, serial)
+	}
+}
+
+func TestEnqueuePendingClustersMatchingFreedNode(t *testing.T) {
+	pendingMatching := v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-b", Namespace: "default"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			PromotePolicies: []v1alpha1.PromotePolicy{
+				{LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}}, NodeCount: 1},
+			},
+		},
+		Status: v1alpha1.VirtualClusterStatus{Phase: v1alpha1.Pending},
+	}
+	pendingNotMatching := v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-c", Namespace: "default"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			PromotePolicies: []v1alpha1.PromotePolicy{
+				{LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "b"}}, NodeCount: 1},
+			},
+		},
+		Status: v1alpha1.VirtualClusterStatus{Phase: v1alpha1.Pending},
+	}
+	preparingMatching := v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-d", Namespace: "default"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			PromotePolicies: []v1alpha1.PromotePolicy{
+				{LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}}, NodeCount: 1},
+			},
+		},
+		Status: v1alpha1.VirtualClusterStatus{Phase: v1alpha1.Preparing},
+	}
+	completedMatching := v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-a", Namespace: "default"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			PromotePolicies: []v1alpha1.PromotePolicy{
+				{LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}}, NodeCount: 1},
+			},
+		},
+		Status: v1alpha1.VirtualClusterStatus{Phase: v1alpha1.Completed},
+	}
+
+	controller := &VirtualClusterInitController{
+		Client: &virtualClusterListerClient{
+			vcList: v1alpha1.VirtualClusterList{Items: []v1alpha1.VirtualCluster{pendingMatching, pendingNotMatching, preparingMatching, completedMatching}},
+		},
+	}
+
+	freedNode := v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Spec:       v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeFreeState, Labels: labels.Set{"zone": "a"}},
+	}
+
+	requests := controller.enqueuePendingClustersMatching(freedNode)
+
+	if len(requests) != 2 {
+		t.Fatalf("expected exactly two virtualclusters enqueued, got %v", requests)
+	}
+	var names []string
+	for _, r := range requests {
+		if r.Namespace != "default" {
+			t.Errorf("expected default namespace, got %v", r)
+		}
+		names = append(names, r.Name)
+	}
+	if !(names[0] == "cluster-b" && names[1] == "cluster-d") {
+		t.Fatalf("expected cluster-b and cluster-d to be enqueued, got %v", names)
+	}
+}
+
+// TestReconcilePendingRetriesImmediatelyAfterFreedNode exercises the
+// end-to-end effect of enqueuePendingClustersMatching's retry, not just its
+// return value: a Pending VirtualCluster that isn't yet isPhaseStuck should
+// still have its node assignment re-driven the moment a matching GlobalNode
+// frees up, instead of Reconcile bouncing it back out with
+// RequeueAfter: constants.StuckPhaseTimeout.
+func TestReconcilePendingRetriesImmediatelyAfterFreedNode(t *testing.T) {
+	freeNode := v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+		Spec:       v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeFreeState, Labels: labels.Set{"zone": "a"}},
+	}
+	kosmosClient := kosmosfake.NewSimpleClientset(&freeNode)
+
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1", Namespace: "default"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			PromotePolicies: []v1alpha1.PromotePolicy{
+				{LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}}, NodeCount: 1},
+			},
+			KubeInKubeConfig: &v1alpha1.KubeInKubeConfig{KubernetesVersion: "v1.27.6"},
+		},
+		Status: v1alpha1.VirtualClusterStatus{
+			Phase:      v1alpha1.Pending,
+			Port:       40010,
+			UpdateTime: &metav1.Time{Time: time.Now()},
+		},
+	}
+	ctrlClient := &inMemoryVirtualClusterClient{vc: vc}
+
+	adminKubeconfigSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: util.GetAdminConfigSecretName(vc.Name), Namespace: vc.Namespace},
+		Data:       map[string][]byte{constants.KubeConfig: fakeKubeconfig},
+	}
+
+	var executed int
+	c := &VirtualClusterInitController{
+		Client:        ctrlClient,
+		RootClientSet: fake.NewSimpleClientset(adminKubeconfigSecret),
+		KosmosClient:  kosmosClient,
+		ExecutorFactory: func(_ *v1alpha1.VirtualCluster, _ client.Client, _ *rest.Config, _ *v1alpha1.KubeNestConfiguration) (VirtualClusterExecutor, error) {
+			return &stubExecutor{executed: &executed}, nil
+		},
+	}
+
+	requests := c.enqueuePendingClustersMatching(freeNode)
+	if len(requests) != 1 || requests[0].Name != "vc1" {
+		t.Fatalf("enqueuePendingClustersMatching() = %v, want a single request for vc1", requests)
+	}
+
+	result, err := c.Reconcile(context.TODO(), requests[0])
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if result.RequeueAfter == constants.StuckPhaseTimeout {
+		t.Fatal("expected Reconcile to retry immediately instead of waiting out the full StuckPhaseTimeout")
+	}
+	if executed != 1 {
+		t.Fatalf("expected the stub executor to run exactly once, got %d", executed)
+	}
+	if ctrlClient.vc.Status.Phase != v1alpha1.Initialized {
+		t.Fatalf("expected phase %q, got %q", v1alpha1.Initialized, ctrlClient.vc.Status.Phase)
+	}
+}
+
+func TestAssignWorkNodesRecordsNodeAssignmentEvents(t *testing.T) {
+	freeNode := v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+		Spec:       v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeFreeState},
+	}
+	kosmosClient := kosmosfake.NewSimpleClientset(&freeNode)
+	recorder := record.NewFakeRecorder(10)
+
+	c := &VirtualClusterInitController{Client: &virtualClusterListerClient{}, KosmosClient: kosmosClient, EventRecorder: recorder}
+	virtualCluster := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			PromotePolicies: []v1alpha1.PromotePolicy{
+				{NodeCount: 1},
+			},
+		},
+	}
+
+	if _, err := c.assignWorkNodes(context.TODO(), virtualCluster); err != nil {
+		t.Fatalf("assignWorkNodes() error = %v", err)
+	}
+
+	var gotStarted, gotCompleted bool
+	for done := false; !done; {
+		select {
+		case event := <-recorder.Events:
+			if strings.Contains(event, EventReasonNodeAssignmentStarted) {
+				gotStarted = true
 			}
-			port := portsPool[i]
-			i++
-			return port, nil
+			if strings.Contains(event, EventReasonNodeAssignmentCompleted) {
+				gotCompleted = true
+			}
+		default:
+			done = true
 		}
-	}()
+	}
+	if !gotStarted {
+		t.Errorf("expected a %s event", EventReasonNodeAssignmentStarted)
+	}
+	if !gotCompleted {
+		t.Errorf("expected a %s event", EventReasonNodeAssignmentCompleted)
+	}
+}
 
-	for p, err := next(); err == nil; p, err = next() {
-		fmt.Printf("port: %d\n", p)
+func TestAssignWorkNodesPopulatesPromotePolicyStatuses(t *testing.T) {
+	satisfiedNode := v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+		Spec:       v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeFreeState, Labels: labels.Set{"pool": "satisfied"}},
+	}
+	shortNode := v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-b"},
+		Spec:       v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeFreeState, Labels: labels.Set{"pool": "short"}},
+	}
+	kosmosClient := kosmosfake.NewSimpleClientset(&satisfiedNode, &shortNode)
+
+	c := &VirtualClusterInitController{KosmosClient: kosmosClient}
+	virtualCluster := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "mixed-vc"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			PromotePolicies: []v1alpha1.PromotePolicy{
+				{
+					LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "satisfied"}},
+					NodeCount:     1,
+				},
+				{
+					LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pool": "short"}},
+					NodeCount:     2,
+				},
+			},
+		},
+	}
+
+	if _, err := c.assignWorkNodes(context.TODO(), virtualCluster); err == nil {
+		t.Fatal("expected assignWorkNodes() to error for the short policy")
+	}
+
+	if len(virtualCluster.Status.PromotePolicyStatuses) != 2 {
+		t.Fatalf("expected 2 PromotePolicyStatuses, got %d: %+v", len(virtualCluster.Status.PromotePolicyStatuses), virtualCluster.Status.PromotePolicyStatuses)
+	}
+
+	satisfied := virtualCluster.Status.PromotePolicyStatuses[0]
+	if satisfied.Desired != 1 || satisfied.Assigned != 1 || !satisfied.Ready || satisfied.Shortage != 0 {
+		t.Errorf("satisfied policy status = %+v, want Desired=1 Assigned=1 Ready=true Shortage=0", satisfied)
+	}
+
+	short := virtualCluster.Status.PromotePolicyStatuses[1]
+	if short.Desired != 2 || short.Assigned != 0 || short.Ready || short.Shortage != 2 {
+		t.Errorf("short policy status = %+v, want Desired=2 Assigned=0 Ready=false Shortage=2", short)
+	}
+}
+
+// TestAssignWorkNodesConcurrentClaimsNoDoubleAssignment exercises
+// assignWorkNodes from many goroutines contending for the same pool of
+// GlobalNodes, verifying globalNodeLock both avoids data races (run this
+// test with -race) and prevents two VirtualClusters from claiming the same
+// node.
+func TestAssignWorkNodesConcurrentClaimsNoDoubleAssignment(t *testing.T) {
+	const nodeCount = 20
+	objs := make([]runtime.Object, 0, nodeCount)
+	for i := 0; i < nodeCount; i++ {
+		objs = append(objs, &v1alpha1.GlobalNode{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("node-%d", i)},
+			Spec:       v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeFreeState},
+		})
+	}
+	kosmosClient := kosmosfake.NewSimpleClientset(objs...)
+	c := &VirtualClusterInitController{Client: &virtualClusterListerClient{}, KosmosClient: kosmosClient}
+
+	var wg sync.WaitGroup
+	claimedCh := make(chan []string, nodeCount)
+	for i := 0; i < nodeCount; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			vc := &v1alpha1.VirtualCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("vc-%d", i)},
+				Spec:       v1alpha1.VirtualClusterSpec{PromotePolicies: []v1alpha1.PromotePolicy{{NodeCount: 1}}},
+			}
+			claimed, err := c.assignWorkNodes(context.TODO(), vc)
+			if err != nil {
+				t.Errorf("assignWorkNodes() error = %v", err)
+				return
+			}
+			claimedCh <- claimed
+		}(i)
+	}
+	wg.Wait()
+	close(claimedCh)
+
+	seen := make(map[string]bool)
+	total := 0
+	for claimed := range claimedCh {
+		for _, name := range claimed {
+			if seen[name] {
+				t.Errorf("node %q was claimed by more than one concurrent assignWorkNodes call", name)
+			}
+			seen[name] = true
+			total++
+		}
+	}
+	if total != nodeCount {
+		t.Errorf("expected all %d nodes to be claimed exactly once, got %d claims", nodeCount, total)
+	}
+}
+
+// TestAssignWorkNodesScalesDownToZero exercises a PromotePolicy going from
+// NodeCount: 3 to NodeCount: 0, as happens when a VirtualCluster is
+// hibernated down to WorkersScaledDown. assignWorkNodes is responsible only
+// for the decrease selection and for rewriting
+// Spec.PromoteResources.NodeInfos down to the empty set; actually flipping
+// the released GlobalNodes back to Free happens asynchronously afterwards,
+// once NodeController notices they dropped out of NodeInfos and runs its own
+// unjoin workflow, so that is not asserted here.
+func TestAssignWorkNodesScalesDownToZero(t *testing.T) {
+	nodes := make([]runtime.Object, 0, 3)
+	nodeInfos := make([]v1alpha1.NodeInfo, 0, 3)
+	for i := 0; i < 3; i++ {
+		name := fmt.Sprintf("node-%d", i)
+		node := &v1alpha1.GlobalNode{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeInUse},
+			Status:     v1alpha1.GlobalNodeStatus{VirtualCluster: "vc1"},
+		}
+		nodes = append(nodes, node)
+		nodeInfos = append(nodeInfos, v1alpha1.NodeInfo{NodeName: name})
+	}
+	kosmosClient := kosmosfake.NewSimpleClientset(nodes...)
+
+	c := &VirtualClusterInitController{Client: &virtualClusterListerClient{}, KosmosClient: kosmosClient}
+	virtualCluster := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			PromotePolicies:  []v1alpha1.PromotePolicy{{NodeCount: 0}},
+			PromoteResources: v1alpha1.PromoteResources{NodeInfos: nodeInfos},
+		},
+	}
+
+	if _, err := c.assignWorkNodes(context.TODO(), virtualCluster); err != nil {
+		t.Fatalf("assignWorkNodes() error = %v", err)
+	}
+
+	if len(virtualCluster.Spec.PromoteResources.NodeInfos) != 0 {
+		t.Fatalf("expected NodeInfos to be emptied, got %+v", virtualCluster.Spec.PromoteResources.NodeInfos)
+	}
+
+	if len(virtualCluster.Status.PromotePolicyStatuses) != 1 {
+		t.Fatalf("expected 1 PromotePolicyStatus, got %d", len(virtualCluster.Status.PromotePolicyStatuses))
+	}
+	status := virtualCluster.Status.PromotePolicyStatuses[0]
+	if status.Desired != 0 || status.Assigned != 0 || !status.Ready || status.Shortage != 0 {
+		t.Errorf("policy status = %+v, want Desired=0 Assigned=0 Ready=true Shortage=0", status)
+	}
+
+	if got := workersCompletionPhase(virtualCluster); got != v1alpha1.WorkersScaledDown {
+		t.Errorf("workersCompletionPhase() after scale-down = %q, want %q", got, v1alpha1.WorkersScaledDown)
+	}
+}
+
+// TestAssignWorkNodesScalesUpFromZero exercises the reverse transition: a
+// VirtualCluster recovering from WorkersScaledDown (Spec.PromoteResources.NodeInfos
+// empty) has its PromotePolicies raised back to NodeCount: 2, and
+// assignWorkNodes should claim 2 free GlobalNodes exactly as it would for a
+// brand-new VirtualCluster.
+func TestAssignWorkNodesScalesUpFromZero(t *testing.T) {
+	nodeA := &v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+		Spec:       v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeFreeState},
+	}
+	nodeB := &v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-b"},
+		Spec:       v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeFreeState},
+	}
+	kosmosClient := kosmosfake.NewSimpleClientset(nodeA, nodeB)
+
+	c := &VirtualClusterInitController{Client: &virtualClusterListerClient{}, KosmosClient: kosmosClient}
+	virtualCluster := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			PromotePolicies: []v1alpha1.PromotePolicy{{NodeCount: 2}},
+		},
+	}
+
+	claimed, err := c.assignWorkNodes(context.TODO(), virtualCluster)
+	if err != nil {
+		t.Fatalf("assignWorkNodes() error = %v", err)
+	}
+	if len(claimed) != 2 {
+		t.Fatalf("expected 2 nodes claimed, got %d: %v", len(claimed), claimed)
+	}
+	if len(virtualCluster.Spec.PromoteResources.NodeInfos) != 2 {
+		t.Fatalf("expected 2 NodeInfos, got %+v", virtualCluster.Spec.PromoteResources.NodeInfos)
+	}
+
+	for _, name := range []string{"node-a", "node-b"} {
+		node, err := kosmosClient.KosmosV1alpha1().GlobalNodes().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Get(%s) error = %v", name, err)
+		}
+		if node.Spec.State != v1alpha1.NodeInUse {
+			t.Errorf("expected %s to be claimed, got state %q", name, node.Spec.State)
+		}
+		if node.Status.VirtualCluster != "vc1" {
+			t.Errorf("expected %s to record vc1 as its owner, got %q", name, node.Status.VirtualCluster)
+		}
+	}
+
+	if got := workersCompletionPhase(virtualCluster); got != v1alpha1.Completed {
+		t.Errorf("workersCompletionPhase() after scale-up = %q, want %q", got, v1alpha1.Completed)
+	}
+}
+
+func TestAssignWorkNodesRetriesConflictOnClaimWithoutLeakingInUseState(t *testing.T) {
+	nodeA := v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+		Spec:       v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeFreeState},
+	}
+	nodeB := v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-b"},
+		Spec:       v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeFreeState},
+	}
+	kosmosClient := kosmosfake.NewSimpleClientset(&nodeA, &nodeB)
+
+	// Simulate another reconcile racing node-b's spec update: the very first
+	// update to node-b conflicts, forcing setGlobalNodeUsageStatus's
+	// RetryOnConflict to refetch and reapply.
+	var conflicted bool
+	kosmosClient.PrependReactor("update", "globalnodes", func(action ktesting.Action) (bool, runtime.Object, error) {
+		updateAction, ok := action.(ktesting.UpdateAction)
+		if !ok {
+			return false, nil, nil
+		}
+		node, ok := updateAction.GetObject().(*v1alpha1.GlobalNode)
+		if !ok || node.Name != "node-b" || conflicted {
+			return false, nil, nil
+		}
+		conflicted = true
+		return true, nil, apierrors.NewConflict(v1alpha1.Resource("globalnode"), node.Name, fmt.Errorf("concurrent update"))
+	})
+
+	c := &VirtualClusterInitController{Client: &virtualClusterListerClient{}, KosmosClient: kosmosClient}
+	virtualCluster := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1"},
+		Spec:       v1alpha1.VirtualClusterSpec{PromotePolicies: []v1alpha1.PromotePolicy{{NodeCount: 2}}},
+	}
+
+	claimed, err := c.assignWorkNodes(context.TODO(), virtualCluster)
+	if err != nil {
+		t.Fatalf("assignWorkNodes() error = %v, want the conflicted update to be retried and converge", err)
+	}
+	if !conflicted {
+		t.Fatal("expected the injected conflict reactor to have fired for node-b")
+	}
+	if len(claimed) != 2 {
+		t.Fatalf("expected both nodes to end up claimed, got %v", claimed)
+	}
+
+	for _, name := range []string{"node-a", "node-b"} {
+		node, err := kosmosClient.KosmosV1alpha1().GlobalNodes().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("Get(%s) error = %v", name, err)
+		}
+		if node.Spec.State != v1alpha1.NodeInUse {
+			t.Errorf("expected %s to converge to %q despite the conflict, got %q", name, v1alpha1.NodeInUse, node.Spec.State)
+		}
+		if node.Status.VirtualCluster != "vc1" {
+			t.Errorf("expected %s to record vc1 as its owner, got %q", name, node.Status.VirtualCluster)
+		}
 	}
 }
 
+func TestRecordEventNilRecorderDoesNotPanic(t *testing.T) {
+	c := &VirtualClusterInitController{}
+	virtualCluster := &v1alpha1.VirtualCluster{ObjectMeta: metav1.ObjectMeta{Name: "vc1"}}
+
+	c.recordEvent(virtualCluster, corev1.EventTypeWarning, EventReasonReadinessWaitTimeout, "timed out: %v", fmt.Errorf("boom"))
+}
+
 func TestCreateApiAnpServer(t *testing.T) {
 	var name, namespace string
 	apiAnpAgentSvc := createAPIAnpAgentSvc(name, namespace, nameMap)
@@ -59,3 +2192,322 @@ func TestCreateApiAnpServer(t *testing.T) {
 		t.Fatalf("apiAnpAgentSvc.Spec.Ports[2].Port != 8084")
 	}
 }
+
+func TestParseNodePoolRoundTripsYAML(t *testing.T) {
+	data := []byte(`
+address: 10.0.0.5
+labels:
+  zone: a
+cluster: member1
+state: free
+`)
+
+	pool, err := ParseNodePool(data)
+	if err != nil {
+		t.Fatalf("ParseNodePool() error = %v", err)
+	}
+	if pool.Address != "10.0.0.5" || pool.Cluster != "member1" || pool.State != "free" || pool.Labels["zone"] != "a" {
+		t.Errorf("ParseNodePool() = %+v, want address=10.0.0.5 cluster=member1 state=free labels[zone]=a", pool)
+	}
+}
+
+func TestParseNodePoolRoundTripsJSON(t *testing.T) {
+	data := []byte(`{"address":"node-a.example.com","labels":{"zone":"b"},"cluster":"member2","state":"occupied"}`)
+
+	pool, err := ParseNodePool(data)
+	if err != nil {
+		t.Fatalf("ParseNodePool() error = %v", err)
+	}
+	if pool.Address != "node-a.example.com" || pool.Cluster != "member2" || pool.State != "occupied" || pool.Labels["zone"] != "b" {
+		t.Errorf("ParseNodePool() = %+v, want address=node-a.example.com cluster=member2 state=occupied labels[zone]=b", pool)
+	}
+}
+
+func TestParseNodePoolRejectsInvalidAddress(t *testing.T) {
+	if _, err := ParseNodePool([]byte(`address: "not a hostname!"`)); err == nil {
+		t.Fatal("ParseNodePool() error = nil, want an error for an invalid address")
+	}
+}
+
+func TestParseNodePoolRejectsUnknownState(t *testing.T) {
+	if _, err := ParseNodePool([]byte("address: 10.0.0.5\nstate: bogus\n")); err == nil {
+		t.Fatal("ParseNodePool() error = nil, want an error for an unrecognized state")
+	}
+}
+
+func TestParseNodePoolRejectsInvalidLabelKey(t *testing.T) {
+	data := []byte("address: 10.0.0.5\nlabels:\n  \"not a valid key!\": a\n")
+	if _, err := ParseNodePool(data); err == nil {
+		t.Fatal("ParseNodePool() error = nil, want an error for an invalid label key")
+	}
+}
+
+func TestGetNodePoolFromConfigMapReadsAndValidates(t *testing.T) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-pool", Namespace: "ns1"},
+		Data:       map[string]string{"nodepool.yaml": "address: 10.0.0.5\nstate: free\n"},
+	}
+	client := fake.NewSimpleClientset(cm)
+
+	pool, err := GetNodePoolFromConfigMap(client, "ns1", "node-pool", "nodepool.yaml")
+	if err != nil {
+		t.Fatalf("GetNodePoolFromConfigMap() error = %v", err)
+	}
+	if pool.Address != "10.0.0.5" || pool.State != "free" {
+		t.Errorf("GetNodePoolFromConfigMap() = %+v, want address=10.0.0.5 state=free", pool)
+	}
+}
+
+// externalControlPlaneKubeconfig builds a minimal kubeconfig pointing at
+// server, good enough for kubernetes.NewForConfig to build a working client.
+func externalControlPlaneKubeconfig(server string) []byte {
+	return []byte(fmt.Sprintf(`
+apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: %s
+  name: external
+contexts:
+- context:
+    cluster: external
+    user: external
+  name: external
+current-context: external
+users:
+- name: external
+  user: {}
+`, server))
+}
+
+func TestCreateVirtualClusterWithExternalControlPlaneAssignsNodesAfterConnectivityCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/version" {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"major":"1","minor":"27"}`))
+			return
+		}
+		t.Fatalf("unexpected request to %s", r.URL.Path)
+	}))
+	defer server.Close()
+
+	node := v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-a"},
+		Spec:       v1alpha1.GlobalNodeSpec{State: v1alpha1.NodeFreeState},
+	}
+	kosmosClient := kosmosfake.NewSimpleClientset(&node)
+	rootClient := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "external-kubeconfig", Namespace: "default"},
+		Data:       map[string][]byte{constants.KubeConfig: externalControlPlaneKubeconfig(server.URL)},
+	})
+	c := &VirtualClusterInitController{
+		Client:        &virtualClusterListerClient{},
+		KosmosClient:  kosmosClient,
+		RootClientSet: rootClient,
+	}
+	virtualCluster := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc-a", Namespace: "default"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			PromotePolicies:  []v1alpha1.PromotePolicy{{NodeCount: 1}},
+			KubeInKubeConfig: &v1alpha1.KubeInKubeConfig{KubernetesVersion: "v1.27.6"},
+			ExternalControlPlane: &v1alpha1.ExternalControlPlane{
+				APIServerEndpoint:   server.URL,
+				KubeconfigSecretRef: "external-kubeconfig",
+			},
+		},
+	}
+
+	if err := c.createVirtualCluster(context.TODO(), virtualCluster, &v1alpha1.KubeNestConfiguration{}); err != nil {
+		t.Fatalf("createVirtualCluster() error = %v", err)
+	}
+
+	if virtualCluster.Spec.Kubeconfig == "" {
+		t.Error("expected Spec.Kubeconfig to be populated from the external control plane's kubeconfig secret")
+	}
+	condition := meta.FindStatusCondition(virtualCluster.Status.Conditions, v1alpha1.ControlPlaneReadyConditionType)
+	if condition == nil || condition.Status != metav1.ConditionTrue {
+		t.Errorf("expected %s condition to be True, got %v", v1alpha1.ControlPlaneReadyConditionType, condition)
+	}
+	updated, err := kosmosClient.KosmosV1alpha1().GlobalNodes().Get(context.TODO(), "node-a", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("Get(node-a) error = %v", err)
+	}
+	if updated.Spec.State != v1alpha1.NodeInUse {
+		t.Errorf("expected node-a to be claimed (%q), got %q", v1alpha1.NodeInUse, updated.Spec.State)
+	}
+}
+
+func TestCreateVirtualClusterWithExternalControlPlaneFailsWhenSecretMissing(t *testing.T) {
+	c := &VirtualClusterInitController{
+		Client:        &virtualClusterListerClient{},
+		KosmosClient:  kosmosfake.NewSimpleClientset(),
+		RootClientSet: fake.NewSimpleClientset(),
+	}
+	virtualCluster := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc-a", Namespace: "default"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			ExternalControlPlane: &v1alpha1.ExternalControlPlane{
+				APIServerEndpoint:   "https://example.invalid:6443",
+				KubeconfigSecretRef: "missing-secret",
+			},
+		},
+	}
+
+	if err := c.createVirtualCluster(context.TODO(), virtualCluster, &v1alpha1.KubeNestConfiguration{}); err == nil {
+		t.Fatal("expected createVirtualCluster() to error when the referenced kubeconfig secret is missing")
+	}
+}
+
+func TestCreateVirtualClusterWithExternalControlPlaneFailsWhenUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	server.Close()
+
+	rootClient := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "external-kubeconfig", Namespace: "default"},
+		Data:       map[string][]byte{constants.KubeConfig: externalControlPlaneKubeconfig(server.URL)},
+	})
+	c := &VirtualClusterInitController{
+		Client:        &virtualClusterListerClient{},
+		KosmosClient:  kosmosfake.NewSimpleClientset(),
+		RootClientSet: rootClient,
+	}
+	virtualCluster := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc-a", Namespace: "default"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			KubeInKubeConfig: &v1alpha1.KubeInKubeConfig{KubernetesVersion: "v1.27.6"},
+			ExternalControlPlane: &v1alpha1.ExternalControlPlane{
+				APIServerEndpoint:   server.URL,
+				KubeconfigSecretRef: "external-kubeconfig",
+			},
+		},
+	}
+
+	err := c.createVirtualCluster(context.TODO(), virtualCluster, &v1alpha1.KubeNestConfiguration{})
+	if err == nil {
+		t.Fatal("expected createVirtualCluster() to error when the external control plane is unreachable")
+	}
+	condition := meta.FindStatusCondition(virtualCluster.Status.Conditions, v1alpha1.ControlPlaneReadyConditionType)
+	if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "ExternalControlPlaneUnreachable" {
+		t.Errorf("expected %s condition to be False/ExternalControlPlaneUnreachable, got %v", v1alpha1.ControlPlaneReadyConditionType, condition)
+	}
+}
+
+func TestEnsureFinalizerAddsMainAndSubFinalizers(t *testing.T) {
+	virtualCluster := &v1alpha1.VirtualCluster{ObjectMeta: metav1.ObjectMeta{Name: "vc-a", Namespace: "default"}}
+	memClient := &inMemoryVirtualClusterClient{vc: virtualCluster}
+	c := &VirtualClusterInitController{
+		Client: memClient,
+		SubFinalizers: []SubFinalizer{
+			{Name: "kosmos.io/node-cleanup", Ready: func(*v1alpha1.VirtualCluster) bool { return true }},
+		},
+	}
+
+	if _, err := c.ensureFinalizer(virtualCluster); err != nil {
+		t.Fatalf("ensureFinalizer() error = %v", err)
+	}
+	if !controllerutil.ContainsFinalizer(memClient.vc, VirtualClusterControllerFinalizer) {
+		t.Errorf("expected %s to be added", VirtualClusterControllerFinalizer)
+	}
+	if !controllerutil.ContainsFinalizer(memClient.vc, "kosmos.io/node-cleanup") {
+		t.Error("expected kosmos.io/node-cleanup to be added")
+	}
+}
+
+// TestRemoveFinalizerKeepsThirdPartyFinalizersAndDefersMainUntilSubFinalizerReady
+// verifies both halves of the finalizer contract: a finalizer owned by an
+// unrelated controller is never touched, and VirtualClusterControllerFinalizer
+// is only removed once every registered SubFinalizer has already cleared.
+func TestRemoveFinalizerKeepsThirdPartyFinalizersAndDefersMainUntilSubFinalizerReady(t *testing.T) {
+	virtualCluster := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "vc-a",
+			Namespace:  "default",
+			Finalizers: []string{VirtualClusterControllerFinalizer, "kosmos.io/node-cleanup", "thirdparty.io/coordinated-deletion"},
+		},
+	}
+	nodeCleanupReady := false
+	memClient := &inMemoryVirtualClusterClient{vc: virtualCluster}
+	c := &VirtualClusterInitController{
+		Client: memClient,
+		SubFinalizers: []SubFinalizer{
+			{Name: "kosmos.io/node-cleanup", Ready: func(*v1alpha1.VirtualCluster) bool { return nodeCleanupReady }},
+		},
+	}
+
+	result, err := c.removeFinalizer(virtualCluster)
+	if err != nil {
+		t.Fatalf("removeFinalizer() error = %v", err)
+	}
+	if !result.Requeue {
+		t.Error("expected Requeue while the sub-finalizer's cleanup is still pending")
+	}
+	if !controllerutil.ContainsFinalizer(memClient.vc, VirtualClusterControllerFinalizer) {
+		t.Error("expected VirtualClusterControllerFinalizer to remain while kosmos.io/node-cleanup is not ready")
+	}
+	if !controllerutil.ContainsFinalizer(memClient.vc, "kosmos.io/node-cleanup") {
+		t.Error("expected kosmos.io/node-cleanup to remain while its cleanup is not ready")
+	}
+	if !controllerutil.ContainsFinalizer(memClient.vc, "thirdparty.io/coordinated-deletion") {
+		t.Error("expected thirdparty.io/coordinated-deletion to be left untouched")
+	}
+
+	nodeCleanupReady = true
+	if _, err := c.removeFinalizer(memClient.vc); err != nil {
+		t.Fatalf("removeFinalizer() error = %v", err)
+	}
+	if controllerutil.ContainsFinalizer(memClient.vc, "kosmos.io/node-cleanup") {
+		t.Error("expected kosmos.io/node-cleanup to be removed once ready")
+	}
+	if controllerutil.ContainsFinalizer(memClient.vc, VirtualClusterControllerFinalizer) {
+		t.Error("expected VirtualClusterControllerFinalizer to be removed once every sub-finalizer cleared")
+	}
+	if !controllerutil.ContainsFinalizer(memClient.vc, "thirdparty.io/coordinated-deletion") {
+		t.Error("expected thirdparty.io/coordinated-deletion to still be left untouched")
+	}
+}
+
+func TestGetHostNetworkPortsAllocatesThroughPortManagerWhenSet(t *testing.T) {
+	portManager := fakeutil.NewHostPortManager(40010)
+	c := &VirtualClusterInitController{PortManager: portManager}
+	virtualCluster := &v1alpha1.VirtualCluster{ObjectMeta: metav1.ObjectMeta{Name: "vc1"}}
+
+	ports, err := c.GetHostNetworkPorts(virtualCluster)
+	if err != nil {
+		t.Fatalf("GetHostNetworkPorts() error = %v", err)
+	}
+	if len(ports) != constants.VirtualClusterPortNum {
+		t.Fatalf("GetHostNetworkPorts() returned %d ports, want %d", len(ports), constants.VirtualClusterPortNum)
+	}
+	if got := portManager.AllocatedPorts(); len(got) != constants.VirtualClusterPortNum {
+		t.Fatalf("PortManager.AllocatedPorts() = %v, want %d ports marked allocated", got, constants.VirtualClusterPortNum)
+	}
+}
+
+func TestReleaseClusterPortsOnDestroyReturnsPortsToPortManager(t *testing.T) {
+	portManager := fakeutil.NewHostPortManager(0)
+	if err := portManager.SyncFromClusters(context.TODO(), []v1alpha1.VirtualCluster{{
+		Status: v1alpha1.VirtualClusterStatus{
+			Port:    40010,
+			PortMap: map[string]int32{constants.APIServerNetworkProxyAgentPortKey: 40011},
+		},
+	}}); err != nil {
+		t.Fatalf("SyncFromClusters() error = %v", err)
+	}
+
+	c := &VirtualClusterInitController{PortManager: portManager}
+	virtualCluster := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1"},
+		Status: v1alpha1.VirtualClusterStatus{
+			Port:    40010,
+			PortMap: map[string]int32{constants.APIServerNetworkProxyAgentPortKey: 40011},
+		},
+	}
+
+	c.releaseClusterPortsOnDestroy(virtualCluster)
+
+	if got := portManager.AllocatedPorts(); len(got) != 0 {
+		t.Fatalf("AllocatedPorts() = %v, want none allocated after release", got)
+	}
+}
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/constants"
+)
+
+// VirtualClusterTTLEventReasonExpiring is the reason recorded on the warning
+// event emitted as a VirtualCluster's TTL deadline nears.
+const VirtualClusterTTLEventReasonExpiring = "TTLExpiring"
+
+// VirtualClusterTTLController deletes a VirtualCluster once it has outlived
+// its optional Spec.TTL, for ephemeral CI/test clusters that should be
+// cleaned up automatically. The deadline is recomputed from
+// CreationTimestamp and the current Spec.TTL on every reconcile, so
+// extending the TTL on an existing VirtualCluster is honored even if the
+// cluster has already outlived its original deadline.
+type VirtualClusterTTLController struct {
+	client.Client
+	EventRecorder record.EventRecorder
+}
+
+func (r *VirtualClusterTTLController) SetupWithManager(mgr manager.Manager) error {
+	if r.Client == nil {
+		r.Client = mgr.GetClient()
+	}
+	if r.EventRecorder == nil {
+		r.EventRecorder = mgr.GetEventRecorderFor(constants.VirtualClusterTTLControllerName)
+	}
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(constants.VirtualClusterTTLControllerName).
+		For(&v1alpha1.VirtualCluster{}).
+		Complete(r)
+}
+
+func (r *VirtualClusterTTLController) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	var vc v1alpha1.VirtualCluster
+	if err := r.Get(ctx, request.NamespacedName, &vc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if vc.Spec.TTL == "" || !vc.DeletionTimestamp.IsZero() {
+		return reconcile.Result{}, nil
+	}
+
+	ttl, err := time.ParseDuration(vc.Spec.TTL)
+	if err != nil {
+		klog.Warningf("virtual-cluster-ttl-controller: invalid TTL %q for %s, skipping: %v", vc.Spec.TTL, request.NamespacedName, err)
+		return reconcile.Result{}, nil
+	}
+
+	expiry := vc.CreationTimestamp.Add(ttl)
+	if remaining := time.Until(expiry); remaining > 0 {
+		if remaining <= constants.VirtualClusterTTLExpiryWarningWindow {
+			r.EventRecorder.Eventf(&vc, corev1.EventTypeWarning, VirtualClusterTTLEventReasonExpiring, "VirtualCluster will be deleted by its TTL in %s", remaining.Round(time.Second))
+			return reconcile.Result{RequeueAfter: remaining}, nil
+		}
+		return reconcile.Result{RequeueAfter: constants.VirtualClusterTTLCheckInterval}, nil
+	}
+
+	klog.Infof("virtual-cluster-ttl-controller: %s has outlived its TTL of %s, deleting", request.NamespacedName, vc.Spec.TTL)
+	if err := r.Delete(ctx, &vc); err != nil && !apierrors.IsNotFound(err) {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
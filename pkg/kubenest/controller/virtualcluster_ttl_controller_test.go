@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+)
+
+// ttlStoreClient stubs the client.Client Get/Delete methods
+// VirtualClusterTTLController needs, and records whether Delete was called.
+type ttlStoreClient struct {
+	client.Client
+	vc      *v1alpha1.VirtualCluster
+	deleted bool
+}
+
+func (c *ttlStoreClient) Get(_ context.Context, _ types.NamespacedName, obj client.Object, _ ...client.GetOption) error {
+	vc, ok := obj.(*v1alpha1.VirtualCluster)
+	if !ok {
+		return fmt.Errorf("unexpected object type %T", obj)
+	}
+	*vc = *c.vc.DeepCopy()
+	return nil
+}
+
+func (c *ttlStoreClient) Delete(_ context.Context, _ client.Object, _ ...client.DeleteOption) error {
+	c.deleted = true
+	return nil
+}
+
+func virtualClusterWithTTLAndAge(ttl string, age time.Duration) *v1alpha1.VirtualCluster {
+	return &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "vc1",
+			Namespace:         "kosmos-system",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+		},
+		Spec: v1alpha1.VirtualClusterSpec{TTL: ttl},
+	}
+}
+
+func TestVirtualClusterTTLControllerDeletesExpiredCluster(t *testing.T) {
+	store := &ttlStoreClient{vc: virtualClusterWithTTLAndAge("1h", 2*time.Hour)}
+	r := &VirtualClusterTTLController{Client: store, EventRecorder: record.NewFakeRecorder(1)}
+
+	if _, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: store.vc.Name, Namespace: store.vc.Namespace}}); err != nil {
+		t.Fatalf("Reconcile() error = %v, want nil", err)
+	}
+	if !store.deleted {
+		t.Error("Reconcile() did not delete a VirtualCluster past its TTL")
+	}
+}
+
+func TestVirtualClusterTTLControllerKeepsClusterWithinTTL(t *testing.T) {
+	store := &ttlStoreClient{vc: virtualClusterWithTTLAndAge("24h", 1*time.Hour)}
+	r := &VirtualClusterTTLController{Client: store, EventRecorder: record.NewFakeRecorder(1)}
+
+	if _, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: store.vc.Name, Namespace: store.vc.Namespace}}); err != nil {
+		t.Fatalf("Reconcile() error = %v, want nil", err)
+	}
+	if store.deleted {
+		t.Error("Reconcile() deleted a VirtualCluster that is still within its TTL")
+	}
+}
+
+func TestVirtualClusterTTLControllerHonorsExtendedTTL(t *testing.T) {
+	// Created long ago, but with a TTL long enough that it hasn't expired:
+	// extending Spec.TTL on an existing, already-old cluster must push the
+	// deadline out rather than deleting based on some earlier, cached value.
+	store := &ttlStoreClient{vc: virtualClusterWithTTLAndAge("720h", 48*time.Hour)}
+	r := &VirtualClusterTTLController{Client: store, EventRecorder: record.NewFakeRecorder(1)}
+
+	if _, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: store.vc.Name, Namespace: store.vc.Namespace}}); err != nil {
+		t.Fatalf("Reconcile() error = %v, want nil", err)
+	}
+	if store.deleted {
+		t.Error("Reconcile() deleted a VirtualCluster whose TTL was extended past its age")
+	}
+}
+
+func TestVirtualClusterTTLControllerIgnoresClusterWithoutTTL(t *testing.T) {
+	store := &ttlStoreClient{vc: virtualClusterWithTTLAndAge("", 48*time.Hour)}
+	r := &VirtualClusterTTLController{Client: store, EventRecorder: record.NewFakeRecorder(1)}
+
+	if _, err := r.Reconcile(context.TODO(), reconcile.Request{NamespacedName: types.NamespacedName{Name: store.vc.Name, Namespace: store.vc.Namespace}}); err != nil {
+		t.Fatalf("Reconcile() error = %v, want nil", err)
+	}
+	if store.deleted {
+		t.Error("Reconcile() deleted a VirtualCluster with no TTL set")
+	}
+}
@@ -2,9 +2,11 @@ package controlplane
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/yaml"
 	clientset "k8s.io/client-go/kubernetes"
 
@@ -29,8 +31,32 @@ func DeleteVirtualClusterAPIServer(client clientset.Interface, name, namespace s
 	return nil
 }
 
+func createPodSecurityAdmissionConfigMap(client clientset.Interface, namespace string, data util.PodSecurityAdmissionTemplateData) error {
+	configMapBytes, err := util.ParseTemplate(apiserver.PodSecurityAdmissionConfigMap, struct {
+		Namespace string
+		util.PodSecurityAdmissionTemplateData
+	}{
+		Namespace:                        namespace,
+		PodSecurityAdmissionTemplateData: data,
+	})
+	if err != nil {
+		return fmt.Errorf("error when parsing pod security admission config map template: %w", err)
+	}
+
+	cm := &v1.ConfigMap{}
+	if err := yaml.Unmarshal([]byte(configMapBytes), cm); err != nil {
+		return fmt.Errorf("error when decoding pod security admission config map: %w", err)
+	}
+
+	if err := util.CreateOrUpdateConfigMap(client, cm); err != nil {
+		return fmt.Errorf("error when creating pod security admission config map for %s, err: %w", cm.Name, err)
+	}
+	return nil
+}
+
 func installAPIServer(client clientset.Interface, name, namespace string, portMap map[string]int32, kubeNestConfiguration *v1alpha1.KubeNestConfiguration, vc *v1alpha1.VirtualCluster) error {
-	imageRepository, imageVersion := util.GetImageMessage()
+	_, imageVersion := util.GetImageMessage()
+	imageRepository := util.ResolveImageRepository(vc)
 	clusterIP, err := util.GetEtcdServiceClusterIP(namespace, name+constants.EtcdSuffix, client)
 	if err != nil {
 		return nil
@@ -38,36 +64,113 @@ func installAPIServer(client clientset.Interface, name, namespace string, portMa
 
 	vclabel := util.GetVirtualControllerLabel()
 
+	apiServerReplicas := kubeNestConfiguration.KubeInKubeConfig.APIServerReplicas
+	if vc.Spec.ControlPlaneConfig != nil {
+		apiServerReplicas = int(util.ResolveReplicas(vc.Spec.ControlPlaneConfig.APIServerReplicas, apiServerReplicas))
+	}
+
+	if err := util.ValidateServiceSubnet(constants.APIServerServiceSubnet); err != nil {
+		return err
+	}
+
 	IPV6FirstFlag, err := util.IPV6First(constants.APIServerServiceSubnet)
 	if err != nil {
 		return err
 	}
 
+	shutdownDelayDuration := kubeNestConfiguration.KubeInKubeConfig.ShutdownDelayDuration
+	if err := util.ValidateNonNegativeDuration("shutdownDelayDuration", shutdownDelayDuration); err != nil {
+		return err
+	}
+	shutdownGracePeriod := kubeNestConfiguration.KubeInKubeConfig.ShutdownGracePeriod
+	if err := util.ValidateNonNegativeDuration("shutdownGracePeriod", shutdownGracePeriod); err != nil {
+		return err
+	}
+	storageMediaType := kubeNestConfiguration.KubeInKubeConfig.StorageMediaType
+	if err := util.ValidateStorageMediaType(storageMediaType); err != nil {
+		return err
+	}
+
+	var corsAllowedOrigins []string
+	if vc.Spec.KubeInKubeConfig != nil {
+		corsAllowedOrigins = vc.Spec.KubeInKubeConfig.CORSAllowedOrigins
+	}
+	if err := util.ValidateCORSAllowedOrigins(corsAllowedOrigins); err != nil {
+		return err
+	}
+
+	var apiServerExtraArgs map[string]string
+	if vc.Spec.ControlPlaneConfig != nil {
+		apiServerExtraArgs = vc.Spec.ControlPlaneConfig.APIServerExtraArgs
+	}
+	if err := util.ValidateExtraArgs(constants.APIServer, apiServerExtraArgs, util.DisallowedAPIServerExtraArgs); err != nil {
+		return err
+	}
+
+	auditData, err := util.BuildAuditTemplateData(kubeNestConfiguration.KubeInKubeConfig.Audit, namespace, name)
+	if err != nil {
+		return err
+	}
+
+	webhookData, err := util.BuildWebhookTemplateData(client, namespace, kubeNestConfiguration.KubeInKubeConfig.AuthenticationWebhook, kubeNestConfiguration.KubeInKubeConfig.AuthorizationWebhook)
+	if err != nil {
+		return err
+	}
+
+	podSecurityAdmissionData, err := util.BuildPodSecurityAdmissionTemplateData(kubeNestConfiguration.KubeInKubeConfig.PodSecurityAdmission, name)
+	if err != nil {
+		return err
+	}
+	if podSecurityAdmissionData.PodSecurityAdmissionEnabled {
+		if err := createPodSecurityAdmissionConfigMap(client, namespace, podSecurityAdmissionData); err != nil {
+			return err
+		}
+	}
+
+	apiServerImage := util.ResolveComponentImage(vc, constants.APIServer, "kube-apiserver", imageVersion)
+
 	apiserverDeploymentBytes, err := util.ParseTemplate(apiserver.ApiserverDeployment, struct {
 		DeploymentName, Namespace, ImageRepository, EtcdClientService, Version, VirtualControllerLabel string
+		APIServerImage                                                                                 string
 		ServiceSubnet, VirtualClusterCertsSecret, EtcdCertsSecret                                      string
+		ShutdownDelayDuration, ShutdownGracePeriod                                                     string
+		StorageMediaType                                                                               string
+		CORSAllowedOrigins                                                                             string
 		Replicas                                                                                       int
 		EtcdListenClientPort                                                                           int32
 		ClusterPort                                                                                    int32
 		AdmissionPlugins                                                                               bool
 		IPV6First                                                                                      bool
 		UseAPIServerNodePort                                                                           bool
+		Profiling                                                                                      bool
+		util.AuditTemplateData
+		util.WebhookTemplateData
+		util.PodSecurityAdmissionTemplateData
 	}{
-		DeploymentName:            util.GetAPIServerName(name),
-		Namespace:                 namespace,
-		ImageRepository:           imageRepository,
-		Version:                   imageVersion,
-		VirtualControllerLabel:    vclabel,
-		EtcdClientService:         clusterIP,
-		ServiceSubnet:             constants.APIServerServiceSubnet,
-		VirtualClusterCertsSecret: util.GetCertName(name),
-		EtcdCertsSecret:           util.GetEtcdCertName(name),
-		Replicas:                  kubeNestConfiguration.KubeInKubeConfig.APIServerReplicas,
-		EtcdListenClientPort:      constants.APIServerEtcdListenClientPort,
-		ClusterPort:               portMap[constants.APIServerPortKey],
-		IPV6First:                 IPV6FirstFlag,
-		AdmissionPlugins:          kubeNestConfiguration.KubeInKubeConfig.AdmissionPlugins,
-		UseAPIServerNodePort:      vc.Spec.KubeInKubeConfig != nil && vc.Spec.KubeInKubeConfig.APIServerServiceType == v1alpha1.NodePort,
+		DeploymentName:                   util.GetAPIServerName(name),
+		Namespace:                        namespace,
+		ImageRepository:                  imageRepository,
+		APIServerImage:                   apiServerImage,
+		Version:                          imageVersion,
+		VirtualControllerLabel:           vclabel,
+		EtcdClientService:                clusterIP,
+		ServiceSubnet:                    constants.APIServerServiceSubnet,
+		VirtualClusterCertsSecret:        util.GetCertName(name),
+		EtcdCertsSecret:                  util.GetEtcdCertName(name),
+		ShutdownDelayDuration:            shutdownDelayDuration,
+		ShutdownGracePeriod:              shutdownGracePeriod,
+		StorageMediaType:                 storageMediaType,
+		CORSAllowedOrigins:               strings.Join(corsAllowedOrigins, ","),
+		Replicas:                         apiServerReplicas,
+		EtcdListenClientPort:             constants.APIServerEtcdListenClientPort,
+		ClusterPort:                      portMap[constants.APIServerPortKey],
+		IPV6First:                        IPV6FirstFlag,
+		AdmissionPlugins:                 kubeNestConfiguration.KubeInKubeConfig.AdmissionPlugins,
+		UseAPIServerNodePort:             vc.Spec.KubeInKubeConfig != nil && vc.Spec.KubeInKubeConfig.APIServerServiceType == v1alpha1.NodePort,
+		Profiling:                        kubeNestConfiguration.KubeInKubeConfig.Profiling,
+		AuditTemplateData:                auditData,
+		WebhookTemplateData:              webhookData,
+		PodSecurityAdmissionTemplateData: podSecurityAdmissionData,
 	})
 	if err != nil {
 		return fmt.Errorf("error when parsing virtual cluster apiserver deployment template: %w", err)
@@ -77,6 +180,9 @@ func installAPIServer(client clientset.Interface, name, namespace string, portMa
 	if err := yaml.Unmarshal([]byte(apiserverDeploymentBytes), apiserverDeployment); err != nil {
 		return fmt.Errorf("error when decoding virtual cluster apiserver deployment: %w", err)
 	}
+	util.MergeResourceLabelsAndAnnotations(apiserverDeployment, vc.Spec.ResourceLabels, vc.Spec.ResourceAnnotations)
+	apiserverContainer := &apiserverDeployment.Spec.Template.Spec.Containers[0]
+	apiserverContainer.Command = util.MergeExtraArgsIntoCommand(apiserverContainer.Command, apiServerExtraArgs)
 
 	if err := util.CreateOrUpdateDeployment(client, apiserverDeployment); err != nil {
 		return fmt.Errorf("error when creating deployment for %s, err: %w", apiserverDeployment.Name, err)
@@ -0,0 +1,490 @@
+package controlplane
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/constants"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/util"
+)
+
+func newApiserverTestClient(name, namespace string) *fake.Clientset {
+	return fake.NewSimpleClientset(&corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: name + constants.EtcdSuffix, Namespace: namespace},
+		Spec: corev1.ServiceSpec{
+			Type:      corev1.ServiceType(constants.EtcdServiceType),
+			ClusterIP: "10.0.0.1",
+		},
+	})
+}
+
+func TestEnsureVirtualClusterAPIServerRendersConfiguredReplicas(t *testing.T) {
+	name, namespace := "vc1", "test-namespace"
+	client := newApiserverTestClient(name, namespace)
+	kubeNestConfiguration := &v1alpha1.KubeNestConfiguration{
+		KubeInKubeConfig: v1alpha1.KubeInKubeConfig{APIServerReplicas: 1},
+	}
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       v1alpha1.VirtualClusterSpec{ControlPlaneConfig: &v1alpha1.ControlPlaneConfig{APIServerReplicas: 3}},
+	}
+
+	if err := EnsureVirtualClusterAPIServer(client, name, namespace, map[string]int32{}, kubeNestConfiguration, vc); err != nil {
+		t.Fatalf("EnsureVirtualClusterAPIServer() error = %v", err)
+	}
+
+	deployment, err := client.AppsV1().Deployments(namespace).Get(context.TODO(), util.GetAPIServerName(name), metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get apiserver deployment: %v", err)
+	}
+	if got := *deployment.Spec.Replicas; got != 3 {
+		t.Errorf("expected apiserver deployment to use the configured replica count 3, got %d", got)
+	}
+}
+
+func TestEnsureVirtualClusterAPIServerMergesExtraArgs(t *testing.T) {
+	name, namespace := "vc1", "test-namespace"
+	client := newApiserverTestClient(name, namespace)
+	kubeNestConfiguration := &v1alpha1.KubeNestConfiguration{}
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1alpha1.VirtualClusterSpec{
+			ControlPlaneConfig: &v1alpha1.ControlPlaneConfig{
+				APIServerExtraArgs: map[string]string{"feature-gates": "SomeFeature=true"},
+			},
+		},
+	}
+
+	if err := EnsureVirtualClusterAPIServer(client, name, namespace, map[string]int32{}, kubeNestConfiguration, vc); err != nil {
+		t.Fatalf("EnsureVirtualClusterAPIServer() error = %v", err)
+	}
+
+	deployment, err := client.AppsV1().Deployments(namespace).Get(context.TODO(), util.GetAPIServerName(name), metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get apiserver deployment: %v", err)
+	}
+	command := deployment.Spec.Template.Spec.Containers[0].Command
+	found := false
+	for _, arg := range command {
+		if arg == "--feature-gates=SomeFeature=true" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected rendered apiserver command to contain the configured feature-gates flag, got %v", command)
+	}
+}
+
+func TestEnsureVirtualClusterAPIServerRejectsDisallowedExtraArg(t *testing.T) {
+	name, namespace := "vc1", "test-namespace"
+	client := newApiserverTestClient(name, namespace)
+	kubeNestConfiguration := &v1alpha1.KubeNestConfiguration{}
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1alpha1.VirtualClusterSpec{
+			ControlPlaneConfig: &v1alpha1.ControlPlaneConfig{
+				APIServerExtraArgs: map[string]string{"etcd-servers": "https://evil.example.com:2379"},
+			},
+		},
+	}
+
+	if err := EnsureVirtualClusterAPIServer(client, name, namespace, map[string]int32{}, kubeNestConfiguration, vc); err == nil {
+		t.Fatal("expected EnsureVirtualClusterAPIServer() to reject an etcd-servers override")
+	}
+}
+
+func TestEnsureVirtualClusterAPIServerExtraArgDoesNotOverrideKosmosFlag(t *testing.T) {
+	name, namespace := "vc1", "test-namespace"
+	client := newApiserverTestClient(name, namespace)
+	kubeNestConfiguration := &v1alpha1.KubeNestConfiguration{}
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1alpha1.VirtualClusterSpec{
+			ControlPlaneConfig: &v1alpha1.ControlPlaneConfig{
+				APIServerExtraArgs: map[string]string{"v": "8"},
+			},
+		},
+	}
+
+	if err := EnsureVirtualClusterAPIServer(client, name, namespace, map[string]int32{}, kubeNestConfiguration, vc); err != nil {
+		t.Fatalf("EnsureVirtualClusterAPIServer() error = %v", err)
+	}
+
+	deployment, err := client.AppsV1().Deployments(namespace).Get(context.TODO(), util.GetAPIServerName(name), metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get apiserver deployment: %v", err)
+	}
+	command := deployment.Spec.Template.Spec.Containers[0].Command
+	count := 0
+	for _, arg := range command {
+		if strings.HasPrefix(arg, "--v=") {
+			count++
+			if arg != "--v=4" {
+				t.Errorf("expected kosmos's own --v=4 to win over the extra arg, got %q", arg)
+			}
+		}
+	}
+	if count != 1 {
+		t.Fatalf("expected exactly one --v flag in the rendered command, got %d", count)
+	}
+}
+
+func TestEnsureVirtualClusterAPIServerMergesResourceLabelsAndAnnotations(t *testing.T) {
+	name, namespace := "vc1", "test-namespace"
+	client := newApiserverTestClient(name, namespace)
+	kubeNestConfiguration := &v1alpha1.KubeNestConfiguration{}
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1alpha1.VirtualClusterSpec{
+			ResourceLabels:      map[string]string{"cost-center": "platform", "virtualCluster-app": "attempted-override"},
+			ResourceAnnotations: map[string]string{"team": "infra"},
+		},
+	}
+
+	if err := EnsureVirtualClusterAPIServer(client, name, namespace, map[string]int32{}, kubeNestConfiguration, vc); err != nil {
+		t.Fatalf("EnsureVirtualClusterAPIServer() error = %v", err)
+	}
+
+	deployment, err := client.AppsV1().Deployments(namespace).Get(context.TODO(), util.GetAPIServerName(name), metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get apiserver deployment: %v", err)
+	}
+	if deployment.Labels["cost-center"] != "platform" {
+		t.Errorf("expected custom label cost-center=platform, got %q", deployment.Labels["cost-center"])
+	}
+	if deployment.Annotations["team"] != "infra" {
+		t.Errorf("expected custom annotation team=infra, got %q", deployment.Annotations["team"])
+	}
+	if deployment.Labels["virtualCluster-app"] != "apiserver" {
+		t.Errorf("expected kosmos-managed label virtualCluster-app to survive merging, got %q", deployment.Labels["virtualCluster-app"])
+	}
+}
+
+func TestEnsureVirtualClusterAPIServerAuditLogBackend(t *testing.T) {
+	name, namespace := "vc1", "test-namespace"
+	client := newApiserverTestClient(name, namespace)
+	kubeNestConfiguration := &v1alpha1.KubeNestConfiguration{
+		KubeInKubeConfig: v1alpha1.KubeInKubeConfig{
+			Audit: &v1alpha1.AuditConfig{
+				Backend: v1alpha1.AuditLogBackend,
+				Log: &v1alpha1.AuditLogConfig{
+					Path:      "/var/log/audit/audit.log",
+					MaxAge:    7,
+					MaxBackup: 3,
+					MaxSize:   100,
+				},
+			},
+		},
+	}
+	vc := &v1alpha1.VirtualCluster{ObjectMeta: metav1.ObjectMeta{Name: name}}
+
+	if err := EnsureVirtualClusterAPIServer(client, name, namespace, map[string]int32{}, kubeNestConfiguration, vc); err != nil {
+		t.Fatalf("EnsureVirtualClusterAPIServer() error = %v", err)
+	}
+
+	deployment, err := client.AppsV1().Deployments(namespace).Get(context.TODO(), util.GetAPIServerName(name), metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get apiserver deployment: %v", err)
+	}
+
+	command := deployment.Spec.Template.Spec.Containers[0].Command
+	for _, want := range []string{
+		"--audit-log-path=/var/log/audit/audit.log",
+		"--audit-log-maxage=7",
+		"--audit-log-maxbackup=3",
+		"--audit-log-maxsize=100",
+	} {
+		if !containsFlag(command, want) {
+			t.Errorf("expected rendered apiserver command to carry %q, got %v", want, command)
+		}
+	}
+
+	if !hasVolumeMount(deployment.Spec.Template.Spec.Containers[0].VolumeMounts, "audit-log", "/var/log/audit") {
+		t.Errorf("expected audit-log volume mounted at /var/log/audit, got %v", deployment.Spec.Template.Spec.Containers[0].VolumeMounts)
+	}
+	if !hasHostPathVolume(deployment.Spec.Template.Spec.Volumes, "audit-log", "/var/log/audit") {
+		t.Errorf("expected audit-log hostPath volume at /var/log/audit, got %v", deployment.Spec.Template.Spec.Volumes)
+	}
+}
+
+func TestEnsureVirtualClusterAPIServerAuditWebhookBackend(t *testing.T) {
+	name, namespace := "vc1", "test-namespace"
+	client := newApiserverTestClient(name, namespace)
+	kubeNestConfiguration := &v1alpha1.KubeNestConfiguration{
+		KubeInKubeConfig: v1alpha1.KubeInKubeConfig{
+			Audit: &v1alpha1.AuditConfig{
+				Backend: v1alpha1.AuditWebhookBackend,
+				Webhook: &v1alpha1.AuditWebhookConfig{
+					ConfigMapName:  "audit-webhook-config",
+					InitialBackoff: "5s",
+				},
+			},
+		},
+	}
+	vc := &v1alpha1.VirtualCluster{ObjectMeta: metav1.ObjectMeta{Name: name}}
+
+	if err := EnsureVirtualClusterAPIServer(client, name, namespace, map[string]int32{}, kubeNestConfiguration, vc); err != nil {
+		t.Fatalf("EnsureVirtualClusterAPIServer() error = %v", err)
+	}
+
+	deployment, err := client.AppsV1().Deployments(namespace).Get(context.TODO(), util.GetAPIServerName(name), metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get apiserver deployment: %v", err)
+	}
+
+	command := deployment.Spec.Template.Spec.Containers[0].Command
+	for _, want := range []string{
+		"--audit-webhook-config-file=/etc/kubernetes/audit/webhook-config.yaml",
+		"--audit-webhook-initial-backoff=5s",
+	} {
+		if !containsFlag(command, want) {
+			t.Errorf("expected rendered apiserver command to carry %q, got %v", want, command)
+		}
+	}
+
+	if !hasVolumeMount(deployment.Spec.Template.Spec.Containers[0].VolumeMounts, "audit-webhook-config", "/etc/kubernetes/audit") {
+		t.Errorf("expected audit-webhook-config volume mounted at /etc/kubernetes/audit, got %v", deployment.Spec.Template.Spec.Containers[0].VolumeMounts)
+	}
+
+	found := false
+	for _, v := range deployment.Spec.Template.Spec.Volumes {
+		if v.Name == "audit-webhook-config" && v.ConfigMap != nil && v.ConfigMap.Name == "audit-webhook-config" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected audit-webhook-config ConfigMap volume, got %v", deployment.Spec.Template.Spec.Volumes)
+	}
+}
+
+func TestEnsureVirtualClusterAPIServerRejectsMismatchedAuditBackend(t *testing.T) {
+	name, namespace := "vc1", "test-namespace"
+	client := newApiserverTestClient(name, namespace)
+	kubeNestConfiguration := &v1alpha1.KubeNestConfiguration{
+		KubeInKubeConfig: v1alpha1.KubeInKubeConfig{
+			Audit: &v1alpha1.AuditConfig{
+				Backend: v1alpha1.AuditLogBackend,
+				Webhook: &v1alpha1.AuditWebhookConfig{ConfigMapName: "audit-webhook-config"},
+			},
+		},
+	}
+	vc := &v1alpha1.VirtualCluster{ObjectMeta: metav1.ObjectMeta{Name: name}}
+
+	if err := EnsureVirtualClusterAPIServer(client, name, namespace, map[string]int32{}, kubeNestConfiguration, vc); err == nil {
+		t.Fatal("expected an error for a Log backend with webhook also set, got nil")
+	}
+}
+
+func TestEnsureVirtualClusterAPIServerAuthWebhooks(t *testing.T) {
+	name, namespace := "vc1", "test-namespace"
+	client := newApiserverTestClient(name, namespace)
+	client.PrependReactor("get", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		getAction := action.(k8stesting.GetAction)
+		return true, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: getAction.GetName(), Namespace: namespace},
+			Data:       map[string][]byte{constants.KubeConfig: []byte("fake-kubeconfig")},
+		}, nil
+	})
+	kubeNestConfiguration := &v1alpha1.KubeNestConfiguration{
+		KubeInKubeConfig: v1alpha1.KubeInKubeConfig{
+			AuthenticationWebhook: &v1alpha1.WebhookKubeconfigConfig{SecretName: "authn-webhook-kubeconfig"},
+			AuthorizationWebhook:  &v1alpha1.WebhookKubeconfigConfig{SecretName: "authz-webhook-kubeconfig"},
+		},
+	}
+	vc := &v1alpha1.VirtualCluster{ObjectMeta: metav1.ObjectMeta{Name: name}}
+
+	if err := EnsureVirtualClusterAPIServer(client, name, namespace, map[string]int32{}, kubeNestConfiguration, vc); err != nil {
+		t.Fatalf("EnsureVirtualClusterAPIServer() error = %v", err)
+	}
+
+	deployment, err := client.AppsV1().Deployments(namespace).Get(context.TODO(), util.GetAPIServerName(name), metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get apiserver deployment: %v", err)
+	}
+
+	command := deployment.Spec.Template.Spec.Containers[0].Command
+	for _, want := range []string{
+		"--authentication-token-webhook-config-file=/etc/kubernetes/authentication-webhook/kubeconfig",
+		"--authorization-webhook-config-file=/etc/kubernetes/authorization-webhook/kubeconfig",
+	} {
+		if !containsFlag(command, want) {
+			t.Errorf("expected rendered apiserver command to carry %q, got %v", want, command)
+		}
+	}
+
+	if !hasVolumeMount(deployment.Spec.Template.Spec.Containers[0].VolumeMounts, "authentication-webhook-config", "/etc/kubernetes/authentication-webhook") {
+		t.Errorf("expected authentication-webhook-config volume mounted, got %v", deployment.Spec.Template.Spec.Containers[0].VolumeMounts)
+	}
+	if !hasVolumeMount(deployment.Spec.Template.Spec.Containers[0].VolumeMounts, "authorization-webhook-config", "/etc/kubernetes/authorization-webhook") {
+		t.Errorf("expected authorization-webhook-config volume mounted, got %v", deployment.Spec.Template.Spec.Containers[0].VolumeMounts)
+	}
+}
+
+func TestEnsureVirtualClusterAPIServerRejectsMissingWebhookSecret(t *testing.T) {
+	name, namespace := "vc1", "test-namespace"
+	client := newApiserverTestClient(name, namespace)
+	kubeNestConfiguration := &v1alpha1.KubeNestConfiguration{
+		KubeInKubeConfig: v1alpha1.KubeInKubeConfig{
+			AuthenticationWebhook: &v1alpha1.WebhookKubeconfigConfig{SecretName: "missing-secret"},
+		},
+	}
+	vc := &v1alpha1.VirtualCluster{ObjectMeta: metav1.ObjectMeta{Name: name}}
+
+	if err := EnsureVirtualClusterAPIServer(client, name, namespace, map[string]int32{}, kubeNestConfiguration, vc); err == nil {
+		t.Fatal("expected an error for a webhook secret that doesn't exist, got nil")
+	}
+}
+
+func TestEnsureVirtualClusterAPIServerRejectsWebhookSecretMissingKubeconfigKey(t *testing.T) {
+	name, namespace := "vc1", "test-namespace"
+	client := newApiserverTestClient(name, namespace)
+	client.PrependReactor("get", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		getAction := action.(k8stesting.GetAction)
+		return true, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: getAction.GetName(), Namespace: namespace},
+		}, nil
+	})
+	kubeNestConfiguration := &v1alpha1.KubeNestConfiguration{
+		KubeInKubeConfig: v1alpha1.KubeInKubeConfig{
+			AuthenticationWebhook: &v1alpha1.WebhookKubeconfigConfig{SecretName: "authn-webhook-kubeconfig"},
+		},
+	}
+	vc := &v1alpha1.VirtualCluster{ObjectMeta: metav1.ObjectMeta{Name: name}}
+
+	if err := EnsureVirtualClusterAPIServer(client, name, namespace, map[string]int32{}, kubeNestConfiguration, vc); err == nil {
+		t.Fatal("expected an error for a webhook secret missing the kubeconfig data key, got nil")
+	}
+}
+
+func TestEnsureVirtualClusterAPIServerCORSAllowedOrigins(t *testing.T) {
+	name, namespace := "vc1", "test-namespace"
+	client := newApiserverTestClient(name, namespace)
+	kubeNestConfiguration := &v1alpha1.KubeNestConfiguration{}
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1alpha1.VirtualClusterSpec{
+			KubeInKubeConfig: &v1alpha1.KubeInKubeConfig{
+				CORSAllowedOrigins: []string{`https://dashboard\.example\.com`, `http://localhost:[0-9]+`},
+			},
+		},
+	}
+
+	if err := EnsureVirtualClusterAPIServer(client, name, namespace, map[string]int32{}, kubeNestConfiguration, vc); err != nil {
+		t.Fatalf("EnsureVirtualClusterAPIServer() error = %v", err)
+	}
+
+	deployment, err := client.AppsV1().Deployments(namespace).Get(context.TODO(), util.GetAPIServerName(name), metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get apiserver deployment: %v", err)
+	}
+
+	command := deployment.Spec.Template.Spec.Containers[0].Command
+	want := `--cors-allowed-origins=https://dashboard\.example\.com,http://localhost:[0-9]+`
+	if !containsFlag(command, want) {
+		t.Errorf("expected rendered apiserver command to carry %q, got %v", want, command)
+	}
+}
+
+func TestEnsureVirtualClusterAPIServerPodSecurityAdmission(t *testing.T) {
+	name, namespace := "vc1", "test-namespace"
+	client := newApiserverTestClient(name, namespace)
+	kubeNestConfiguration := &v1alpha1.KubeNestConfiguration{
+		KubeInKubeConfig: v1alpha1.KubeInKubeConfig{
+			PodSecurityAdmission: &v1alpha1.PodSecurityAdmissionConfig{
+				Enforce: "restricted",
+				Audit:   "baseline",
+				Warn:    "baseline",
+			},
+		},
+	}
+	vc := &v1alpha1.VirtualCluster{ObjectMeta: metav1.ObjectMeta{Name: name}}
+
+	if err := EnsureVirtualClusterAPIServer(client, name, namespace, map[string]int32{}, kubeNestConfiguration, vc); err != nil {
+		t.Fatalf("EnsureVirtualClusterAPIServer() error = %v", err)
+	}
+
+	deployment, err := client.AppsV1().Deployments(namespace).Get(context.TODO(), util.GetAPIServerName(name), metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get apiserver deployment: %v", err)
+	}
+
+	command := deployment.Spec.Template.Spec.Containers[0].Command
+	want := "--admission-control-config-file=/etc/kubernetes/pod-security-admission/admission-config.yaml"
+	if !containsFlag(command, want) {
+		t.Errorf("expected rendered apiserver command to carry %q, got %v", want, command)
+	}
+
+	if !hasVolumeMount(deployment.Spec.Template.Spec.Containers[0].VolumeMounts, "pod-security-admission-config", "/etc/kubernetes/pod-security-admission") {
+		t.Errorf("expected pod-security-admission-config volume mounted, got %v", deployment.Spec.Template.Spec.Containers[0].VolumeMounts)
+	}
+
+	configMap, err := client.CoreV1().ConfigMaps(namespace).Get(context.TODO(), util.GetPodSecurityAdmissionConfigMapName(name), metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get pod security admission config map: %v", err)
+	}
+	admissionConfig := configMap.Data["admission-config.yaml"]
+	for _, want := range []string{"enforce: \"restricted\"", "audit: \"baseline\"", "warn: \"baseline\""} {
+		if !strings.Contains(admissionConfig, want) {
+			t.Errorf("expected admission config to carry %q, got %s", want, admissionConfig)
+		}
+	}
+}
+
+func TestEnsureVirtualClusterAPIServerRejectsInvalidPodSecurityAdmissionLevel(t *testing.T) {
+	name, namespace := "vc1", "test-namespace"
+	client := newApiserverTestClient(name, namespace)
+	kubeNestConfiguration := &v1alpha1.KubeNestConfiguration{
+		KubeInKubeConfig: v1alpha1.KubeInKubeConfig{
+			PodSecurityAdmission: &v1alpha1.PodSecurityAdmissionConfig{Enforce: "bogus"},
+		},
+	}
+	vc := &v1alpha1.VirtualCluster{ObjectMeta: metav1.ObjectMeta{Name: name}}
+
+	if err := EnsureVirtualClusterAPIServer(client, name, namespace, map[string]int32{}, kubeNestConfiguration, vc); err == nil {
+		t.Fatal("expected an error for an invalid podSecurityAdmission level, got nil")
+	}
+}
+
+func TestEnsureVirtualClusterAPIServerRejectsInvalidCORSAllowedOrigin(t *testing.T) {
+	name, namespace := "vc1", "test-namespace"
+	client := newApiserverTestClient(name, namespace)
+	kubeNestConfiguration := &v1alpha1.KubeNestConfiguration{}
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: v1alpha1.VirtualClusterSpec{
+			KubeInKubeConfig: &v1alpha1.KubeInKubeConfig{
+				CORSAllowedOrigins: []string{"("},
+			},
+		},
+	}
+
+	if err := EnsureVirtualClusterAPIServer(client, name, namespace, map[string]int32{}, kubeNestConfiguration, vc); err == nil {
+		t.Fatal("expected an error for a corsAllowedOrigins entry that isn't a valid regular expression, got nil")
+	}
+}
+
+func hasVolumeMount(mounts []corev1.VolumeMount, name, path string) bool {
+	for _, m := range mounts {
+		if m.Name == name && m.MountPath == path {
+			return true
+		}
+	}
+	return false
+}
+
+func hasHostPathVolume(volumes []corev1.Volume, name, path string) bool {
+	for _, v := range volumes {
+		if v.Name == name && v.HostPath != nil && v.HostPath.Path == path {
+			return true
+		}
+	}
+	return false
+}
@@ -10,13 +10,14 @@ import (
 	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
 
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
 	"github.com/kosmos.io/kosmos/pkg/kubenest/constants"
 	controller "github.com/kosmos.io/kosmos/pkg/kubenest/manifest/controlplane/kubecontroller"
 	"github.com/kosmos.io/kosmos/pkg/kubenest/manifest/controlplane/scheduler"
 	"github.com/kosmos.io/kosmos/pkg/kubenest/util"
 )
 
-func EnsureControlPlaneComponent(component, name, namespace string, client clientset.Interface, clusterCIDR string) error {
+func EnsureControlPlaneComponent(component, name, namespace string, client clientset.Interface, clusterCIDR string, profiling bool, nodeMonitorPeriod, nodeMonitorGracePeriod, podEvictionTimeout string, controllerManagerReplicas int32, vc *v1alpha1.VirtualCluster) error {
 	configMaps, err := getComponentConfigMapManifests(name, namespace)
 	if err != nil {
 		return err
@@ -31,7 +32,7 @@ func EnsureControlPlaneComponent(component, name, namespace string, client clien
 		return fmt.Errorf("failed to create configMap resource for component %s, err: %w", component, err)
 	}
 
-	deployments, err := getComponentManifests(name, namespace, clusterCIDR)
+	deployments, err := getComponentManifests(name, namespace, clusterCIDR, profiling, nodeMonitorPeriod, nodeMonitorGracePeriod, podEvictionTimeout, controllerManagerReplicas, vc)
 	if err != nil {
 		return err
 	}
@@ -72,12 +73,12 @@ func DeleteControlPlaneComponent(component, virtualclusterName, namespace string
 	return nil
 }
 
-func getComponentManifests(name, namespace, clusterCIDR string) (map[string]*appsv1.Deployment, error) {
-	kubeControllerManager, err := getKubeControllerManagerManifest(name, namespace, clusterCIDR)
+func getComponentManifests(name, namespace, clusterCIDR string, profiling bool, nodeMonitorPeriod, nodeMonitorGracePeriod, podEvictionTimeout string, controllerManagerReplicas int32, vc *v1alpha1.VirtualCluster) (map[string]*appsv1.Deployment, error) {
+	kubeControllerManager, err := getKubeControllerManagerManifest(name, namespace, clusterCIDR, profiling, nodeMonitorPeriod, nodeMonitorGracePeriod, podEvictionTimeout, controllerManagerReplicas, vc)
 	if err != nil {
 		return nil, err
 	}
-	virtualClusterScheduler, err := getVirtualClusterSchedulerManifest(name, namespace)
+	virtualClusterScheduler, err := getVirtualClusterSchedulerManifest(name, namespace, vc)
 	if err != nil {
 		return nil, err
 	}
@@ -111,8 +112,10 @@ func getComponentConfigmaps(component string) []string {
 	return nil
 }
 
-func getKubeControllerManagerManifest(name, namespace, clusterCIDR string) (*appsv1.Deployment, error) {
-	imageRepository, imageVersion := util.GetImageMessage()
+func getKubeControllerManagerManifest(name, namespace, clusterCIDR string, profiling bool, nodeMonitorPeriod, nodeMonitorGracePeriod, podEvictionTimeout string, replicas int32, vc *v1alpha1.VirtualCluster) (*appsv1.Deployment, error) {
+	_, imageVersion := util.GetImageMessage()
+	imageRepository := util.ResolveImageRepository(vc)
+	kubeControllerManagerImage := util.ResolveComponentImage(vc, constants.KubeControllerManagerComponent, constants.KubeControllerManager, imageVersion)
 
 	vclabel := util.GetVirtualControllerLabel()
 
@@ -128,21 +131,29 @@ func getKubeControllerManagerManifest(name, namespace, clusterCIDR string) (*app
 
 	kubeControllerManagerBytes, err := util.ParseTemplate(controller.KubeControllerManagerDeployment, struct {
 		DeploymentName, Namespace, ImageRepository, Version, VirtualControllerLabel, PodSubnet string
+		KubeControllerManagerImage                                                             string
 		VirtualClusterCertsSecret, KubeconfigSecret, ServiceSubnet                             string
+		NodeMonitorPeriod, NodeMonitorGracePeriod, PodEvictionTimeout                          string
 		Replicas                                                                               int32
 		IPV6First                                                                              bool
+		Profiling                                                                              bool
 	}{
-		DeploymentName:            fmt.Sprintf("%s-%s", name, "kube-controller-manager"),
-		Namespace:                 namespace,
-		ImageRepository:           imageRepository,
-		Version:                   imageVersion,
-		VirtualControllerLabel:    vclabel,
-		VirtualClusterCertsSecret: util.GetCertName(name),
-		KubeconfigSecret:          util.GetAdminConfigClusterIPSecretName(name),
-		ServiceSubnet:             constants.APIServerServiceSubnet,
-		PodSubnet:                 podSubnet,
-		Replicas:                  constants.KubeControllerReplicas,
-		IPV6First:                 IPV6FirstFlag,
+		DeploymentName:             fmt.Sprintf("%s-%s", name, "kube-controller-manager"),
+		Namespace:                  namespace,
+		ImageRepository:            imageRepository,
+		KubeControllerManagerImage: kubeControllerManagerImage,
+		Version:                    imageVersion,
+		VirtualControllerLabel:     vclabel,
+		VirtualClusterCertsSecret:  util.GetCertName(name),
+		KubeconfigSecret:           util.GetAdminConfigClusterIPSecretName(name),
+		ServiceSubnet:              constants.APIServerServiceSubnet,
+		PodSubnet:                  podSubnet,
+		Replicas:                   replicas,
+		IPV6First:                  IPV6FirstFlag,
+		Profiling:                  profiling,
+		NodeMonitorPeriod:          nodeMonitorPeriod,
+		NodeMonitorGracePeriod:     nodeMonitorGracePeriod,
+		PodEvictionTimeout:         podEvictionTimeout,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("error when parsing kube-controller-manager deployment template: %w", err)
@@ -153,6 +164,16 @@ func getKubeControllerManagerManifest(name, namespace, clusterCIDR string) (*app
 		return nil, fmt.Errorf("err when decoding kube-controller-manager deployment: %w", err)
 	}
 
+	var controllerManagerExtraArgs map[string]string
+	if vc != nil && vc.Spec.ControlPlaneConfig != nil {
+		controllerManagerExtraArgs = vc.Spec.ControlPlaneConfig.ControllerManagerExtraArgs
+	}
+	if err := util.ValidateExtraArgs(constants.KubeControllerManagerComponent, controllerManagerExtraArgs, util.DisallowedControllerManagerExtraArgs); err != nil {
+		return nil, err
+	}
+	kcmContainer := &kcm.Spec.Template.Spec.Containers[0]
+	kcmContainer.Command = util.MergeExtraArgsIntoCommand(kcmContainer.Command, controllerManagerExtraArgs)
+
 	return kcm, nil
 }
 
@@ -175,18 +196,22 @@ func getVirtualClusterSchedulerConfigMapManifest(name, namespace string) (*v1.Co
 	return config, nil
 }
 
-func getVirtualClusterSchedulerManifest(name, namespace string) (*appsv1.Deployment, error) {
-	imageRepository, imageVersion := util.GetImageMessage()
+func getVirtualClusterSchedulerManifest(name, namespace string, vc *v1alpha1.VirtualCluster) (*appsv1.Deployment, error) {
+	_, imageVersion := util.GetImageMessage()
+	imageRepository := util.ResolveImageRepository(vc)
+	schedulerImage := util.ResolveComponentImage(vc, constants.VirtualClusterSchedulerComponent, constants.VirtualClusterScheduler, imageVersion)
 	vclabel := util.GetVirtualControllerLabel()
 	virtualClusterSchedulerBytes, err := util.ParseTemplate(scheduler.VirtualClusterSchedulerDeployment, struct {
 		Replicas                                                                                     int32
 		DeploymentName, Namespace, SystemNamespace, ImageRepository, Version, VirtualControllerLabel string
+		SchedulerImage                                                                               string
 		Image, KubeconfigSecret                                                                      string
 	}{
 		DeploymentName:         fmt.Sprintf("%s-%s", name, "virtualcluster-scheduler"),
 		Namespace:              namespace,
 		SystemNamespace:        constants.SystemNs,
 		ImageRepository:        imageRepository,
+		SchedulerImage:         schedulerImage,
 		VirtualControllerLabel: vclabel,
 		Version:                imageVersion,
 		KubeconfigSecret:       util.GetAdminConfigClusterIPSecretName(name),
@@ -201,5 +226,15 @@ func getVirtualClusterSchedulerManifest(name, namespace string) (*appsv1.Deploym
 		return nil, fmt.Errorf("err when decoding virtualCluster-scheduler deployment: %w", err)
 	}
 
+	var schedulerExtraArgs map[string]string
+	if vc != nil && vc.Spec.ControlPlaneConfig != nil {
+		schedulerExtraArgs = vc.Spec.ControlPlaneConfig.SchedulerExtraArgs
+	}
+	if err := util.ValidateExtraArgs(constants.VirtualClusterSchedulerComponent, schedulerExtraArgs, util.DisallowedSchedulerExtraArgs); err != nil {
+		return nil, err
+	}
+	schedulerContainer := &deploy.Spec.Template.Spec.Containers[0]
+	schedulerContainer.Command = util.MergeExtraArgsIntoCommand(schedulerContainer.Command, schedulerExtraArgs)
+
 	return deploy, nil
 }
@@ -0,0 +1,56 @@
+package controlplane
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kosmos.io/kosmos/pkg/kubenest/constants"
+)
+
+func TestEnsureControlPlaneComponentRendersConfiguredReplicas(t *testing.T) {
+	name, namespace := "vc1", "test-namespace"
+	client := fake.NewSimpleClientset()
+
+	err := EnsureControlPlaneComponent(constants.KubeControllerManagerComponent, name, namespace, client, "", false, "", "", "", 4, nil)
+	if err != nil {
+		t.Fatalf("EnsureControlPlaneComponent() error = %v", err)
+	}
+
+	deployment, err := client.AppsV1().Deployments(namespace).Get(context.TODO(), fmt.Sprintf("%s-%s", name, "kube-controller-manager"), metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get kube-controller-manager deployment: %v", err)
+	}
+	if got := *deployment.Spec.Replicas; got != 4 {
+		t.Errorf("expected kube-controller-manager deployment to use the configured replica count 4, got %d", got)
+	}
+}
+
+func TestEnsureControlPlaneComponentRendersNodeLifecycleFlags(t *testing.T) {
+	name, namespace := "vc1", "test-namespace"
+	client := fake.NewSimpleClientset()
+
+	err := EnsureControlPlaneComponent(constants.KubeControllerManagerComponent, name, namespace, client, "", false, "5s", "40s", "5m", constants.KubeControllerReplicas, nil)
+	if err != nil {
+		t.Fatalf("EnsureControlPlaneComponent() error = %v", err)
+	}
+
+	deployment, err := client.AppsV1().Deployments(namespace).Get(context.TODO(), fmt.Sprintf("%s-%s", name, "kube-controller-manager"), metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get kube-controller-manager deployment: %v", err)
+	}
+
+	command := deployment.Spec.Template.Spec.Containers[0].Command
+	for _, want := range []string{
+		"--node-monitor-period=5s",
+		"--node-monitor-grace-period=40s",
+		"--pod-eviction-timeout=5m",
+	} {
+		if !containsFlag(command, want) {
+			t.Errorf("expected rendered kube-controller-manager command to carry %q, got %v", want, command)
+		}
+	}
+}
@@ -32,12 +32,28 @@ func DeleteVirtualClusterEtcd(client clientset.Interface, name, namespace string
 
 // nolint
 func installEtcd(client clientset.Interface, name, namespace string, kubeNestConfiguration *v1alpha1.KubeNestConfiguration, vc *v1alpha1.VirtualCluster) error {
-	imageRepository, imageVersion := util.GetImageMessage()
+	_, imageVersion := util.GetImageMessage()
+	imageRepository := util.ResolveImageRepository(vc)
+
+	var etcdStorageClassOverride, etcdStorageSizeOverride string
+	if vc.Spec.Etcd != nil {
+		etcdStorageClassOverride = vc.Spec.Etcd.StorageClassName
+		etcdStorageSizeOverride = vc.Spec.Etcd.StorageSize
+	}
+	if err := util.ValidateEtcdStorageSize(etcdStorageSizeOverride); err != nil {
+		return err
+	}
 
 	var resourceQuantity resource.Quantity
 	var err error
 
-	if vc.Spec.KubeInKubeConfig.ETCDUnitSize != "" {
+	if etcdStorageSizeOverride != "" {
+		resourceQuantity, err = resource.ParseQuantity(etcdStorageSizeOverride)
+		if err != nil {
+			klog.Errorf("Failed to parse vc.Spec.Etcd.StorageSize %s: %v", etcdStorageSizeOverride, err)
+			return err
+		}
+	} else if vc.Spec.KubeInKubeConfig.ETCDUnitSize != "" {
 		resourceQuantity, err = resource.ParseQuantity(vc.Spec.KubeInKubeConfig.ETCDUnitSize)
 		if err != nil {
 			klog.Errorf("Failed to parse etcdSize %s: %v", vc.Spec.KubeInKubeConfig.ETCDUnitSize, err)
@@ -59,7 +75,16 @@ func installEtcd(client clientset.Interface, name, namespace string, kubeNestCon
 
 	}
 
-	initialClusters := make([]string, constants.EtcdReplicas)
+	var etcdReplicasOverride int
+	if vc.Spec.ControlPlaneConfig != nil {
+		etcdReplicasOverride = vc.Spec.ControlPlaneConfig.EtcdReplicas
+	}
+	if err := util.ValidateEtcdReplicas(etcdReplicasOverride); err != nil {
+		return err
+	}
+	etcdReplicas := util.ResolveReplicas(etcdReplicasOverride, constants.EtcdReplicas)
+
+	initialClusters := make([]string, etcdReplicas)
 	for index := range initialClusters {
 		memberName := fmt.Sprintf("%s-%d", util.GetEtcdServerName(name), index)
 		// build etcd member cluster peer url
@@ -76,31 +101,66 @@ func installEtcd(client clientset.Interface, name, namespace string, kubeNestCon
 	if newErr != nil {
 		return err
 	}
+
+	etcdVersion := kubeNestConfiguration.KubeInKubeConfig.EtcdVersion
+	if vc.Spec.KubeInKubeConfig.EtcdVersion != "" {
+		etcdVersion = vc.Spec.KubeInKubeConfig.EtcdVersion
+	}
+	if etcdVersion == "" {
+		etcdVersion = imageVersion
+	}
+	if err := util.ValidateEtcdVersionCompatibility(imageVersion, etcdVersion); err != nil {
+		return err
+	}
+
+	snapshotCount := vc.Spec.KubeInKubeConfig.EtcdSnapshotCount
+	if err := util.ValidatePositiveEtcdTuningValue("etcdSnapshotCount", snapshotCount); err != nil {
+		return err
+	}
+	if snapshotCount == 0 {
+		snapshotCount = constants.EtcdDefaultSnapshotCount
+	}
+	autoCompactionRetentionHours := vc.Spec.KubeInKubeConfig.EtcdAutoCompactionRetentionHours
+	if err := util.ValidatePositiveEtcdTuningValue("etcdAutoCompactionRetentionHours", autoCompactionRetentionHours); err != nil {
+		return err
+	}
+
+	etcdImage := util.ResolveComponentImage(vc, constants.Etcd, "etcd", etcdVersion)
+
+	etcdStorageClass := kubeNestConfiguration.KubeInKubeConfig.ETCDStorageClass
+	if etcdStorageClassOverride != "" {
+		etcdStorageClass = etcdStorageClassOverride
+	}
+
 	etcdStatefulSetBytes, err := util.ParseTemplate(etcd.EtcdStatefulSet, struct {
-		StatefulSetName, Namespace, ImageRepository, Image, EtcdClientService, Version, VirtualControllerLabel string
-		CertsSecretName, EtcdPeerServiceName                                                                   string
-		InitialCluster, EtcdDataVolumeName, EtcdCipherSuites                                                   string
-		Replicas, EtcdListenClientPort, EtcdListenPeerPort                                                     int32
-		ETCDStorageClass, ETCDStorageSize                                                                      string
-		IPV6First                                                                                              bool
+		StatefulSetName, Namespace, ImageRepository, Image, EtcdImage, EtcdClientService, Version, VirtualControllerLabel string
+		CertsSecretName, EtcdPeerServiceName                                                                              string
+		InitialCluster, EtcdDataVolumeName, EtcdCipherSuites                                                              string
+		Replicas, EtcdListenClientPort, EtcdListenPeerPort                                                                int32
+		ETCDStorageClass, ETCDStorageSize                                                                                 string
+		EtcdSnapshotCount, EtcdAutoCompactionRetentionHours                                                               int64
+		IPV6First                                                                                                         bool
 	}{
-		StatefulSetName:        util.GetEtcdServerName(name),
-		Namespace:              namespace,
-		ImageRepository:        imageRepository,
-		Version:                imageVersion,
-		VirtualControllerLabel: vcLabel,
-		EtcdClientService:      util.GetEtcdClientServerName(name),
-		CertsSecretName:        util.GetEtcdCertName(name),
-		EtcdPeerServiceName:    util.GetEtcdServerName(name),
-		EtcdDataVolumeName:     constants.EtcdDataVolumeName,
-		InitialCluster:         strings.Join(initialClusters, ","),
-		EtcdCipherSuites:       strings.Join(flag.PreferredTLSCipherNames(), ","),
-		Replicas:               constants.EtcdReplicas,
-		EtcdListenClientPort:   constants.EtcdListenClientPort,
-		EtcdListenPeerPort:     constants.EtcdListenPeerPort,
-		ETCDStorageClass:       kubeNestConfiguration.KubeInKubeConfig.ETCDStorageClass,
-		ETCDStorageSize:        resourceQuantity.String(),
-		IPV6First:              IPV6FirstFlag,
+		StatefulSetName:                  util.GetEtcdServerName(name),
+		Namespace:                        namespace,
+		ImageRepository:                  imageRepository,
+		EtcdImage:                        etcdImage,
+		Version:                          etcdVersion,
+		VirtualControllerLabel:           vcLabel,
+		EtcdClientService:                util.GetEtcdClientServerName(name),
+		CertsSecretName:                  util.GetEtcdCertName(name),
+		EtcdPeerServiceName:              util.GetEtcdServerName(name),
+		EtcdDataVolumeName:               constants.EtcdDataVolumeName,
+		InitialCluster:                   strings.Join(initialClusters, ","),
+		EtcdCipherSuites:                 strings.Join(flag.PreferredTLSCipherNames(), ","),
+		Replicas:                         etcdReplicas,
+		EtcdListenClientPort:             constants.EtcdListenClientPort,
+		EtcdListenPeerPort:               constants.EtcdListenPeerPort,
+		ETCDStorageClass:                 etcdStorageClass,
+		ETCDStorageSize:                  resourceQuantity.String(),
+		EtcdSnapshotCount:                snapshotCount,
+		EtcdAutoCompactionRetentionHours: autoCompactionRetentionHours,
+		IPV6First:                        IPV6FirstFlag,
 	})
 	if err != nil {
 		return fmt.Errorf("error when parsing Etcd statefuelset template: %w", err)
@@ -0,0 +1,229 @@
+package controlplane
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/constants"
+)
+
+func TestEnsureVirtualClusterEtcdRendersConfiguredSnapshotCount(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	kubeNestConfiguration := &v1alpha1.KubeNestConfiguration{
+		KubeInKubeConfig: v1alpha1.KubeInKubeConfig{
+			ETCDStorageClass: "standard",
+			ETCDUnitSize:     "1Gi",
+		},
+	}
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			KubeInKubeConfig: &v1alpha1.KubeInKubeConfig{
+				ETCDUnitSize:                     "1Gi",
+				EtcdSnapshotCount:                5000,
+				EtcdAutoCompactionRetentionHours: 12,
+			},
+		},
+	}
+
+	if err := EnsureVirtualClusterEtcd(client, "vc1", "test-namespace", kubeNestConfiguration, vc); err != nil {
+		t.Fatalf("EnsureVirtualClusterEtcd() error = %v", err)
+	}
+
+	sts, err := client.AppsV1().StatefulSets("test-namespace").Get(context.TODO(), "vc1-etcd", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get etcd statefulset: %v", err)
+	}
+
+	command := sts.Spec.Template.Spec.Containers[0].Command
+	if !containsFlag(command, "--snapshot-count=5000") {
+		t.Errorf("expected rendered etcd command to carry --snapshot-count=5000, got %v", command)
+	}
+	if !containsFlag(command, "--auto-compaction-retention=12") {
+		t.Errorf("expected rendered etcd command to carry --auto-compaction-retention=12, got %v", command)
+	}
+}
+
+func TestEnsureVirtualClusterEtcdRendersConfiguredVersion(t *testing.T) {
+	t.Setenv(constants.DefaultImageVersionEnv, "v1.27.6")
+
+	client := fake.NewSimpleClientset()
+	kubeNestConfiguration := &v1alpha1.KubeNestConfiguration{
+		KubeInKubeConfig: v1alpha1.KubeInKubeConfig{
+			ETCDStorageClass: "standard",
+			ETCDUnitSize:     "1Gi",
+		},
+	}
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			KubeInKubeConfig: &v1alpha1.KubeInKubeConfig{
+				ETCDUnitSize: "1Gi",
+				EtcdVersion:  "3.5.9",
+			},
+		},
+	}
+
+	if err := EnsureVirtualClusterEtcd(client, "vc1", "test-namespace", kubeNestConfiguration, vc); err != nil {
+		t.Fatalf("EnsureVirtualClusterEtcd() error = %v", err)
+	}
+
+	sts, err := client.AppsV1().StatefulSets("test-namespace").Get(context.TODO(), "vc1-etcd", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get etcd statefulset: %v", err)
+	}
+
+	image := sts.Spec.Template.Spec.Containers[0].Image
+	if !strings.HasSuffix(image, ":3.5.9") {
+		t.Errorf("expected rendered etcd image to use configured version 3.5.9, got %q", image)
+	}
+}
+
+func TestEnsureVirtualClusterEtcdRejectsIncompatibleVersion(t *testing.T) {
+	t.Setenv(constants.DefaultImageVersionEnv, "v1.27.6")
+
+	client := fake.NewSimpleClientset()
+	kubeNestConfiguration := &v1alpha1.KubeNestConfiguration{
+		KubeInKubeConfig: v1alpha1.KubeInKubeConfig{
+			ETCDStorageClass: "standard",
+			ETCDUnitSize:     "1Gi",
+		},
+	}
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			KubeInKubeConfig: &v1alpha1.KubeInKubeConfig{
+				ETCDUnitSize: "1Gi",
+				EtcdVersion:  "2.3.0",
+			},
+		},
+	}
+
+	if err := EnsureVirtualClusterEtcd(client, "vc1", "test-namespace", kubeNestConfiguration, vc); err == nil {
+		t.Fatal("expected EnsureVirtualClusterEtcd() to reject an etcd version incompatible with the apiserver version")
+	}
+}
+
+func TestEnsureVirtualClusterEtcdRendersConfiguredReplicas(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	kubeNestConfiguration := &v1alpha1.KubeNestConfiguration{
+		KubeInKubeConfig: v1alpha1.KubeInKubeConfig{
+			ETCDStorageClass: "standard",
+			ETCDUnitSize:     "1Gi",
+		},
+	}
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			KubeInKubeConfig:   &v1alpha1.KubeInKubeConfig{ETCDUnitSize: "1Gi"},
+			ControlPlaneConfig: &v1alpha1.ControlPlaneConfig{EtcdReplicas: 5},
+		},
+	}
+
+	if err := EnsureVirtualClusterEtcd(client, "vc1", "test-namespace", kubeNestConfiguration, vc); err != nil {
+		t.Fatalf("EnsureVirtualClusterEtcd() error = %v", err)
+	}
+
+	sts, err := client.AppsV1().StatefulSets("test-namespace").Get(context.TODO(), "vc1-etcd", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get etcd statefulset: %v", err)
+	}
+	if got := *sts.Spec.Replicas; got != 5 {
+		t.Errorf("expected etcd statefulset to use the configured replica count 5, got %d", got)
+	}
+}
+
+func TestEnsureVirtualClusterEtcdRejectsEvenReplicaCount(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	kubeNestConfiguration := &v1alpha1.KubeNestConfiguration{
+		KubeInKubeConfig: v1alpha1.KubeInKubeConfig{
+			ETCDStorageClass: "standard",
+			ETCDUnitSize:     "1Gi",
+		},
+	}
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			KubeInKubeConfig:   &v1alpha1.KubeInKubeConfig{ETCDUnitSize: "1Gi"},
+			ControlPlaneConfig: &v1alpha1.ControlPlaneConfig{EtcdReplicas: 4},
+		},
+	}
+
+	if err := EnsureVirtualClusterEtcd(client, "vc1", "test-namespace", kubeNestConfiguration, vc); err == nil {
+		t.Fatal("expected EnsureVirtualClusterEtcd() to reject an even etcd replica count")
+	}
+}
+
+func TestEnsureVirtualClusterEtcdRendersOverriddenStorageClassAndSize(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	kubeNestConfiguration := &v1alpha1.KubeNestConfiguration{
+		KubeInKubeConfig: v1alpha1.KubeInKubeConfig{
+			ETCDStorageClass: "standard",
+			ETCDUnitSize:     "1Gi",
+		},
+	}
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			KubeInKubeConfig: &v1alpha1.KubeInKubeConfig{ETCDUnitSize: "1Gi"},
+			Etcd: &v1alpha1.EtcdConfig{
+				StorageClassName: "fast-ssd",
+				StorageSize:      "20Gi",
+			},
+		},
+	}
+
+	if err := EnsureVirtualClusterEtcd(client, "vc1", "test-namespace", kubeNestConfiguration, vc); err != nil {
+		t.Fatalf("EnsureVirtualClusterEtcd() error = %v", err)
+	}
+
+	sts, err := client.AppsV1().StatefulSets("test-namespace").Get(context.TODO(), "vc1-etcd", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get etcd statefulset: %v", err)
+	}
+	if len(sts.Spec.VolumeClaimTemplates) != 1 {
+		t.Fatalf("expected 1 volume claim template, got %d", len(sts.Spec.VolumeClaimTemplates))
+	}
+	pvc := sts.Spec.VolumeClaimTemplates[0]
+	if got := *pvc.Spec.StorageClassName; got != "fast-ssd" {
+		t.Errorf("expected storage class fast-ssd, got %q", got)
+	}
+	if got := pvc.Spec.Resources.Requests.Storage().String(); got != "20Gi" {
+		t.Errorf("expected storage size 20Gi, got %q", got)
+	}
+}
+
+func TestEnsureVirtualClusterEtcdRejectsInvalidStorageSize(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	kubeNestConfiguration := &v1alpha1.KubeNestConfiguration{
+		KubeInKubeConfig: v1alpha1.KubeInKubeConfig{
+			ETCDStorageClass: "standard",
+			ETCDUnitSize:     "1Gi",
+		},
+	}
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1"},
+		Spec: v1alpha1.VirtualClusterSpec{
+			KubeInKubeConfig: &v1alpha1.KubeInKubeConfig{ETCDUnitSize: "1Gi"},
+			Etcd:             &v1alpha1.EtcdConfig{StorageSize: "not-a-quantity"},
+		},
+	}
+
+	if err := EnsureVirtualClusterEtcd(client, "vc1", "test-namespace", kubeNestConfiguration, vc); err == nil {
+		t.Fatal("expected EnsureVirtualClusterEtcd() to reject an unparseable etcd.storageSize")
+	}
+}
+
+func containsFlag(command []string, flag string) bool {
+	for _, c := range command {
+		if strings.TrimSpace(c) == flag {
+			return true
+		}
+	}
+	return false
+}
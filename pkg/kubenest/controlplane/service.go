@@ -58,6 +58,7 @@ func createServerService(client clientset.Interface, name, namespace string, por
 		ServiceName, Namespace, ServiceType string
 		ServicePort                         int32
 		IPFamilies                          []corev1.IPFamily
+		IPFamilyPolicy                      corev1.IPFamilyPolicy
 		UseAPIServerNodePort                bool
 	}{
 		ServiceName:          util.GetAPIServerName(name),
@@ -65,6 +66,7 @@ func createServerService(client clientset.Interface, name, namespace string, por
 		ServiceType:          constants.APIServerServiceType,
 		ServicePort:          portMap[constants.APIServerPortKey],
 		IPFamilies:           ipFamilies,
+		IPFamilyPolicy:       utils.IPFamilyPolicyFor(ipFamilies),
 		UseAPIServerNodePort: vc.Spec.KubeInKubeConfig != nil && vc.Spec.KubeInKubeConfig.APIServerServiceType == v1alpha1.NodePort,
 	})
 	if err != nil {
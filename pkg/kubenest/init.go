@@ -35,6 +35,7 @@ type initData struct {
 	privateRegistry       string
 	externalIP            string
 	externalIps           []string
+	externalHostName      string
 	vipMap                map[string]string
 	hostPort              int32
 	hostPortMap           map[string]int32
@@ -48,55 +49,129 @@ type InitOptions struct {
 	Name                  string
 	Namespace             string
 	Kubeconfig            *rest.Config
-	virtualClusterVersion string
 	virtualClusterDataDir string
 	virtualCluster        *v1alpha1.VirtualCluster
 	KubeNestOptions       *v1alpha1.KubeNestConfiguration
+	// ExtraInitTasks and ExtraUninstallTasks are registered into the
+	// TaskRegistry alongside the built-in init/uninstall tasks, letting a
+	// caller insert custom provisioning steps (e.g. applying an org-mandated
+	// CNI) without forking NewInitPhase/UninstallPhase. Priority/After in
+	// each entry is resolved against the built-in entries' own names, listed
+	// in the doc comment above registerBuiltinInitTasks/
+	// registerBuiltinUninstallTasks.
+	ExtraInitTasks      []workflow.TaskEntry
+	ExtraUninstallTasks []workflow.TaskEntry
 }
 
-func NewInitPhase(opts *InitOptions) *workflow.Phase {
-	initPhase := workflow.NewPhase()
+// builtinTaskPriorityStep spaces built-in task priorities apart, leaving
+// room for a caller's ExtraInitTasks/ExtraUninstallTasks entry to slot in
+// between two built-in tasks via Priority without renumbering anything.
+const builtinTaskPriorityStep = 10
+
+// registerBuiltinInitTasks registers kosmos's default init task set, in the
+// same order NewInitPhase has always run them, under the following names:
+// "virtualcluster-service", "certs", "upload-certs", "etcd", "apiserver",
+// "upload-kubeconfig", "check-apiserver-health", "components",
+// "check-controlplane", "anp", "coredns", "components-from-manifests",
+// "endpoint", "apf", "resourcequota", "default-storageclass".
+func registerBuiltinInitTasks(registry *workflow.TaskRegistry) {
+	builtins := []struct {
+		name string
+		task workflow.Task
+	}{
+		{"virtualcluster-service", tasks.NewVirtualClusterServiceTask()},
+		{"certs", tasks.NewCertTask()},
+		{"upload-certs", tasks.NewUploadCertsTask()},
+		{"etcd", tasks.NewEtcdTask()},
+		{"apiserver", tasks.NewVirtualClusterApiserverTask()},
+		{"upload-kubeconfig", tasks.NewUploadKubeconfigTask()},
+		{"check-apiserver-health", tasks.NewCheckApiserverHealthTask()},
+		{"components", tasks.NewComponentTask()},
+		{"check-controlplane", tasks.NewCheckControlPlaneTask()},
+		{"anp", tasks.NewAnpTask()},
+		// create proxy
+		// {"proxy", tasks.NewVirtualClusterProxyTask()},
+		// create core-dns
+		{"coredns", tasks.NewCoreDNSTask()},
+		// add server
+		{"components-from-manifests", tasks.NewComponentsFromManifestsTask()},
+		{"endpoint", tasks.NewEndPointTask()},
+		// configure per-client connection limits
+		{"apf", tasks.NewAPFTask()},
+		// size the namespace's ResourceQuota to the control plane's own footprint
+		{"resourcequota", tasks.NewResourceQuotaTask()},
+		// reconcile the tenant cluster's default StorageClass, if configured
+		{"default-storageclass", tasks.NewDefaultStorageClassTask()},
+	}
+	for i, b := range builtins {
+		registry.Register(workflow.TaskEntry{Name: b.name, Task: b.task, Priority: (i + 1) * builtinTaskPriorityStep})
+	}
+}
+
+// registerBuiltinUninstallTasks registers kosmos's default uninstall task
+// set, in the same order UninstallPhase has always run them, under the
+// following names: "coredns", "components", "apiserver", "anp", "etcd",
+// "virtualcluster-service", "certs-and-kubeconfig", "etcd-pvc".
+func registerBuiltinUninstallTasks(registry *workflow.TaskRegistry) {
+	builtins := []struct {
+		name string
+		task workflow.Task
+	}{
+		{"coredns", tasks.UninstallCoreDNSTask()},
+		{"components", tasks.UninstallComponentTask()},
+		{"apiserver", tasks.UninstallVirtualClusterApiserverTask()},
+		{"anp", tasks.UninstallAnpTask()},
+		{"etcd", tasks.UninstallEtcdTask()},
+		{"virtualcluster-service", tasks.UninstallVirtualClusterServiceTask()},
+		{"certs-and-kubeconfig", tasks.UninstallCertsAndKubeconfigTask()},
+		{"etcd-pvc", tasks.DeleteEtcdPvcTask()},
+		// {"proxy", tasks.UninstallVirtualClusterProxyTask()},
+	}
+	for i, b := range builtins {
+		registry.Register(workflow.TaskEntry{Name: b.name, Task: b.task, Priority: (i + 1) * builtinTaskPriorityStep})
+	}
+}
 
-	initPhase.AppendTask(tasks.NewVirtualClusterServiceTask())
-	initPhase.AppendTask(tasks.NewCertTask())
-	initPhase.AppendTask(tasks.NewUploadCertsTask())
-	initPhase.AppendTask(tasks.NewEtcdTask())
-	initPhase.AppendTask(tasks.NewVirtualClusterApiserverTask())
-	initPhase.AppendTask(tasks.NewUploadKubeconfigTask())
-	initPhase.AppendTask(tasks.NewCheckApiserverHealthTask())
-	initPhase.AppendTask(tasks.NewComponentTask())
-	initPhase.AppendTask(tasks.NewCheckControlPlaneTask())
-	initPhase.AppendTask(tasks.NewAnpTask())
-	// create proxy
-	//initPhase.AppendTask(tasks.NewVirtualClusterProxyTask())
-	// create core-dns
-	initPhase.AppendTask(tasks.NewCoreDNSTask())
-	// add server
-	initPhase.AppendTask(tasks.NewComponentsFromManifestsTask())
-	initPhase.AppendTask(tasks.NewEndPointTask())
+func NewInitPhase(opts *InitOptions) (*workflow.Phase, error) {
+	registry := workflow.NewTaskRegistry()
+	registerBuiltinInitTasks(registry)
+	for _, extra := range opts.ExtraInitTasks {
+		registry.Register(extra)
+	}
+	resolved, err := registry.Resolve()
+	if err != nil {
+		return nil, fmt.Errorf("resolve init task registry: %w", err)
+	}
 
+	initPhase := workflow.NewPhase()
+	for _, task := range resolved {
+		initPhase.AppendTask(task)
+	}
 	initPhase.SetDataInitializer(func() (workflow.RunData, error) {
 		return newRunData(opts)
 	})
-	return initPhase
+	return initPhase, nil
 }
 
-func UninstallPhase(opts *InitOptions) *workflow.Phase {
-	destroyPhase := workflow.NewPhase()
-	destroyPhase.AppendTask(tasks.UninstallCoreDNSTask())
-	destroyPhase.AppendTask(tasks.UninstallComponentTask())
-	destroyPhase.AppendTask(tasks.UninstallVirtualClusterApiserverTask())
-	destroyPhase.AppendTask(tasks.UninstallAnpTask())
-	destroyPhase.AppendTask(tasks.UninstallEtcdTask())
-	destroyPhase.AppendTask(tasks.UninstallVirtualClusterServiceTask())
-	destroyPhase.AppendTask(tasks.UninstallCertsAndKubeconfigTask())
-	destroyPhase.AppendTask(tasks.DeleteEtcdPvcTask())
-	//destroyPhase.AppendTask(tasks.UninstallVirtualClusterProxyTask())
+func UninstallPhase(opts *InitOptions) (*workflow.Phase, error) {
+	registry := workflow.NewTaskRegistry()
+	registerBuiltinUninstallTasks(registry)
+	for _, extra := range opts.ExtraUninstallTasks {
+		registry.Register(extra)
+	}
+	resolved, err := registry.Resolve()
+	if err != nil {
+		return nil, fmt.Errorf("resolve uninstall task registry: %w", err)
+	}
 
+	destroyPhase := workflow.NewPhase()
+	for _, task := range resolved {
+		destroyPhase.AppendTask(task)
+	}
 	destroyPhase.SetDataInitializer(func() (workflow.RunData, error) {
 		return newRunData(opts)
 	})
-	return destroyPhase
+	return destroyPhase, nil
 }
 
 type InitOpt func(o *InitOptions)
@@ -113,7 +188,6 @@ func NewPhaseInitOptions(opts ...InitOpt) *InitOptions {
 func defaultJobInitOptions() *InitOptions {
 	virtualCluster := &v1alpha1.VirtualCluster{}
 	return &InitOptions{
-		virtualClusterVersion: "0.0.0",
 		virtualClusterDataDir: "var/lib/virtualCluster",
 		virtualCluster:        virtualCluster,
 	}
@@ -139,6 +213,23 @@ func NewInitOptWithKubeNestOptions(options *v1alpha1.KubeNestConfiguration) Init
 	}
 }
 
+// NewInitOptWithExtraInitTasks registers additional tasks into NewInitPhase's
+// TaskRegistry alongside the built-in init tasks. See InitOptions.ExtraInitTasks.
+func NewInitOptWithExtraInitTasks(entries ...workflow.TaskEntry) InitOpt {
+	return func(o *InitOptions) {
+		o.ExtraInitTasks = append(o.ExtraInitTasks, entries...)
+	}
+}
+
+// NewInitOptWithExtraUninstallTasks registers additional tasks into
+// UninstallPhase's TaskRegistry alongside the built-in uninstall tasks. See
+// InitOptions.ExtraUninstallTasks.
+func NewInitOptWithExtraUninstallTasks(entries ...workflow.TaskEntry) InitOpt {
+	return func(o *InitOptions) {
+		o.ExtraUninstallTasks = append(o.ExtraUninstallTasks, entries...)
+	}
+}
+
 func newRunData(opt *InitOptions) (*initData, error) {
 	if err := opt.Validate(); err != nil {
 		return nil, err
@@ -160,9 +251,10 @@ func newRunData(opt *InitOptions) (*initData, error) {
 		return nil, fmt.Errorf("error when creating  kosmosClient client, err: %w", err)
 	}
 
-	version, err := utilversion.ParseGeneric(opt.virtualClusterVersion)
+	kubernetesVersion := util.ResolveKubernetesVersion(opt.virtualCluster, opt.KubeNestOptions)
+	version, err := utilversion.ParseGeneric(kubernetesVersion)
 	if err != nil {
-		return nil, fmt.Errorf("unexpected virtual cluster invalid version %s", opt.virtualClusterVersion)
+		return nil, fmt.Errorf("unexpected virtual cluster invalid version %s", kubernetesVersion)
 	}
 
 	var address string
@@ -190,6 +282,7 @@ func newRunData(opt *InitOptions) (*initData, error) {
 		CertStore:             cert.NewCertStore(),
 		externalIP:            opt.virtualCluster.Spec.ExternalIP,
 		externalIps:           opt.virtualCluster.Spec.ExternalIps,
+		externalHostName:      opt.virtualCluster.Spec.ExternalHostName,
 		hostPort:              opt.virtualCluster.Status.Port,
 		hostPortMap:           opt.virtualCluster.Status.PortMap,
 		vipMap:                opt.virtualCluster.Status.VipMap,
@@ -208,9 +301,16 @@ func (opt *InitOptions) Validate() error {
 		return errors.New("unexpected empty name or namespace")
 	}
 
-	_, err := utilversion.ParseGeneric(opt.virtualClusterVersion)
-	if err != nil {
-		return fmt.Errorf("unexpected virtual cluster invalid version %s", opt.virtualClusterVersion)
+	if err := util.ValidateKubernetesVersion(util.ResolveKubernetesVersion(opt.virtualCluster, opt.KubeNestOptions)); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := util.ValidateExternalHostName(opt.virtualCluster.Spec.ExternalHostName); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := util.ValidateExternalIPs(opt.virtualCluster.Spec.ExternalIps); err != nil {
+		errs = append(errs, err)
 	}
 
 	return utilerrors.NewAggregate(errs)
@@ -240,6 +340,10 @@ func (i initData) RemoteClient() clientset.Interface {
 	return i.remoteClient
 }
 
+func (i initData) VirtualClusterVersion() string {
+	return i.virtualClusterVersion.String()
+}
+
 func (i initData) KosmosClient() versioned.Interface {
 	return i.kosmosClient
 }
@@ -258,6 +362,8 @@ func (i initData) ExternalIP() string {
 
 func (i initData) ExternalIPs() []string { return i.externalIps }
 
+func (i initData) ExternalHostName() string { return i.externalHostName }
+
 func (i initData) VipMap() map[string]string {
 	return i.vipMap
 }
@@ -289,3 +395,11 @@ func (i initData) PluginOptions() map[string]string {
 	}
 	return pluginOptoinsMapping
 }
+
+func (i initData) ResourceLabels() map[string]string {
+	return i.virtualCluster.Spec.ResourceLabels
+}
+
+func (i initData) ResourceAnnotations() map[string]string {
+	return i.virtualCluster.Spec.ResourceAnnotations
+}
@@ -0,0 +1,56 @@
+package kubenest
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/constants"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/util"
+)
+
+// GetAdminKubeconfig reads vc's admin kubeconfig Secret from hostClient and
+// returns its raw bytes, consolidating the decode logic (Secret name,
+// constants.KubeConfig data key) that used to be duplicated across the
+// init controller's tasks. vc must have already reached the Completed or
+// WorkersScaledDown phase -- the admin kubeconfig Secret doesn't exist, or
+// may still be mid-write, before then.
+func GetAdminKubeconfig(ctx context.Context, hostClient clientset.Interface, vc *v1alpha1.VirtualCluster) ([]byte, error) {
+	if vc.Status.Phase != v1alpha1.Completed && vc.Status.Phase != v1alpha1.WorkersScaledDown {
+		return nil, fmt.Errorf("virtualcluster %s/%s is not ready: phase is %q, want %q or %q",
+			vc.GetNamespace(), vc.GetName(), vc.Status.Phase, v1alpha1.Completed, v1alpha1.WorkersScaledDown)
+	}
+
+	secretName := util.GetAdminConfigSecretName(vc.GetName())
+	secret, err := hostClient.CoreV1().Secrets(vc.GetNamespace()).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("get admin kubeconfig secret %s/%s: %w", vc.GetNamespace(), secretName, err)
+	}
+
+	kubeconfig := secret.Data[constants.KubeConfig]
+	if len(kubeconfig) == 0 {
+		return nil, fmt.Errorf("admin kubeconfig secret %s/%s is missing data key %q", vc.GetNamespace(), secretName, constants.KubeConfig)
+	}
+	return kubeconfig, nil
+}
+
+// GetAdminRestConfig is GetAdminKubeconfig followed by
+// clientcmd.RESTConfigFromKubeConfig, for callers that want a ready-to-use
+// *rest.Config instead of raw kubeconfig bytes.
+func GetAdminRestConfig(ctx context.Context, hostClient clientset.Interface, vc *v1alpha1.VirtualCluster) (*rest.Config, error) {
+	kubeconfig, err := GetAdminKubeconfig(ctx, hostClient, vc)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("build rest.Config from virtualcluster %s/%s admin kubeconfig: %w", vc.GetNamespace(), vc.GetName(), err)
+	}
+	return config, nil
+}
@@ -0,0 +1,120 @@
+package kubenest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/constants"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/util"
+)
+
+const fakeKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- cluster:
+    server: https://127.0.0.1:6443
+  name: vc1
+contexts:
+- context:
+    cluster: vc1
+    user: vc1-admin
+  name: vc1
+current-context: vc1
+users:
+- name: vc1-admin
+  user: {}
+`
+
+func TestGetAdminKubeconfigRejectsUnreadyVirtualCluster(t *testing.T) {
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1", Namespace: "ns1"},
+		Status:     v1alpha1.VirtualClusterStatus{Phase: v1alpha1.Preparing},
+	}
+	client := fake.NewSimpleClientset()
+
+	_, err := GetAdminKubeconfig(context.TODO(), client, vc)
+	if err == nil {
+		t.Fatal("GetAdminKubeconfig() error = nil, want an error for a VirtualCluster that isn't Completed or WorkersScaledDown yet")
+	}
+	if !strings.Contains(err.Error(), string(v1alpha1.Preparing)) {
+		t.Fatalf("expected error to name the current phase, got %q", err.Error())
+	}
+}
+
+func TestGetAdminKubeconfigReadsSecret(t *testing.T) {
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1", Namespace: "ns1"},
+		Status:     v1alpha1.VirtualClusterStatus{Phase: v1alpha1.Completed},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: util.GetAdminConfigSecretName(vc.Name), Namespace: vc.Namespace},
+		Data:       map[string][]byte{constants.KubeConfig: []byte(fakeKubeconfig)},
+	}
+	client := fake.NewSimpleClientset(secret)
+
+	got, err := GetAdminKubeconfig(context.TODO(), client, vc)
+	if err != nil {
+		t.Fatalf("GetAdminKubeconfig() error = %v", err)
+	}
+	if string(got) != fakeKubeconfig {
+		t.Errorf("GetAdminKubeconfig() = %q, want %q", got, fakeKubeconfig)
+	}
+}
+
+func TestGetAdminKubeconfigAllowsWorkersScaledDown(t *testing.T) {
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1", Namespace: "ns1"},
+		Status:     v1alpha1.VirtualClusterStatus{Phase: v1alpha1.WorkersScaledDown},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: util.GetAdminConfigSecretName(vc.Name), Namespace: vc.Namespace},
+		Data:       map[string][]byte{constants.KubeConfig: []byte(fakeKubeconfig)},
+	}
+	client := fake.NewSimpleClientset(secret)
+
+	if _, err := GetAdminKubeconfig(context.TODO(), client, vc); err != nil {
+		t.Fatalf("GetAdminKubeconfig() error = %v, want nil since a hibernated control plane's kubeconfig is still valid", err)
+	}
+}
+
+func TestGetAdminKubeconfigErrorsOnMissingDataKey(t *testing.T) {
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1", Namespace: "ns1"},
+		Status:     v1alpha1.VirtualClusterStatus{Phase: v1alpha1.Completed},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: util.GetAdminConfigSecretName(vc.Name), Namespace: vc.Namespace},
+	}
+	client := fake.NewSimpleClientset(secret)
+
+	_, err := GetAdminKubeconfig(context.TODO(), client, vc)
+	if err == nil {
+		t.Fatal("GetAdminKubeconfig() error = nil, want an error when the secret has no kubeconfig data")
+	}
+}
+
+func TestGetAdminRestConfigBuildsRestConfig(t *testing.T) {
+	vc := &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1", Namespace: "ns1"},
+		Status:     v1alpha1.VirtualClusterStatus{Phase: v1alpha1.Completed},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: util.GetAdminConfigSecretName(vc.Name), Namespace: vc.Namespace},
+		Data:       map[string][]byte{constants.KubeConfig: []byte(fakeKubeconfig)},
+	}
+	client := fake.NewSimpleClientset(secret)
+
+	config, err := GetAdminRestConfig(context.TODO(), client, vc)
+	if err != nil {
+		t.Fatalf("GetAdminRestConfig() error = %v", err)
+	}
+	if config.Host != "https://127.0.0.1:6443" {
+		t.Errorf("config.Host = %q, want %q", config.Host, "https://127.0.0.1:6443")
+	}
+}
@@ -49,7 +49,7 @@ spec:
               topologyKey: kubernetes.io/hostname
       containers:
       - name: kube-apiserver
-        image:  {{ .ImageRepository }}/kube-apiserver:{{ .Version }}
+        image:  {{ .APIServerImage }}
         imagePullPolicy: IfNotPresent
         env:
         {{ if .UseAPIServerNodePort }}
@@ -103,6 +103,19 @@ spec:
         - --max-requests-inflight=1500
         - --max-mutating-requests-inflight=500
         - --v=4
+        - --profiling={{ .Profiling }}
+        {{ if .ShutdownDelayDuration }}
+        - --shutdown-delay-duration={{ .ShutdownDelayDuration }}
+        {{ end }}
+        {{ if .ShutdownGracePeriod }}
+        - --shutdown-grace-period={{ .ShutdownGracePeriod }}
+        {{ end }}
+        {{ if .StorageMediaType }}
+        - --storage-media-type={{ .StorageMediaType }}
+        {{ end }}
+        {{ if .CORSAllowedOrigins }}
+        - --cors-allowed-origins={{ .CORSAllowedOrigins }}
+        {{ end }}
         {{ if .UseAPIServerNodePort }}
         - --advertise-address=$(HOSTIP)
         {{ else }}
@@ -111,6 +124,33 @@ spec:
         {{ if not .AdmissionPlugins }}
         - --disable-admission-plugins=License
         {{ end }}
+        {{ if eq .AuditBackend "Log" }}
+        - --audit-log-path={{ .AuditLogPath }}
+        {{ if .AuditLogMaxAge }}
+        - --audit-log-maxage={{ .AuditLogMaxAge }}
+        {{ end }}
+        {{ if .AuditLogMaxBackup }}
+        - --audit-log-maxbackup={{ .AuditLogMaxBackup }}
+        {{ end }}
+        {{ if .AuditLogMaxSize }}
+        - --audit-log-maxsize={{ .AuditLogMaxSize }}
+        {{ end }}
+        {{ end }}
+        {{ if eq .AuditBackend "Webhook" }}
+        - --audit-webhook-config-file=/etc/kubernetes/audit/{{ .AuditWebhookConfigMapKey }}
+        {{ if .AuditWebhookInitialBackoff }}
+        - --audit-webhook-initial-backoff={{ .AuditWebhookInitialBackoff }}
+        {{ end }}
+        {{ end }}
+        {{ if .AuthenticationWebhookSecretName }}
+        - --authentication-token-webhook-config-file=/etc/kubernetes/authentication-webhook/kubeconfig
+        {{ end }}
+        {{ if .AuthorizationWebhookSecretName }}
+        - --authorization-webhook-config-file=/etc/kubernetes/authorization-webhook/kubeconfig
+        {{ end }}
+        {{ if .PodSecurityAdmissionEnabled }}
+        - --admission-control-config-file=/etc/kubernetes/pod-security-admission/admission-config.yaml
+        {{ end }}
         livenessProbe:
           failureThreshold: 8
           httpGet:
@@ -142,6 +182,31 @@ spec:
         - mountPath: /etc/etcd/pki
           name: etcd-cert
           readOnly: true
+        {{ if eq .AuditBackend "Log" }}
+        - name: audit-log
+          mountPath: {{ .AuditLogDir }}
+          readOnly: false
+        {{ end }}
+        {{ if eq .AuditBackend "Webhook" }}
+        - name: audit-webhook-config
+          mountPath: /etc/kubernetes/audit
+          readOnly: true
+        {{ end }}
+        {{ if .AuthenticationWebhookSecretName }}
+        - name: authentication-webhook-config
+          mountPath: /etc/kubernetes/authentication-webhook
+          readOnly: true
+        {{ end }}
+        {{ if .AuthorizationWebhookSecretName }}
+        - name: authorization-webhook-config
+          mountPath: /etc/kubernetes/authorization-webhook
+          readOnly: true
+        {{ end }}
+        {{ if .PodSecurityAdmissionEnabled }}
+        - name: pod-security-admission-config
+          mountPath: /etc/kubernetes/pod-security-admission
+          readOnly: true
+        {{ end }}
       priorityClassName: system-node-critical
       volumes:
       - name: apiserver-cert
@@ -150,6 +215,32 @@ spec:
       - name: etcd-cert
         secret:
           secretName: {{ .EtcdCertsSecret }}
+      {{ if eq .AuditBackend "Log" }}
+      - name: audit-log
+        hostPath:
+          path: {{ .AuditLogDir }}
+          type: DirectoryOrCreate
+      {{ end }}
+      {{ if eq .AuditBackend "Webhook" }}
+      - name: audit-webhook-config
+        configMap:
+          name: {{ .AuditWebhookConfigMapName }}
+      {{ end }}
+      {{ if .AuthenticationWebhookSecretName }}
+      - name: authentication-webhook-config
+        secret:
+          secretName: {{ .AuthenticationWebhookSecretName }}
+      {{ end }}
+      {{ if .AuthorizationWebhookSecretName }}
+      - name: authorization-webhook-config
+        secret:
+          secretName: {{ .AuthorizationWebhookSecretName }}
+      {{ end }}
+      {{ if .PodSecurityAdmissionEnabled }}
+      - name: pod-security-admission-config
+        configMap:
+          name: {{ .PodSecurityAdmissionConfigMapName }}
+      {{ end }}
 `
 	ApiserverAnpDeployment = `
 apiVersion: apps/v1
@@ -203,7 +294,7 @@ spec:
               topologyKey: kubernetes.io/hostname
       containers:
       - name: kube-apiserver
-        image:  {{ .ImageRepository }}/kube-apiserver:{{ .Version }}
+        image:  {{ .APIServerImage }}
         imagePullPolicy: IfNotPresent
         env:
         {{ if .UseAPIServerNodePort }}
@@ -257,6 +348,19 @@ spec:
         - --max-requests-inflight=1500
         - --max-mutating-requests-inflight=500
         - --v=4
+        - --profiling={{ .Profiling }}
+        {{ if .ShutdownDelayDuration }}
+        - --shutdown-delay-duration={{ .ShutdownDelayDuration }}
+        {{ end }}
+        {{ if .ShutdownGracePeriod }}
+        - --shutdown-grace-period={{ .ShutdownGracePeriod }}
+        {{ end }}
+        {{ if .StorageMediaType }}
+        - --storage-media-type={{ .StorageMediaType }}
+        {{ end }}
+        {{ if .CORSAllowedOrigins }}
+        - --cors-allowed-origins={{ .CORSAllowedOrigins }}
+        {{ end }}
         {{ if .UseAPIServerNodePort }}
         - --advertise-address=$(HOSTIP)
         {{ else }}
@@ -266,6 +370,33 @@ spec:
         {{ if not .AdmissionPlugins }}
         - --disable-admission-plugins=License
         {{ end }}
+        {{ if eq .AuditBackend "Log" }}
+        - --audit-log-path={{ .AuditLogPath }}
+        {{ if .AuditLogMaxAge }}
+        - --audit-log-maxage={{ .AuditLogMaxAge }}
+        {{ end }}
+        {{ if .AuditLogMaxBackup }}
+        - --audit-log-maxbackup={{ .AuditLogMaxBackup }}
+        {{ end }}
+        {{ if .AuditLogMaxSize }}
+        - --audit-log-maxsize={{ .AuditLogMaxSize }}
+        {{ end }}
+        {{ end }}
+        {{ if eq .AuditBackend "Webhook" }}
+        - --audit-webhook-config-file=/etc/kubernetes/audit/{{ .AuditWebhookConfigMapKey }}
+        {{ if .AuditWebhookInitialBackoff }}
+        - --audit-webhook-initial-backoff={{ .AuditWebhookInitialBackoff }}
+        {{ end }}
+        {{ end }}
+        {{ if .AuthenticationWebhookSecretName }}
+        - --authentication-token-webhook-config-file=/etc/kubernetes/authentication-webhook/kubeconfig
+        {{ end }}
+        {{ if .AuthorizationWebhookSecretName }}
+        - --authorization-webhook-config-file=/etc/kubernetes/authorization-webhook/kubeconfig
+        {{ end }}
+        {{ if .PodSecurityAdmissionEnabled }}
+        - --admission-control-config-file=/etc/kubernetes/pod-security-admission/admission-config.yaml
+        {{ end }}
         livenessProbe:
           failureThreshold: 8
           httpGet:
@@ -303,6 +434,31 @@ spec:
         - name: kas-proxy
           mountPath: /etc/kubernetes/konnectivity-server-config/{{ .Namespace }}/{{ .Name }}/egress_selector_configuration.yaml
           subPath: egress_selector_configuration.yaml
+        {{ if eq .AuditBackend "Log" }}
+        - name: audit-log
+          mountPath: {{ .AuditLogDir }}
+          readOnly: false
+        {{ end }}
+        {{ if eq .AuditBackend "Webhook" }}
+        - name: audit-webhook-config
+          mountPath: /etc/kubernetes/audit
+          readOnly: true
+        {{ end }}
+        {{ if .AuthenticationWebhookSecretName }}
+        - name: authentication-webhook-config
+          mountPath: /etc/kubernetes/authentication-webhook
+          readOnly: true
+        {{ end }}
+        {{ if .AuthorizationWebhookSecretName }}
+        - name: authorization-webhook-config
+          mountPath: /etc/kubernetes/authorization-webhook
+          readOnly: true
+        {{ end }}
+        {{ if .PodSecurityAdmissionEnabled }}
+        - name: pod-security-admission-config
+          mountPath: /etc/kubernetes/pod-security-admission
+          readOnly: true
+        {{ end }}
       - name: konnectivity-server-container
         image: {{ .ImageRepository }}/kas-network-proxy-server:{{ .Version }}
         resources:
@@ -399,6 +555,32 @@ spec:
       - name: kas-proxy
         configMap:
           name: kas-proxy-files
+      {{ if eq .AuditBackend "Log" }}
+      - name: audit-log
+        hostPath:
+          path: {{ .AuditLogDir }}
+          type: DirectoryOrCreate
+      {{ end }}
+      {{ if eq .AuditBackend "Webhook" }}
+      - name: audit-webhook-config
+        configMap:
+          name: {{ .AuditWebhookConfigMapName }}
+      {{ end }}
+      {{ if .AuthenticationWebhookSecretName }}
+      - name: authentication-webhook-config
+        secret:
+          secretName: {{ .AuthenticationWebhookSecretName }}
+      {{ end }}
+      {{ if .AuthorizationWebhookSecretName }}
+      - name: authorization-webhook-config
+        secret:
+          secretName: {{ .AuthorizationWebhookSecretName }}
+      {{ end }}
+      {{ if .PodSecurityAdmissionEnabled }}
+      - name: pod-security-admission-config
+        configMap:
+          name: {{ .PodSecurityAdmissionConfigMapName }}
+      {{ end }}
 `
 	ApiserverAnpAgentService = `
 apiVersion: v1
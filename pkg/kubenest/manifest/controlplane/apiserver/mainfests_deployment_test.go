@@ -0,0 +1,152 @@
+package apiserver
+
+import (
+	"fmt"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/kosmos.io/kosmos/pkg/kubenest/util"
+)
+
+func ParseDeploymentTemplate(profiling bool) (*appsv1.Deployment, error) {
+	return parseDeploymentTemplate(profiling, "", "", "", "")
+}
+
+func parseDeploymentTemplate(profiling bool, shutdownDelayDuration, shutdownGracePeriod, storageMediaType, corsAllowedOrigins string) (*appsv1.Deployment, error) {
+	apiserverDeploymentBytes, err := util.ParseTemplate(ApiserverDeployment, struct {
+		DeploymentName, Namespace, ImageRepository, EtcdClientService, Version, VirtualControllerLabel string
+		APIServerImage                                                                                 string
+		ServiceSubnet, VirtualClusterCertsSecret, EtcdCertsSecret                                      string
+		ShutdownDelayDuration, ShutdownGracePeriod                                                     string
+		StorageMediaType                                                                               string
+		CORSAllowedOrigins                                                                             string
+		Replicas                                                                                       int
+		EtcdListenClientPort                                                                           int32
+		ClusterPort                                                                                    int32
+		AdmissionPlugins                                                                               bool
+		IPV6First                                                                                      bool
+		UseAPIServerNodePort                                                                           bool
+		Profiling                                                                                      bool
+		AuditBackend, AuditLogPath, AuditLogDir                                                        string
+		AuditLogMaxAge, AuditLogMaxBackup, AuditLogMaxSize                                             int32
+		AuditWebhookConfigMapName, AuditWebhookConfigMapKey, AuditWebhookInitialBackoff                string
+		AuthenticationWebhookSecretName, AuthorizationWebhookSecretName                                string
+		PodSecurityAdmissionEnabled                                                                    bool
+	}{
+		DeploymentName:            fmt.Sprintf("%s-%s", "test", "apiserver"),
+		Namespace:                 "test-namespace",
+		ImageRepository:           "test-repo",
+		APIServerImage:            "test-repo/kube-apiserver:v1.26.3",
+		EtcdClientService:         "test-etcd",
+		Version:                   "v1.26.3",
+		VirtualControllerLabel:    "virtualCluster-control-plane",
+		ServiceSubnet:             "10.96.0.0/12",
+		VirtualClusterCertsSecret: "test-certs",
+		EtcdCertsSecret:           "test-etcd-certs",
+		ShutdownDelayDuration:     shutdownDelayDuration,
+		ShutdownGracePeriod:       shutdownGracePeriod,
+		StorageMediaType:          storageMediaType,
+		CORSAllowedOrigins:        corsAllowedOrigins,
+		Replicas:                  1,
+		EtcdListenClientPort:      2379,
+		ClusterPort:               40010,
+		Profiling:                 profiling,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error when parsing virtualClusterApiserver deployment template: %s", err)
+	}
+
+	apiserverDeployment := &appsv1.Deployment{}
+	if err := yaml.Unmarshal([]byte(apiserverDeploymentBytes), apiserverDeployment); err != nil {
+		return nil, fmt.Errorf("error when decoding virtual cluster apiserver deployment: %s", err)
+	}
+	return apiserverDeployment, nil
+}
+
+func TestApiserverDeploymentProfilingFlag(t *testing.T) {
+	tests := []bool{true, false}
+	for _, profiling := range tests {
+		t.Run(fmt.Sprintf("profiling=%t", profiling), func(t *testing.T) {
+			deployment, err := ParseDeploymentTemplate(profiling)
+			if err != nil {
+				t.Fatalf("happen error: %s", err)
+			}
+
+			want := fmt.Sprintf("--profiling=%t", profiling)
+			args := deployment.Spec.Template.Spec.Containers[0].Command
+			found := false
+			for _, arg := range args {
+				if arg == want {
+					found = true
+					break
+				}
+			}
+			if !found {
+				t.Errorf("ParseDeploymentTemplate() command = %v, want to contain %q", args, want)
+			}
+		})
+	}
+}
+
+func TestApiserverDeploymentShutdownFlags(t *testing.T) {
+	deployment, err := parseDeploymentTemplate(false, "30s", "1m", "", "")
+	if err != nil {
+		t.Fatalf("happen error: %s", err)
+	}
+
+	args := deployment.Spec.Template.Spec.Containers[0].Command
+	for _, want := range []string{"--shutdown-delay-duration=30s", "--shutdown-grace-period=1m"} {
+		found := false
+		for _, arg := range args {
+			if arg == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("ParseDeploymentTemplate() command = %v, want to contain %q", args, want)
+		}
+	}
+}
+
+func TestApiserverDeploymentStorageMediaType(t *testing.T) {
+	deployment, err := parseDeploymentTemplate(false, "", "", "application/vnd.kubernetes.protobuf", "")
+	if err != nil {
+		t.Fatalf("happen error: %s", err)
+	}
+
+	want := "--storage-media-type=application/vnd.kubernetes.protobuf"
+	args := deployment.Spec.Template.Spec.Containers[0].Command
+	found := false
+	for _, arg := range args {
+		if arg == want {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Errorf("ParseDeploymentTemplate() command = %v, want to contain %q", args, want)
+	}
+}
+
+func TestApiserverDeploymentCORSAllowedOrigins(t *testing.T) {
+	deployment, err := parseDeploymentTemplate(false, "", "", "", `https://dashboard\.example\.com`)
+	if err != nil {
+		t.Fatalf("happen error: %s", err)
+	}
+
+	want := `--cors-allowed-origins=https://dashboard\.example\.com`
+	args := deployment.Spec.Template.Spec.Containers[0].Command
+	found := false
+	for _, arg := range args {
+		if arg == want {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Errorf("ParseDeploymentTemplate() command = %v, want to contain %q", args, want)
+	}
+}
@@ -15,6 +15,7 @@ spec:
   {{- range .IPFamilies }}
   - {{ . }}
   {{- end }}
+  ipFamilyPolicy: {{ .IPFamilyPolicy }}
   ports:
   - name: client
     port: {{ .ServicePort }}
@@ -18,6 +18,7 @@ func ParseServerTemplate(apiServerServiceSubnet string) (*corev1.Service, error)
 		ServiceName, Namespace, ServiceType string
 		ServicePort                         int32
 		IPFamilies                          []corev1.IPFamily
+		IPFamilyPolicy                      corev1.IPFamilyPolicy
 		UseAPIServerNodePort                bool
 	}{
 		ServiceName:          fmt.Sprintf("%s-%s", "test", "apiserver"),
@@ -25,6 +26,7 @@ func ParseServerTemplate(apiServerServiceSubnet string) (*corev1.Service, error)
 		ServiceType:          constants.APIServerServiceType,
 		ServicePort:          40010,
 		IPFamilies:           ipFamilies,
+		IPFamilyPolicy:       utils.IPFamilyPolicyFor(ipFamilies),
 		UseAPIServerNodePort: false,
 	})
 
@@ -53,29 +55,34 @@ func CompareIPFamilies(a []corev1.IPFamily, b []corev1.IPFamily) bool {
 
 func TestSyncIPPool(t *testing.T) {
 	tests := []struct {
-		name  string
-		input string
-		want  []corev1.IPFamily
+		name       string
+		input      string
+		want       []corev1.IPFamily
+		wantPolicy corev1.IPFamilyPolicy
 	}{
 		{
-			name:  "ipv4 only",
-			input: "10.237.6.0/18",
-			want:  []corev1.IPFamily{corev1.IPv4Protocol},
+			name:       "ipv4 only",
+			input:      "10.237.6.0/18",
+			want:       []corev1.IPFamily{corev1.IPv4Protocol},
+			wantPolicy: corev1.IPFamilyPolicySingleStack,
 		},
 		{
-			name:  "ipv6 only",
-			input: "2409:8c2f:3800:0011::0a18:0000/114",
-			want:  []corev1.IPFamily{corev1.IPv6Protocol},
+			name:       "ipv6 only",
+			input:      "2409:8c2f:3800:0011::0a18:0000/114",
+			want:       []corev1.IPFamily{corev1.IPv6Protocol},
+			wantPolicy: corev1.IPFamilyPolicySingleStack,
 		},
 		{
-			name:  "ipv4 first",
-			input: "10.237.6.0/18,2409:8c2f:3800:0011::0a18:0000/114",
-			want:  []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol},
+			name:       "ipv4 first",
+			input:      "10.237.6.0/18,2409:8c2f:3800:0011::0a18:0000/114",
+			want:       []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol},
+			wantPolicy: corev1.IPFamilyPolicyRequireDualStack,
 		},
 		{
-			name:  "ipv6 first",
-			input: "2409:8c2f:3800:0011::0a18:0000/114,10.237.6.0/18",
-			want:  []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol},
+			name:       "ipv6 first",
+			input:      "2409:8c2f:3800:0011::0a18:0000/114,10.237.6.0/18",
+			want:       []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol},
+			wantPolicy: corev1.IPFamilyPolicyRequireDualStack,
 		},
 	}
 	for _, tt := range tests {
@@ -88,6 +95,9 @@ func TestSyncIPPool(t *testing.T) {
 			if !CompareIPFamilies(svc.Spec.IPFamilies, tt.want) {
 				t.Errorf("ParseServerTemplate()=%v, want %v", svc.Spec.IPFamilies, tt.want)
 			}
+			if svc.Spec.IPFamilyPolicy == nil || *svc.Spec.IPFamilyPolicy != tt.wantPolicy {
+				t.Errorf("ParseServerTemplate() IPFamilyPolicy = %v, want %v", svc.Spec.IPFamilyPolicy, tt.wantPolicy)
+			}
 		})
 	}
 }
@@ -34,4 +34,32 @@ metadata:
   name: kas-proxy-files
   namespace: {{ .Namespace }}
 `
+
+	PodSecurityAdmissionConfigMap = `
+apiVersion: v1
+data:
+  admission-config.yaml: |
+    apiVersion: apiserver.config.k8s.io/v1
+    kind: AdmissionConfiguration
+    plugins:
+    - name: PodSecurity
+      configuration:
+        apiVersion: pod-security.admission.config.k8s.io/v1
+        kind: PodSecurityConfiguration
+        defaults:
+          enforce: "{{ .PodSecurityAdmissionEnforce }}"
+          enforce-version: "{{ .PodSecurityAdmissionEnforceVersion }}"
+          audit: "{{ .PodSecurityAdmissionAudit }}"
+          audit-version: "{{ .PodSecurityAdmissionAuditVersion }}"
+          warn: "{{ .PodSecurityAdmissionWarn }}"
+          warn-version: "{{ .PodSecurityAdmissionWarnVersion }}"
+        exemptions:
+          usernames: []
+          runtimeClasses: []
+          namespaces: []
+kind: ConfigMap
+metadata:
+  name: {{ .PodSecurityAdmissionConfigMapName }}
+  namespace: {{ .Namespace }}
+`
 )
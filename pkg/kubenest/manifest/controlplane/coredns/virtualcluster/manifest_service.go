@@ -12,6 +12,9 @@ metadata:
   name: kube-dns
   namespace: kube-system
 spec:
+  {{ if .ClusterDNS }}
+  clusterIP: {{ .ClusterDNS }}
+  {{ end }}
   ports:
   - name: dns
     port: 53
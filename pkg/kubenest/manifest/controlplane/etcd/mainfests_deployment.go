@@ -48,7 +48,7 @@ spec:
               topologyKey: kubernetes.io/hostname
       containers:
       - name: etcd
-        image:  {{ .ImageRepository }}/etcd:{{ .Version }}
+        image:  {{ .EtcdImage }}
         imagePullPolicy: IfNotPresent
         command:
         - /usr/local/bin/etcd
@@ -68,7 +68,10 @@ spec:
         - --cert-file=/etc/virtualcluster/pki/etcd/etcd-server.crt
         - --key-file=/etc/virtualcluster/pki/etcd/etcd-server.key
         - --data-dir=/var/lib/etcd
-        - --snapshot-count=10000
+        - --snapshot-count={{ .EtcdSnapshotCount }}
+        {{ if .EtcdAutoCompactionRetentionHours }}
+        - --auto-compaction-retention={{ .EtcdAutoCompactionRetentionHours }}
+        {{ end }}
         - --log-level=debug
         - --cipher-suites={{ .EtcdCipherSuites }}
         #- --peer-cert-file=/etc/virtualcluster/pki/etcd/etcd-server.crt
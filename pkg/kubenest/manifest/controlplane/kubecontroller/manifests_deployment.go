@@ -47,7 +47,7 @@ spec:
               topologyKey: kubernetes.io/hostname
       containers:
       - name: kube-controller-manager
-        image:  {{ .ImageRepository }}/kube-controller-manager:{{ .Version }}
+        image:  {{ .KubeControllerManagerImage }}
         imagePullPolicy: IfNotPresent
         command:
         - kube-controller-manager
@@ -66,6 +66,16 @@ spec:
         {{ if not .IPV6First }}
         - --node-cidr-mask-size=24
         {{ end }}
+        - --profiling={{ .Profiling }}
+        {{ if .NodeMonitorPeriod }}
+        - --node-monitor-period={{ .NodeMonitorPeriod }}
+        {{ end }}
+        {{ if .NodeMonitorGracePeriod }}
+        - --node-monitor-grace-period={{ .NodeMonitorGracePeriod }}
+        {{ end }}
+        {{ if .PodEvictionTimeout }}
+        - --pod-eviction-timeout={{ .PodEvictionTimeout }}
+        {{ end }}
         - --root-ca-file=/etc/virtualcluster/pki/ca.crt
         - --service-account-private-key-file=/etc/virtualcluster/pki/virtualCluster.key
         - --service-cluster-ip-range={{ .ServiceSubnet }}
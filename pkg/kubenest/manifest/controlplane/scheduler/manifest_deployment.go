@@ -45,7 +45,7 @@ spec:
               topologyKey: kubernetes.io/hostname
       containers:     
       - name: scheduler
-        image: {{ .ImageRepository }}/scheduler:{{ .Version }}
+        image: {{ .SchedulerImage }}
         imagePullPolicy: IfNotPresent
         command:        
         - scheduler     
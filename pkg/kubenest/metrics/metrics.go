@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// VirtualClusterCreationDuration records how long a VirtualCluster took to
+// go from creation to the Completed phase, for SLO tracking.
+var VirtualClusterCreationDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+	Name:    "kosmos_virtualcluster_creation_duration_seconds",
+	Help:    "Time taken for a VirtualCluster to go from creation to the Completed phase.",
+	Buckets: prometheus.ExponentialBuckets(10, 2, 10),
+})
+
+// VirtualClusterReconcileDuration records how long each VirtualClusterInitController
+// reconcile took, labeled by the phase the VirtualCluster was in when the
+// reconcile started.
+var VirtualClusterReconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "kosmos_virtualcluster_reconcile_duration_seconds",
+	Help:    "Time taken by VirtualClusterInitController to reconcile a VirtualCluster, labeled by phase.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"phase"})
+
+// VirtualClusterPhaseCount tracks how many VirtualClusters currently sit in
+// each phase, so a stuck rollout (e.g. many clusters stuck Pending) shows up
+// as a gauge instead of only being visible by listing clusters.
+var VirtualClusterPhaseCount = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "kosmos_virtualcluster_phase_count",
+	Help: "Number of VirtualClusters currently in each phase.",
+}, []string{"phase"})
+
+// GlobalNodeStateCount tracks how many GlobalNodes are currently Free vs
+// InUse, for capacity planning.
+var GlobalNodeStateCount = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "kosmos_globalnode_state_count",
+	Help: "Number of GlobalNodes currently in each state.",
+}, []string{"state"})
+
+// NodeAssignmentFailures counts work node assignment failures caused by a
+// PromotePolicy having no more matching or free GlobalNodes to satisfy it.
+// Labeled by the VirtualCluster that failed, since cardinality is bounded by
+// the (small) number of VirtualClusters in a cluster.
+var NodeAssignmentFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "kosmos_virtualcluster_node_assignment_failures_total",
+	Help: "Count of work node assignment failures due to insufficient matching GlobalNodes.",
+}, []string{"namespace", "name"})
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		VirtualClusterCreationDuration,
+		VirtualClusterReconcileDuration,
+		VirtualClusterPhaseCount,
+		GlobalNodeStateCount,
+		NodeAssignmentFailures,
+	)
+}
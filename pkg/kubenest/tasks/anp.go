@@ -14,6 +14,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/yaml"
 	"k8s.io/client-go/dynamic"
 	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
 
@@ -143,6 +144,29 @@ func uninstallAnp(r workflow.RunData) error {
 	}
 	return nil
 }
+func createPodSecurityAdmissionConfigMap(client clientset.Interface, namespace string, data util.PodSecurityAdmissionTemplateData) error {
+	configMapBytes, err := util.ParseTemplate(apiserver.PodSecurityAdmissionConfigMap, struct {
+		Namespace string
+		util.PodSecurityAdmissionTemplateData
+	}{
+		Namespace:                        namespace,
+		PodSecurityAdmissionTemplateData: data,
+	})
+	if err != nil {
+		return fmt.Errorf("error when parsing pod security admission config map template: %w", err)
+	}
+
+	cm := &v1.ConfigMap{}
+	if err := yaml.Unmarshal([]byte(configMapBytes), cm); err != nil {
+		return fmt.Errorf("error when decoding pod security admission config map: %w", err)
+	}
+
+	if err := util.CreateOrUpdateConfigMap(client, cm); err != nil {
+		return fmt.Errorf("error when creating pod security admission config map for %s, err: %w", cm.Name, err)
+	}
+	return nil
+}
+
 func installAnpServer(client clientset.Interface, name, namespace string, portMap map[string]int32, kubeNestConfiguration *v1alpha1.KubeNestConfiguration, vc *v1alpha1.VirtualCluster) error {
 	imageRepository, imageVersion := util.GetImageMessage()
 	clusterIP, err := util.GetEtcdServiceClusterIP(namespace, name+constants.EtcdSuffix, client)
@@ -157,9 +181,49 @@ func installAnpServer(client clientset.Interface, name, namespace string, portMa
 
 	vclabel := util.GetVirtualControllerLabel()
 
+	shutdownDelayDuration := kubeNestConfiguration.KubeInKubeConfig.ShutdownDelayDuration
+	if err := util.ValidateNonNegativeDuration("shutdownDelayDuration", shutdownDelayDuration); err != nil {
+		return err
+	}
+	shutdownGracePeriod := kubeNestConfiguration.KubeInKubeConfig.ShutdownGracePeriod
+	if err := util.ValidateNonNegativeDuration("shutdownGracePeriod", shutdownGracePeriod); err != nil {
+		return err
+	}
+	storageMediaType := kubeNestConfiguration.KubeInKubeConfig.StorageMediaType
+	if err := util.ValidateStorageMediaType(storageMediaType); err != nil {
+		return err
+	}
+
+	apiServerReplicas := kubeNestConfiguration.KubeInKubeConfig.APIServerReplicas
+	if vc.Spec.ControlPlaneConfig != nil {
+		apiServerReplicas = int(util.ResolveReplicas(vc.Spec.ControlPlaneConfig.APIServerReplicas, apiServerReplicas))
+	}
+
+	auditData, err := util.BuildAuditTemplateData(kubeNestConfiguration.KubeInKubeConfig.Audit, namespace, name)
+	if err != nil {
+		return err
+	}
+
+	webhookData, err := util.BuildWebhookTemplateData(client, namespace, kubeNestConfiguration.KubeInKubeConfig.AuthenticationWebhook, kubeNestConfiguration.KubeInKubeConfig.AuthorizationWebhook)
+	if err != nil {
+		return err
+	}
+
+	podSecurityAdmissionData, err := util.BuildPodSecurityAdmissionTemplateData(kubeNestConfiguration.KubeInKubeConfig.PodSecurityAdmission, name)
+	if err != nil {
+		return err
+	}
+	if podSecurityAdmissionData.PodSecurityAdmissionEnabled {
+		if err := createPodSecurityAdmissionConfigMap(client, namespace, podSecurityAdmissionData); err != nil {
+			return err
+		}
+	}
+
 	apiserverDeploymentBytes, err := util.ParseTemplate(apiserver.ApiserverAnpDeployment, struct {
 		DeploymentName, Namespace, ImageRepository, EtcdClientService, Version, VirtualControllerLabel string
 		ServiceSubnet, VirtualClusterCertsSecret, EtcdCertsSecret                                      string
+		ShutdownDelayDuration, ShutdownGracePeriod                                                     string
+		StorageMediaType                                                                               string
 		Replicas                                                                                       int
 		EtcdListenClientPort                                                                           int32
 		ClusterPort                                                                                    int32
@@ -173,29 +237,40 @@ func installAnpServer(client clientset.Interface, name, namespace string, portMa
 		AdmissionPlugins                                                                               bool
 		IPV6First                                                                                      bool
 		UseAPIServerNodePort                                                                           bool
+		Profiling                                                                                      bool
+		util.AuditTemplateData
+		util.WebhookTemplateData
+		util.PodSecurityAdmissionTemplateData
 	}{
-		DeploymentName:            util.GetAPIServerName(name),
-		Namespace:                 namespace,
-		ImageRepository:           imageRepository,
-		Version:                   imageVersion,
-		VirtualControllerLabel:    vclabel,
-		EtcdClientService:         clusterIP,
-		ServiceSubnet:             constants.APIServerServiceSubnet,
-		VirtualClusterCertsSecret: util.GetCertName(name),
-		EtcdCertsSecret:           util.GetEtcdCertName(name),
-		Replicas:                  kubeNestConfiguration.KubeInKubeConfig.APIServerReplicas,
-		EtcdListenClientPort:      constants.APIServerEtcdListenClientPort,
-		ClusterPort:               portMap[constants.APIServerPortKey],
-		AgentPort:                 portMap[constants.APIServerNetworkProxyAgentPortKey],
-		ServerPort:                portMap[constants.APIServerNetworkProxyServerPortKey],
-		HealthPort:                portMap[constants.APIServerNetworkProxyHealthPortKey],
-		AdminPort:                 portMap[constants.APIServerNetworkProxyAdminPortKey],
-		KubeconfigSecret:          util.GetAdminConfigClusterIPSecretName(name),
-		Name:                      name,
-		AnpMode:                   kubeNestConfiguration.KubeInKubeConfig.AnpMode,
-		AdmissionPlugins:          kubeNestConfiguration.KubeInKubeConfig.AdmissionPlugins,
-		IPV6First:                 IPV6FirstFlag,
-		UseAPIServerNodePort:      vc.Spec.KubeInKubeConfig != nil && vc.Spec.KubeInKubeConfig.APIServerServiceType == v1alpha1.NodePort,
+		DeploymentName:                   util.GetAPIServerName(name),
+		Namespace:                        namespace,
+		ImageRepository:                  imageRepository,
+		Version:                          imageVersion,
+		VirtualControllerLabel:           vclabel,
+		EtcdClientService:                clusterIP,
+		ServiceSubnet:                    constants.APIServerServiceSubnet,
+		VirtualClusterCertsSecret:        util.GetCertName(name),
+		EtcdCertsSecret:                  util.GetEtcdCertName(name),
+		ShutdownDelayDuration:            shutdownDelayDuration,
+		ShutdownGracePeriod:              shutdownGracePeriod,
+		StorageMediaType:                 storageMediaType,
+		Replicas:                         apiServerReplicas,
+		EtcdListenClientPort:             constants.APIServerEtcdListenClientPort,
+		ClusterPort:                      portMap[constants.APIServerPortKey],
+		AgentPort:                        portMap[constants.APIServerNetworkProxyAgentPortKey],
+		ServerPort:                       portMap[constants.APIServerNetworkProxyServerPortKey],
+		HealthPort:                       portMap[constants.APIServerNetworkProxyHealthPortKey],
+		AdminPort:                        portMap[constants.APIServerNetworkProxyAdminPortKey],
+		KubeconfigSecret:                 util.GetAdminConfigClusterIPSecretName(name),
+		Name:                             name,
+		AnpMode:                          kubeNestConfiguration.KubeInKubeConfig.AnpMode,
+		AdmissionPlugins:                 kubeNestConfiguration.KubeInKubeConfig.AdmissionPlugins,
+		IPV6First:                        IPV6FirstFlag,
+		UseAPIServerNodePort:             vc.Spec.KubeInKubeConfig != nil && vc.Spec.KubeInKubeConfig.APIServerServiceType == v1alpha1.NodePort,
+		Profiling:                        kubeNestConfiguration.KubeInKubeConfig.Profiling,
+		AuditTemplateData:                auditData,
+		WebhookTemplateData:              webhookData,
+		PodSecurityAdmissionTemplateData: podSecurityAdmissionData,
 	})
 	if err != nil {
 		return fmt.Errorf("error when parsing virtual cluster apiserver deployment template: %w", err)
@@ -372,7 +447,13 @@ func getVcDynamicClient(client clientset.Interface, name, namespace string) (dyn
 	}
 	return dynamicClient, nil
 }
-func GetVcClientset(client clientset.Interface, name, namespace string) (clientset.Interface, error) {
+
+// GetVcClientset builds a clientset targeting the virtual cluster's tenant
+// apiserver from its admin kubeconfig Secret. kubeInKubeConfig may be nil;
+// when its ContentType is set, the client's request/response content type
+// negotiation is steered accordingly (e.g. to protobuf, to reduce apiserver
+// load for internal clients) instead of client-go's json default.
+func GetVcClientset(client clientset.Interface, name, namespace string, kubeInKubeConfig *v1alpha1.KubeInKubeConfig) (clientset.Interface, error) {
 	secret, err := client.CoreV1().Secrets(namespace).Get(context.TODO(),
 		util.GetAdminConfigSecretName(name), metav1.GetOptions{})
 	if err != nil {
@@ -383,6 +464,9 @@ func GetVcClientset(client clientset.Interface, name, namespace string) (clients
 	if err != nil {
 		return nil, err
 	}
+	if err := applyContentType(config, kubeInKubeConfig); err != nil {
+		return nil, err
+	}
 
 	vcClient, err := clientset.NewForConfig(config)
 	if err != nil {
@@ -392,6 +476,21 @@ func GetVcClientset(client clientset.Interface, name, namespace string) (clients
 	return vcClient, nil
 }
 
+// applyContentType overrides config's ContentType/AcceptContentTypes when
+// kubeInKubeConfig requests one, so both requests sent and responses
+// accepted by the built client use the configured content type.
+func applyContentType(config *rest.Config, kubeInKubeConfig *v1alpha1.KubeInKubeConfig) error {
+	if kubeInKubeConfig == nil || kubeInKubeConfig.ContentType == "" {
+		return nil
+	}
+	if err := util.ValidateContentType(kubeInKubeConfig.ContentType); err != nil {
+		return err
+	}
+	config.ContentType = kubeInKubeConfig.ContentType
+	config.AcceptContentTypes = kubeInKubeConfig.ContentType
+	return nil
+}
+
 func runUploadProxyAgentCert(r workflow.RunData) error {
 	data, ok := r.(InitData)
 	if !ok {
@@ -407,7 +506,7 @@ func runUploadProxyAgentCert(r workflow.RunData) error {
 			certsData[c.CertName()] = c.CertData()
 		}
 	}
-	vcClient, err := GetVcClientset(data.RemoteClient(), name, namespace)
+	vcClient, err := GetVcClientset(data.RemoteClient(), name, namespace, data.VirtualCluster().Spec.KubeInKubeConfig)
 	if err != nil {
 		return fmt.Errorf("failed to get virtual cluster client, err: %w", err)
 	}
@@ -0,0 +1,47 @@
+package tasks
+
+import (
+	"testing"
+
+	"k8s.io/client-go/rest"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+)
+
+func TestApplyContentTypeSetsNegotiationToProtobuf(t *testing.T) {
+	config := &rest.Config{}
+	kubeInKubeConfig := &v1alpha1.KubeInKubeConfig{ContentType: "application/vnd.kubernetes.protobuf"}
+
+	if err := applyContentType(config, kubeInKubeConfig); err != nil {
+		t.Fatalf("applyContentType() error = %v", err)
+	}
+	if config.ContentType != "application/vnd.kubernetes.protobuf" {
+		t.Errorf("ContentType = %q, want protobuf", config.ContentType)
+	}
+	if config.AcceptContentTypes != "application/vnd.kubernetes.protobuf" {
+		t.Errorf("AcceptContentTypes = %q, want protobuf", config.AcceptContentTypes)
+	}
+}
+
+func TestApplyContentTypeLeavesConfigUntouchedWhenUnset(t *testing.T) {
+	config := &rest.Config{}
+
+	if err := applyContentType(config, nil); err != nil {
+		t.Fatalf("applyContentType() error = %v", err)
+	}
+	if err := applyContentType(config, &v1alpha1.KubeInKubeConfig{}); err != nil {
+		t.Fatalf("applyContentType() error = %v", err)
+	}
+	if config.ContentType != "" || config.AcceptContentTypes != "" {
+		t.Errorf("expected config to be untouched, got ContentType=%q AcceptContentTypes=%q", config.ContentType, config.AcceptContentTypes)
+	}
+}
+
+func TestApplyContentTypeRejectsUnsupportedValue(t *testing.T) {
+	config := &rest.Config{}
+	kubeInKubeConfig := &v1alpha1.KubeInKubeConfig{ContentType: "application/xml"}
+
+	if err := applyContentType(config, kubeInKubeConfig); err == nil {
+		t.Fatal("expected an unsupported contentType to be rejected")
+	}
+}
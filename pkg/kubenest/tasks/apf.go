@@ -0,0 +1,126 @@
+package tasks
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	flowcontrolv1beta3 "k8s.io/api/flowcontrol/v1beta3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/constants"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/util"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/workflow"
+)
+
+// connectionLimitFlowSchemaName and connectionLimitPriorityLevelName name the
+// FlowSchema/PriorityLevelConfiguration pair NewAPFTask manages.
+const (
+	connectionLimitFlowSchemaName     = "kosmos-per-client-connection-limit"
+	connectionLimitPriorityLevelName  = "kosmos-per-client-connection-limit"
+	connectionLimitMatchingPrecedence = 900
+)
+
+func NewAPFTask() workflow.Task {
+	return workflow.Task{
+		Name: "apf",
+		Run:  runAPF,
+		Skip: skipAPF,
+	}
+}
+
+func skipAPF(d workflow.RunData) (bool, error) {
+	data, ok := d.(InitData)
+	if !ok {
+		return false, errors.New("apf task invoked with an invalid data struct")
+	}
+
+	vc := data.VirtualCluster()
+	return vc.Spec.KubeInKubeConfig == nil || vc.Spec.KubeInKubeConfig.ConnectionLimitPerClient == nil, nil
+}
+
+func runAPF(r workflow.RunData) error {
+	data, ok := r.(InitData)
+	if !ok {
+		return errors.New("apf task invoked with an invalid data struct")
+	}
+
+	secret, err := data.RemoteClient().CoreV1().Secrets(data.GetNamespace()).Get(context.TODO(),
+		util.GetAdminConfigSecretName(data.GetName()), metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrap(err, "Get virtualcluster kubeconfig secret error")
+	}
+	config, err := clientcmd.RESTConfigFromKubeConfig(secret.Data[constants.KubeConfig])
+	if err != nil {
+		return err
+	}
+	virtualClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	plc, flowSchema := buildConnectionLimitObjects(data.VirtualCluster().Spec.KubeInKubeConfig.ConnectionLimitPerClient)
+	if err := util.CreateOrUpdatePriorityLevelConfiguration(virtualClient, plc); err != nil {
+		return errors.Wrap(err, "create or update per-client connection limit prioritylevelconfiguration")
+	}
+	if err := util.CreateOrUpdateFlowSchema(virtualClient, flowSchema); err != nil {
+		return errors.Wrap(err, "create or update per-client connection limit flowschema")
+	}
+	return nil
+}
+
+// buildConnectionLimitObjects builds a PriorityLevelConfiguration/FlowSchema
+// pair that caps how many concurrent requests a single client identity (as
+// distinguished by username) may have in flight against the virtual
+// apiserver, queuing the rest up to cfg.QueueLength before rejecting them.
+func buildConnectionLimitObjects(cfg *v1alpha1.ConnectionLimitConfig) (*flowcontrolv1beta3.PriorityLevelConfiguration, *flowcontrolv1beta3.FlowSchema) {
+	plc := &flowcontrolv1beta3.PriorityLevelConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: connectionLimitPriorityLevelName},
+		Spec: flowcontrolv1beta3.PriorityLevelConfigurationSpec{
+			Type: flowcontrolv1beta3.PriorityLevelEnablementLimited,
+			Limited: &flowcontrolv1beta3.LimitedPriorityLevelConfiguration{
+				NominalConcurrencyShares: cfg.AssuredConcurrencyShares,
+				LimitResponse: flowcontrolv1beta3.LimitResponse{
+					Type: flowcontrolv1beta3.LimitResponseTypeQueue,
+					Queuing: &flowcontrolv1beta3.QueuingConfiguration{
+						Queues:           128,
+						HandSize:         6,
+						QueueLengthLimit: cfg.QueueLength,
+					},
+				},
+			},
+		},
+	}
+
+	flowSchema := &flowcontrolv1beta3.FlowSchema{
+		ObjectMeta: metav1.ObjectMeta{Name: connectionLimitFlowSchemaName},
+		Spec: flowcontrolv1beta3.FlowSchemaSpec{
+			PriorityLevelConfiguration: flowcontrolv1beta3.PriorityLevelConfigurationReference{
+				Name: connectionLimitPriorityLevelName,
+			},
+			MatchingPrecedence:  connectionLimitMatchingPrecedence,
+			DistinguisherMethod: &flowcontrolv1beta3.FlowDistinguisherMethod{Type: flowcontrolv1beta3.FlowDistinguisherMethodByUserType},
+			Rules: []flowcontrolv1beta3.PolicyRulesWithSubjects{
+				{
+					Subjects: []flowcontrolv1beta3.Subject{
+						{Kind: flowcontrolv1beta3.SubjectKindGroup, Group: &flowcontrolv1beta3.GroupSubject{Name: "system:authenticated"}},
+						{Kind: flowcontrolv1beta3.SubjectKindGroup, Group: &flowcontrolv1beta3.GroupSubject{Name: "system:unauthenticated"}},
+					},
+					ResourceRules: []flowcontrolv1beta3.ResourcePolicyRule{
+						{
+							Verbs:        []string{"*"},
+							APIGroups:    []string{"*"},
+							Resources:    []string{"*"},
+							Namespaces:   []string{"*"},
+							ClusterScope: true,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	return plc, flowSchema
+}
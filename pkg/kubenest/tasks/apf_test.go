@@ -0,0 +1,44 @@
+package tasks
+
+import (
+	"testing"
+
+	flowcontrolv1beta3 "k8s.io/api/flowcontrol/v1beta3"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+)
+
+func TestBuildConnectionLimitObjectsAppliesLimitingConfiguration(t *testing.T) {
+	cfg := &v1alpha1.ConnectionLimitConfig{
+		AssuredConcurrencyShares: 7,
+		QueueLength:              42,
+	}
+
+	plc, flowSchema := buildConnectionLimitObjects(cfg)
+
+	if plc.Spec.Type != flowcontrolv1beta3.PriorityLevelEnablementLimited {
+		t.Fatalf("expected a limited prioritylevelconfiguration, got type %q", plc.Spec.Type)
+	}
+	if plc.Spec.Limited == nil {
+		t.Fatalf("expected Limited to be set")
+	}
+	if plc.Spec.Limited.NominalConcurrencyShares != cfg.AssuredConcurrencyShares {
+		t.Errorf("NominalConcurrencyShares = %d, want %d", plc.Spec.Limited.NominalConcurrencyShares, cfg.AssuredConcurrencyShares)
+	}
+	if plc.Spec.Limited.LimitResponse.Type != flowcontrolv1beta3.LimitResponseTypeQueue {
+		t.Fatalf("expected a queuing limit response, got type %q", plc.Spec.Limited.LimitResponse.Type)
+	}
+	if plc.Spec.Limited.LimitResponse.Queuing == nil || plc.Spec.Limited.LimitResponse.Queuing.QueueLengthLimit != cfg.QueueLength {
+		t.Errorf("QueueLengthLimit = %+v, want %d", plc.Spec.Limited.LimitResponse.Queuing, cfg.QueueLength)
+	}
+
+	if flowSchema.Spec.PriorityLevelConfiguration.Name != plc.Name {
+		t.Errorf("flowschema references prioritylevelconfiguration %q, want %q", flowSchema.Spec.PriorityLevelConfiguration.Name, plc.Name)
+	}
+	if flowSchema.Spec.DistinguisherMethod == nil || flowSchema.Spec.DistinguisherMethod.Type != flowcontrolv1beta3.FlowDistinguisherMethodByUserType {
+		t.Errorf("expected the flowschema to distinguish clients by user, got %+v", flowSchema.Spec.DistinguisherMethod)
+	}
+	if len(flowSchema.Spec.Rules) == 0 {
+		t.Fatalf("expected at least one rule in the flowschema")
+	}
+}
@@ -137,6 +137,7 @@ func mutateCertConfig(data InitData, cc *cert.CertConfig) error {
 			ClusterIPs:       data.ServiceClusterIP(),
 			ExternalIP:       data.ExternalIP(),
 			ExternalIPs:      data.ExternalIPs(),
+			ExternalHostName: data.ExternalHostName(),
 			VipMap:           data.VipMap(),
 		}, cc)
 		if err != nil {
@@ -8,6 +8,7 @@ import (
 
 	"github.com/kosmos.io/kosmos/pkg/kubenest/constants"
 	"github.com/kosmos.io/kosmos/pkg/kubenest/controlplane"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/util"
 	"github.com/kosmos.io/kosmos/pkg/kubenest/workflow"
 )
 
@@ -49,12 +50,39 @@ func runComponentSubTask(component string) func(r workflow.RunData) error {
 
 		kubeNestOpt := data.KubeNestOpt()
 
+		var nodeMonitorPeriod, nodeMonitorGracePeriod, podEvictionTimeout string
+		if kubeInKubeConfig := data.VirtualCluster().Spec.KubeInKubeConfig; kubeInKubeConfig != nil {
+			nodeMonitorPeriod = kubeInKubeConfig.NodeMonitorPeriod
+			nodeMonitorGracePeriod = kubeInKubeConfig.NodeMonitorGracePeriod
+			podEvictionTimeout = kubeInKubeConfig.PodEvictionTimeout
+		}
+		for name, value := range map[string]string{
+			"nodeMonitorPeriod":      nodeMonitorPeriod,
+			"nodeMonitorGracePeriod": nodeMonitorGracePeriod,
+			"podEvictionTimeout":     podEvictionTimeout,
+		} {
+			if err := util.ValidateNonNegativeDuration(name, value); err != nil {
+				return err
+			}
+		}
+
+		var controllerManagerReplicas int
+		if controlPlaneConfig := data.VirtualCluster().Spec.ControlPlaneConfig; controlPlaneConfig != nil {
+			controllerManagerReplicas = controlPlaneConfig.ControllerManagerReplicas
+		}
+
 		err := controlplane.EnsureControlPlaneComponent(
 			component,
 			data.GetName(),
 			data.GetNamespace(),
 			data.RemoteClient(),
 			kubeNestOpt.KubeInKubeConfig.ClusterCIDR,
+			kubeNestOpt.KubeInKubeConfig.Profiling,
+			nodeMonitorPeriod,
+			nodeMonitorGracePeriod,
+			podEvictionTimeout,
+			util.ResolveReplicas(controllerManagerReplicas, constants.KubeControllerReplicas),
+			data.VirtualCluster(),
 		)
 		if err != nil {
 			return fmt.Errorf("failed to apply component %s, err: %w", component, err)
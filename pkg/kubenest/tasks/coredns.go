@@ -74,6 +74,7 @@ func UninstallCoreDNSTask() workflow.Task {
 	return workflow.Task{
 		Name:        "coredns",
 		Run:         runCoreDNS,
+		Skip:        skipCoreDNS,
 		RunSubTasks: true,
 		Tasks: []workflow.Task{
 			{
@@ -249,6 +250,14 @@ func runCoreDNSVirtualTask(r workflow.RunData) error {
 		return fmt.Errorf("get master node ip from env failed")
 	}
 
+	clusterDNS := ""
+	if kubeInKubeConfig := data.VirtualCluster().Spec.KubeInKubeConfig; kubeInKubeConfig != nil && kubeInKubeConfig.ClusterDNS != "" {
+		if err := util.ValidateClusterDNS(kubeInKubeConfig.ClusterDNS, constants.APIServerServiceSubnet); err != nil {
+			return errors.Wrap(err, "invalid clusterDNS")
+		}
+		clusterDNS = kubeInKubeConfig.ClusterDNS
+	}
+
 	for _, component := range components {
 		klog.V(2).Infof("Deploy component %s", component.Name)
 
@@ -259,6 +268,7 @@ func runCoreDNSVirtualTask(r workflow.RunData) error {
 			"DNSTCPPort":      DNSTCPPort,
 			"MetricsPort":     MetricsPort,
 			"HostNodeAddress": HostNodeAddress,
+			"ClusterDNS":      clusterDNS,
 		}
 		for k, v := range data.PluginOptions() {
 			templatedMapping[k] = v
@@ -16,15 +16,28 @@ type InitData interface {
 	ControlplaneAddress() string
 	ServiceClusterIP() []string
 	RemoteClient() clientset.Interface
+	// VirtualClusterVersion returns the Kubernetes version resolved for this
+	// virtual cluster's control plane (see util.ResolveKubernetesVersion),
+	// already validated against util.SupportedKubernetesVersions.
+	VirtualClusterVersion() string
 	KosmosClient() versioned.Interface
 	DataDir() string
 	VirtualCluster() *v1alpha1.VirtualCluster
 	ExternalIP() string
 	ExternalIPs() []string
+	ExternalHostName() string
 	HostPort() int32
 	HostPortMap() map[string]int32
 	VipMap() map[string]string
 	DynamicClient() *dynamic.DynamicClient
 	KubeNestOpt() *v1alpha1.KubeNestConfiguration
 	PluginOptions() map[string]string
+	// ResourceLabels returns the operator-specified labels to merge onto
+	// every object this VirtualCluster provisions (see
+	// v1alpha1.VirtualClusterSpec.ResourceLabels).
+	ResourceLabels() map[string]string
+	// ResourceAnnotations returns the operator-specified annotations to
+	// merge onto every object this VirtualCluster provisions (see
+	// v1alpha1.VirtualClusterSpec.ResourceAnnotations).
+	ResourceAnnotations() map[string]string
 }
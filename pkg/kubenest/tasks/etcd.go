@@ -12,12 +12,28 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
 	"github.com/kosmos.io/kosmos/pkg/kubenest/constants"
 	"github.com/kosmos.io/kosmos/pkg/kubenest/controlplane"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/util"
 	apiclient "github.com/kosmos.io/kosmos/pkg/kubenest/util/api-client"
 	"github.com/kosmos.io/kosmos/pkg/kubenest/workflow"
 )
 
+// etcdQuorum returns the configured etcd replica count and the number of
+// started members required for the cluster to have a quorum, given how many
+// replicas the VirtualCluster asked for (falling back to
+// constants.EtcdReplicas, same as deploy-etcd and delete-etcd-pvc).
+func etcdQuorum(vc *v1alpha1.VirtualCluster) (replicas, quorum int) {
+	var etcdReplicasOverride int
+	if controlPlaneConfig := vc.Spec.ControlPlaneConfig; controlPlaneConfig != nil {
+		etcdReplicasOverride = controlPlaneConfig.EtcdReplicas
+	}
+	replicas = int(util.ResolveReplicas(etcdReplicasOverride, constants.EtcdReplicas))
+	quorum = replicas/2 + 1
+	return replicas, quorum
+}
+
 var (
 	etcdLabels = labels.Set{constants.Label: constants.Etcd}
 )
@@ -36,6 +52,10 @@ func NewEtcdTask() workflow.Task {
 				Name: "check-etcd",
 				Run:  runCheckEtcd,
 			},
+			{
+				Name: "wait-etcd-quorum",
+				Run:  runWaitEtcdQuorum,
+			},
 		},
 	}
 }
@@ -81,6 +101,52 @@ func runCheckEtcd(r workflow.RunData) error {
 	return nil
 }
 
+// runWaitEtcdQuorum polls the virtual cluster's etcd cluster through a
+// dedicated etcd client until a quorum of members have started, so the
+// apiserver task doesn't start dialing an etcd cluster that can't yet serve
+// linearizable reads.
+func runWaitEtcdQuorum(r workflow.RunData) error {
+	data, ok := r.(InitData)
+	if !ok {
+		return errors.New("wait-etcd-quorum task invoked with an invalid data struct")
+	}
+
+	vc := data.VirtualCluster()
+	replicas, quorum := etcdQuorum(vc)
+
+	cli, err := util.NewEtcdClient(data.RemoteClient(), vc)
+	if err != nil {
+		return fmt.Errorf("failed to build etcd client to wait for quorum, err: %w", err)
+	}
+	defer cli.Close()
+
+	var started int
+	err = wait.PollImmediate(constants.EtcdQuorumPollInterval, constants.EtcdQuorumWaitTimeout, func() (bool, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), constants.EtcdClientDialTimeout)
+		defer cancel()
+
+		resp, listErr := cli.MemberList(ctx)
+		if listErr != nil {
+			klog.V(4).InfoS("[wait-etcd-quorum] list etcd members failed, retrying", "virtual cluster", klog.KObj(data), "err", listErr)
+			return false, nil
+		}
+
+		started = 0
+		for _, member := range resp.Members {
+			if member.Name != "" {
+				started++
+			}
+		}
+		return started >= quorum, nil
+	})
+	if err != nil {
+		return fmt.Errorf("etcd quorum not established within %s: saw %d of %d expected members, quorum requires %d", constants.EtcdQuorumWaitTimeout, started, replicas, quorum)
+	}
+
+	klog.V(2).InfoS("[wait-etcd-quorum] etcd quorum established", "virtual cluster", klog.KObj(data), "started", started, "quorum", quorum)
+	return nil
+}
+
 func UninstallEtcdTask() workflow.Task {
 	return workflow.Task{
 		Name:        "Etcd",
@@ -128,13 +194,27 @@ func UninstallEtcd(r workflow.RunData) error {
 	return nil
 }
 
+// deleteEtcdPvc deletes the PVCs backing this VirtualCluster's etcd members,
+// unless Spec.Etcd.RetainDataOnDelete opts out of it so the data survives
+// teardown (e.g. to recreate the cluster from the same etcd state later).
 func deleteEtcdPvc(r workflow.RunData) error {
 	data, ok := r.(InitData)
 	if !ok {
 		return errors.New("destroy-etcd task invoked with an invalid data struct")
 	}
 
-	for i := 0; i < constants.EtcdReplicas; i++ {
+	if etcd := data.VirtualCluster().Spec.Etcd; etcd != nil && etcd.RetainDataOnDelete {
+		klog.V(2).Infof("Retaining etcd pvc for %s per Spec.Etcd.RetainDataOnDelete", data.GetName())
+		return nil
+	}
+
+	var etcdReplicasOverride int
+	if controlPlaneConfig := data.VirtualCluster().Spec.ControlPlaneConfig; controlPlaneConfig != nil {
+		etcdReplicasOverride = controlPlaneConfig.EtcdReplicas
+	}
+	etcdReplicas := util.ResolveReplicas(etcdReplicasOverride, constants.EtcdReplicas)
+
+	for i := 0; i < int(etcdReplicas); i++ {
 		pvc := fmt.Sprintf("%s-%s-etcd-%d", constants.EtcdDataVolumeName, data.GetName(), i)
 		klog.V(2).Infof("Delete pvc %s/%s", pvc, data.GetNamespace())
 		err := data.RemoteClient().CoreV1().PersistentVolumeClaims(data.GetNamespace()).Delete(context.TODO(), pvc, metav1.DeleteOptions{})
@@ -154,6 +234,10 @@ func checkPvcDeleted(r workflow.RunData) error {
 		return errors.New("destroy-etcd task invoked with an invalid data struct")
 	}
 
+	if etcd := data.VirtualCluster().Spec.Etcd; etcd != nil && etcd.RetainDataOnDelete {
+		return nil
+	}
+
 	klog.V(2).Infof("Check if %s etcd pvc deleted", data.GetName())
 	err := wait.PollImmediate(5*time.Second, constants.ComponentBeDeletedTimeout, func() (done bool, err error) {
 		pvcList, err := data.RemoteClient().CoreV1().PersistentVolumeClaims(data.GetNamespace()).List(context.TODO(), metav1.ListOptions{LabelSelector: virtualClusterEtcdLabels.String()})
@@ -0,0 +1,103 @@
+package tasks
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientset "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/constants"
+)
+
+// fakeKubeconfigSink records whatever it's asked to write, so a test can
+// assert the kubeconfig reached it instead of (or as well as) a Secret.
+type fakeKubeconfigSink struct {
+	namespace string
+	name      string
+	data      map[string][]byte
+}
+
+func (f *fakeKubeconfigSink) Write(_ clientset.Interface, namespace, name string, data map[string][]byte, _, _ map[string]string) error {
+	f.namespace = namespace
+	f.name = name
+	f.data = data
+	return nil
+}
+
+func TestKubeconfigSinkForDefaultsToSecret(t *testing.T) {
+	if _, ok := kubeconfigSinkFor("").(secretKubeconfigSink); !ok {
+		t.Fatalf("expected an unset sink type to default to secretKubeconfigSink")
+	}
+	if _, ok := kubeconfigSinkFor(v1alpha1.SecretKubeconfigSink).(secretKubeconfigSink); !ok {
+		t.Fatalf("expected %q to resolve to secretKubeconfigSink", v1alpha1.SecretKubeconfigSink)
+	}
+}
+
+func TestKubeconfigSinkForNoneIsNoop(t *testing.T) {
+	if _, ok := kubeconfigSinkFor(v1alpha1.NoneKubeconfigSink).(noopKubeconfigSink); !ok {
+		t.Fatalf("expected %q to resolve to noopKubeconfigSink", v1alpha1.NoneKubeconfigSink)
+	}
+}
+
+func TestSecretKubeconfigSinkWritesToSecret(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	sink := secretKubeconfigSink{}
+
+	if err := sink.Write(client, "ns1", "vc1-admin-config", map[string][]byte{"kubeconfig": []byte("data")}, nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	secret, err := client.CoreV1().Secrets("ns1").Get(context.TODO(), "vc1-admin-config", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected secret to be created: %v", err)
+	}
+	if string(secret.Data["kubeconfig"]) != "data" {
+		t.Errorf("secret kubeconfig data = %q, want %q", secret.Data["kubeconfig"], "data")
+	}
+}
+
+func TestSecretKubeconfigSinkMergesResourceLabelsAndAnnotationsWithoutOverwritingKosmosLabel(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	sink := secretKubeconfigSink{}
+
+	resourceLabels := map[string]string{"cost-center": "platform", constants.VirtualClusterLabelKeyName: "attempted-override"}
+	resourceAnnotations := map[string]string{"team": "infra"}
+
+	if err := sink.Write(client, "ns1", "vc1-admin-config", map[string][]byte{"kubeconfig": []byte("data")}, resourceLabels, resourceAnnotations); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	secret, err := client.CoreV1().Secrets("ns1").Get(context.TODO(), "vc1-admin-config", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected secret to be created: %v", err)
+	}
+	if secret.Labels["cost-center"] != "platform" {
+		t.Errorf("expected custom label cost-center=platform, got %q", secret.Labels["cost-center"])
+	}
+	if secret.Annotations["team"] != "infra" {
+		t.Errorf("expected custom annotation team=infra, got %q", secret.Annotations["team"])
+	}
+	if secret.Labels[constants.VirtualClusterLabelKeyName] != constants.VirtualClusterController {
+		t.Errorf("expected kosmos-managed label %s to survive merging, got %q", constants.VirtualClusterLabelKeyName, secret.Labels[constants.VirtualClusterLabelKeyName])
+	}
+}
+
+func TestFakeKubeconfigSinkReceivesKubeconfigWithoutASecret(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	sink := &fakeKubeconfigSink{}
+
+	if err := sink.Write(client, "ns1", "vc1-admin-config", map[string][]byte{"kubeconfig": []byte("data")}, nil, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if string(sink.data["kubeconfig"]) != "data" {
+		t.Errorf("fake sink kubeconfig data = %q, want %q", sink.data["kubeconfig"], "data")
+	}
+
+	if _, err := client.CoreV1().Secrets("ns1").Get(context.TODO(), "vc1-admin-config", metav1.GetOptions{}); err == nil {
+		t.Fatalf("expected no secret to be created when writing through a non-Secret sink")
+	}
+}
@@ -0,0 +1,98 @@
+package tasks
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kosmos.io/kosmos/pkg/kubenest/util"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/workflow"
+)
+
+func NewResourceQuotaTask() workflow.Task {
+	return workflow.Task{
+		Name: "resource-quota",
+		Run:  runResourceQuota,
+	}
+}
+
+// runResourceQuota sums the resource requests/limits of every control-plane
+// Deployment and StatefulSet already applied to the virtual cluster's
+// namespace (scaled by their replica counts) and applies a ResourceQuota
+// sized to that footprint, so the namespace can never grow past what its own
+// configured components ask for.
+func runResourceQuota(r workflow.RunData) error {
+	data, ok := r.(InitData)
+	if !ok {
+		return errors.New("resource-quota task invoked with an invalid data struct")
+	}
+
+	deployments, err := data.RemoteClient().AppsV1().Deployments(data.GetNamespace()).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "list control plane deployments")
+	}
+	statefulSets, err := data.RemoteClient().AppsV1().StatefulSets(data.GetNamespace()).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrap(err, "list control plane statefulsets")
+	}
+
+	quota := buildControlPlaneResourceQuota(data.GetNamespace(), util.GetControlPlaneResourceQuotaName(data.GetName()), deployments.Items, statefulSets.Items)
+	if err := util.CreateOrUpdateResourceQuota(data.RemoteClient(), quota); err != nil {
+		return errors.Wrap(err, "create or update control plane resourcequota")
+	}
+	return nil
+}
+
+// buildControlPlaneResourceQuota computes a ResourceQuota's hard limits from
+// the pod templates of deployments and statefulSets, each container's
+// requests and limits counted once per replica. Quantities are accumulated
+// in milli-units so fractional requests (e.g. cpu: 1m) aren't lost to
+// rounding along the way.
+func buildControlPlaneResourceQuota(namespace, name string, deployments []appsv1.Deployment, statefulSets []appsv1.StatefulSet) *v1.ResourceQuota {
+	milli := map[v1.ResourceName]int64{}
+
+	for _, deployment := range deployments {
+		addScaledResources(milli, deployment.Spec.Template.Spec.Containers, replicasOrOne(deployment.Spec.Replicas))
+	}
+	for _, statefulSet := range statefulSets {
+		addScaledResources(milli, statefulSet.Spec.Template.Spec.Containers, replicasOrOne(statefulSet.Spec.Replicas))
+	}
+
+	hard := v1.ResourceList{}
+	for resourceName, value := range milli {
+		hard[resourceName] = *resource.NewMilliQuantity(value, resource.DecimalSI)
+	}
+
+	return &v1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       v1.ResourceQuotaSpec{Hard: hard},
+	}
+}
+
+func replicasOrOne(replicas *int32) int64 {
+	if replicas == nil || *replicas <= 0 {
+		return 1
+	}
+	return int64(*replicas)
+}
+
+func addScaledResources(milli map[v1.ResourceName]int64, containers []v1.Container, replicas int64) {
+	for _, container := range containers {
+		addScaledQuantity(milli, v1.ResourceRequestsCPU, container.Resources.Requests, v1.ResourceCPU, replicas)
+		addScaledQuantity(milli, v1.ResourceRequestsMemory, container.Resources.Requests, v1.ResourceMemory, replicas)
+		addScaledQuantity(milli, v1.ResourceLimitsCPU, container.Resources.Limits, v1.ResourceCPU, replicas)
+		addScaledQuantity(milli, v1.ResourceLimitsMemory, container.Resources.Limits, v1.ResourceMemory, replicas)
+	}
+}
+
+func addScaledQuantity(milli map[v1.ResourceName]int64, hardKey v1.ResourceName, from v1.ResourceList, fromKey v1.ResourceName, replicas int64) {
+	quantity, ok := from[fromKey]
+	if !ok {
+		return
+	}
+	milli[hardKey] += quantity.MilliValue() * replicas
+}
@@ -0,0 +1,76 @@
+package tasks
+
+import (
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func replicas(n int32) *int32 { return &n }
+
+func TestBuildControlPlaneResourceQuotaSumsScaledComponentResources(t *testing.T) {
+	apiserver := appsv1.Deployment{
+		Spec: appsv1.DeploymentSpec{
+			Replicas: replicas(2),
+			Template: v1.PodTemplateSpec{Spec: v1.PodSpec{Containers: []v1.Container{
+				{
+					Resources: v1.ResourceRequirements{
+						Requests: v1.ResourceList{
+							v1.ResourceCPU:    resource.MustParse("100m"),
+							v1.ResourceMemory: resource.MustParse("200Mi"),
+						},
+						Limits: v1.ResourceList{
+							v1.ResourceCPU:    resource.MustParse("500m"),
+							v1.ResourceMemory: resource.MustParse("1Gi"),
+						},
+					},
+				},
+			}}},
+		},
+	}
+	etcd := appsv1.StatefulSet{
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: replicas(3),
+			Template: v1.PodTemplateSpec{Spec: v1.PodSpec{Containers: []v1.Container{
+				{
+					Resources: v1.ResourceRequirements{
+						Requests: v1.ResourceList{
+							v1.ResourceCPU:    resource.MustParse("50m"),
+							v1.ResourceMemory: resource.MustParse("100Mi"),
+						},
+					},
+				},
+			}}},
+		},
+	}
+
+	quota := buildControlPlaneResourceQuota("test-ns", "vc1-control-plane-quota", []appsv1.Deployment{apiserver}, []appsv1.StatefulSet{etcd})
+
+	if quota.Name != "vc1-control-plane-quota" || quota.Namespace != "test-ns" {
+		t.Fatalf("unexpected quota metadata: %+v", quota.ObjectMeta)
+	}
+
+	wantRequestsCPU := resource.MustParse("350m")     // 2*100m + 3*50m
+	wantRequestsMemory := resource.MustParse("700Mi") // 2*200Mi + 3*100Mi
+	wantLimitsCPU := resource.MustParse("1")          // 2*500m
+	wantLimitsMemory := resource.MustParse("2Gi")     // 2*1Gi
+
+	gotRequestsCPU := quota.Spec.Hard[v1.ResourceRequestsCPU]
+	if gotRequestsCPU.Cmp(wantRequestsCPU) != 0 {
+		t.Errorf("requests.cpu = %s, want %s", gotRequestsCPU.String(), wantRequestsCPU.String())
+	}
+	gotRequestsMemory := quota.Spec.Hard[v1.ResourceRequestsMemory]
+	if gotRequestsMemory.Cmp(wantRequestsMemory) != 0 {
+		t.Errorf("requests.memory = %s, want %s", gotRequestsMemory.String(), wantRequestsMemory.String())
+	}
+	gotLimitsCPU := quota.Spec.Hard[v1.ResourceLimitsCPU]
+	if gotLimitsCPU.Cmp(wantLimitsCPU) != 0 {
+		t.Errorf("limits.cpu = %s, want %s", gotLimitsCPU.String(), wantLimitsCPU.String())
+	}
+	gotLimitsMemory := quota.Spec.Hard[v1.ResourceLimitsMemory]
+	if gotLimitsMemory.Cmp(wantLimitsMemory) != 0 {
+		t.Errorf("limits.memory = %s, want %s", gotLimitsMemory.String(), wantLimitsMemory.String())
+	}
+}
@@ -0,0 +1,92 @@
+package tasks
+
+import (
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/util"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/workflow"
+)
+
+// IsDefaultStorageClassAnnotation marks a StorageClass as the cluster-wide
+// default, matching how the Kubernetes storage admission controller picks a
+// default for PVCs that don't request one.
+const IsDefaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+
+// defaultStorageClassName is used when DefaultStorageClassConfig.Name is
+// left unset.
+const defaultStorageClassName = "default"
+
+func NewDefaultStorageClassTask() workflow.Task {
+	return workflow.Task{
+		Name: "default-storage-class",
+		Run:  runDefaultStorageClass,
+		Skip: skipDefaultStorageClass,
+	}
+}
+
+// skipDefaultStorageClass skips the task unless the virtual cluster opted in
+// via KubeInKubeConfig.DefaultStorageClass, since most virtual clusters are
+// expected to bring their own storage.
+func skipDefaultStorageClass(r workflow.RunData) (bool, error) {
+	data, ok := r.(InitData)
+	if !ok {
+		return false, errors.New("default-storage-class task invoked with an invalid data struct")
+	}
+
+	vc := data.VirtualCluster()
+	return vc.Spec.KubeInKubeConfig == nil || vc.Spec.KubeInKubeConfig.DefaultStorageClass == nil, nil
+}
+
+// runDefaultStorageClass reconciles the StorageClass configured by
+// KubeInKubeConfig.DefaultStorageClass into the tenant cluster, so tenants
+// deploying stateful workloads have a default to bind PVCs against without
+// configuring storage themselves.
+func runDefaultStorageClass(r workflow.RunData) error {
+	data, ok := r.(InitData)
+	if !ok {
+		return errors.New("default-storage-class task invoked with an invalid data struct")
+	}
+
+	kubeInKubeConfig := data.VirtualCluster().Spec.KubeInKubeConfig
+	config := kubeInKubeConfig.DefaultStorageClass
+
+	vcClient, err := GetVcClientset(data.RemoteClient(), data.GetName(), data.GetNamespace(), kubeInKubeConfig)
+	if err != nil {
+		return errors.Wrap(err, "failed to get virtual cluster client")
+	}
+
+	if err := util.CreateOrUpdateStorageClass(vcClient, buildDefaultStorageClass(config)); err != nil {
+		return errors.Wrap(err, "create or update default storageclass")
+	}
+
+	klog.V(2).InfoS("[default-storage-class] Successfully reconciled default storageclass", "virtual cluster", klog.KObj(data))
+	return nil
+}
+
+// buildDefaultStorageClass renders config into a StorageClass marked as the
+// tenant cluster's default.
+func buildDefaultStorageClass(config *v1alpha1.DefaultStorageClassConfig) *storagev1.StorageClass {
+	name := config.Name
+	if name == "" {
+		name = defaultStorageClassName
+	}
+	reclaimPolicy := corev1.PersistentVolumeReclaimDelete
+	if config.ReclaimPolicy != "" {
+		reclaimPolicy = corev1.PersistentVolumeReclaimPolicy(config.ReclaimPolicy)
+	}
+
+	return &storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{IsDefaultStorageClassAnnotation: "true"},
+		},
+		Provisioner:   config.Provisioner,
+		Parameters:    config.Parameters,
+		ReclaimPolicy: &reclaimPolicy,
+	}
+}
@@ -0,0 +1,51 @@
+package tasks
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+)
+
+func TestBuildDefaultStorageClassMarksItAsDefault(t *testing.T) {
+	cfg := &v1alpha1.DefaultStorageClassConfig{
+		Provisioner: "csi.example.com",
+		Parameters:  map[string]string{"type": "ssd"},
+	}
+
+	sc := buildDefaultStorageClass(cfg)
+
+	if sc.Name != defaultStorageClassName {
+		t.Errorf("Name = %q, want %q", sc.Name, defaultStorageClassName)
+	}
+	if sc.Annotations[IsDefaultStorageClassAnnotation] != "true" {
+		t.Errorf("expected %s annotation to be set to true, got %+v", IsDefaultStorageClassAnnotation, sc.Annotations)
+	}
+	if sc.Provisioner != cfg.Provisioner {
+		t.Errorf("Provisioner = %q, want %q", sc.Provisioner, cfg.Provisioner)
+	}
+	if sc.Parameters["type"] != "ssd" {
+		t.Errorf("Parameters[type] = %q, want %q", sc.Parameters["type"], "ssd")
+	}
+	if sc.ReclaimPolicy == nil || *sc.ReclaimPolicy != corev1.PersistentVolumeReclaimDelete {
+		t.Errorf("expected default ReclaimPolicy to be Delete, got %v", sc.ReclaimPolicy)
+	}
+}
+
+func TestBuildDefaultStorageClassHonoursNameAndReclaimPolicy(t *testing.T) {
+	cfg := &v1alpha1.DefaultStorageClassConfig{
+		Name:          "tenant-default",
+		Provisioner:   "csi.example.com",
+		ReclaimPolicy: string(corev1.PersistentVolumeReclaimRetain),
+	}
+
+	sc := buildDefaultStorageClass(cfg)
+
+	if sc.Name != "tenant-default" {
+		t.Errorf("Name = %q, want %q", sc.Name, "tenant-default")
+	}
+	if sc.ReclaimPolicy == nil || *sc.ReclaimPolicy != corev1.PersistentVolumeReclaimRetain {
+		t.Errorf("expected configured ReclaimPolicy to be Retain, got %v", sc.ReclaimPolicy)
+	}
+}
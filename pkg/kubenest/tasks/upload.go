@@ -6,6 +6,7 @@ import (
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -14,6 +15,7 @@ import (
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 	"k8s.io/klog/v2"
 
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
 	"github.com/kosmos.io/kosmos/pkg/kubenest/constants"
 	"github.com/kosmos.io/kosmos/pkg/kubenest/util"
 	"github.com/kosmos.io/kosmos/pkg/kubenest/util/cert"
@@ -58,6 +60,10 @@ func NewUploadKubeconfigTask() workflow.Task {
 				Name: "UploadAdminKubeconfig",
 				Run:  runUploadAdminKubeconfig,
 			},
+			{
+				Name: "UploadAdditionalKubeconfigs",
+				Run:  runUploadAdditionalKubeconfigs,
+			},
 		},
 	}
 }
@@ -98,14 +104,16 @@ func runUploadVirtualClusterCert(r workflow.RunData) error {
 		certsData[c.CertName()] = c.CertData()
 	}
 
-	err := createOrUpdateSecret(data.RemoteClient(), &corev1.Secret{
+	certSecret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      util.GetCertName(data.GetName()),
 			Namespace: data.GetNamespace(),
 			Labels:    VirtualClusterControllerLabel,
 		},
 		Data: certsData,
-	})
+	}
+	util.MergeResourceLabelsAndAnnotations(certSecret, data.ResourceLabels(), data.ResourceAnnotations())
+	err := createOrUpdateSecret(data.RemoteClient(), certSecret)
 	if err != nil {
 		return fmt.Errorf("failed to upload virtual cluster cert to secret, err: %w", err)
 	}
@@ -124,7 +132,7 @@ func runUploadEtcdCert(r workflow.RunData) error {
 	server := data.GetCert(constants.EtcdServerCertAndKeyName)
 	client := data.GetCert(constants.EtcdClientCertAndKeyName)
 
-	err := createOrUpdateSecret(data.RemoteClient(), &corev1.Secret{
+	etcdCertSecret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: data.GetNamespace(),
 			Name:      util.GetEtcdCertName(data.GetName()),
@@ -139,7 +147,9 @@ func runUploadEtcdCert(r workflow.RunData) error {
 			client.CertName(): client.CertData(),
 			client.KeyName():  client.KeyData(),
 		},
-	})
+	}
+	util.MergeResourceLabelsAndAnnotations(etcdCertSecret, data.ResourceLabels(), data.ResourceAnnotations())
+	err := createOrUpdateSecret(data.RemoteClient(), etcdCertSecret)
 	if err != nil {
 		return fmt.Errorf("failed to upload etcd certs to secret, err: %w", err)
 	}
@@ -148,6 +158,51 @@ func runUploadEtcdCert(r workflow.RunData) error {
 	return nil
 }
 
+// KubeconfigSink is where a VirtualCluster's generated admin kubeconfig is
+// persisted once the control plane is ready. KubeInKubeConfig.KubeconfigSink
+// picks the implementation through kubeconfigSinkFor, which knows only the
+// built-in sinks: None selects noopKubeconfigSink and any other value,
+// including unset, selects secretKubeconfigSink. No other implementation can
+// be selected through that field.
+type KubeconfigSink interface {
+	Write(client clientset.Interface, namespace, name string, data map[string][]byte, resourceLabels, resourceAnnotations map[string]string) error
+}
+
+// secretKubeconfigSink is the default KubeconfigSink, preserving the
+// pre-existing behavior of storing the kubeconfig in a Kubernetes Secret.
+type secretKubeconfigSink struct{}
+
+func (secretKubeconfigSink) Write(client clientset.Interface, namespace, name string, data map[string][]byte, resourceLabels, resourceAnnotations map[string]string) error {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+			Labels:    VirtualClusterControllerLabel,
+		},
+		Data: data,
+	}
+	util.MergeResourceLabelsAndAnnotations(secret, resourceLabels, resourceAnnotations)
+	return createOrUpdateSecret(client, secret)
+}
+
+// noopKubeconfigSink discards the kubeconfig without writing it anywhere.
+type noopKubeconfigSink struct{}
+
+func (noopKubeconfigSink) Write(clientset.Interface, string, string, map[string][]byte, map[string]string, map[string]string) error {
+	return nil
+}
+
+// kubeconfigSinkFor resolves the KubeconfigSink selected by sinkType,
+// defaulting to secretKubeconfigSink when unset.
+func kubeconfigSinkFor(sinkType v1alpha1.KubeconfigSinkType) KubeconfigSink {
+	switch sinkType {
+	case v1alpha1.NoneKubeconfigSink:
+		return noopKubeconfigSink{}
+	default:
+		return secretKubeconfigSink{}
+	}
+}
+
 func createOrUpdateSecret(client clientset.Interface, secret *corev1.Secret) error {
 	_, err := client.CoreV1().Secrets(secret.GetNamespace()).Create(context.TODO(), secret, metav1.CreateOptions{})
 	if err != nil {
@@ -201,34 +256,153 @@ func runUploadAdminKubeconfig(r workflow.RunData) error {
 		return err
 	}
 
-	err = createOrUpdateSecret(data.RemoteClient(), &corev1.Secret{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: data.GetNamespace(),
-			Name:      util.GetAdminConfigSecretName(data.GetName()),
-			Labels:    VirtualClusterControllerLabel,
-		},
-		Data: map[string][]byte{"kubeconfig": controlplaneIPConfigBytes},
-	})
+	var externalConfigBytes []byte
+	if externalHostName := data.ExternalHostName(); externalHostName != "" {
+		// external hostname + nodePort, for NAT'd environments where
+		// ControlplaneAddress isn't reachable from outside the cluster.
+		externalEndpoint := fmt.Sprintf("https://%s", utils.GenerateAddrStr(externalHostName, fmt.Sprintf("%d", portInfo.NodePort)))
+		externalKubeconfig, err := buildKubeConfigFromSpec(data, externalEndpoint)
+		if err != nil {
+			return err
+		}
+		externalConfigBytes, err = clientcmd.Write(*externalKubeconfig)
+		if err != nil {
+			return err
+		}
+	}
+
+	var sinkType v1alpha1.KubeconfigSinkType
+	if kubeInKubeConfig := data.VirtualCluster().Spec.KubeInKubeConfig; kubeInKubeConfig != nil {
+		sinkType = kubeInKubeConfig.KubeconfigSink
+	}
+	sink := kubeconfigSinkFor(sinkType)
+
+	err = sink.Write(data.RemoteClient(), data.GetNamespace(), util.GetAdminConfigSecretName(data.GetName()), map[string][]byte{"kubeconfig": controlplaneIPConfigBytes}, data.ResourceLabels(), data.ResourceAnnotations())
 	if err != nil {
-		return fmt.Errorf("failed to create secret of kubeconfig, err: %w", err)
+		return fmt.Errorf("failed to write kubeconfig to sink, err: %w", err)
 	}
 
-	err = createOrUpdateSecret(data.RemoteClient(), &corev1.Secret{
-		ObjectMeta: metav1.ObjectMeta{
-			Namespace: data.GetNamespace(),
-			Name:      util.GetAdminConfigClusterIPSecretName(data.GetName()),
-			Labels:    VirtualClusterControllerLabel,
-		},
-		Data: map[string][]byte{"kubeconfig": clusterIPConfigBytes},
-	})
+	err = sink.Write(data.RemoteClient(), data.GetNamespace(), util.GetAdminConfigClusterIPSecretName(data.GetName()), map[string][]byte{"kubeconfig": clusterIPConfigBytes}, data.ResourceLabels(), data.ResourceAnnotations())
 	if err != nil {
-		return fmt.Errorf("failed to create secret of kubeconfig-clusterip, err: %w", err)
+		return fmt.Errorf("failed to write kubeconfig-clusterip to sink, err: %w", err)
+	}
+
+	if externalConfigBytes != nil {
+		err = sink.Write(data.RemoteClient(), data.GetNamespace(), util.GetAdminConfigExternalSecretName(data.GetName()), map[string][]byte{"kubeconfig": externalConfigBytes}, data.ResourceLabels(), data.ResourceAnnotations())
+		if err != nil {
+			return fmt.Errorf("failed to write kubeconfig-external to sink, err: %w", err)
+		}
 	}
 
-	klog.V(2).InfoS("[UploadAdminKubeconfig] Successfully created secrets of virtual cluster apiserver kubeconfig", "virtual cluster", klog.KObj(data))
+	klog.V(2).InfoS("[UploadAdminKubeconfig] Successfully wrote virtual cluster apiserver kubeconfig to sink", "virtual cluster", klog.KObj(data))
 	return nil
 }
 
+// runUploadAdditionalKubeconfigs provisions the scoped kubeconfigs requested
+// via Spec.AdditionalKubeconfigs: each gets its own client certificate, not
+// part of the system:masters group the cluster-admin kubeconfig uses, bound
+// to its ClusterRole by a ClusterRoleBinding created in the tenant cluster,
+// then is written to its own secret the same way the admin kubeconfig is.
+func runUploadAdditionalKubeconfigs(r workflow.RunData) error {
+	data, ok := r.(InitData)
+	if !ok {
+		return errors.New("UploadAdditionalKubeconfigs task invoked with an invalid data struct")
+	}
+
+	entries := data.VirtualCluster().Spec.AdditionalKubeconfigs
+	if len(entries) == 0 {
+		return nil
+	}
+
+	service, err := data.RemoteClient().CoreV1().Services(data.GetNamespace()).Get(context.TODO(), util.GetAPIServerName(data.GetName()), metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	portInfo := getPortInfoFromAPIServerService(service)
+	endpoint := fmt.Sprintf("https://%s", utils.GenerateAddrStr(data.ControlplaneAddress(), fmt.Sprintf("%d", portInfo.NodePort)))
+
+	// The ClusterRoleBinding for each entry's identity has to be created by
+	// someone who already has cluster-admin in the tenant cluster, since the
+	// entry's own identity has no access until that binding exists.
+	adminKubeconfig, err := buildKubeConfigFromSpec(data, endpoint)
+	if err != nil {
+		return err
+	}
+	tenantClient, err := tenantClientFromKubeconfig(adminKubeconfig)
+	if err != nil {
+		return fmt.Errorf("failed to build tenant client to provision additional kubeconfig RBAC, err: %w", err)
+	}
+
+	var sinkType v1alpha1.KubeconfigSinkType
+	if kubeInKubeConfig := data.VirtualCluster().Spec.KubeInKubeConfig; kubeInKubeConfig != nil {
+		sinkType = kubeInKubeConfig.KubeconfigSink
+	}
+	sink := kubeconfigSinkFor(sinkType)
+
+	for _, entry := range entries {
+		commonName := fmt.Sprintf("%s:%s", data.GetName(), entry.Name)
+
+		if err := util.CreateOrUpdateClusterRoleBinding(tenantClient, additionalKubeconfigClusterRoleBinding(commonName, entry.ClusterRole)); err != nil {
+			return fmt.Errorf("failed to bind additional kubeconfig %q to ClusterRole %q, err: %w", entry.Name, entry.ClusterRole, err)
+		}
+
+		kubeconfig, err := buildKubeConfigFromCertConfig(data, endpoint, cert.VirtualClusterAdditionalKubeconfigClient(commonName))
+		if err != nil {
+			return fmt.Errorf("failed to build additional kubeconfig %q, err: %w", entry.Name, err)
+		}
+		configBytes, err := clientcmd.Write(*kubeconfig)
+		if err != nil {
+			return err
+		}
+
+		err = sink.Write(data.RemoteClient(), data.GetNamespace(), util.GetAdditionalKubeconfigSecretName(data.GetName(), entry.Name), map[string][]byte{"kubeconfig": configBytes}, data.ResourceLabels(), data.ResourceAnnotations())
+		if err != nil {
+			return fmt.Errorf("failed to write additional kubeconfig %q to sink, err: %w", entry.Name, err)
+		}
+	}
+
+	klog.V(2).InfoS("[UploadAdditionalKubeconfigs] Successfully wrote additional scoped kubeconfigs to sink", "virtual cluster", klog.KObj(data))
+	return nil
+}
+
+// additionalKubeconfigClusterRoleBinding binds subjectName, the CommonName
+// an additional kubeconfig's client certificate was signed with, to
+// clusterRole.
+func additionalKubeconfigClusterRoleBinding(subjectName, clusterRole string) *rbacv1.ClusterRoleBinding {
+	return &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: fmt.Sprintf("additional-kubeconfig:%s", subjectName),
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     clusterRole,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:     rbacv1.UserKind,
+				APIGroup: rbacv1.GroupName,
+				Name:     subjectName,
+			},
+		},
+	}
+}
+
+// tenantClientFromKubeconfig builds a client for the virtual cluster's own
+// apiserver directly from an in-memory kubeconfig, for use before that
+// kubeconfig has been persisted anywhere.
+func tenantClientFromKubeconfig(kubeconfig *clientcmdapi.Config) (clientset.Interface, error) {
+	kubeconfigBytes, err := clientcmd.Write(*kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	restConfig, err := utils.NewConfigFromBytes(kubeconfigBytes)
+	if err != nil {
+		return nil, err
+	}
+	return clientset.NewForConfig(restConfig)
+}
+
 func getPortInfoFromAPIServerService(service *corev1.Service) PortInfo {
 	var portInfo PortInfo
 	if service.Spec.Type == corev1.ServiceTypeNodePort {
@@ -245,13 +419,20 @@ func getPortInfoFromAPIServerService(service *corev1.Service) PortInfo {
 }
 
 func buildKubeConfigFromSpec(data InitData, serverURL string) (*clientcmdapi.Config, error) {
+	return buildKubeConfigFromCertConfig(data, serverURL, cert.VirtualClusterCertClient())
+}
+
+// buildKubeConfigFromCertConfig signs cc's client certificate against this
+// VirtualCluster's CA and builds a kubeconfig pointed at serverURL that
+// authenticates with it, the way buildKubeConfigFromSpec does for the
+// cluster-admin kubeconfig and runUploadAdditionalKubeconfigs does for each
+// Spec.AdditionalKubeconfigs entry.
+func buildKubeConfigFromCertConfig(data InitData, serverURL string, cc *cert.CertConfig) (*clientcmdapi.Config, error) {
 	ca := data.GetCert(constants.CaCertAndKeyName)
 	if ca == nil {
 		return nil, errors.New("unable build virtual cluster admin kubeconfig, CA cert is empty")
 	}
 
-	cc := cert.VirtualClusterCertClient()
-
 	if err := mutateCertConfig(data, cc); err != nil {
 		return nil, fmt.Errorf("error when mutate cert altNames for %s, err: %w", cc.Name, err)
 	}
@@ -305,6 +486,10 @@ func deleteSecrets(r workflow.RunData) error {
 		util.GetEtcdCertName(data.GetName()),
 		util.GetAdminConfigSecretName(data.GetName()),
 		util.GetAdminConfigClusterIPSecretName(data.GetName()),
+		util.GetAdminConfigExternalSecretName(data.GetName()),
+	}
+	for _, entry := range data.VirtualCluster().Spec.AdditionalKubeconfigs {
+		secrets = append(secrets, util.GetAdditionalKubeconfigSecretName(data.GetName(), entry.Name))
 	}
 	for _, secret := range secrets {
 		err := data.RemoteClient().CoreV1().Secrets(data.GetNamespace()).Delete(context.TODO(), secret, metav1.DeleteOptions{})
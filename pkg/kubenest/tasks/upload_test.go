@@ -0,0 +1,19 @@
+package tasks
+
+import (
+	"testing"
+)
+
+func TestAdditionalKubeconfigClusterRoleBindingBindsSubjectToClusterRole(t *testing.T) {
+	binding := additionalKubeconfigClusterRoleBinding("vc1:view", "view")
+
+	if binding.RoleRef.Name != "view" {
+		t.Errorf("RoleRef.Name = %q, want %q", binding.RoleRef.Name, "view")
+	}
+	if binding.RoleRef.Kind != "ClusterRole" {
+		t.Errorf("RoleRef.Kind = %q, want %q", binding.RoleRef.Kind, "ClusterRole")
+	}
+	if len(binding.Subjects) != 1 || binding.Subjects[0].Name != "vc1:view" {
+		t.Errorf("Subjects = %v, want a single subject named %q", binding.Subjects, "vc1:view")
+	}
+}
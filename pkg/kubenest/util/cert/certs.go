@@ -45,6 +45,7 @@ type AltNamesMutatorConfig struct {
 	ClusterIPs       []string
 	ExternalIP       string
 	ExternalIPs      []string
+	ExternalHostName string
 	VipMap           map[string]string
 }
 
@@ -290,6 +291,10 @@ func apiServerAltNamesMutator(cfg *AltNamesMutatorConfig) (*certutil.AltNames, e
 		}
 	}
 
+	if len(cfg.ExternalHostName) > 0 {
+		appendSANsToAltNames(altNames, []string{cfg.ExternalHostName})
+	}
+
 	if len(cfg.VipMap) > 0 {
 		for _, vip := range cfg.VipMap {
 			appendSANsToAltNames(altNames, []string{vip})
@@ -531,3 +536,21 @@ func VirtualClusterCertClient() *CertConfig {
 		AltNamesMutatorFunc: makeAltNamesMutator(apiServerAltNamesMutator),
 	}
 }
+
+// VirtualClusterAdditionalKubeconfigClient returns a CertConfig for signing
+// the client certificate of one of a VirtualCluster's
+// Spec.AdditionalKubeconfigs entries. Unlike VirtualClusterCertClient, its
+// CommonName isn't "system:admin" and it carries no Organization, so it
+// isn't part of the system:masters group: its access comes entirely from
+// whatever ClusterRoleBinding targets commonName as a Subject.
+func VirtualClusterAdditionalKubeconfigClient(commonName string) *CertConfig {
+	return &CertConfig{
+		Name:   "virtualCluster-client",
+		CAName: constants.CaCertAndKeyName,
+		Config: certutil.Config{
+			CommonName: commonName,
+			Usages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		},
+		AltNamesMutatorFunc: makeAltNamesMutator(apiServerAltNamesMutator),
+	}
+}
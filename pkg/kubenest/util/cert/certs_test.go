@@ -0,0 +1,52 @@
+package cert
+
+import (
+	"testing"
+)
+
+func TestAPIServerAltNamesMutatorIncludesExternalHostName(t *testing.T) {
+	cfg := &AltNamesMutatorConfig{
+		Namespace:        "test-namespace",
+		ControlplaneAddr: "10.0.0.1",
+		ExternalHostName: "vc1.example.com",
+	}
+
+	altNames, err := apiServerAltNamesMutator(cfg)
+	if err != nil {
+		t.Fatalf("apiServerAltNamesMutator() error = %v", err)
+	}
+
+	if !containsDNSName(altNames.DNSNames, cfg.ExternalHostName) {
+		t.Errorf("expected SANs to contain external hostname %q, got %v", cfg.ExternalHostName, altNames.DNSNames)
+	}
+
+	found := false
+	for _, ip := range altNames.IPs {
+		if ip.String() == cfg.ControlplaneAddr {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected SANs to still contain internal advertise address %q, got %v", cfg.ControlplaneAddr, altNames.IPs)
+	}
+}
+
+func TestVirtualClusterAdditionalKubeconfigClientIsNotSystemMasters(t *testing.T) {
+	cc := VirtualClusterAdditionalKubeconfigClient("vc1:view")
+
+	if cc.Config.CommonName != "vc1:view" {
+		t.Errorf("CommonName = %q, want %q", cc.Config.CommonName, "vc1:view")
+	}
+	if len(cc.Config.Organization) != 0 {
+		t.Errorf("expected no Organization (not part of system:masters), got %v", cc.Config.Organization)
+	}
+}
+
+func containsDNSName(names []string, want string) bool {
+	for _, name := range names {
+		if name == want {
+			return true
+		}
+	}
+	return false
+}
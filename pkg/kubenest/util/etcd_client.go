@@ -0,0 +1,48 @@
+package util
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/constants"
+)
+
+// NewEtcdClient builds a clientv3.Client for a VirtualCluster's etcd
+// cluster, authenticating with the etcd client certificate uploaded to the
+// host cluster during cluster creation (see runUploadEtcdCert). Callers own
+// the returned client and are responsible for closing it.
+func NewEtcdClient(hostClient kubernetes.Interface, vc *v1alpha1.VirtualCluster) (*clientv3.Client, error) {
+	secret, err := hostClient.CoreV1().Secrets(vc.Namespace).Get(context.TODO(), GetEtcdCertName(vc.Name), metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("get etcd cert secret: %w", err)
+	}
+
+	tlsCert, err := tls.X509KeyPair(
+		secret.Data[constants.EtcdClientCertAndKeyName+constants.CertExtension],
+		secret.Data[constants.EtcdClientCertAndKeyName+constants.KeyExtension],
+	)
+	if err != nil {
+		return nil, fmt.Errorf("parse etcd client certificate: %w", err)
+	}
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM(secret.Data[constants.EtcdCaCertAndKeyName+constants.CertExtension]) {
+		return nil, fmt.Errorf("parse etcd ca certificate")
+	}
+
+	endpoint := fmt.Sprintf("https://%s.%s.svc.cluster.local:%d", GetEtcdClientServerName(vc.Name), vc.Namespace, constants.EtcdListenClientPort)
+	return clientv3.New(clientv3.Config{
+		Endpoints:   []string{endpoint},
+		DialTimeout: constants.EtcdClientDialTimeout,
+		TLS: &tls.Config{
+			Certificates: []tls.Certificate{tlsCert},
+			RootCAs:      caPool,
+		},
+	})
+}
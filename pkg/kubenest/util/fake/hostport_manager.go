@@ -0,0 +1,100 @@
+// Package fake provides in-memory fakes for interfaces in
+// github.com/kosmos.io/kosmos/pkg/kubenest/util, for use in controller unit
+// tests that need a HostPortAllocator without provisioning a real port pool.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/util"
+)
+
+// HostPortManager is an in-memory util.HostPortAllocator that hands out
+// sequential ports starting at NextPort, tracking allocations so tests can
+// assert on what was allocated and released.
+type HostPortManager struct {
+	mu sync.Mutex
+
+	// NextPort is the next port AllocatePortRange will hand out. It defaults
+	// to 0; callers that care about the allocated values should set it
+	// before use.
+	NextPort int32
+
+	// Allocated is the set of ports currently considered allocated.
+	Allocated map[int32]bool
+}
+
+// NewHostPortManager returns a HostPortManager that starts allocating from
+// nextPort.
+func NewHostPortManager(nextPort int32) *HostPortManager {
+	return &HostPortManager{
+		NextPort:  nextPort,
+		Allocated: make(map[int32]bool),
+	}
+}
+
+var _ util.HostPortAllocator = &HostPortManager{}
+
+// AllocatePortRange hands out count sequential ports starting at NextPort and
+// advances NextPort past them.
+func (m *HostPortManager) AllocatePortRange(count int) ([]int32, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if count <= 0 {
+		return nil, fmt.Errorf("count must be positive, got %d", count)
+	}
+
+	ports := make([]int32, count)
+	for i := 0; i < count; i++ {
+		ports[i] = m.NextPort
+		m.Allocated[m.NextPort] = true
+		m.NextPort++
+	}
+	return ports, nil
+}
+
+// ReleasePorts marks ports as no longer allocated.
+func (m *HostPortManager) ReleasePorts(ports []int32) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, port := range ports {
+		delete(m.Allocated, port)
+	}
+}
+
+// SyncFromClusters marks the ports already claimed by virtualClusters as
+// allocated.
+func (m *HostPortManager) SyncFromClusters(_ context.Context, virtualClusters []v1alpha1.VirtualCluster) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, vc := range virtualClusters {
+		if vc.Status.Port != 0 {
+			m.Allocated[vc.Status.Port] = true
+		}
+		for _, port := range vc.Status.PortMap {
+			m.Allocated[port] = true
+		}
+	}
+	return nil
+}
+
+// AllocatedPorts returns the currently allocated ports in ascending order,
+// for assertions in tests.
+func (m *HostPortManager) AllocatedPorts() []int32 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ports := make([]int32, 0, len(m.Allocated))
+	for port := range m.Allocated {
+		ports = append(ports, port)
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+	return ports
+}
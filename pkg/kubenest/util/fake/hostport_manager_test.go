@@ -0,0 +1,73 @@
+package fake
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+)
+
+func TestHostPortManagerAllocatePortRangeIsSequential(t *testing.T) {
+	m := NewHostPortManager(30000)
+
+	ports, err := m.AllocatePortRange(3)
+	if err != nil {
+		t.Fatalf("AllocatePortRange(3) error = %v", err)
+	}
+	want := []int32{30000, 30001, 30002}
+	if len(ports) != len(want) {
+		t.Fatalf("AllocatePortRange(3) = %v, want %v", ports, want)
+	}
+	for i, port := range ports {
+		if port != want[i] {
+			t.Fatalf("AllocatePortRange(3) = %v, want %v", ports, want)
+		}
+	}
+}
+
+func TestHostPortManagerReleasePortsUntracksThem(t *testing.T) {
+	m := NewHostPortManager(30000)
+
+	ports, err := m.AllocatePortRange(2)
+	if err != nil {
+		t.Fatalf("AllocatePortRange(2) error = %v", err)
+	}
+
+	m.ReleasePorts(ports)
+
+	if len(m.AllocatedPorts()) != 0 {
+		t.Fatalf("AllocatedPorts() = %v, want none after releasing every allocated port", m.AllocatedPorts())
+	}
+}
+
+func TestHostPortManagerSyncFromClustersTracksExistingPorts(t *testing.T) {
+	m := NewHostPortManager(30000)
+
+	virtualClusters := []v1alpha1.VirtualCluster{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "vc1"},
+			Status:     v1alpha1.VirtualClusterStatus{Port: 30005},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "vc2"},
+			Status:     v1alpha1.VirtualClusterStatus{PortMap: map[string]int32{"apiserver": 30006}},
+		},
+	}
+
+	if err := m.SyncFromClusters(context.TODO(), virtualClusters); err != nil {
+		t.Fatalf("SyncFromClusters() error = %v", err)
+	}
+
+	got := m.AllocatedPorts()
+	want := []int32{30005, 30006}
+	if len(got) != len(want) {
+		t.Fatalf("AllocatedPorts() = %v, want %v", got, want)
+	}
+	for i, port := range got {
+		if port != want[i] {
+			t.Fatalf("AllocatedPorts() = %v, want %v", got, want)
+		}
+	}
+}
@@ -10,7 +10,9 @@ import (
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	flowcontrolv1beta3 "k8s.io/api/flowcontrol/v1beta3"
 	rbacv1 "k8s.io/api/rbac/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
@@ -269,6 +271,98 @@ func CreateOrUpdateClusterRoleBinding(client clientset.Interface, clusterroleBin
 	return nil
 }
 
+func CreateOrUpdateResourceQuota(client clientset.Interface, quota *v1.ResourceQuota) error {
+	_, err := client.CoreV1().ResourceQuotas(quota.GetNamespace()).Create(context.TODO(), quota, metav1.CreateOptions{})
+	if err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+
+		older, err := client.CoreV1().ResourceQuotas(quota.GetNamespace()).Get(context.TODO(), quota.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		quota.ResourceVersion = older.ResourceVersion
+		_, err = client.CoreV1().ResourceQuotas(quota.GetNamespace()).Update(context.TODO(), quota, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+	}
+
+	klog.V(4).InfoS("Successfully created or updated resourcequota", "resourcequota", quota.GetName(), "namespace", quota.GetNamespace())
+	return nil
+}
+
+func CreateOrUpdateStorageClass(client clientset.Interface, storageClass *storagev1.StorageClass) error {
+	_, err := client.StorageV1().StorageClasses().Create(context.TODO(), storageClass, metav1.CreateOptions{})
+	if err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+
+		older, err := client.StorageV1().StorageClasses().Get(context.TODO(), storageClass.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		storageClass.ResourceVersion = older.ResourceVersion
+		_, err = client.StorageV1().StorageClasses().Update(context.TODO(), storageClass, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+	}
+
+	klog.V(4).InfoS("Successfully created or updated storageclass", "storageclass", storageClass.GetName())
+	return nil
+}
+
+func CreateOrUpdatePriorityLevelConfiguration(client clientset.Interface, plc *flowcontrolv1beta3.PriorityLevelConfiguration) error {
+	_, err := client.FlowcontrolV1beta3().PriorityLevelConfigurations().Create(context.TODO(), plc, metav1.CreateOptions{})
+	if err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+
+		older, err := client.FlowcontrolV1beta3().PriorityLevelConfigurations().Get(context.TODO(), plc.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		plc.ResourceVersion = older.ResourceVersion
+		_, err = client.FlowcontrolV1beta3().PriorityLevelConfigurations().Update(context.TODO(), plc, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+	}
+
+	klog.V(4).InfoS("Successfully created or updated prioritylevelconfiguration", "prioritylevelconfiguration", plc.GetName())
+	return nil
+}
+
+func CreateOrUpdateFlowSchema(client clientset.Interface, flowSchema *flowcontrolv1beta3.FlowSchema) error {
+	_, err := client.FlowcontrolV1beta3().FlowSchemas().Create(context.TODO(), flowSchema, metav1.CreateOptions{})
+	if err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+
+		older, err := client.FlowcontrolV1beta3().FlowSchemas().Get(context.TODO(), flowSchema.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		flowSchema.ResourceVersion = older.ResourceVersion
+		_, err = client.FlowcontrolV1beta3().FlowSchemas().Update(context.TODO(), flowSchema, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+	}
+
+	klog.V(4).InfoS("Successfully created or updated flowschema", "flowschema", flowSchema.GetName())
+	return nil
+}
+
 func CreateObject(dynamicClient dynamic.Interface, namespace string, name string, obj *unstructured.Unstructured) error {
 	gvk := obj.GroupVersionKind()
 	gvr, _ := meta.UnsafeGuessKindToResource(gvk)
@@ -482,3 +576,36 @@ func ReplaceObject(dynamicClient dynamic.Interface, obj *unstructured.Unstructur
 	klog.V(2).Infof("Replaced %s %s in namespace %s", gvr.String(), name, namespace)
 	return nil
 }
+
+// MergeResourceLabelsAndAnnotations adds resourceLabels/resourceAnnotations
+// (typically VirtualClusterSpec.ResourceLabels/ResourceAnnotations) onto obj,
+// without overwriting any key obj already carries -- kosmos-managed labels
+// such as virtualCluster-app always win, so teardown selection by those
+// labels keeps working regardless of what an operator configured. obj is
+// given new maps, so a map it shared with other objects is left unchanged.
+func MergeResourceLabelsAndAnnotations(obj metav1.Object, resourceLabels, resourceAnnotations map[string]string) {
+	if len(resourceLabels) > 0 {
+		merged := make(map[string]string, len(obj.GetLabels())+len(resourceLabels))
+		for k, v := range obj.GetLabels() {
+			merged[k] = v
+		}
+		for k, v := range resourceLabels {
+			if _, exists := merged[k]; !exists {
+				merged[k] = v
+			}
+		}
+		obj.SetLabels(merged)
+	}
+	if len(resourceAnnotations) > 0 {
+		merged := make(map[string]string, len(obj.GetAnnotations())+len(resourceAnnotations))
+		for k, v := range obj.GetAnnotations() {
+			merged[k] = v
+		}
+		for k, v := range resourceAnnotations {
+			if _, exists := merged[k]; !exists {
+				merged[k] = v
+			}
+		}
+		obj.SetAnnotations(merged)
+	}
+}
@@ -0,0 +1,134 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+)
+
+// ErrNotEnoughHostPorts is returned by HostPortManager.AllocatePortRange when
+// the pool does not have enough free ports left to satisfy the request.
+var ErrNotEnoughHostPorts = fmt.Errorf("not enough free host ports to satisfy request")
+
+// HostPortAllocator is the subset of *HostPortManager that
+// VirtualClusterInitController depends on. It exists so controller unit
+// tests can substitute a fake allocator instead of provisioning a real
+// port pool.
+type HostPortAllocator interface {
+	AllocatePortRange(count int) ([]int32, error)
+	ReleasePorts(ports []int32)
+	SyncFromClusters(ctx context.Context, virtualClusters []v1alpha1.VirtualCluster) error
+}
+
+var _ HostPortAllocator = &HostPortManager{}
+
+// HostPortManager tracks which ports out of a fixed pool are currently handed
+// out, so callers that need several ports at once (apiserver, etcd peer, etcd
+// client, konnectivity, ...) can reserve them as a single atomic batch instead
+// of racing each other one port at a time.
+type HostPortManager struct {
+	mu        sync.Mutex
+	free      map[int32]bool
+	allocated map[int32]bool
+}
+
+// NewHostPortManager builds a HostPortManager whose pool is the given ports,
+// all initially free.
+func NewHostPortManager(pool []int32) *HostPortManager {
+	free := make(map[int32]bool, len(pool))
+	for _, port := range pool {
+		free[port] = true
+	}
+	return &HostPortManager{
+		free:      free,
+		allocated: make(map[int32]bool, len(pool)),
+	}
+}
+
+// AllocatePortRange atomically reserves count free ports from the pool. If
+// the pool cannot satisfy the request, no ports are reserved and
+// ErrNotEnoughHostPorts is returned. The returned ports are sorted for
+// deterministic, easy-to-read allocation results.
+func (m *HostPortManager) AllocatePortRange(count int) ([]int32, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if count <= 0 {
+		return nil, fmt.Errorf("count must be positive, got %d", count)
+	}
+	if len(m.free) < count {
+		return nil, fmt.Errorf("%w: need %d, have %d", ErrNotEnoughHostPorts, count, len(m.free))
+	}
+
+	ports := make([]int32, 0, count)
+	for port := range m.free {
+		ports = append(ports, port)
+		if len(ports) == count {
+			break
+		}
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+
+	for _, port := range ports {
+		delete(m.free, port)
+		m.allocated[port] = true
+	}
+
+	return ports, nil
+}
+
+// SyncFromClusters reconstructs the allocation table from the ports already
+// in use by virtualClusters, so a HostPortManager rebuilt after a controller
+// restart doesn't hand out ports a still-running VirtualCluster already
+// holds. It should be called once during controller setup, before the
+// manager serves any AllocatePortRange calls. Only pool ports are affected;
+// a VirtualCluster port outside the pool is ignored. Syncing a port that is
+// already marked allocated is a no-op, so SyncFromClusters is safe to call
+// more than once.
+func (m *HostPortManager) SyncFromClusters(ctx context.Context, virtualClusters []v1alpha1.VirtualCluster) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, vc := range virtualClusters {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		claimed := make([]int32, 0, len(vc.Status.PortMap)+1)
+		if vc.Status.Port != 0 {
+			claimed = append(claimed, vc.Status.Port)
+		}
+		for _, port := range vc.Status.PortMap {
+			claimed = append(claimed, port)
+		}
+
+		for _, port := range claimed {
+			if !m.free[port] {
+				continue
+			}
+			delete(m.free, port)
+			m.allocated[port] = true
+		}
+	}
+
+	return nil
+}
+
+// ReleasePorts returns previously allocated ports to the free pool, so the
+// init controller can give them back on cluster deletion. Releasing a port
+// that was never allocated is a no-op.
+func (m *HostPortManager) ReleasePorts(ports []int32) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, port := range ports {
+		if !m.allocated[port] {
+			continue
+		}
+		delete(m.allocated, port)
+		m.free[port] = true
+	}
+}
@@ -0,0 +1,146 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+)
+
+func TestHostPortManagerAllocatePortRangeRollsBackOnShortage(t *testing.T) {
+	m := NewHostPortManager([]int32{30000, 30001, 30002})
+
+	if _, err := m.AllocatePortRange(4); !errors.Is(err, ErrNotEnoughHostPorts) {
+		t.Fatalf("AllocatePortRange(4) error = %v, want ErrNotEnoughHostPorts", err)
+	}
+
+	ports, err := m.AllocatePortRange(3)
+	if err != nil {
+		t.Fatalf("AllocatePortRange(3) error = %v, want nil after a failed over-request", err)
+	}
+	if len(ports) != 3 {
+		t.Fatalf("AllocatePortRange(3) = %v, want all 3 pool ports", ports)
+	}
+}
+
+func TestHostPortManagerReleasePortsReturnsThemToThePool(t *testing.T) {
+	m := NewHostPortManager([]int32{30000, 30001})
+
+	ports, err := m.AllocatePortRange(2)
+	if err != nil {
+		t.Fatalf("AllocatePortRange(2) error = %v", err)
+	}
+
+	m.ReleasePorts(ports)
+
+	again, err := m.AllocatePortRange(2)
+	if err != nil {
+		t.Fatalf("AllocatePortRange(2) after release error = %v", err)
+	}
+	if len(again) != 2 {
+		t.Fatalf("AllocatePortRange(2) after release = %v, want 2 ports", again)
+	}
+}
+
+func TestHostPortManagerSyncFromClustersReservesExistingPortsOnly(t *testing.T) {
+	m := NewHostPortManager([]int32{30000, 30001, 30002})
+
+	virtualClusters := []v1alpha1.VirtualCluster{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "vc1"},
+			Status:     v1alpha1.VirtualClusterStatus{Port: 30000},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "vc2"},
+			Status: v1alpha1.VirtualClusterStatus{
+				PortMap: map[string]int32{"apiserver": 30001, "outside-pool": 40000},
+			},
+		},
+	}
+
+	if err := m.SyncFromClusters(context.TODO(), virtualClusters); err != nil {
+		t.Fatalf("SyncFromClusters() error = %v", err)
+	}
+
+	ports, err := m.AllocatePortRange(1)
+	if err != nil {
+		t.Fatalf("AllocatePortRange(1) error = %v", err)
+	}
+	if ports[0] != 30002 {
+		t.Fatalf("AllocatePortRange(1) = %v, want the only port SyncFromClusters left free (30002)", ports)
+	}
+
+	if _, err := m.AllocatePortRange(1); !errors.Is(err, ErrNotEnoughHostPorts) {
+		t.Fatalf("AllocatePortRange(1) error = %v, want ErrNotEnoughHostPorts once the pool is exhausted", err)
+	}
+}
+
+func TestHostPortManagerSyncFromClustersIsIdempotent(t *testing.T) {
+	m := NewHostPortManager([]int32{30000, 30001})
+	virtualClusters := []v1alpha1.VirtualCluster{
+		{Status: v1alpha1.VirtualClusterStatus{Port: 30000}},
+	}
+
+	if err := m.SyncFromClusters(context.TODO(), virtualClusters); err != nil {
+		t.Fatalf("SyncFromClusters() error = %v", err)
+	}
+	if err := m.SyncFromClusters(context.TODO(), virtualClusters); err != nil {
+		t.Fatalf("second SyncFromClusters() error = %v", err)
+	}
+
+	ports, err := m.AllocatePortRange(1)
+	if err != nil {
+		t.Fatalf("AllocatePortRange(1) error = %v", err)
+	}
+	if ports[0] != 30001 {
+		t.Fatalf("AllocatePortRange(1) = %v, want 30001", ports)
+	}
+}
+
+// TestHostPortManagerConcurrentAllocationNeverDoublesAPort spins up many
+// goroutines racing to allocate batches from a small pool and asserts that no
+// two successful allocations ever overlap on the same port.
+func TestHostPortManagerConcurrentAllocationNeverDoublesAPort(t *testing.T) {
+	const poolSize = 40
+	const batchSize = 4
+	const workers = 20
+
+	pool := make([]int32, poolSize)
+	for i := range pool {
+		pool[i] = int32(30000 + i)
+	}
+	m := NewHostPortManager(pool)
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		claimed = make(map[int32]int)
+	)
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ports, err := m.AllocatePortRange(batchSize)
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for _, port := range ports {
+				claimed[port]++
+			}
+		}()
+	}
+	wg.Wait()
+
+	for port, count := range claimed {
+		if count > 1 {
+			t.Errorf("port %d was allocated %d times concurrently, want at most once", port, count)
+		}
+	}
+}
@@ -1,8 +1,11 @@
 package util
 
 import (
+	"fmt"
 	"os"
+	"regexp"
 
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
 	"github.com/kosmos.io/kosmos/pkg/kubenest/constants"
 	"github.com/kosmos.io/kosmos/pkg/utils"
 )
@@ -19,6 +22,58 @@ func GetImageMessage() (imageRepository string, imageVersion string) {
 	return imageRepository, imageVersion
 }
 
+// registryReferencePattern is a permissive check for a registry/repository
+// reference: one or more '/'-separated path segments of
+// alphanumerics/.-_, optionally followed by a port on the first segment.
+// It deliberately doesn't validate against the full, much stricter docker
+// reference grammar, since it only needs to catch obviously-wrong input
+// (whitespace, empty segments) in ImageRepository before it reaches a
+// manifest template.
+var registryReferencePattern = regexp.MustCompile(`^[a-zA-Z0-9]+([.-][a-zA-Z0-9]+)*(:[0-9]+)?(/[a-zA-Z0-9]+([._-][a-zA-Z0-9]+)*)*$`)
+
+// ValidateImageRepository rejects an ImageRepository that isn't a plausible
+// registry/repository reference, so a typo is caught at admission time
+// instead of surfacing as an ImagePullBackOff once the control plane is
+// already being provisioned.
+func ValidateImageRepository(repository string) error {
+	if repository == "" {
+		return nil
+	}
+	if !registryReferencePattern.MatchString(repository) {
+		return fmt.Errorf("imageRepository %q is not a valid registry reference", repository)
+	}
+	return nil
+}
+
+// ResolveImageRepository returns the container registry to use for vc's
+// control-plane component images: vc.Spec.ImageRepository if set, otherwise
+// the operator-wide default from GetImageMessage.
+func ResolveImageRepository(vc *v1alpha1.VirtualCluster) string {
+	if vc != nil && vc.Spec.ImageRepository != "" {
+		return vc.Spec.ImageRepository
+	}
+	repository, _ := GetImageMessage()
+	return repository
+}
+
+// ResolveComponentImage returns the image reference to render into a
+// component's manifest template. A vc.Spec.ComponentImageOverrides entry
+// keyed by component is already a fully-qualified image and is returned
+// verbatim; otherwise the image is built from ResolveImageRepository,
+// imageName, and defaultVersion, matching the
+// {{ .ImageRepository }}/imageName:{{ .Version }} shape the manifest
+// templates already use. component identifies the override (e.g.
+// constants.APIServer) while imageName is the actual image on the registry
+// (e.g. "kube-apiserver"); the two differ for some components.
+func ResolveComponentImage(vc *v1alpha1.VirtualCluster, component, imageName, defaultVersion string) string {
+	if vc != nil {
+		if override, ok := vc.Spec.ComponentImageOverrides[component]; ok && override != "" {
+			return override
+		}
+	}
+	return fmt.Sprintf("%s/%s:%s", ResolveImageRepository(vc), imageName, defaultVersion)
+}
+
 func GetCoreDNSImageTag() string {
 	coreDNSImageTag := os.Getenv(constants.DefaultCoreDNSImageTagEnv)
 	if coreDNSImageTag == "" {
@@ -0,0 +1,57 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+)
+
+func TestValidateImageRepository(t *testing.T) {
+	tests := []struct {
+		name       string
+		repository string
+		wantErr    bool
+	}{
+		{name: "empty is allowed", repository: ""},
+		{name: "plain repository", repository: "ghcr.io/kosmos-io"},
+		{name: "repository with port", repository: "registry.internal:5000/kosmos-io"},
+		{name: "repository with whitespace", repository: "ghcr.io/kosmos io", wantErr: true},
+		{name: "repository with a scheme", repository: "https://ghcr.io/kosmos-io", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateImageRepository(tt.repository)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateImageRepository(%q) error = %v, wantErr %v", tt.repository, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResolveImageRepository(t *testing.T) {
+	defaultRepository, _ := GetImageMessage()
+
+	if got := ResolveImageRepository(nil); got != defaultRepository {
+		t.Errorf("ResolveImageRepository(nil) = %q, want default %q", got, defaultRepository)
+	}
+
+	vc := &v1alpha1.VirtualCluster{Spec: v1alpha1.VirtualClusterSpec{ImageRepository: "my.registry.io/mirror"}}
+	if got := ResolveImageRepository(vc); got != "my.registry.io/mirror" {
+		t.Errorf("ResolveImageRepository() = %q, want the VirtualCluster override", got)
+	}
+}
+
+func TestResolveComponentImage(t *testing.T) {
+	vc := &v1alpha1.VirtualCluster{Spec: v1alpha1.VirtualClusterSpec{
+		ImageRepository:         "my.registry.io/mirror",
+		ComponentImageOverrides: map[string]string{"etcd": "my.registry.io/special/etcd:v3.5.9"},
+	}}
+
+	if got := ResolveComponentImage(vc, "kube-apiserver", "kube-apiserver", "v1.26.3"); got != "my.registry.io/mirror/kube-apiserver:v1.26.3" {
+		t.Errorf("ResolveComponentImage() = %q, want the ImageRepository substituted in", got)
+	}
+
+	if got := ResolveComponentImage(vc, "etcd", "etcd", "v1.26.3"); got != "my.registry.io/special/etcd:v3.5.9" {
+		t.Errorf("ResolveComponentImage() = %q, want the fully-qualified ComponentImageOverrides entry used verbatim", got)
+	}
+}
@@ -15,6 +15,17 @@ func CreateWithCerts(serverURL, clusterName, userName string, caCert []byte, cli
 	return config
 }
 
+// CreateWithToken builds a kubeconfig authenticating with a bearer token
+// instead of a client certificate, for use with short-lived tokens minted
+// via TokenRequest.
+func CreateWithToken(serverURL, clusterName, userName string, caCert []byte, token string) *clientcmdapi.Config {
+	config := CreateBasic(serverURL, clusterName, userName, caCert)
+	config.AuthInfos[userName] = &clientcmdapi.AuthInfo{
+		Token: token,
+	}
+	return config
+}
+
 func CreateBasic(serverURL, clusterName, userName string, caCert []byte) *clientcmdapi.Config {
 	// Use the cluster and the username as the context name
 	contextName := fmt.Sprintf("%s@%s", userName, clusterName)
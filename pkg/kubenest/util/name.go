@@ -41,3 +41,22 @@ func GetAdminConfigSecretName(name string) string {
 func GetAdminConfigClusterIPSecretName(name string) string {
 	return fmt.Sprintf("%s-%s", name, "admin-config-clusterip")
 }
+
+func GetAdminConfigExternalSecretName(name string) string {
+	return fmt.Sprintf("%s-%s", name, "admin-config-external")
+}
+
+// GetAdditionalKubeconfigSecretName returns the secret name for one of a
+// VirtualCluster's Spec.AdditionalKubeconfigs entries, e.g. "vc1-view-config"
+// for a VirtualCluster named "vc1" with an entry named "view".
+func GetAdditionalKubeconfigSecretName(name, entryName string) string {
+	return fmt.Sprintf("%s-%s-%s", name, entryName, "config")
+}
+
+func GetControlPlaneResourceQuotaName(name string) string {
+	return fmt.Sprintf("%s-%s", name, "control-plane-quota")
+}
+
+func GetPodSecurityAdmissionConfigMapName(name string) string {
+	return fmt.Sprintf("%s-%s", name, "pod-security-admission")
+}
@@ -1,19 +1,34 @@
 package util
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"math/big"
 	"net"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
+	"time"
 
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
 	"k8s.io/client-go/kubernetes"
 
 	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/constants"
 	"github.com/kosmos.io/kosmos/pkg/utils"
 )
 
+// ErrPromotePolicyUnsatisfiable marks a PromotePolicy.NodeCount that can never
+// be satisfied, as opposed to a transient shortage of currently-free nodes.
+var ErrPromotePolicyUnsatisfiable = errors.New("promote policy node count can never be satisfied")
+
 func FindGlobalNode(nodeName string, globalNodes []v1alpha1.GlobalNode) (*v1alpha1.GlobalNode, bool) {
 	for _, globalNode := range globalNodes {
 		if globalNode.Name == nodeName {
@@ -83,6 +98,543 @@ func GetFirstIP(ipNetStrs string) ([]net.IP, error) {
 	return ips, nil
 }
 
+// ValidateClusterDNS checks that clusterDNS is a valid IP address that falls
+// within serviceSubnet, mirroring the way kube-apiserver's --service-cluster-ip-range
+// constrains the CoreDNS service IP.
+func ValidateClusterDNS(clusterDNS, serviceSubnet string) error {
+	ip := net.ParseIP(clusterDNS)
+	if ip == nil {
+		return fmt.Errorf("clusterDNS %q is not a valid IP address", clusterDNS)
+	}
+
+	for _, cidr := range strings.Split(serviceSubnet, ",") {
+		_, ipNet, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return fmt.Errorf("parse service subnet %q failed: %s", cidr, err)
+		}
+		if ipNet.Contains(ip) {
+			return nil
+		}
+	}
+	return fmt.Errorf("clusterDNS %q is not within service subnet %q", clusterDNS, serviceSubnet)
+}
+
+// ValidateServiceSubnet rejects a --service-cluster-ip-range value that
+// kube-apiserver would itself reject: each comma-separated entry must be a
+// valid CIDR, there may be at most one of each IP family (kube-apiserver's
+// own dual-stack limit), and two entries of the same family - a
+// misconfigured "single-stack written twice" - are rejected rather than
+// silently taking the first one.
+func ValidateServiceSubnet(serviceSubnet string) error {
+	cidrs := strings.Split(serviceSubnet, ",")
+	if len(cidrs) > 2 {
+		return fmt.Errorf("service subnet %q has %d ranges, at most 2 (one per IP family) are supported", serviceSubnet, len(cidrs))
+	}
+
+	seenFamily := make(map[bool]string, len(cidrs))
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		_, _, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return fmt.Errorf("parse service subnet %q failed: %s", cidr, err)
+		}
+		isIPv6 := utils.IsIPv6(cidr)
+		if existing, ok := seenFamily[isIPv6]; ok {
+			return fmt.Errorf("service subnet %q has two ranges of the same IP family (%q and %q); dual-stack requires one IPv4 and one IPv6 range", serviceSubnet, existing, cidr)
+		}
+		seenFamily[isIPv6] = cidr
+	}
+	return nil
+}
+
+// ValidateNonNegativeDuration parses value as a time.Duration and rejects
+// negative durations, mirroring the constraint kube-apiserver places on its
+// own --shutdown-delay-duration and --shutdown-grace-period flags. An empty
+// value is treated as unset and considered valid.
+func ValidateNonNegativeDuration(name, value string) error {
+	if value == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("%s %q is not a valid duration: %s", name, value, err)
+	}
+	if d < 0 {
+		return fmt.Errorf("%s %q must not be negative", name, value)
+	}
+	return nil
+}
+
+// ValidatePromotePolicyNodeCount rejects a PromotePolicy whose NodeCount
+// exceeds matchedNodeCount, the total number of GlobalNodes matching its
+// LabelSelector and Tolerations regardless of their current State. Unlike a
+// shortage of currently-free nodes, this case can never be satisfied by
+// nodes freeing up later, so it wraps ErrPromotePolicyUnsatisfiable.
+func ValidatePromotePolicyNodeCount(policy v1alpha1.PromotePolicy, matchedNodeCount int) error {
+	if int(policy.NodeCount) > matchedNodeCount {
+		return fmt.Errorf("%w: policy %v requests %d nodes but only %d nodes could ever match it", ErrPromotePolicyUnsatisfiable, policy.LabelSelector, policy.NodeCount, matchedNodeCount)
+	}
+	return nil
+}
+
+// ValidateExternalHostName rejects an externalHostName that isn't a valid
+// DNS hostname, so a typo surfaces before it's baked into a cert SAN and the
+// external admin kubeconfig. An empty value is treated as unset and
+// considered valid.
+func ValidateExternalHostName(hostname string) error {
+	if hostname == "" {
+		return nil
+	}
+	if errs := validation.IsDNS1123Subdomain(hostname); len(errs) > 0 {
+		return fmt.Errorf("externalHostName %q is not a valid hostname: %s", hostname, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// ValidateExternalIPs rejects any entry of ips that isn't a valid IP address
+// or DNS hostname, so a typo surfaces before it's baked into a cert SAN
+// instead of silently producing a serving cert that doesn't cover the
+// intended address.
+func ValidateExternalIPs(ips []string) error {
+	for _, ip := range ips {
+		if net.ParseIP(ip) != nil {
+			continue
+		}
+		if errs := validation.IsDNS1123Subdomain(ip); len(errs) > 0 {
+			return fmt.Errorf("externalIps entry %q is not a valid IP address or hostname: %s", ip, strings.Join(errs, ", "))
+		}
+	}
+	return nil
+}
+
+// AllowedStorageMediaTypes are the storage media types accepted by the
+// apiserver's --storage-media-type flag.
+var AllowedStorageMediaTypes = []string{"application/json", "application/vnd.kubernetes.protobuf"}
+
+func ValidateStorageMediaType(value string) error {
+	if value == "" {
+		return nil
+	}
+	for _, allowed := range AllowedStorageMediaTypes {
+		if value == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("storageMediaType %q is not one of %v", value, AllowedStorageMediaTypes)
+}
+
+// AllowedContentTypes are the request/response content types accepted for
+// KubeInKubeConfig.ContentType, matching the mime types client-go's
+// rest.Config recognizes for ContentType/AcceptContentTypes negotiation.
+var AllowedContentTypes = []string{"application/json", "application/vnd.kubernetes.protobuf"}
+
+// ValidateContentType rejects a ContentType that isn't one of
+// AllowedContentTypes. An empty value is always valid; it means client-go's
+// own default (JSON) is used.
+func ValidateContentType(value string) error {
+	if value == "" {
+		return nil
+	}
+	for _, allowed := range AllowedContentTypes {
+		if value == allowed {
+			return nil
+		}
+	}
+	return fmt.Errorf("contentType %q is not one of %v", value, AllowedContentTypes)
+}
+
+// ValidateCORSAllowedOrigins rejects a CORSAllowedOrigins list containing an
+// entry that doesn't compile as a regular expression, mirroring the
+// constraint kube-apiserver places on its own --cors-allowed-origins flag. An
+// empty list is treated as unset and considered valid.
+func ValidateCORSAllowedOrigins(origins []string) error {
+	for _, origin := range origins {
+		if _, err := regexp.Compile(origin); err != nil {
+			return fmt.Errorf("corsAllowedOrigins entry %q is not a valid regular expression: %s", origin, err)
+		}
+	}
+	return nil
+}
+
+// RecognizedKubeletExtraArgs are the kubelet flags (without the leading
+// "--") GlobalNodeSpec.KubeletExtraArgs is allowed to set, limited to
+// capacity-management flags this codebase expects operators to need.
+var RecognizedKubeletExtraArgs = map[string]bool{
+	"max-pods":                true,
+	"system-reserved":         true,
+	"kube-reserved":           true,
+	"eviction-hard":           true,
+	"pod-max-pids":            true,
+	"image-gc-high-threshold": true,
+	"image-gc-low-threshold":  true,
+}
+
+// ValidateKubeletExtraArgs rejects a KubeletExtraArgs map containing a key
+// that isn't one of RecognizedKubeletExtraArgs, so a typo'd or unsupported
+// flag fails fast instead of being silently passed through to kubelet on
+// join. A nil/empty map is always valid.
+func ValidateKubeletExtraArgs(args map[string]string) error {
+	for flag := range args {
+		if !RecognizedKubeletExtraArgs[flag] {
+			return fmt.Errorf("kubeletExtraArgs flag %q is not recognized", flag)
+		}
+	}
+	return nil
+}
+
+// DisallowedAPIServerExtraArgs are the apiserver flags (without the leading
+// "--") ControlPlaneConfig.APIServerExtraArgs is not allowed to set, since
+// kosmos relies on its own values for these to provision the control plane
+// at all; overriding them wouldn't just be ineffective, it would break
+// provisioning.
+var DisallowedAPIServerExtraArgs = map[string]bool{
+	"etcd-servers":                     true,
+	"etcd-cafile":                      true,
+	"etcd-certfile":                    true,
+	"etcd-keyfile":                     true,
+	"secure-port":                      true,
+	"bind-address":                     true,
+	"advertise-address":                true,
+	"client-ca-file":                   true,
+	"tls-cert-file":                    true,
+	"tls-private-key-file":             true,
+	"service-account-key-file":         true,
+	"service-account-signing-key-file": true,
+	"service-cluster-ip-range":         true,
+}
+
+// DisallowedControllerManagerExtraArgs are the kube-controller-manager
+// flags ControlPlaneConfig.ControllerManagerExtraArgs is not allowed to set,
+// for the same reason as DisallowedAPIServerExtraArgs.
+var DisallowedControllerManagerExtraArgs = map[string]bool{
+	"kubeconfig":                       true,
+	"authentication-kubeconfig":        true,
+	"authorization-kubeconfig":         true,
+	"cluster-cidr":                     true,
+	"service-cluster-ip-range":         true,
+	"root-ca-file":                     true,
+	"cluster-signing-cert-file":        true,
+	"cluster-signing-key-file":         true,
+	"service-account-private-key-file": true,
+}
+
+// DisallowedSchedulerExtraArgs are the virtualcluster-scheduler flags
+// ControlPlaneConfig.SchedulerExtraArgs is not allowed to set, for the same
+// reason as DisallowedAPIServerExtraArgs.
+var DisallowedSchedulerExtraArgs = map[string]bool{
+	"kubeconfig":                true,
+	"config":                    true,
+	"authentication-kubeconfig": true,
+	"authorization-kubeconfig":  true,
+}
+
+// ValidateExtraArgs rejects an extra-args map containing a key in
+// disallowed, identifying component in the error so a VirtualCluster
+// setting APIServerExtraArgs, ControllerManagerExtraArgs and
+// SchedulerExtraArgs all get an error that points at the right one. A
+// nil/empty map is always valid.
+func ValidateExtraArgs(component string, args map[string]string, disallowed map[string]bool) error {
+	for flag := range args {
+		if disallowed[flag] {
+			return fmt.Errorf("%s extra arg %q is not allowed: kosmos sets it to provision the control plane", component, flag)
+		}
+	}
+	return nil
+}
+
+// MergeExtraArgsIntoCommand appends extraArgs to command as "--key=value"
+// flags, in key-sorted order for deterministic output, skipping any key
+// already set by command (matched as a "--key" or "--key=" prefix) so the
+// flags kosmos itself sets to provision the control plane always take
+// precedence over a conflicting entry in extraArgs.
+func MergeExtraArgsIntoCommand(command []string, extraArgs map[string]string) []string {
+	if len(extraArgs) == 0 {
+		return command
+	}
+	keys := make([]string, 0, len(extraArgs))
+	for key := range extraArgs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if commandSetsFlag(command, key) {
+			continue
+		}
+		command = append(command, fmt.Sprintf("--%s=%s", key, extraArgs[key]))
+	}
+	return command
+}
+
+// commandSetsFlag reports whether command already contains an entry for
+// flag, either as exactly "--flag" or as "--flag=<value>".
+func commandSetsFlag(command []string, flag string) bool {
+	prefix := "--" + flag
+	for _, arg := range command {
+		if arg == prefix || strings.HasPrefix(arg, prefix+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidatePositiveEtcdTuningValue validates an optional etcd tuning value
+// (e.g. --snapshot-count, --auto-compaction-retention) that must be a
+// positive integer when the caller has opted in to setting it; 0 means "use
+// etcd's default" and is always valid.
+func ValidatePositiveEtcdTuningValue(flag string, value int64) error {
+	if value < 0 {
+		return fmt.Errorf("%s must be a positive integer, got %d", flag, value)
+	}
+	return nil
+}
+
+// ValidateEtcdReplicas validates a per-VirtualCluster EtcdReplicas override.
+// Etcd needs a majority of its members to make progress, so the replica
+// count must be odd and at least 1; 0 means "use the default" and is always
+// valid.
+func ValidateEtcdReplicas(replicas int) error {
+	if replicas == 0 {
+		return nil
+	}
+	if replicas < 1 || replicas%2 == 0 {
+		return fmt.Errorf("controlPlaneConfig.etcdReplicas must be an odd number >= 1, got %d", replicas)
+	}
+	return nil
+}
+
+// ValidateEtcdStorageSize validates a per-VirtualCluster etcd PVC storage
+// size override (Spec.Etcd.StorageSize), which must parse as a
+// resource.Quantity and be greater than zero; an empty size means "use the
+// default" and is always valid.
+func ValidateEtcdStorageSize(size string) error {
+	if size == "" {
+		return nil
+	}
+	quantity, err := resource.ParseQuantity(size)
+	if err != nil {
+		return fmt.Errorf("etcd.storageSize %q is not a valid resource quantity: %w", size, err)
+	}
+	if quantity.Value() <= 0 {
+		return fmt.Errorf("etcd.storageSize must be greater than zero, got %q", size)
+	}
+	return nil
+}
+
+// ResolveReplicas returns override when the per-VirtualCluster
+// ControlPlaneConfig has opted in to it (i.e. override > 0), otherwise it
+// falls back to the operator's default.
+func ResolveReplicas(override, fallback int) int32 {
+	if override > 0 {
+		return int32(override)
+	}
+	return int32(fallback)
+}
+
+// AuditWebhookConfigMapKey is the ConfigMap data key an AuditWebhookConfig's
+// referenced ConfigMap is expected to hold the webhook kubeconfig file under.
+const AuditWebhookConfigMapKey = "webhook-config.yaml"
+
+// ValidateAuditConfig rejects an AuditConfig whose Backend doesn't match the
+// one of Log/Webhook that's actually set, or whose selected backend is
+// missing required fields. A nil audit config is always valid; it leaves
+// audit logging off.
+func ValidateAuditConfig(audit *v1alpha1.AuditConfig) error {
+	if audit == nil {
+		return nil
+	}
+	switch audit.Backend {
+	case v1alpha1.AuditLogBackend:
+		if audit.Webhook != nil {
+			return fmt.Errorf("audit backend %q must not set webhook", audit.Backend)
+		}
+	case v1alpha1.AuditWebhookBackend:
+		if audit.Log != nil {
+			return fmt.Errorf("audit backend %q must not set log", audit.Backend)
+		}
+		if audit.Webhook == nil || audit.Webhook.ConfigMapName == "" {
+			return fmt.Errorf("audit backend %q requires webhook.configMapName", audit.Backend)
+		}
+	default:
+		return fmt.Errorf("audit backend must be %q or %q, got %q", v1alpha1.AuditLogBackend, v1alpha1.AuditWebhookBackend, audit.Backend)
+	}
+	return nil
+}
+
+// AuditTemplateData holds the apiserver Deployment template fields derived
+// from an AuditConfig. Its zero value has an empty AuditBackend, which
+// renders no audit flags or volumes, matching a nil AuditConfig.
+type AuditTemplateData struct {
+	AuditBackend               string
+	AuditLogPath               string
+	AuditLogDir                string
+	AuditLogMaxAge             int32
+	AuditLogMaxBackup          int32
+	AuditLogMaxSize            int32
+	AuditWebhookConfigMapName  string
+	AuditWebhookConfigMapKey   string
+	AuditWebhookInitialBackoff string
+}
+
+// BuildAuditTemplateData validates audit and derives the apiserver Deployment
+// template fields for whichever backend it selects. namespace and name are
+// the virtual cluster's, used to default the audit log's on-host path.
+func BuildAuditTemplateData(audit *v1alpha1.AuditConfig, namespace, name string) (AuditTemplateData, error) {
+	if err := ValidateAuditConfig(audit); err != nil {
+		return AuditTemplateData{}, err
+	}
+	if audit == nil {
+		return AuditTemplateData{}, nil
+	}
+
+	data := AuditTemplateData{AuditBackend: string(audit.Backend)}
+	switch audit.Backend {
+	case v1alpha1.AuditLogBackend:
+		path := fmt.Sprintf("/var/log/%s/%s/audit.log", namespace, name)
+		if audit.Log != nil && audit.Log.Path != "" {
+			path = audit.Log.Path
+		}
+		data.AuditLogPath = path
+		data.AuditLogDir = filepath.Dir(path)
+		if audit.Log != nil {
+			data.AuditLogMaxAge = audit.Log.MaxAge
+			data.AuditLogMaxBackup = audit.Log.MaxBackup
+			data.AuditLogMaxSize = audit.Log.MaxSize
+		}
+	case v1alpha1.AuditWebhookBackend:
+		data.AuditWebhookConfigMapName = audit.Webhook.ConfigMapName
+		data.AuditWebhookConfigMapKey = AuditWebhookConfigMapKey
+		data.AuditWebhookInitialBackoff = audit.Webhook.InitialBackoff
+	}
+	return data, nil
+}
+
+// ValidateWebhookKubeconfigConfig checks that cfg's Secret exists in
+// namespace and actually carries a kubeconfig, so a mistyped or
+// not-yet-created SecretName fails fast instead of wedging the apiserver in
+// a CrashLoopBackOff. A nil cfg is always valid.
+func ValidateWebhookKubeconfigConfig(client kubernetes.Interface, namespace string, cfg *v1alpha1.WebhookKubeconfigConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	if cfg.SecretName == "" {
+		return fmt.Errorf("webhook kubeconfig secretName must be set")
+	}
+	secret, err := client.CoreV1().Secrets(namespace).Get(context.TODO(), cfg.SecretName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("get webhook kubeconfig secret %s/%s: %w", namespace, cfg.SecretName, err)
+	}
+	if len(secret.Data[constants.KubeConfig]) == 0 {
+		return fmt.Errorf("webhook kubeconfig secret %s/%s missing data key %q", namespace, cfg.SecretName, constants.KubeConfig)
+	}
+	return nil
+}
+
+// WebhookTemplateData holds the apiserver Deployment template fields derived
+// from AuthenticationWebhook/AuthorizationWebhook. A zero-valued field means
+// that webhook isn't configured and renders no flag or volume for it.
+type WebhookTemplateData struct {
+	AuthenticationWebhookSecretName string
+	AuthorizationWebhookSecretName  string
+}
+
+// BuildWebhookTemplateData validates authenticationWebhook and
+// authorizationWebhook against client and derives the apiserver Deployment
+// template fields for whichever of them are set.
+func BuildWebhookTemplateData(client kubernetes.Interface, namespace string, authenticationWebhook, authorizationWebhook *v1alpha1.WebhookKubeconfigConfig) (WebhookTemplateData, error) {
+	if err := ValidateWebhookKubeconfigConfig(client, namespace, authenticationWebhook); err != nil {
+		return WebhookTemplateData{}, err
+	}
+	if err := ValidateWebhookKubeconfigConfig(client, namespace, authorizationWebhook); err != nil {
+		return WebhookTemplateData{}, err
+	}
+
+	var data WebhookTemplateData
+	if authenticationWebhook != nil {
+		data.AuthenticationWebhookSecretName = authenticationWebhook.SecretName
+	}
+	if authorizationWebhook != nil {
+		data.AuthorizationWebhookSecretName = authorizationWebhook.SecretName
+	}
+	return data, nil
+}
+
+// AllowedPodSecurityLevels are the only valid values for a
+// PodSecurityAdmissionConfig's Enforce/Audit/Warn fields, matching the Pod
+// Security Standards levels Kubernetes itself recognizes.
+var AllowedPodSecurityLevels = map[string]bool{
+	"privileged": true,
+	"baseline":   true,
+	"restricted": true,
+}
+
+// ValidatePodSecurityAdmissionConfig rejects a PodSecurityAdmissionConfig
+// whose Enforce/Audit/Warn levels aren't one of AllowedPodSecurityLevels. A
+// nil config is always valid; it leaves the apiserver's own PodSecurity
+// defaults in place.
+func ValidatePodSecurityAdmissionConfig(cfg *v1alpha1.PodSecurityAdmissionConfig) error {
+	if cfg == nil {
+		return nil
+	}
+	for flag, level := range map[string]string{"enforce": cfg.Enforce, "audit": cfg.Audit, "warn": cfg.Warn} {
+		if level != "" && !AllowedPodSecurityLevels[level] {
+			return fmt.Errorf("podSecurityAdmission.%s must be one of privileged, baseline, restricted, got %q", flag, level)
+		}
+	}
+	return nil
+}
+
+// PodSecurityAdmissionTemplateData holds the apiserver Deployment template
+// fields derived from a PodSecurityAdmissionConfig. A zero-valued Enabled
+// means no PodSecurityAdmissionConfig was set, renders no admission config
+// flag or volume, and leaves the apiserver's own defaults in place.
+type PodSecurityAdmissionTemplateData struct {
+	PodSecurityAdmissionEnabled        bool
+	PodSecurityAdmissionConfigMapName  string
+	PodSecurityAdmissionEnforce        string
+	PodSecurityAdmissionEnforceVersion string
+	PodSecurityAdmissionAudit          string
+	PodSecurityAdmissionAuditVersion   string
+	PodSecurityAdmissionWarn           string
+	PodSecurityAdmissionWarnVersion    string
+}
+
+// BuildPodSecurityAdmissionTemplateData validates cfg and derives the
+// apiserver Deployment template fields for it, defaulting each unset level to
+// "privileged" and each unset version to "latest", matching Kubernetes' own
+// PodSecurityConfiguration defaulting. name is the virtual cluster's, used to
+// derive the generated ConfigMap's name.
+func BuildPodSecurityAdmissionTemplateData(cfg *v1alpha1.PodSecurityAdmissionConfig, name string) (PodSecurityAdmissionTemplateData, error) {
+	if err := ValidatePodSecurityAdmissionConfig(cfg); err != nil {
+		return PodSecurityAdmissionTemplateData{}, err
+	}
+	if cfg == nil {
+		return PodSecurityAdmissionTemplateData{}, nil
+	}
+
+	level := func(v string) string {
+		if v == "" {
+			return "privileged"
+		}
+		return v
+	}
+	version := func(v string) string {
+		if v == "" {
+			return "latest"
+		}
+		return v
+	}
+	return PodSecurityAdmissionTemplateData{
+		PodSecurityAdmissionEnabled:        true,
+		PodSecurityAdmissionConfigMapName:  GetPodSecurityAdmissionConfigMapName(name),
+		PodSecurityAdmissionEnforce:        level(cfg.Enforce),
+		PodSecurityAdmissionEnforceVersion: version(cfg.EnforceVersion),
+		PodSecurityAdmissionAudit:          level(cfg.Audit),
+		PodSecurityAdmissionAuditVersion:   version(cfg.AuditVersion),
+		PodSecurityAdmissionWarn:           level(cfg.Warn),
+		PodSecurityAdmissionWarnVersion:    version(cfg.WarnVersion),
+	}, nil
+}
+
 func IPV6First(ipNetStr string) (bool, error) {
 	ipNetStrArray := strings.Split(ipNetStr, ",")
 	if len(ipNetStrArray) > 2 {
@@ -254,6 +806,84 @@ func IsIPInRange(ipStr string, ranges []string) (bool, error) {
 	return false, nil
 }
 
+// EtcdCompatibilityMatrix lists, for each apiserver version this codebase
+// bundles, the etcd versions it has been validated against. An apiserver
+// version with no entry accepts any etcd version, since it predates this
+// matrix being introduced.
+var EtcdCompatibilityMatrix = map[string][]string{
+	"v1.27.6": {"3.5.6", "3.5.7", "3.5.9"},
+	"v1.28.9": {"3.5.9", "3.5.10"},
+}
+
+// ValidateEtcdVersionCompatibility rejects an etcdVersion that isn't listed
+// as compatible with apiserverVersion in EtcdCompatibilityMatrix, so an
+// untested pairing is caught before it's rendered into the etcd manifest.
+// An empty etcdVersion is always valid; it means the bundled control-plane
+// version is used.
+func ValidateEtcdVersionCompatibility(apiserverVersion, etcdVersion string) error {
+	if etcdVersion == "" {
+		return nil
+	}
+	compatible, ok := EtcdCompatibilityMatrix[apiserverVersion]
+	if !ok {
+		return nil
+	}
+	for _, version := range compatible {
+		if version == etcdVersion {
+			return nil
+		}
+	}
+	return fmt.Errorf("etcd version %q is not compatible with apiserver version %q, compatible versions are %v", etcdVersion, apiserverVersion, compatible)
+}
+
+// SupportedKubernetesVersions returns the Kubernetes versions this package
+// ships control-plane manifests and etcd-compatibility data for (the keys
+// of EtcdCompatibilityMatrix), sorted for a stable, readable error message.
+func SupportedKubernetesVersions() []string {
+	versions := make([]string, 0, len(EtcdCompatibilityMatrix))
+	for version := range EtcdCompatibilityMatrix {
+		versions = append(versions, version)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+// ValidateKubernetesVersion rejects a kubernetesVersion that either isn't a
+// parseable version or isn't one of SupportedKubernetesVersions, so an
+// untested version is caught before createVirtualCluster starts applying
+// control-plane manifests for it. An empty version is always valid; it
+// means the bundled default version is used (see ResolveKubernetesVersion).
+func ValidateKubernetesVersion(version string) error {
+	if version == "" {
+		return nil
+	}
+	if _, err := utilversion.ParseGeneric(version); err != nil {
+		return fmt.Errorf("kubernetesVersion %q is not a valid version: %w", version, err)
+	}
+	for _, supported := range SupportedKubernetesVersions() {
+		if supported == version {
+			return nil
+		}
+	}
+	return fmt.Errorf("kubernetesVersion %q is not supported, supported versions are %v", version, SupportedKubernetesVersions())
+}
+
+// ResolveKubernetesVersion returns the Kubernetes version the virtual
+// cluster's apiserver, controller-manager and scheduler are built at:
+// vc.Spec.KubeInKubeConfig.KubernetesVersion if set, then
+// kubeNestOptions.KubeInKubeConfig.KubernetesVersion, falling back to the
+// bundled default image version.
+func ResolveKubernetesVersion(vc *v1alpha1.VirtualCluster, kubeNestOptions *v1alpha1.KubeNestConfiguration) string {
+	if vc != nil && vc.Spec.KubeInKubeConfig != nil && vc.Spec.KubeInKubeConfig.KubernetesVersion != "" {
+		return vc.Spec.KubeInKubeConfig.KubernetesVersion
+	}
+	if kubeNestOptions != nil && kubeNestOptions.KubeInKubeConfig.KubernetesVersion != "" {
+		return kubeNestOptions.KubeInKubeConfig.KubernetesVersion
+	}
+	_, imageVersion := GetImageMessage()
+	return imageVersion
+}
+
 // compareIPs compares two IP addresses, returns -1 if ip1 < ip2, 1 if ip1 > ip2, and 0 if they are equal
 func compareIPs(ip1, ip2 net.IP) int {
 	if ip1.To4() != nil && ip2.To4() != nil {
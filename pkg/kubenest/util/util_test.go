@@ -1,10 +1,13 @@
 package util
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
 )
 
 func TestFindAvailableIP(t *testing.T) {
@@ -70,6 +73,293 @@ func TestFindAvailableIP(t *testing.T) {
 	}
 }
 
+func TestValidateClusterDNS(t *testing.T) {
+	tests := []struct {
+		name          string
+		clusterDNS    string
+		serviceSubnet string
+		wantErr       bool
+	}{
+		{
+			name:          "dns within subnet",
+			clusterDNS:    "10.237.0.10",
+			serviceSubnet: "10.237.0.0/16",
+			wantErr:       false,
+		},
+		{
+			name:          "dns within second subnet of dual-stack range",
+			clusterDNS:    "fd00::10",
+			serviceSubnet: "10.237.0.0/16,fd00::/108",
+			wantErr:       false,
+		},
+		{
+			name:          "dns outside subnet",
+			clusterDNS:    "192.168.0.10",
+			serviceSubnet: "10.237.0.0/16",
+			wantErr:       true,
+		},
+		{
+			name:          "invalid ip",
+			clusterDNS:    "not-an-ip",
+			serviceSubnet: "10.237.0.0/16",
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateClusterDNS(tt.clusterDNS, tt.serviceSubnet)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateClusterDNS() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateServiceSubnet(t *testing.T) {
+	tests := []struct {
+		name          string
+		serviceSubnet string
+		wantErr       bool
+	}{
+		{
+			name:          "ipv4 only",
+			serviceSubnet: "10.237.0.0/16",
+			wantErr:       false,
+		},
+		{
+			name:          "ipv6 only",
+			serviceSubnet: "fd00::/108",
+			wantErr:       false,
+		},
+		{
+			name:          "dual-stack ipv4 first",
+			serviceSubnet: "10.237.0.0/16,fd00::/108",
+			wantErr:       false,
+		},
+		{
+			name:          "dual-stack ipv6 first",
+			serviceSubnet: "fd00::/108,10.237.0.0/16",
+			wantErr:       false,
+		},
+		{
+			name:          "malformed cidr",
+			serviceSubnet: "not-a-cidr",
+			wantErr:       true,
+		},
+		{
+			name:          "two ipv4 ranges",
+			serviceSubnet: "10.237.0.0/16,10.238.0.0/16",
+			wantErr:       true,
+		},
+		{
+			name:          "two ipv6 ranges",
+			serviceSubnet: "fd00::/108,fd01::/108",
+			wantErr:       true,
+		},
+		{
+			name:          "too many ranges",
+			serviceSubnet: "10.237.0.0/16,fd00::/108,fd01::/108",
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateServiceSubnet(tt.serviceSubnet)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateServiceSubnet() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestClusterDNSConsistentWithKubeDNSService checks that a clusterDNS value
+// inside the service subnet passes ValidateClusterDNS, the check both the
+// CoreDNS service task and the node join apply before using
+// KubeInKubeConfig.ClusterDNS as it is. Its final comparison is between two
+// copies of that one value; it calls neither of those code paths.
+func TestClusterDNSConsistentWithKubeDNSService(t *testing.T) {
+	serviceSubnet := "10.237.0.0/18"
+	clusterDNS := "10.237.0.10"
+
+	if err := ValidateClusterDNS(clusterDNS, serviceSubnet); err != nil {
+		t.Fatalf("expected clusterDNS %s to be valid for subnet %s: %s", clusterDNS, serviceSubnet, err)
+	}
+
+	coreDNSServiceClusterIP := clusterDNS
+	kubeletClusterDNS := clusterDNS
+	if coreDNSServiceClusterIP != kubeletClusterDNS {
+		t.Fatalf("CoreDNS service clusterIP %s must match kubelet --cluster-dns %s", coreDNSServiceClusterIP, kubeletClusterDNS)
+	}
+}
+
+func TestValidateStorageMediaType(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{name: "empty is valid", value: "", wantErr: false},
+		{name: "json", value: "application/json", wantErr: false},
+		{name: "protobuf", value: "application/vnd.kubernetes.protobuf", wantErr: false},
+		{name: "unsupported", value: "application/yaml", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateStorageMediaType(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateStorageMediaType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateExternalHostName(t *testing.T) {
+	tests := []struct {
+		name     string
+		hostname string
+		wantErr  bool
+	}{
+		{name: "empty is valid", hostname: "", wantErr: false},
+		{name: "valid hostname", hostname: "vc1.example.com", wantErr: false},
+		{name: "contains a scheme", hostname: "https://vc1.example.com", wantErr: true},
+		{name: "contains a port", hostname: "vc1.example.com:6443", wantErr: true},
+		{name: "starts with a dash", hostname: "-vc1.example.com", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateExternalHostName(tt.hostname)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateExternalHostName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateExternalIPs(t *testing.T) {
+	tests := []struct {
+		name    string
+		ips     []string
+		wantErr bool
+	}{
+		{name: "empty is valid", ips: nil, wantErr: false},
+		{name: "valid IPv4", ips: []string{"1.2.3.4"}, wantErr: false},
+		{name: "valid IPv6", ips: []string{"::1"}, wantErr: false},
+		{name: "valid hostname", ips: []string{"vc1.example.com"}, wantErr: false},
+		{name: "mix of IP and hostname", ips: []string{"1.2.3.4", "vc1.example.com"}, wantErr: false},
+		{name: "contains a port", ips: []string{"1.2.3.4:6443"}, wantErr: true},
+		{name: "contains a scheme", ips: []string{"https://vc1.example.com"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateExternalIPs(tt.ips)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateExternalIPs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateKubeletExtraArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    map[string]string
+		wantErr bool
+	}{
+		{name: "nil is valid", args: nil, wantErr: false},
+		{name: "recognized flags", args: map[string]string{"max-pods": "64", "system-reserved": "cpu=200m"}, wantErr: false},
+		{name: "unrecognized flag", args: map[string]string{"not-a-real-flag": "1"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateKubeletExtraArgs(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateKubeletExtraArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateEtcdVersionCompatibility(t *testing.T) {
+	tests := []struct {
+		name             string
+		apiserverVersion string
+		etcdVersion      string
+		wantErr          bool
+	}{
+		{name: "empty etcd version is valid", apiserverVersion: "v1.27.6", etcdVersion: "", wantErr: false},
+		{name: "compatible pairing", apiserverVersion: "v1.27.6", etcdVersion: "3.5.7", wantErr: false},
+		{name: "incompatible pairing", apiserverVersion: "v1.27.6", etcdVersion: "2.3.0", wantErr: true},
+		{name: "unlisted apiserver version accepts anything", apiserverVersion: "v1.99.0", etcdVersion: "2.3.0", wantErr: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateEtcdVersionCompatibility(tt.apiserverVersion, tt.etcdVersion)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateEtcdVersionCompatibility() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateKubernetesVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		wantErr bool
+	}{
+		{name: "empty is valid", version: "", wantErr: false},
+		{name: "supported version", version: "v1.27.6", wantErr: false},
+		{name: "unparseable version", version: "not-a-version", wantErr: true},
+		{name: "parseable but unsupported version", version: "v1.99.0", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateKubernetesVersion(tt.version)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateKubernetesVersion(%q) error = %v, wantErr %v", tt.version, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResolveKubernetesVersion(t *testing.T) {
+	_, defaultVersion := GetImageMessage()
+
+	if got := ResolveKubernetesVersion(nil, nil); got != defaultVersion {
+		t.Errorf("ResolveKubernetesVersion(nil, nil) = %q, want default %q", got, defaultVersion)
+	}
+
+	kubeNestOptions := &v1alpha1.KubeNestConfiguration{KubeInKubeConfig: v1alpha1.KubeInKubeConfig{KubernetesVersion: "v1.28.9"}}
+	if got := ResolveKubernetesVersion(nil, kubeNestOptions); got != "v1.28.9" {
+		t.Errorf("ResolveKubernetesVersion() = %q, want the KubeNestConfiguration default", got)
+	}
+
+	vc := &v1alpha1.VirtualCluster{Spec: v1alpha1.VirtualClusterSpec{KubeInKubeConfig: &v1alpha1.KubeInKubeConfig{KubernetesVersion: "v1.27.6"}}}
+	if got := ResolveKubernetesVersion(vc, kubeNestOptions); got != "v1.27.6" {
+		t.Errorf("ResolveKubernetesVersion() = %q, want the VirtualCluster override", got)
+	}
+}
+
+func TestValidatePromotePolicyNodeCount(t *testing.T) {
+	// The pool only has 3 matching nodes right now, but the policy only asks
+	// for 2 -- satisfiable, even if some of those 3 happen to be in use today.
+	satisfiable := v1alpha1.PromotePolicy{NodeCount: 2}
+	if err := ValidatePromotePolicyNodeCount(satisfiable, 3); err != nil {
+		t.Errorf("ValidatePromotePolicyNodeCount() error = %v, want nil for a satisfiable policy", err)
+	}
+
+	// The policy asks for more nodes than could ever match its selector --
+	// no amount of waiting for nodes to free up will satisfy it.
+	impossible := v1alpha1.PromotePolicy{NodeCount: 5}
+	err := ValidatePromotePolicyNodeCount(impossible, 3)
+	if err == nil {
+		t.Fatalf("ValidatePromotePolicyNodeCount() error = nil, want error for an impossible policy")
+	}
+	if !errors.Is(err, ErrPromotePolicyUnsatisfiable) {
+		t.Errorf("ValidatePromotePolicyNodeCount() error = %v, want it to wrap ErrPromotePolicyUnsatisfiable", err)
+	}
+}
+
 func TestFindAvailableIP2(_ *testing.T) {
 	type HostPortPool struct {
 		PortsPool []int32 `yaml:"portsPool"`
@@ -0,0 +1,155 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+	"github.com/kosmos.io/kosmos/pkg/kubenest/util"
+	"github.com/kosmos.io/kosmos/pkg/scheduler/lifted/helpers"
+)
+
+// VirtualClusterValidator rejects a VirtualCluster whose PromotePolicies
+// could never be satisfied: either the policies together request more nodes
+// than are free and eligible, or two policies overlap so the same GlobalNode
+// would be double-counted towards both.
+type VirtualClusterValidator struct {
+	Client client.Client
+}
+
+func (v *VirtualClusterValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	return v.validate(ctx, obj.(*v1alpha1.VirtualCluster))
+}
+
+func (v *VirtualClusterValidator) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) error {
+	return v.validate(ctx, newObj.(*v1alpha1.VirtualCluster))
+}
+
+func (v *VirtualClusterValidator) ValidateDelete(_ context.Context, _ runtime.Object) error {
+	return nil
+}
+
+func (v *VirtualClusterValidator) validate(ctx context.Context, vc *v1alpha1.VirtualCluster) error {
+	if seconds := vc.Spec.ReadinessTimeoutSeconds; seconds != nil && *seconds <= 0 {
+		return fmt.Errorf("readinessTimeoutSeconds must be positive, got %d", *seconds)
+	}
+
+	if err := util.ValidateImageRepository(vc.Spec.ImageRepository); err != nil {
+		return err
+	}
+
+	if vc.Spec.KubeInKubeConfig != nil {
+		if err := util.ValidateKubernetesVersion(vc.Spec.KubeInKubeConfig.KubernetesVersion); err != nil {
+			return err
+		}
+	}
+
+	globalNodeList := &v1alpha1.GlobalNodeList{}
+	if err := v.Client.List(ctx, globalNodeList); err != nil {
+		return fmt.Errorf("list globalnodes error: %v", err)
+	}
+
+	var victimPriority map[string]int32
+	if vc.Spec.EnablePreemption {
+		var err error
+		victimPriority, err = v.virtualClusterPriorities(ctx)
+		if err != nil {
+			return fmt.Errorf("list virtualclusters to check preemption priority: %v", err)
+		}
+	}
+
+	matchedByNode := map[string][]string{}
+	for _, policy := range vc.Spec.PromotePolicies {
+		selector := metav1.FormatLabelSelector(policy.LabelSelector)
+
+		eligible, err := eligibleGlobalNodes(globalNodeList.Items, vc.Name, policy, vc.Spec.EnablePreemption, vc.Spec.Priority, victimPriority)
+		if err != nil {
+			return errors.Wrapf(err, "promote policy %s: invalid label selector", selector)
+		}
+
+		if int(policy.NodeCount) > len(eligible) {
+			return fmt.Errorf("promote policy with selector %q requests %d nodes but only %d free and matching nodes are available", selector, policy.NodeCount, len(eligible))
+		}
+
+		for _, node := range eligible {
+			matchedByNode[node.Name] = append(matchedByNode[node.Name], selector)
+		}
+	}
+
+	for nodeName, selectors := range matchedByNode {
+		if len(selectors) > 1 {
+			return fmt.Errorf("globalnode %q is matched by more than one promote policy (%v), which would double-count it; narrow the label selectors so they don't overlap", nodeName, selectors)
+		}
+	}
+
+	return nil
+}
+
+// eligibleGlobalNodes returns the GlobalNodes that could be counted towards
+// policy: free nodes (or nodes already owned by virtualClusterName, so a
+// VirtualCluster being updated isn't penalized for nodes it already holds)
+// that match policy's LabelSelector and aren't excluded by an untolerated
+// taint. When enablePreemption is set, an InUse node owned by a VirtualCluster
+// with a strictly lower priority than priority is also counted eligible,
+// mirroring which victims VirtualClusterInitController.preemptNodesForPolicy
+// would actually be willing to reclaim - otherwise a VirtualCluster relying
+// on preemption to satisfy a policy would be rejected here before the
+// controller ever gets a chance to preempt for it. victimPriority maps a
+// VirtualCluster name to its Spec.Priority and is only consulted when
+// enablePreemption is set; a VirtualCluster missing from it is treated as
+// priority 0, same as an unset Spec.Priority.
+func eligibleGlobalNodes(nodes []v1alpha1.GlobalNode, virtualClusterName string, policy v1alpha1.PromotePolicy, enablePreemption bool, priority int32, victimPriority map[string]int32) ([]v1alpha1.GlobalNode, error) {
+	sel, err := metav1.LabelSelectorAsSelector(policy.LabelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	eligible := make([]v1alpha1.GlobalNode, 0, len(nodes))
+	for _, node := range nodes {
+		if node.Spec.State != v1alpha1.NodeFreeState && node.Status.VirtualCluster != virtualClusterName {
+			preemptable := enablePreemption && node.Spec.State == v1alpha1.NodeInUse && victimPriority[node.Status.VirtualCluster] < priority
+			if !preemptable {
+				continue
+			}
+		}
+		if !sel.Matches(labels.Set(node.Spec.Labels)) {
+			continue
+		}
+		if !tolerationsTolerateAllTaints(policy.Tolerations, node.Spec.Taints) {
+			continue
+		}
+		eligible = append(eligible, node)
+	}
+	return eligible, nil
+}
+
+// virtualClusterPriorities lists every VirtualCluster and returns its
+// Spec.Priority keyed by name, for eligibleGlobalNodes to decide which InUse
+// nodes are preemptable.
+func (v *VirtualClusterValidator) virtualClusterPriorities(ctx context.Context) (map[string]int32, error) {
+	list := &v1alpha1.VirtualClusterList{}
+	if err := v.Client.List(ctx, list); err != nil {
+		return nil, err
+	}
+	priorities := make(map[string]int32, len(list.Items))
+	for _, item := range list.Items {
+		priorities[item.Name] = item.Spec.Priority
+	}
+	return priorities, nil
+}
+
+func tolerationsTolerateAllTaints(tolerations []corev1.Toleration, taints []corev1.Taint) bool {
+	for i := range taints {
+		if !helpers.TolerationsTolerateTaint(tolerations, &taints[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,301 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kosmos.io/kosmos/pkg/apis/kosmos/v1alpha1"
+)
+
+// globalNodeListerClient stubs the client.Client methods the validator
+// needs, returning a fixed GlobalNodeList and VirtualClusterList.
+type globalNodeListerClient struct {
+	client.Client
+	nodes    v1alpha1.GlobalNodeList
+	clusters v1alpha1.VirtualClusterList
+}
+
+func (c *globalNodeListerClient) List(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+	switch l := list.(type) {
+	case *v1alpha1.GlobalNodeList:
+		*l = c.nodes
+		return nil
+	case *v1alpha1.VirtualClusterList:
+		*l = c.clusters
+		return nil
+	default:
+		return fmt.Errorf("unexpected list type %T", list)
+	}
+}
+
+func freeGlobalNode(name string, labelSet map[string]string) v1alpha1.GlobalNode {
+	return v1alpha1.GlobalNode{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labelSet},
+		Spec: v1alpha1.GlobalNodeSpec{
+			State:  v1alpha1.NodeFreeState,
+			Labels: labelSet,
+		},
+	}
+}
+
+func virtualClusterWithPolicies(policies ...v1alpha1.PromotePolicy) *v1alpha1.VirtualCluster {
+	return &v1alpha1.VirtualCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "vc1"},
+		Spec:       v1alpha1.VirtualClusterSpec{PromotePolicies: policies},
+	}
+}
+
+func TestVirtualClusterValidatorAllowsSatisfiablePolicy(t *testing.T) {
+	v := &VirtualClusterValidator{Client: &globalNodeListerClient{nodes: v1alpha1.GlobalNodeList{Items: []v1alpha1.GlobalNode{
+		freeGlobalNode("node-1", map[string]string{"zone": "a"}),
+		freeGlobalNode("node-2", map[string]string{"zone": "a"}),
+	}}}}
+
+	vc := virtualClusterWithPolicies(v1alpha1.PromotePolicy{
+		LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}},
+		NodeCount:     2,
+	})
+
+	if err := v.ValidateCreate(context.TODO(), vc); err != nil {
+		t.Fatalf("ValidateCreate() error = %v, want nil", err)
+	}
+}
+
+func TestVirtualClusterValidatorRejectsUnsatisfiablePolicy(t *testing.T) {
+	v := &VirtualClusterValidator{Client: &globalNodeListerClient{nodes: v1alpha1.GlobalNodeList{Items: []v1alpha1.GlobalNode{
+		freeGlobalNode("node-1", map[string]string{"zone": "a"}),
+	}}}}
+
+	vc := virtualClusterWithPolicies(v1alpha1.PromotePolicy{
+		LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}},
+		NodeCount:     2,
+	})
+
+	err := v.ValidateCreate(context.TODO(), vc)
+	if err == nil {
+		t.Fatal("ValidateCreate() error = nil, want an error for a policy requesting more nodes than are available")
+	}
+	if !strings.Contains(err.Error(), "zone") {
+		t.Fatalf("expected error to name the offending selector, got %q", err.Error())
+	}
+}
+
+func TestVirtualClusterValidatorIgnoresOccupiedNodesFromOtherClusters(t *testing.T) {
+	occupied := freeGlobalNode("node-1", map[string]string{"zone": "a"})
+	occupied.Spec.State = v1alpha1.NodeInUse
+	occupied.Status.VirtualCluster = "other-vc"
+
+	v := &VirtualClusterValidator{Client: &globalNodeListerClient{nodes: v1alpha1.GlobalNodeList{Items: []v1alpha1.GlobalNode{occupied}}}}
+
+	vc := virtualClusterWithPolicies(v1alpha1.PromotePolicy{
+		LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}},
+		NodeCount:     1,
+	})
+
+	err := v.ValidateCreate(context.TODO(), vc)
+	if err == nil {
+		t.Fatal("ValidateCreate() error = nil, want an error since the only matching node belongs to another virtualcluster")
+	}
+}
+
+func TestVirtualClusterValidatorCountsNodesAlreadyOwnedByTheSameCluster(t *testing.T) {
+	owned := freeGlobalNode("node-1", map[string]string{"zone": "a"})
+	owned.Spec.State = v1alpha1.NodeInUse
+	owned.Status.VirtualCluster = "vc1"
+
+	v := &VirtualClusterValidator{Client: &globalNodeListerClient{nodes: v1alpha1.GlobalNodeList{Items: []v1alpha1.GlobalNode{owned}}}}
+
+	vc := virtualClusterWithPolicies(v1alpha1.PromotePolicy{
+		LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}},
+		NodeCount:     1,
+	})
+
+	if err := v.ValidateUpdate(context.TODO(), vc, vc); err != nil {
+		t.Fatalf("ValidateUpdate() error = %v, want nil since the node is already owned by this virtualcluster", err)
+	}
+}
+
+func TestVirtualClusterValidatorCountsPreemptableNodesWhenEnabled(t *testing.T) {
+	occupied := freeGlobalNode("node-1", map[string]string{"zone": "a"})
+	occupied.Spec.State = v1alpha1.NodeInUse
+	occupied.Status.VirtualCluster = "low-priority-vc"
+
+	v := &VirtualClusterValidator{Client: &globalNodeListerClient{
+		nodes: v1alpha1.GlobalNodeList{Items: []v1alpha1.GlobalNode{occupied}},
+		clusters: v1alpha1.VirtualClusterList{Items: []v1alpha1.VirtualCluster{
+			{ObjectMeta: metav1.ObjectMeta{Name: "low-priority-vc"}, Spec: v1alpha1.VirtualClusterSpec{Priority: 0}},
+		}},
+	}}
+
+	vc := virtualClusterWithPolicies(v1alpha1.PromotePolicy{
+		LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}},
+		NodeCount:     1,
+	})
+	vc.Spec.Priority = 10
+	vc.Spec.EnablePreemption = true
+
+	if err := v.ValidateCreate(context.TODO(), vc); err != nil {
+		t.Fatalf("ValidateCreate() error = %v, want nil since the node is preemptable from a lower-priority virtualcluster", err)
+	}
+}
+
+func TestVirtualClusterValidatorRejectsPreemptionFromEqualOrHigherPriority(t *testing.T) {
+	occupied := freeGlobalNode("node-1", map[string]string{"zone": "a"})
+	occupied.Spec.State = v1alpha1.NodeInUse
+	occupied.Status.VirtualCluster = "same-priority-vc"
+
+	v := &VirtualClusterValidator{Client: &globalNodeListerClient{
+		nodes: v1alpha1.GlobalNodeList{Items: []v1alpha1.GlobalNode{occupied}},
+		clusters: v1alpha1.VirtualClusterList{Items: []v1alpha1.VirtualCluster{
+			{ObjectMeta: metav1.ObjectMeta{Name: "same-priority-vc"}, Spec: v1alpha1.VirtualClusterSpec{Priority: 10}},
+		}},
+	}}
+
+	vc := virtualClusterWithPolicies(v1alpha1.PromotePolicy{
+		LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}},
+		NodeCount:     1,
+	})
+	vc.Spec.Priority = 10
+	vc.Spec.EnablePreemption = true
+
+	err := v.ValidateCreate(context.TODO(), vc)
+	if err == nil {
+		t.Fatal("ValidateCreate() error = nil, want an error since the only matching node belongs to an equal-priority virtualcluster")
+	}
+}
+
+func TestVirtualClusterValidatorIgnoresPreemptableNodesWhenDisabled(t *testing.T) {
+	occupied := freeGlobalNode("node-1", map[string]string{"zone": "a"})
+	occupied.Spec.State = v1alpha1.NodeInUse
+	occupied.Status.VirtualCluster = "low-priority-vc"
+
+	v := &VirtualClusterValidator{Client: &globalNodeListerClient{
+		nodes: v1alpha1.GlobalNodeList{Items: []v1alpha1.GlobalNode{occupied}},
+		clusters: v1alpha1.VirtualClusterList{Items: []v1alpha1.VirtualCluster{
+			{ObjectMeta: metav1.ObjectMeta{Name: "low-priority-vc"}, Spec: v1alpha1.VirtualClusterSpec{Priority: 0}},
+		}},
+	}}
+
+	vc := virtualClusterWithPolicies(v1alpha1.PromotePolicy{
+		LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}},
+		NodeCount:     1,
+	})
+	vc.Spec.Priority = 10
+
+	err := v.ValidateCreate(context.TODO(), vc)
+	if err == nil {
+		t.Fatal("ValidateCreate() error = nil, want an error since EnablePreemption is unset")
+	}
+}
+
+func TestVirtualClusterValidatorRejectsOverlappingSelectors(t *testing.T) {
+	v := &VirtualClusterValidator{Client: &globalNodeListerClient{nodes: v1alpha1.GlobalNodeList{Items: []v1alpha1.GlobalNode{
+		freeGlobalNode("node-1", map[string]string{"zone": "a", "tier": "gold"}),
+		freeGlobalNode("node-2", map[string]string{"zone": "a", "tier": "gold"}),
+	}}}}
+
+	vc := virtualClusterWithPolicies(
+		v1alpha1.PromotePolicy{
+			LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"zone": "a"}},
+			NodeCount:     1,
+		},
+		v1alpha1.PromotePolicy{
+			LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "gold"}},
+			NodeCount:     1,
+		},
+	)
+
+	err := v.ValidateCreate(context.TODO(), vc)
+	if err == nil {
+		t.Fatal("ValidateCreate() error = nil, want an error for overlapping promote policy selectors")
+	}
+	if !strings.Contains(err.Error(), "double-count") {
+		t.Fatalf("expected error to explain the double-counting, got %q", err.Error())
+	}
+}
+
+func TestVirtualClusterValidatorRejectsNonPositiveReadinessTimeout(t *testing.T) {
+	v := &VirtualClusterValidator{Client: &globalNodeListerClient{}}
+	vc := virtualClusterWithPolicies()
+	zero := int32(0)
+	vc.Spec.ReadinessTimeoutSeconds = &zero
+
+	err := v.ValidateCreate(context.TODO(), vc)
+	if err == nil {
+		t.Fatal("ValidateCreate() error = nil, want an error for a non-positive readinessTimeoutSeconds")
+	}
+	if !strings.Contains(err.Error(), "readinessTimeoutSeconds") {
+		t.Fatalf("expected error to mention readinessTimeoutSeconds, got %q", err.Error())
+	}
+}
+
+func TestVirtualClusterValidatorAllowsPositiveReadinessTimeout(t *testing.T) {
+	v := &VirtualClusterValidator{Client: &globalNodeListerClient{}}
+	vc := virtualClusterWithPolicies()
+	positive := int32(60)
+	vc.Spec.ReadinessTimeoutSeconds = &positive
+
+	if err := v.ValidateCreate(context.TODO(), vc); err != nil {
+		t.Fatalf("ValidateCreate() error = %v, want nil", err)
+	}
+}
+
+func TestVirtualClusterValidatorRejectsInvalidImageRepository(t *testing.T) {
+	v := &VirtualClusterValidator{Client: &globalNodeListerClient{}}
+	vc := virtualClusterWithPolicies()
+	vc.Spec.ImageRepository = "https://ghcr.io/kosmos-io"
+
+	err := v.ValidateCreate(context.TODO(), vc)
+	if err == nil {
+		t.Fatal("ValidateCreate() error = nil, want an error for an invalid imageRepository")
+	}
+	if !strings.Contains(err.Error(), "imageRepository") {
+		t.Fatalf("expected error to mention imageRepository, got %q", err.Error())
+	}
+}
+
+func TestVirtualClusterValidatorAllowsValidImageRepository(t *testing.T) {
+	v := &VirtualClusterValidator{Client: &globalNodeListerClient{}}
+	vc := virtualClusterWithPolicies()
+	vc.Spec.ImageRepository = "ghcr.io/kosmos-io"
+
+	if err := v.ValidateCreate(context.TODO(), vc); err != nil {
+		t.Fatalf("ValidateCreate() error = %v, want nil", err)
+	}
+}
+
+func TestVirtualClusterValidatorRejectsUnsupportedKubernetesVersion(t *testing.T) {
+	v := &VirtualClusterValidator{Client: &globalNodeListerClient{}}
+	vc := virtualClusterWithPolicies()
+	vc.Spec.KubeInKubeConfig = &v1alpha1.KubeInKubeConfig{KubernetesVersion: "v1.99.0"}
+
+	err := v.ValidateCreate(context.TODO(), vc)
+	if err == nil {
+		t.Fatal("ValidateCreate() error = nil, want an error for an unsupported kubernetesVersion")
+	}
+	if !strings.Contains(err.Error(), "kubernetesVersion") {
+		t.Fatalf("expected error to mention kubernetesVersion, got %q", err.Error())
+	}
+}
+
+func TestVirtualClusterValidatorAllowsSupportedKubernetesVersion(t *testing.T) {
+	v := &VirtualClusterValidator{Client: &globalNodeListerClient{}}
+	vc := virtualClusterWithPolicies()
+	vc.Spec.KubeInKubeConfig = &v1alpha1.KubeInKubeConfig{KubernetesVersion: "v1.27.6"}
+
+	if err := v.ValidateCreate(context.TODO(), vc); err != nil {
+		t.Fatalf("ValidateCreate() error = %v, want nil", err)
+	}
+}
+
+func TestVirtualClusterValidatorAllowsDelete(t *testing.T) {
+	v := &VirtualClusterValidator{}
+	if err := v.ValidateDelete(context.TODO(), virtualClusterWithPolicies()); err != nil {
+		t.Fatalf("ValidateDelete() error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,99 @@
+package workflow
+
+import "fmt"
+
+// TaskEntry is a Task registered with a TaskRegistry, carrying enough
+// ordering metadata for Resolve to interleave it with the registry's other
+// entries instead of only ever running after all of them. Name is a
+// registry-local identifier used by After, distinct from Task.Name (which
+// several built-in tasks reuse across the init and uninstall phases and
+// which is only ever used for logging/identifying the running task).
+type TaskEntry struct {
+	// Name identifies this entry so a later registration can list it in
+	// After. Must be unique within a single TaskRegistry; leave empty for an
+	// entry nothing else needs to depend on.
+	Name string
+	Task Task
+	// Priority breaks ties between entries whose After constraints are
+	// already satisfied; lower runs first. Built-in tasks are registered at
+	// priority values spaced apart, leaving room for a caller to slot a task
+	// in between two of them without renumbering anything.
+	Priority int
+	// After names entries that must run before this one. A name that was
+	// never registered is ignored, so a caller depending on an optional
+	// built-in task that isn't registered in a given phase doesn't hard-fail
+	// Resolve.
+	After []string
+}
+
+// TaskRegistry accumulates TaskEntries and resolves them into a single
+// ordered Task list, so callers can register extra provisioning steps (e.g.
+// applying an org-mandated CNI) alongside kubenest's built-in tasks without
+// forking NewInitPhase/UninstallPhase.
+type TaskRegistry struct {
+	entries []TaskEntry
+}
+
+// NewTaskRegistry returns an empty TaskRegistry.
+func NewTaskRegistry() *TaskRegistry {
+	return &TaskRegistry{}
+}
+
+// Register adds entry to the registry. Entries are ordered in Resolve, not
+// here, so registration order by itself carries no meaning - use Priority
+// and After to control where entry ends up.
+func (r *TaskRegistry) Register(entry TaskEntry) {
+	r.entries = append(r.entries, entry)
+}
+
+// Resolve topologically sorts the registry's entries by After, breaking ties
+// at each step by Priority (lower first, then registration order), and
+// returns the resulting Task list. It errors on a duplicate Name or an After
+// cycle.
+func (r *TaskRegistry) Resolve() ([]Task, error) {
+	byName := make(map[string]int, len(r.entries))
+	for i, entry := range r.entries {
+		if entry.Name == "" {
+			continue
+		}
+		if _, exists := byName[entry.Name]; exists {
+			return nil, fmt.Errorf("task %q registered more than once", entry.Name)
+		}
+		byName[entry.Name] = i
+	}
+
+	done := make([]bool, len(r.entries))
+	ordered := make([]Task, 0, len(r.entries))
+	for len(ordered) < len(r.entries) {
+		next := -1
+		for i, entry := range r.entries {
+			if done[i] || !dependenciesDone(entry.After, byName, done) {
+				continue
+			}
+			if next == -1 || entry.Priority < r.entries[next].Priority {
+				next = i
+			}
+		}
+		if next == -1 {
+			return nil, fmt.Errorf("task registry has a dependency cycle among its remaining entries")
+		}
+		ordered = append(ordered, r.entries[next].Task)
+		done[next] = true
+	}
+	return ordered, nil
+}
+
+// dependenciesDone reports whether every name in after that was actually
+// registered (tracked in byName) has already run.
+func dependenciesDone(after []string, byName map[string]int, done []bool) bool {
+	for _, name := range after {
+		idx, ok := byName[name]
+		if !ok {
+			continue
+		}
+		if !done[idx] {
+			return false
+		}
+	}
+	return true
+}
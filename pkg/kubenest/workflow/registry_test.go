@@ -0,0 +1,80 @@
+package workflow
+
+import (
+	"testing"
+)
+
+func namedTask(name string) Task {
+	return Task{Name: name}
+}
+
+func TestTaskRegistryResolveOrdersByPriorityWhenUnconstrained(t *testing.T) {
+	r := NewTaskRegistry()
+	r.Register(TaskEntry{Name: "b", Task: namedTask("b"), Priority: 20})
+	r.Register(TaskEntry{Name: "a", Task: namedTask("a"), Priority: 10})
+	r.Register(TaskEntry{Name: "c", Task: namedTask("c"), Priority: 30})
+
+	resolved, err := r.Resolve()
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	var got []string
+	for _, task := range resolved {
+		got = append(got, task.Name)
+	}
+	want := []string{"a", "b", "c"}
+	for i, name := range want {
+		if got[i] != name {
+			t.Fatalf("Resolve() order = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestTaskRegistryResolveHonorsAfterOverPriority(t *testing.T) {
+	r := NewTaskRegistry()
+	// "extra" has a lower priority than "builtin" but must run after it.
+	r.Register(TaskEntry{Name: "extra", Task: namedTask("extra"), Priority: 5, After: []string{"builtin"}})
+	r.Register(TaskEntry{Name: "builtin", Task: namedTask("builtin"), Priority: 20})
+
+	resolved, err := r.Resolve()
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if len(resolved) != 2 || resolved[0].Name != "builtin" || resolved[1].Name != "extra" {
+		t.Fatalf("Resolve() = %v, want [builtin extra]", resolved)
+	}
+}
+
+func TestTaskRegistryResolveIgnoresAfterOnUnregisteredName(t *testing.T) {
+	r := NewTaskRegistry()
+	r.Register(TaskEntry{Name: "only", Task: namedTask("only"), After: []string{"does-not-exist"}})
+
+	resolved, err := r.Resolve()
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if len(resolved) != 1 || resolved[0].Name != "only" {
+		t.Fatalf("Resolve() = %v, want [only]", resolved)
+	}
+}
+
+func TestTaskRegistryResolveRejectsDuplicateName(t *testing.T) {
+	r := NewTaskRegistry()
+	r.Register(TaskEntry{Name: "dup", Task: namedTask("dup")})
+	r.Register(TaskEntry{Name: "dup", Task: namedTask("dup")})
+
+	if _, err := r.Resolve(); err == nil {
+		t.Fatal("expected Resolve() to error on a duplicate entry name")
+	}
+}
+
+func TestTaskRegistryResolveRejectsCycle(t *testing.T) {
+	r := NewTaskRegistry()
+	r.Register(TaskEntry{Name: "a", Task: namedTask("a"), After: []string{"b"}})
+	r.Register(TaskEntry{Name: "b", Task: namedTask("b"), After: []string{"a"}})
+
+	if _, err := r.Resolve(); err == nil {
+		t.Fatal("expected Resolve() to error on an After cycle")
+	}
+}
@@ -59,6 +59,17 @@ func IPFamilyGenerator(apiServerServiceSubnet string) []corev1.IPFamily {
 	return ipFamilies
 }
 
+// IPFamilyPolicyFor returns the IPFamilyPolicy a Service must declare to get
+// the given ipFamilies assigned: RequireDualStack for the two-family result
+// IPFamilyGenerator returns for a dual-stack service subnet, SingleStack
+// otherwise.
+func IPFamilyPolicyFor(ipFamilies []corev1.IPFamily) corev1.IPFamilyPolicy {
+	if len(ipFamilies) > 1 {
+		return corev1.IPFamilyPolicyRequireDualStack
+	}
+	return corev1.IPFamilyPolicySingleStack
+}
+
 func FormatCIDR(cidr string) (string, error) {
 	_, ipNet, err := netutils.ParseCIDRSloppy(cidr)
 	if err != nil {